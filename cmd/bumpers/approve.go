@@ -0,0 +1,32 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// createApproveCommand creates the approve command.
+func createApproveCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "approve TOKEN",
+		Short: "Allow a command blocked pending approval",
+		Long: "Allow a command blocked by a rule with approval: session. The block message includes " +
+			"the token; once approved, that exact command is allowed until a new session starts.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliApp, err := createAppFromCommand(cmd.Context(), cmd.Parent())
+			if err != nil {
+				return err
+			}
+
+			value, err := cliApp.Approve(cmd.Context(), args[0])
+			if err != nil {
+				return fmt.Errorf("failed to approve: %w", err)
+			}
+
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s Approved for this session: %s\n", statusMark(true), value)
+			return nil
+		},
+	}
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/wizzomafizzo/bumpers/internal/display"
+)
+
+// applyColorFlag turns off colored output for --no-color. Without it, color is already off
+// when stdout isn't a terminal, NO_COLOR is set or TERM is dumb.
+func applyColorFlag(cmd *cobra.Command) error {
+	noColor, err := cmd.Flags().GetBool("no-color")
+	if err != nil {
+		return fmt.Errorf("failed to get no-color flag: %w", err)
+	}
+	if noColor {
+		display.DisableColor()
+	}
+	return nil
+}
+
+// statusMark returns the [✓] marker for a passed check or [✗] for a failed one, green or red
+// when color is enabled
+func statusMark(ok bool) string {
+	if ok {
+		return display.Colorize("[✓]", display.Green)
+	}
+	return display.Colorize("[✗]", display.Red)
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wizzomafizzo/bumpers/internal/config"
+)
+
+// configFilePrefix is the file name prefix offered when completing --config
+const configFilePrefix = "bumpers."
+
+// createCompletionCommand creates the shell completion script command
+func createCompletionCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:       "completion bash|zsh|fish",
+		Short:     "Generate shell completion script",
+		ValidArgs: []string{"bash", "zsh", "fish"},
+		Args:      cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root := cmd.Root()
+			out := cmd.OutOrStdout()
+
+			var err error
+			switch args[0] {
+			case "bash":
+				err = root.GenBashCompletionV2(out, true)
+			case "zsh":
+				err = root.GenZshCompletion(out)
+			case "fish":
+				err = root.GenFishCompletion(out, true)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to generate %s completion: %w", args[0], err)
+			}
+			return nil
+		},
+	}
+}
+
+// completeRuleIndices offers 1-based rule indices from the current config, described by
+// their patterns. Any error yields no suggestions.
+func completeRuleIndices(cmd *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	configPath, err := configPathFromCommand(cmd)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	suggestions := make([]string, 0, len(cfg.Rules))
+	for i := range cfg.Rules {
+		suggestions = append(suggestions, strconv.Itoa(i+1)+"\t"+cfg.Rules[i].GetMatch().Pattern)
+	}
+	return suggestions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeCommandNames offers $name suggestions for every command name and alias in the
+// current config. Any error yields no suggestions.
+func completeCommandNames(cmd *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	configPath, err := configPathFromCommand(cmd)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var suggestions []string
+	add := func(name string) {
+		if candidate := "$" + name; strings.HasPrefix(candidate, toComplete) {
+			suggestions = append(suggestions, candidate)
+		}
+	}
+	for i := range cfg.Commands {
+		add(cfg.Commands[i].Name)
+		for _, alias := range cfg.Commands[i].Aliases {
+			add(alias)
+		}
+	}
+	return suggestions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeConfigPath offers directories and bumpers.* files under the path being typed
+func completeConfigPath(_ *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	dir, prefix := filepath.Split(toComplete)
+	readDir := dir
+	if readDir == "" {
+		readDir = "."
+	}
+
+	entries, err := os.ReadDir(readDir)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var suggestions []string
+	directive := cobra.ShellCompDirectiveNoFileComp
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		switch {
+		case entry.IsDir():
+			// Keep the cursor after the separator so completion can continue inside
+			suggestions = append(suggestions, dir+name+string(filepath.Separator))
+			directive |= cobra.ShellCompDirectiveNoSpace
+		case strings.HasPrefix(name, configFilePrefix):
+			suggestions = append(suggestions, dir+name)
+		}
+	}
+	return suggestions, directive
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const completionTestConfig = `rules:
+  - match: "^go test"
+    send: "Use just test"
+  - match: "rm -rf"
+    send: "Be careful"
+commands:
+  - name: "help"
+    aliases: ["h"]
+    send: "Help"
+  - name: "status"
+    send: "Status"
+`
+
+// runCompletion drives cobra's hidden __complete command and returns the suggestion lines
+func runCompletion(t *testing.T, args ...string) []string {
+	t.Helper()
+
+	rootCmd := createNewRootCommand()
+	var out bytes.Buffer
+	rootCmd.SetOut(&out)
+	rootCmd.SetErr(&bytes.Buffer{})
+	rootCmd.SetArgs(append([]string{"__complete"}, args...))
+	require.NoError(t, rootCmd.Execute())
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	// The last line is the shell directive, e.g. ":4"
+	require.NotEmpty(t, lines)
+	require.True(t, strings.HasPrefix(lines[len(lines)-1], ":"))
+	return lines[:len(lines)-1]
+}
+
+func writeCompletionConfig(t *testing.T) string {
+	t.Helper()
+	configPath := filepath.Join(t.TempDir(), "bumpers.yml")
+	require.NoError(t, os.WriteFile(configPath, []byte(completionTestConfig), 0o600))
+	return configPath
+}
+
+func TestCompleteRuleIndices(t *testing.T) {
+	t.Parallel()
+
+	configPath := writeCompletionConfig(t)
+
+	for _, sub := range []string{"remove", "edit"} {
+		suggestions := runCompletion(t, "--config", configPath, "rules", sub, "")
+		assert.Equal(t, []string{"1\t^go test", "2\trm -rf"}, suggestions, sub)
+	}
+}
+
+func TestCompleteRuleIndicesOnlyFirstArg(t *testing.T) {
+	t.Parallel()
+
+	configPath := writeCompletionConfig(t)
+
+	suggestions := runCompletion(t, "--config", configPath, "rules", "remove", "1", "")
+	assert.Empty(t, suggestions)
+}
+
+func TestCompleteRuleIndicesMissingConfig(t *testing.T) {
+	t.Parallel()
+
+	missing := filepath.Join(t.TempDir(), "bumpers.yml")
+	suggestions := runCompletion(t, "--config", missing, "rules", "remove", "")
+	assert.Empty(t, suggestions)
+
+	invalid := filepath.Join(t.TempDir(), "bumpers.yml")
+	require.NoError(t, os.WriteFile(invalid, []byte("rules: [not valid"), 0o600))
+	suggestions = runCompletion(t, "--config", invalid, "rules", "remove", "")
+	assert.Empty(t, suggestions)
+}
+
+func TestCompleteConfigPath(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	for _, name := range []string{"bumpers.yml", "bumpers.json", "other.yml"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(""), 0o600))
+	}
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "rules"), 0o750))
+
+	suggestions := runCompletion(t, "status", "--config", dir+string(filepath.Separator))
+	assert.ElementsMatch(t, []string{
+		filepath.Join(dir, "bumpers.json"),
+		filepath.Join(dir, "bumpers.yml"),
+		filepath.Join(dir, "rules") + string(filepath.Separator),
+	}, suggestions)
+
+	suggestions = runCompletion(t, "status", "--config", filepath.Join(dir, "nope", "x"))
+	assert.Empty(t, suggestions)
+}
+
+func TestCompleteCommandNames(t *testing.T) {
+	t.Parallel()
+
+	configPath := writeCompletionConfig(t)
+
+	cmd := createNewRootCommand()
+	require.NoError(t, cmd.ParseFlags([]string{"--config", configPath}))
+
+	suggestions, _ := completeCommandNames(cmd, nil, "")
+	assert.Equal(t, []string{"$help", "$h", "$status"}, suggestions)
+
+	suggestions, _ = completeCommandNames(cmd, nil, "$s")
+	assert.Equal(t, []string{"$status"}, suggestions)
+}
+
+func TestCompletionCommand(t *testing.T) {
+	t.Parallel()
+
+	for _, shell := range []string{"bash", "zsh", "fish"} {
+		rootCmd := createNewRootCommand()
+		var out bytes.Buffer
+		rootCmd.SetOut(&out)
+		rootCmd.SetArgs([]string{"completion", shell})
+		require.NoError(t, rootCmd.Execute(), shell)
+		assert.Contains(t, out.String(), "bumpers", shell)
+	}
+
+	rootCmd := createNewRootCommand()
+	rootCmd.SetOut(&bytes.Buffer{})
+	rootCmd.SetErr(&bytes.Buffer{})
+	rootCmd.SetArgs([]string{"completion", "powershell"})
+	require.Error(t, rootCmd.Execute())
+}
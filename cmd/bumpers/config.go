@@ -0,0 +1,162 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wizzomafizzo/bumpers/internal/config"
+)
+
+// createConfigCommand creates the config command group
+func createConfigCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Inspect the configuration",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return cmd.Help()
+		},
+	}
+	cmd.AddCommand(createConfigShowCommand())
+	return cmd
+}
+
+// createConfigShowCommand creates the subcommand printing the project or effective config
+func createConfigShowCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "show",
+		Short: "Print the configuration in canonical form",
+		Long: "Print the project's config file in canonical form, with every match and generate " +
+			"written out in full. With --effective, print the config hooks actually use: files " +
+			"merged, the profile applied, locales resolved, invalid rules left out and each rule " +
+			"tagged with its origin. --diff shows what merging and expansion changed. Values " +
+			"matching settings.log_redact_patterns are redacted.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			effective, _ := cmd.Flags().GetBool("effective")
+			asJSON, _ := cmd.Flags().GetBool("json")
+			diff, _ := cmd.Flags().GetBool("diff")
+			if diff && asJSON {
+				return errors.New("--diff and --json cannot be used together")
+			}
+
+			configPath, err := configPathFromCommand(cmd)
+			if err != nil {
+				return err
+			}
+			output, err := showConfig(configPath, effective, asJSON, diff)
+			if err != nil {
+				return err
+			}
+			_, _ = fmt.Fprint(cmd.OutOrStdout(), output)
+			return nil
+		},
+	}
+	cmd.Flags().Bool("effective", false, "Print the merged and expanded config hooks use")
+	cmd.Flags().Bool("json", false, "Print JSON instead of YAML")
+	cmd.Flags().Bool("diff", false, "Show what merging and expansion changed from the project file")
+	return cmd
+}
+
+// showConfig returns the config at configPath encoded for config show
+func showConfig(configPath string, effective, asJSON, diff bool) (string, error) {
+	if diff {
+		return diffEffectiveConfig(configPath)
+	}
+
+	var cfg *config.Config
+	var err error
+	if effective {
+		cfg, err = config.LoadEffective(configPath)
+	} else {
+		cfg, err = config.LoadPrimaryRaw(configPath)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to load config: %w", err)
+	}
+
+	data, err := cfg.EncodeCanonical(asJSON, effective)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode config: %w", err)
+	}
+	return string(data), nil
+}
+
+// diffEffectiveConfig compares the project file with the effective config, both in
+// canonical form. Origin tags are left out since they would mark every rule as changed.
+func diffEffectiveConfig(configPath string) (string, error) {
+	raw, err := config.LoadPrimaryRaw(configPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to load project config: %w", err)
+	}
+	effective, err := config.LoadEffective(configPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to load config: %w", err)
+	}
+
+	// Redact both sides with the effective patterns, which include merged ones
+	raw.Settings.LogRedactPatterns = effective.Settings.LogRedactPatterns
+	for i := range effective.Rules {
+		effective.Rules[i].Origin = nil
+	}
+	rawData, err := raw.EncodeCanonical(false, false)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode project config: %w", err)
+	}
+	effectiveData, err := effective.EncodeCanonical(false, true)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode config: %w", err)
+	}
+
+	lines := diffLines(strings.Split(strings.TrimSuffix(string(rawData), "\n"), "\n"),
+		strings.Split(strings.TrimSuffix(string(effectiveData), "\n"), "\n"))
+	if lines == nil {
+		return "No changes from the project file\n", nil
+	}
+	return strings.Join(lines, "\n") + "\n", nil
+}
+
+// diffLines returns every line of a and b prefixed with "  " when kept, "- " when only in
+// a and "+ " when only in b, or nil when a and b are the same
+func diffLines(a, b []string) []string {
+	// common[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	common := make([][]int, len(a)+1)
+	for i := range common {
+		common[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				common[i][j] = common[i+1][j+1] + 1
+			} else {
+				common[i][j] = max(common[i+1][j], common[i][j+1])
+			}
+		}
+	}
+
+	var lines []string
+	changed := false
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			lines = append(lines, "  "+a[i])
+			i++
+			j++
+		case i < len(a) && (j == len(b) || common[i+1][j] >= common[i][j+1]):
+			lines = append(lines, "- "+a[i])
+			i++
+			changed = true
+		default:
+			lines = append(lines, "+ "+b[j])
+			j++
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	return lines
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffLines(t *testing.T) {
+	t.Parallel()
+
+	if lines := diffLines([]string{"a", "b"}, []string{"a", "b"}); lines != nil {
+		t.Errorf("Expected no diff for equal input, got %q", lines)
+	}
+
+	got := diffLines([]string{"a", "b", "c"}, []string{"a", "x", "c", "d"})
+	want := []string{"  a", "- b", "+ x", "  c", "+ d"}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("diffLines() = %q, want %q", got, want)
+	}
+}
+
+func TestConfigShowEffectiveDiff(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "10-base.yml"), []byte(`settings:
+  log_redact_patterns: ["hunter2"]
+rules:
+  - match: "^npm"
+    send: "Use pnpm"`), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "20-extra.yml"), []byte(`rules:
+  - match: "^mysql -phunter2"
+    send: "Don't inline passwords"`), 0o600))
+
+	run := func(args ...string) string {
+		rootCmd := createNewRootCommand()
+		var output bytes.Buffer
+		rootCmd.SetOut(&output)
+		rootCmd.SetArgs(append([]string{"config", "show", "--config-dir", dir}, args...))
+		require.NoError(t, rootCmd.Execute())
+		return output.String()
+	}
+
+	effective := run("--effective")
+	if !strings.Contains(effective, "file: "+filepath.Join(dir, "20-extra.yml")) {
+		t.Errorf("Expected merged rules tagged with their origin, got:\n%s", effective)
+	}
+	if strings.Contains(effective, "-phunter2") {
+		t.Errorf("Expected redacted values, got:\n%s", effective)
+	}
+
+	diff := run("--diff")
+	if !strings.Contains(diff, "+       pattern: ^mysql -p[REDACTED]") {
+		t.Errorf("Expected the merged rule as an addition, got:\n%s", diff)
+	}
+	if strings.HasPrefix(diff, "- ") || strings.Contains(diff, "\n- ") {
+		t.Errorf("Expected nothing removed from the project file, got:\n%s", diff)
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/wizzomafizzo/bumpers/internal/app"
+	"github.com/wizzomafizzo/bumpers/internal/claude/transcript"
+)
+
+// createDiagnoseCommand creates the diagnose command.
+func createDiagnoseCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diagnose",
+		Short: "Check the inputs bumpers reads for problems",
+		Long: "Check the inputs bumpers reads for problems. With --transcript, check that a Claude " +
+			"transcript is well formed and list each malformed line, which helps explain why intent " +
+			"matching or message context comes back empty. With --claude, check the claude CLI used " +
+			"for AI generation is installed and logged in, refreshing the result status shows.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			transcriptPath, err := cmd.Flags().GetString("transcript")
+			if err != nil {
+				return fmt.Errorf("failed to get transcript flag: %w", err)
+			}
+			checkClaude, err := cmd.Flags().GetBool("claude")
+			if err != nil {
+				return fmt.Errorf("failed to get claude flag: %w", err)
+			}
+			if transcriptPath == "" && !checkClaude {
+				return errors.New("nothing to diagnose, pass --transcript PATH or --claude")
+			}
+
+			if checkClaude {
+				if err := diagnoseClaude(cmd); err != nil {
+					return err
+				}
+			}
+			if transcriptPath == "" {
+				return nil
+			}
+
+			warnings, err := transcript.ValidateTranscriptFormat(transcriptPath)
+			if err != nil {
+				return fmt.Errorf("failed to validate transcript: %w", err)
+			}
+
+			out := cmd.OutOrStdout()
+			if len(warnings) == 0 {
+				_, _ = fmt.Fprintf(out, "[✓] %s: no problems found\n", transcriptPath)
+				return nil
+			}
+			_, _ = fmt.Fprintf(out, "[✗] %s:\n", transcriptPath)
+			for _, warning := range warnings {
+				_, _ = fmt.Fprintf(out, "  %s\n", warning)
+			}
+			return fmt.Errorf("transcript check failed: %d problems found", len(warnings))
+		},
+	}
+
+	cmd.Flags().String("transcript", "", "Check the format of the Claude transcript at `PATH`")
+	cmd.Flags().Bool("claude", false, "Check the claude CLI used for AI generation")
+	return cmd
+}
+
+// diagnoseClaude probes the claude CLI afresh and reports it, failing when it's unavailable
+func diagnoseClaude(cmd *cobra.Command) error {
+	cliApp, err := createAppFromCommand(cmd.Context(), cmd.Parent())
+	if err != nil {
+		return err
+	}
+	probe, err := cliApp.ClaudeStatus(cmd.Context(), true)
+	if err != nil {
+		return fmt.Errorf("failed to check claude CLI: %w", err)
+	}
+
+	_, _ = fmt.Fprint(cmd.OutOrStdout(), app.FormatClaudeStatus(probe))
+	if !probe.Available() {
+		return fmt.Errorf("claude CLI check failed: %s", probe.Status)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	testutil "github.com/wizzomafizzo/bumpers/internal/testing"
+)
+
+func TestDiagnoseTranscript(t *testing.T) {
+	t.Parallel()
+	_, _ = testutil.NewTestContext(t)
+
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "valid.jsonl")
+	malformed := filepath.Join(dir, "malformed.jsonl")
+	entry := `{"type":"user","message":{"role":"user","content":"hi"},"uuid":"u1","timestamp":"2024-01-01T10:00:00Z"}`
+	if err := os.WriteFile(valid, []byte(entry+"\n"), 0o600); err != nil {
+		t.Fatalf("Failed to write transcript: %v", err)
+	}
+	if err := os.WriteFile(malformed, []byte(entry+"\n{\"type\":\"user\"\n"), 0o600); err != nil {
+		t.Fatalf("Failed to write transcript: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		output  string
+		wantErr string
+	}{
+		{name: "valid", path: valid, output: "[✓] " + valid + ": no problems found"},
+		{
+			name:    "malformed",
+			path:    malformed,
+			output:  "line 2: invalid JSON: unexpected end of JSON input",
+			wantErr: "transcript check failed: 1 problems found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			rootCmd := createNewRootCommand()
+			var output bytes.Buffer
+			rootCmd.SetOut(&output)
+			rootCmd.SetArgs([]string{"diagnose", "--transcript", tt.path})
+
+			err := rootCmd.Execute()
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("Expected error %q, got: %v", tt.wantErr, err)
+			}
+			if !strings.Contains(output.String(), tt.output) {
+				t.Errorf("Expected output to contain %q, got: %s", tt.output, output.String())
+			}
+		})
+	}
+}
@@ -84,6 +84,8 @@ func runHookCommand(cmd *cobra.Command, _ []string) error {
 	if err != nil {
 		return err
 	}
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	cliApp.SetHookTimeout(timeout)
 
 	result, exitCode, err := processHookCommand(ctx, cliApp, cmd.InOrStdin(), cmd.ErrOrStderr())
 	if err != nil {
@@ -107,11 +109,14 @@ func runHookCommand(cmd *cobra.Command, _ []string) error {
 
 // createHookCommand creates the hook processing command.
 func createHookCommand() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:          "hook",
 		Short:        "Process hook input from Claude Code",
 		Long:         "Process hook input from Claude Code and apply configured rules",
 		SilenceUsage: true,
 		RunE:         runHookCommand,
 	}
+	cmd.Flags().Duration("timeout", 0,
+		"Maximum time to process the hook before skipping AI generation (default: settings.hook_timeout or 30s)")
+	return cmd
 }
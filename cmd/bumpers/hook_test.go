@@ -2,6 +2,7 @@ package main
 
 import (
 	"testing"
+	"time"
 )
 
 const testHookCommand = "hook"
@@ -55,3 +56,20 @@ func TestCreateHookCommand(t *testing.T) {
 		t.Error("Expected RunE to be set")
 	}
 }
+
+func TestCreateHookCommandTimeoutFlag(t *testing.T) {
+	t.Parallel()
+
+	cmd := createHookCommand()
+	if err := cmd.ParseFlags([]string{"--timeout", "5s"}); err != nil {
+		t.Fatalf("Expected --timeout to parse, got: %v", err)
+	}
+
+	timeout, err := cmd.Flags().GetDuration("timeout")
+	if err != nil {
+		t.Fatalf("Expected a duration flag, got: %v", err)
+	}
+	if timeout != 5*time.Second {
+		t.Errorf("Expected timeout 5s, got %v", timeout)
+	}
+}
@@ -1,28 +1,116 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/wizzomafizzo/bumpers/internal/config"
 )
 
+// installWatchInterval is how often install --watch checks the binary and config for changes
+const installWatchInterval = time.Second
+
 // createInstallCommand creates the install command.
 func createInstallCommand() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "install",
 		Short: "Install bumpers configuration and Claude hooks",
-		Long:  "Install bumpers configuration and Claude hooks",
+		Long: "Install bumpers configuration and Claude hooks. With --watch, keep running and " +
+			"reinstall whenever the bumpers binary or config changes, until interrupted.",
 		RunE: func(cmd *cobra.Command, _ []string) error {
-			app, err := createAppFromCommand(cmd.Context(), cmd.Parent())
+			watch, err := cmd.Flags().GetBool("watch")
+			if err != nil {
+				return fmt.Errorf("failed to get watch flag: %w", err)
+			}
+			configPath, err := configPathFromCommand(cmd.Parent())
 			if err != nil {
 				return err
 			}
 
-			err = app.Initialize()
+			install := func() error {
+				app, appErr := createApp(cmd.Context(), configPath)
+				if appErr != nil {
+					return appErr
+				}
+				if initErr := app.Initialize(); initErr != nil {
+					return fmt.Errorf("failed to initialize: %w", initErr)
+				}
+				return nil
+			}
+			if err := install(); err != nil {
+				return err
+			}
+			if !watch {
+				return nil
+			}
+
+			binaryPath, err := os.Executable()
 			if err != nil {
-				return fmt.Errorf("failed to initialize: %w", err)
+				return fmt.Errorf("failed to get bumpers binary path: %w", err)
 			}
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+			defer stop()
+
+			out := cmd.OutOrStdout()
+			_, _ = fmt.Fprintf(out, "Watching %s and %s for changes, press Ctrl-C to stop\n", binaryPath, configPath)
+			watchInstall(ctx, out, installWatchInterval, []string{binaryPath, configPath}, install)
 			return nil
 		},
 	}
+
+	cmd.Flags().Bool("watch", false, "Reinstall whenever the bumpers binary or config changes")
+	return cmd
+}
+
+// watchInstall polls paths every interval and calls install when any of them changes, until
+// ctx is done. Install failures are reported and watching continues.
+func watchInstall(ctx context.Context, out io.Writer, interval time.Duration, paths []string, install func() error) {
+	last := make([]string, len(paths))
+	for i, path := range paths {
+		last[i] = pathFingerprint(path)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		var changed string
+		for i, path := range paths {
+			// A file being rewritten may briefly be missing, so wait for it to reappear
+			if fingerprint := pathFingerprint(path); fingerprint != "" && fingerprint != last[i] {
+				last[i] = fingerprint
+				changed = path
+			}
+		}
+		if changed == "" {
+			continue
+		}
+
+		_, _ = fmt.Fprintf(out, "%s changed, reinstalling\n", changed)
+		if err := install(); err != nil {
+			_, _ = fmt.Fprintf(out, "[✗] Reinstall failed: %v\n", err)
+			continue
+		}
+		_, _ = fmt.Fprintln(out, "[✓] Reinstalled")
+	}
+}
+
+// pathFingerprint identifies the current contents of a config or binary path by its
+// modification time and size, or returns "" when it can't be read
+func pathFingerprint(path string) string {
+	modTime, size, err := config.SourceStat(path)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("%d:%d", modTime.UnixNano(), size)
 }
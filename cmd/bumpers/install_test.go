@@ -1,7 +1,14 @@
 package main
 
 import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	testutil "github.com/wizzomafizzo/bumpers/internal/testing"
 )
@@ -23,4 +30,75 @@ func TestCreateInstallCommand(t *testing.T) {
 	if cmd.RunE == nil {
 		t.Error("Expected install command to have RunE function")
 	}
+
+	if cmd.Flags().Lookup("watch") == nil {
+		t.Error("Expected install command to have a watch flag")
+	}
+}
+
+// syncBuffer is a bytes.Buffer safe to write from a watcher while a test reads it
+type syncBuffer struct {
+	buf bytes.Buffer
+	mu  sync.Mutex
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p) //nolint:wrapcheck // bytes.Buffer never fails
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestWatchInstallReinstallsOnChange(t *testing.T) {
+	_, _ = testutil.NewTestContext(t)
+	t.Parallel()
+
+	dir := t.TempDir()
+	binaryPath := filepath.Join(dir, "bumpers")
+	configPath := filepath.Join(dir, "bumpers.yml")
+	for _, path := range []string{binaryPath, configPath} {
+		if err := os.WriteFile(path, []byte("v1"), 0o600); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	installs := make(chan struct{}, 10)
+	var output syncBuffer
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		watchInstall(ctx, &output, 10*time.Millisecond, []string{binaryPath, configPath}, func() error {
+			installs <- struct{}{}
+			return nil
+		})
+	}()
+
+	// Nothing changed yet
+	select {
+	case <-installs:
+		t.Fatal("Expected no reinstall before a change")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	if err := os.WriteFile(configPath, []byte("rules: []\n"), 0o600); err != nil {
+		t.Fatalf("Failed to update config: %v", err)
+	}
+	select {
+	case <-installs:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected a reinstall after the config changed")
+	}
+
+	cancel()
+	<-done
+	if !strings.Contains(output.String(), configPath+" changed, reinstalling") {
+		t.Errorf("Expected reinstall message, got: %s", output.String())
+	}
 }
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wizzomafizzo/bumpers/internal/config"
+)
+
+// createMigrateCommand creates the migrate command.
+func createMigrateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Rewrite deprecated config forms to the current schema",
+		Long: "Rewrite deprecated forms in the config file: a rule's pattern key becomes match, " +
+			"a rule's event and sources keys move into the match map, and command names lose " +
+			"the old % prefix. Messages that still mention %command are reported to fix by hand. " +
+			"The original file is kept next to it with a .bak suffix.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			configPath, err := writableConfigPathFromCommand(cmd)
+			if err != nil {
+				return err
+			}
+			dryRun, err := cmd.Flags().GetBool("dry-run")
+			if err != nil {
+				return fmt.Errorf("failed to get dry-run flag: %w", err)
+			}
+			if strings.EqualFold(filepath.Ext(configPath), ".json") {
+				return fmt.Errorf("cannot migrate %s: only YAML configs are supported", configPath)
+			}
+
+			data, err := os.ReadFile(configPath) // #nosec G304 -- path is the user's config file
+			if err != nil {
+				return fmt.Errorf("failed to read config: %w", err)
+			}
+			migration, err := config.Migrate(data)
+			if err != nil {
+				return fmt.Errorf("failed to migrate config: %w", err)
+			}
+
+			out := cmd.OutOrStdout()
+			for _, change := range migration.Changes {
+				_, _ = fmt.Fprintf(out, "  %s\n", change)
+			}
+			for _, warning := range migration.Warnings {
+				_, _ = fmt.Fprintf(out, "Warning: %s\n", warning)
+			}
+			if len(migration.Changes) == 0 {
+				_, _ = fmt.Fprintln(out, "Config is already up to date")
+				return nil
+			}
+			if _, err := config.LoadPartial(migration.Data); err != nil {
+				return fmt.Errorf("migrated config doesn't load, no changes made: %w", err)
+			}
+			if dryRun {
+				_, _ = fmt.Fprintf(out, "\n%s", migration.Data)
+				return nil
+			}
+
+			backupPath := configPath + ".bak"
+			if err := os.WriteFile(backupPath, data, 0o600); err != nil {
+				return fmt.Errorf("failed to write backup: %w", err)
+			}
+			if err := os.WriteFile(configPath, migration.Data, 0o600); err != nil {
+				return fmt.Errorf("failed to write config: %w", err)
+			}
+			_, _ = fmt.Fprintf(out, "%s Migrated %s with %d changes, backup saved to %s\n",
+				statusMark(true), configPath, len(migration.Changes), backupPath)
+			return nil
+		},
+	}
+	cmd.Flags().Bool("dry-run", false, "Print the migrated config instead of saving it")
+	return cmd
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wizzomafizzo/bumpers/internal/config"
+)
+
+func TestMigrateCommand(t *testing.T) {
+	t.Parallel()
+
+	configPath := filepath.Join(t.TempDir(), "bumpers.yml")
+	original := []byte(`rules:
+  - pattern: "^go test"
+    send: "Use just test"
+commands:
+  - name: "%test"
+    send: "Run just test"
+`)
+	require.NoError(t, os.WriteFile(configPath, original, 0o600))
+
+	run := func(args ...string) string {
+		rootCmd := createNewRootCommand()
+		var out bytes.Buffer
+		rootCmd.SetOut(&out)
+		rootCmd.SetArgs(append([]string{"--config", configPath, "migrate"}, args...))
+		require.NoError(t, rootCmd.Execute())
+		return out.String()
+	}
+
+	out := run("--dry-run")
+	assert.Contains(t, out, "rule 1 (line 2): renamed pattern to match")
+	assert.Contains(t, out, "- match: \"^go test\"")
+	assert.Equal(t, original, mustReadFile(t, configPath), "dry run doesn't write")
+
+	out = run()
+	assert.Contains(t, out, "[✓] Migrated "+configPath+" with 2 changes, backup saved to "+configPath+".bak")
+	assert.Equal(t, original, mustReadFile(t, configPath+".bak"))
+
+	cfg, err := config.Load(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, "^go test", cfg.Rules[0].GetMatch().Pattern)
+	assert.Equal(t, "test", cfg.Commands[0].Name)
+
+	assert.Equal(t, "Config is already up to date\n", run())
+}
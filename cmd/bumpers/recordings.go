@@ -0,0 +1,68 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/wizzomafizzo/bumpers/internal/claude"
+)
+
+// defaultRecordingMaxAgeDays is how old a recording must be before prune removes it
+const defaultRecordingMaxAgeDays = 30
+
+// createRecordingsCommand creates the command for managing recorded Claude responses
+func createRecordingsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "recordings",
+		Short: "Manage recorded Claude responses used for offline testing",
+		Long: "Manage recorded Claude responses. Set " + claude.RecordDirEnv + " to a directory to " +
+			"record responses and " + claude.ReplayDirEnv + " to replay them without calling Claude.",
+	}
+
+	cmd.AddCommand(createRecordingsPruneCommand())
+
+	return cmd
+}
+
+// createRecordingsPruneCommand creates the command that deletes old recordings
+func createRecordingsPruneCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "prune [dir]",
+		Short: "Delete recordings older than a number of days",
+		Long: "Delete recordings older than --days. The directory defaults to " +
+			claude.RecordDirEnv + ".",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir := os.Getenv(claude.RecordDirEnv)
+			if len(args) > 0 {
+				dir = args[0]
+			}
+			if dir == "" {
+				return errors.New("no recordings directory given and " + claude.RecordDirEnv + " is not set")
+			}
+
+			days, err := cmd.Flags().GetInt("days")
+			if err != nil {
+				return fmt.Errorf("failed to get days flag: %w", err)
+			}
+			if days < 0 {
+				return fmt.Errorf("invalid days %d: must not be negative", days)
+			}
+
+			removed, err := claude.PruneRecordings(dir, time.Duration(days)*24*time.Hour, time.Now())
+			if err != nil {
+				return fmt.Errorf("failed to prune recordings: %w", err)
+			}
+
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Removed %d recordings older than %d days\n", removed, days)
+			return nil
+		},
+	}
+
+	cmd.Flags().Int("days", defaultRecordingMaxAgeDays, "Remove recordings older than this many days")
+
+	return cmd
+}
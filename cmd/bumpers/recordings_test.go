@@ -0,0 +1,34 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wizzomafizzo/bumpers/internal/claude"
+)
+
+func TestRecordingsPruneCommand(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	for prompt, age := range map[string]time.Duration{"old": 10 * 24 * time.Hour, "new": time.Hour} {
+		data, err := json.Marshal(claude.Recording{Prompt: prompt, RecordedAt: time.Now().Add(-age)})
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(claude.RecordingPath(dir, prompt), data, 0o600))
+	}
+
+	rootCmd := createNewRootCommand()
+	var out bytes.Buffer
+	rootCmd.SetOut(&out)
+	rootCmd.SetArgs([]string{"recordings", "prune", dir, "--days", "7"})
+	require.NoError(t, rootCmd.Execute())
+
+	assert.Contains(t, out.String(), "Removed 1 recordings older than 7 days")
+	assert.NoFileExists(t, claude.RecordingPath(dir, "old"))
+	assert.FileExists(t, claude.RecordingPath(dir, "new"))
+}
@@ -2,10 +2,13 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
 
 	"github.com/spf13/cobra"
 	"github.com/wizzomafizzo/bumpers/internal/app"
+	"github.com/wizzomafizzo/bumpers/internal/config"
 )
 
 // createNewRootCommand creates the main root command that shows help by default.
@@ -13,27 +16,72 @@ func createNewRootCommand() *cobra.Command {
 	rootCmd := &cobra.Command{
 		Use:   "bumpers",
 		Short: "Claude Code hook guard",
+		PersistentPreRunE: func(cmd *cobra.Command, _ []string) error {
+			if err := applyColorFlag(cmd); err != nil {
+				return err
+			}
+			return applyProfileFlag(cmd)
+		},
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			// Show help when run without subcommands
 			return cmd.Help()
 		},
 	}
 
-	// Add persistent config flag
+	// Add persistent config flags
 	rootCmd.PersistentFlags().StringP("config", "c", "bumpers.yml", "Path to config file")
+	rootCmd.PersistentFlags().String("config-dir", "",
+		"Directory of config files (*.yml, *.yaml, *.json) merged in name order, overrides --config")
+	rootCmd.PersistentFlags().String("profile", "",
+		"Config profile whose rules are merged into the base config, overrides "+config.ProfileEnv)
+	rootCmd.PersistentFlags().Bool("no-color", false,
+		"Disable colored output, which is also off when output isn't a terminal or NO_COLOR is set")
+	_ = rootCmd.RegisterFlagCompletionFunc("config", completeConfigPath)
+	_ = rootCmd.MarkPersistentFlagDirname("config-dir")
+
+	// Replaced by our own completion command
+	rootCmd.CompletionOptions.DisableDefaultCmd = true
 
 	// Add subcommands
 	rootCmd.AddCommand(
+		createApproveCommand(),
+		createCompletionCommand(),
+		createConfigCommand(),
+		createDiagnoseCommand(),
 		createHookCommand(),
 		createInstallCommand(),
+		createMigrateCommand(),
+		createRecordingsCommand(),
 		createRulesCommand(),
+		createRunCommand(),
+		createSelftestCommand(),
+		createSimulateCommand(),
+		createStateCommand(),
 		createStatusCommand(),
 		createValidateCommand(),
+		createVersionCommand(),
+		createWatchCommand(),
 	)
 
 	return rootCmd
 }
 
+// applyProfileFlag exports --profile as BUMPERS_PROFILE, so every config load in this
+// process, including hooks, uses the profile
+func applyProfileFlag(cmd *cobra.Command) error {
+	profile, err := cmd.Flags().GetString("profile")
+	if err != nil {
+		return fmt.Errorf("failed to get profile flag: %w", err)
+	}
+	if profile == "" {
+		return nil
+	}
+	if err := os.Setenv(config.ProfileEnv, profile); err != nil {
+		return fmt.Errorf("failed to set %s: %w", config.ProfileEnv, err)
+	}
+	return nil
+}
+
 // createApp creates a CLI app using the factory pattern
 func createApp(ctx context.Context, configPath string) (*app.App, error) {
 	factory := app.NewAppFactory()
@@ -43,10 +91,41 @@ func createApp(ctx context.Context, configPath string) (*app.App, error) {
 
 // createAppFromCommand extracts config path and creates a CLI app
 func createAppFromCommand(ctx context.Context, cmd *cobra.Command) (*app.App, error) {
-	configPath, err := cmd.Flags().GetString("config")
+	configPath, err := configPathFromCommand(cmd)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get config flag: %w", err)
+		return nil, err
 	}
 
 	return createApp(ctx, configPath)
 }
+
+// configPathFromCommand returns the --config-dir directory when set, otherwise the --config file
+func configPathFromCommand(cmd *cobra.Command) (string, error) {
+	configDir, err := cmd.Flags().GetString("config-dir")
+	if err != nil {
+		return "", fmt.Errorf("failed to get config-dir flag: %w", err)
+	}
+	if configDir != "" {
+		return configDir, nil
+	}
+
+	configPath, err := cmd.Flags().GetString("config")
+	if err != nil {
+		return "", fmt.Errorf("failed to get config flag: %w", err)
+	}
+	return configPath, nil
+}
+
+// writableConfigPathFromCommand returns the --config file, rejecting --config-dir
+// since merged directories cannot be written back
+func writableConfigPathFromCommand(cmd *cobra.Command) (string, error) {
+	if configDir, _ := cmd.Flags().GetString("config-dir"); configDir != "" {
+		return "", errors.New("cannot modify config when using --config-dir, use --config to pick a file")
+	}
+
+	configPath, err := cmd.Flags().GetString("config")
+	if err != nil {
+		return "", fmt.Errorf("failed to get config flag: %w", err)
+	}
+	return configPath, nil
+}
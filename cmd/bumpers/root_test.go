@@ -3,9 +3,11 @@ package main
 import (
 	"bytes"
 	"context"
+	"os"
 	"strings"
 	"testing"
 
+	"github.com/wizzomafizzo/bumpers/internal/config"
 	testutil "github.com/wizzomafizzo/bumpers/internal/testing"
 )
 
@@ -145,3 +147,40 @@ func TestCreateAppFromCommand(t *testing.T) {
 		t.Error("Expected createAppFromCommand to return non-nil app")
 	}
 }
+
+func TestConfigPathFromCommandPrefersConfigDir(t *testing.T) {
+	_, _ = testutil.NewTestContext(t) // Context-aware logging available if needed
+	t.Parallel()
+
+	cmd := createNewRootCommand()
+	if err := cmd.ParseFlags([]string{"--config", "test.yml", "--config-dir", "rules/"}); err != nil {
+		t.Fatalf("Failed to parse flags: %v", err)
+	}
+
+	configPath, err := configPathFromCommand(cmd)
+	if err != nil {
+		t.Fatalf("Expected configPathFromCommand to succeed, got error: %v", err)
+	}
+	if configPath != "rules/" {
+		t.Errorf("Expected config dir 'rules/', got %q", configPath)
+	}
+
+	if _, err := writableConfigPathFromCommand(cmd); err == nil {
+		t.Error("Expected writable config path to be rejected with --config-dir")
+	}
+}
+
+func TestProfileFlagSetsProfileEnv(t *testing.T) {
+	t.Setenv(config.ProfileEnv, "")
+
+	cmd := createNewRootCommand()
+	if err := cmd.ParseFlags([]string{"--profile", "ci"}); err != nil {
+		t.Fatalf("Failed to parse flags: %v", err)
+	}
+	if err := applyProfileFlag(cmd); err != nil {
+		t.Fatalf("Expected applyProfileFlag to succeed, got error: %v", err)
+	}
+	if got := os.Getenv(config.ProfileEnv); got != "ci" {
+		t.Errorf("Expected %s=ci, got %q", config.ProfileEnv, got)
+	}
+}
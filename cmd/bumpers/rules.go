@@ -4,16 +4,24 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/wizzomafizzo/bumpers/internal/app"
 	"github.com/wizzomafizzo/bumpers/internal/claude"
 	ai "github.com/wizzomafizzo/bumpers/internal/claude/api"
 	"github.com/wizzomafizzo/bumpers/internal/config"
+	"github.com/wizzomafizzo/bumpers/internal/constants"
+	"github.com/wizzomafizzo/bumpers/internal/display"
+	"github.com/wizzomafizzo/bumpers/internal/matcher"
 	"github.com/wizzomafizzo/bumpers/internal/patterns"
+	"github.com/wizzomafizzo/bumpers/internal/project"
 	"github.com/wizzomafizzo/bumpers/internal/prompt"
+	"gopkg.in/yaml.v3"
 )
 
 const (
@@ -46,9 +54,9 @@ func createRulesCommand() *cobra.Command {
 		Use:   "rules",
 		Short: "Manage bumpers rules",
 		RunE: func(cmd *cobra.Command, _ []string) error {
-			configPath, err := cmd.Flags().GetString("config")
+			configPath, err := configPathFromCommand(cmd)
 			if err != nil {
-				return fmt.Errorf("failed to get config flag: %w", err)
+				return err
 			}
 
 			// Check if config file exists
@@ -72,9 +80,17 @@ func createRulesCommand() *cobra.Command {
 	cmd.AddCommand(
 		createRulesGenerateCommand(),
 		createRulesTestCommand(),
+		createRulesTestFileCommand(),
 		createRulesAddCommand(),
 		createRulesRemoveCommand(),
 		createRulesEditCommand(),
+		createRulesLintCommand(),
+		createRulesStatsCommand(),
+		createRulesStaleCommand(),
+		createRulesMinimizeCommand(),
+		createRulesSortCommand(),
+		createRulesSetEnabledCommand(false),
+		createRulesSetEnabledCommand(true),
 	)
 
 	return cmd
@@ -82,7 +98,7 @@ func createRulesCommand() *cobra.Command {
 
 // createRulesGenerateCommand creates the pattern generation subcommand
 func createRulesGenerateCommand() *cobra.Command {
-	launcher := claude.NewLauncher(nil)
+	launcher := claude.WrapFromEnv(claude.NewLauncher(nil))
 	return createRulesGenerateCommandWithLauncher(launcher)
 }
 
@@ -119,10 +135,22 @@ func createRulesGenerateCommandWithLauncher(launcher ai.MessageGenerator) *cobra
 
 // createRulesTestCommand creates the pattern testing subcommand
 func createRulesTestCommand() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "test",
 		Short: "Test if patterns match commands",
+		Long: "Test if a raw pattern matches a command with \"rules test <pattern> <command>\", or test a " +
+			"configured rule with \"rules test --rule <index|id> <value>\". With --rule, the rule's " +
+			"templates are expanded for the project and its tool, event and sources are checked as a " +
+			"hook would, so a match means the rule fires.",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ruleRef, err := cmd.Flags().GetString("rule")
+			if err != nil {
+				return fmt.Errorf("failed to get rule flag: %w", err)
+			}
+			if ruleRef != "" {
+				return runRulesTestRule(cmd, ruleRef, args)
+			}
+
 			if len(args) < 2 {
 				return errors.New("requires pattern and command arguments")
 			}
@@ -136,9 +164,110 @@ func createRulesTestCommand() *cobra.Command {
 			}
 
 			if matched {
-				_, _ = fmt.Fprintln(cmd.OutOrStdout(), "[✓] Pattern matches!")
+				_, _ = fmt.Fprintln(cmd.OutOrStdout(), statusMark(true), "Pattern matches!")
 			} else {
-				_, _ = fmt.Fprintln(cmd.OutOrStdout(), "[✗] Pattern does not match")
+				_, _ = fmt.Fprintln(cmd.OutOrStdout(), statusMark(false), "Pattern does not match")
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().String("rule", "", "Test the configured rule with this index or id")
+	cmd.Flags().String("tool", "Bash", "Tool name the value is sent to, with --rule")
+	cmd.Flags().String("event", "pre", "Hook event, pre or post, with --rule")
+	cmd.Flags().String("source", "", "Field the value comes from, such as file_path or #intent, with --rule")
+	return cmd
+}
+
+// runRulesTestRule tests the configured rule ruleRef against the single value in args
+func runRulesTestRule(cmd *cobra.Command, ruleRef string, args []string) error {
+	if len(args) != 1 {
+		return errors.New("requires a value argument with --rule")
+	}
+	if err := checkRuleRef(ruleRef); err != nil {
+		return err
+	}
+
+	input := app.RuleCheckInput{Value: args[0]}
+	for name, target := range map[string]*string{"tool": &input.Tool, "event": &input.Event, "source": &input.Source} {
+		value, err := cmd.Flags().GetString(name)
+		if err != nil {
+			return fmt.Errorf("failed to get %s flag: %w", name, err)
+		}
+		*target = value
+	}
+	if input.Event != "pre" && input.Event != "post" {
+		return fmt.Errorf("invalid event %q: must be pre or post", input.Event)
+	}
+
+	cliApp, err := createAppFromCommand(cmd.Context(), cmd)
+	if err != nil {
+		return err
+	}
+	check, err := cliApp.CheckRule(ruleRef, input)
+	if err != nil {
+		return fmt.Errorf("failed to test rule: %w", err)
+	}
+
+	out := cmd.OutOrStdout()
+	if check.Matched {
+		_, _ = fmt.Fprintf(out, "%s Rule %d matches\n", statusMark(true), check.Index)
+	} else {
+		_, _ = fmt.Fprintf(out, "%s Rule %d does not match: %s\n", statusMark(false), check.Index, check.Reason)
+	}
+	_, _ = fmt.Fprintf(out, "Pattern: %s\n", check.Pattern)
+	if check.Source != "" {
+		_, _ = fmt.Fprintf(out, "Source: %s\n", check.Source)
+	}
+	return nil
+}
+
+// createRulesTestFileCommand creates the subcommand that runs a file of rule test cases
+func createRulesTestFileCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "test-file FILE",
+		Short: "Run rule test cases from a file",
+		Long: "Run the test cases listed under \"tests:\" in a YAML file against the configured " +
+			"rules. Each case has a name and an input sent to a tool (Bash by default), and expects " +
+			"a rule to block it unless expected_match is false. Set expected_rule to the pattern " +
+			"of the rule that should match. Exits with an error when any case fails.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if ext := strings.ToLower(filepath.Ext(args[0])); ext != ".yml" && ext != ".yaml" {
+				return fmt.Errorf("unsupported test file %s: must be YAML (.yml or .yaml)", args[0])
+			}
+			data, err := os.ReadFile(args[0]) // #nosec G304 -- path is the user's test file
+			if err != nil {
+				return fmt.Errorf("failed to read test file: %w", err)
+			}
+			cases, err := app.ParseRuleTests(data)
+			if err != nil {
+				return fmt.Errorf("failed to load %s: %w", args[0], err)
+			}
+
+			cliApp, err := createAppFromCommand(cmd.Context(), cmd)
+			if err != nil {
+				return err
+			}
+			results, err := cliApp.RunRuleTests(cases)
+			if err != nil {
+				return fmt.Errorf("failed to run rule tests: %w", err)
+			}
+
+			out := cmd.OutOrStdout()
+			failed := 0
+			for i := range results {
+				result := &results[i]
+				if result.Passed {
+					_, _ = fmt.Fprintln(out, statusMark(true), result.Case.Name)
+					continue
+				}
+				failed++
+				_, _ = fmt.Fprintf(out, "%s %s: %s\n", statusMark(false), result.Case.Name, result.Reason)
+			}
+			_, _ = fmt.Fprintf(out, "\n%d passed, %d failed\n", len(results)-failed, failed)
+			if failed > 0 {
+				return fmt.Errorf("%d of %d rule tests failed", failed, len(results))
 			}
 			return nil
 		},
@@ -151,9 +280,31 @@ func createRulesAddCommand() *cobra.Command {
 		Use:   "add",
 		Short: "Add new rules",
 		RunE: func(cmd *cobra.Command, _ []string) error {
-			configPath, err := cmd.Flags().GetString("config")
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+			if dryRun {
+				pattern, _ := cmd.Flags().GetString("pattern")
+				message, _ := cmd.Flags().GetString("message")
+				tools, _ := cmd.Flags().GetString("tools")
+				generate, _ := cmd.Flags().GetString("generate")
+
+				rule := ruleFromFlags(pattern, message, tools, generate)
+				rule.ID = (&config.Config{}).NewRuleID(&rule)
+				data, err := marshalRuleYAML(rule)
+				if err != nil {
+					return err
+				}
+				_, _ = fmt.Fprint(cmd.OutOrStdout(), data)
+				return nil
+			}
+
+			configPath, err := writableConfigPathFromCommand(cmd)
 			if err != nil {
-				return fmt.Errorf("failed to get config flag: %w", err)
+				return err
+			}
+
+			addCommand, _ := cmd.Flags().GetBool("command")
+			if addCommand {
+				return runInteractiveCommandAddWithConfigPath(configPath)
 			}
 
 			interactive, _ := cmd.Flags().GetBool("interactive")
@@ -172,30 +323,54 @@ func createRulesAddCommand() *cobra.Command {
 	}
 
 	cmd.Flags().BoolP("interactive", "i", false, "Interactive rule creation")
+	cmd.Flags().Bool("command", false, "Interactive $command creation")
 	cmd.Flags().StringP("pattern", "p", "", "Regex pattern for matching")
 	cmd.Flags().StringP("message", "m", "", "Help message to display")
 	cmd.Flags().StringP("tools", "t", bashToolPattern, "Tool regex (default: "+bashToolPattern+")")
 	cmd.Flags().StringP("generate", "g", "off", "AI generation mode (default: off)")
+	cmd.Flags().Bool("dry-run", false, "Print the rule as YAML instead of saving it")
 
 	return cmd
 }
 
-// runNonInteractiveRuleAddWithConfigPath handles non-interactive rule creation with a specific config path
-func runNonInteractiveRuleAddWithConfigPath(pattern, message, tools, generate, configPath string) error {
-	cfg := &config.Config{}
-	rule := config.Rule{
+// ruleFromFlags builds a rule from the non-interactive add flags
+func ruleFromFlags(pattern, message, tools, generate string) config.Rule {
+	return config.Rule{
 		Match:    pattern,
 		Send:     message,
 		Tool:     tools,
 		Generate: generate,
 	}
-	cfg.AddRule(rule)
-	if err := cfg.Save(configPath); err != nil {
+}
+
+// marshalRuleYAML renders rule as a one-item YAML list, ready to paste under a config's rules key
+func marshalRuleYAML(rule config.Rule) (string, error) {
+	data, err := yaml.Marshal([]config.Rule{rule})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal rule: %w", err)
+	}
+	return string(data), nil
+}
+
+// saveConfig saves cfg to configPath, warning on stderr when the file had to be rewritten in
+// full rather than edited in place, which drops its comments and formatting
+func saveConfig(cfg *config.Config, configPath string) error {
+	rewritten, err := cfg.SaveEdits(configPath)
+	if err != nil {
 		return fmt.Errorf("failed to save config: %w", err)
 	}
+	if rewritten {
+		_, _ = fmt.Fprintf(os.Stderr, "Warning: rewrote %s in full, comments and formatting were not kept\n", configPath)
+	}
 	return nil
 }
 
+// runNonInteractiveRuleAddWithConfigPath handles non-interactive rule creation with a specific
+// config path, appending the rule to the existing config
+func runNonInteractiveRuleAddWithConfigPath(pattern, message, tools, generate, configPath string) error {
+	return saveRuleToConfigPath(ruleFromFlags(pattern, message, tools, generate), configPath)
+}
+
 // listRulesFromConfigPath lists rules from a specific config path and returns the output as string
 func listRulesFromConfigPath(configPath string) (string, error) {
 	// Check if config file exists
@@ -210,7 +385,7 @@ func listRulesFromConfigPath(configPath string) (string, error) {
 	}
 
 	// Display rules with indices
-	if len(cfg.Rules) == 0 {
+	if len(cfg.Rules) == 0 && len(cfg.Commands) == 0 {
 		return "No rules found in config", nil
 	}
 
@@ -225,6 +400,9 @@ func listRulesFromConfigPath(configPath string) (string, error) {
 	for i, rule := range cfg.Rules {
 		// Format index with zero padding
 		_, _ = fmt.Fprintf(&output, "[%0*d] Pattern: %s\n", indexWidth, i+1, rule.GetMatch().Pattern)
+		if rule.ID != "" {
+			_, _ = fmt.Fprintf(&output, "%sID: %s\n", indent, rule.ID)
+		}
 		_, _ = fmt.Fprintf(&output, "%sMessage: %s\n", indent, rule.Send)
 		if rule.Tool != "" {
 			_, _ = fmt.Fprintf(&output, "%sTools: %s\n", indent, rule.Tool)
@@ -233,12 +411,38 @@ func listRulesFromConfigPath(configPath string) (string, error) {
 		if generate.Mode != "off" && generate.Mode != "session" {
 			_, _ = fmt.Fprintf(&output, "%sGenerate: %s\n", indent, generate.Mode)
 		}
+		if !rule.IsEnabled() {
+			_, _ = fmt.Fprintf(&output, "%sEnabled: %s\n", indent, display.Colorize("false", display.Red))
+		}
 		_, _ = fmt.Fprintln(&output)
 	}
 
+	writeCommandsList(&output, cfg.Commands)
+
 	return output.String(), nil
 }
 
+// writeCommandsList appends the configured commands and their aliases to the output
+func writeCommandsList(output *strings.Builder, commands []config.Command) {
+	if len(commands) == 0 {
+		return
+	}
+
+	_, _ = fmt.Fprintln(output, "Commands:")
+	for i := range commands {
+		cmd := &commands[i]
+		_, _ = fmt.Fprintf(output, "  %s%s", constants.CommandPrefix, cmd.Name)
+		if len(cmd.Aliases) > 0 {
+			aliases := make([]string, len(cmd.Aliases))
+			for j, alias := range cmd.Aliases {
+				aliases[j] = constants.CommandPrefix + alias
+			}
+			_, _ = fmt.Fprintf(output, " (aliases: %s)", strings.Join(aliases, ", "))
+		}
+		_, _ = fmt.Fprintln(output)
+	}
+}
+
 // deleteRuleFromConfigPath deletes a rule by index from a specific config path
 func deleteRuleFromConfigPath(index int, configPath string) error {
 	// Load config
@@ -253,8 +457,8 @@ func deleteRuleFromConfigPath(index int, configPath string) error {
 	}
 
 	// Save updated config
-	if err := cfg.Save(configPath); err != nil {
-		return fmt.Errorf("failed to save config: %w", err)
+	if err := saveConfig(cfg, configPath); err != nil {
+		return err
 	}
 	return nil
 }
@@ -325,6 +529,87 @@ func runInteractiveRuleAddWithPrompterAndConfigPath(prompter prompt.Prompter, co
 	return collectRuleInputsForAdd(prompter, configPath)
 }
 
+// runInteractiveCommandAddWithConfigPath handles the interactive command creation flow with config path
+func runInteractiveCommandAddWithConfigPath(configPath string) error {
+	p := prompt.NewLinerPrompter()
+	return runInteractiveCommandAddWithPrompterAndConfigPath(p, configPath)
+}
+
+// runInteractiveCommandAddWithPrompterAndConfigPath handles the interactive command creation flow
+// with a custom prompter and config path
+func runInteractiveCommandAddWithPrompterAndConfigPath(prompter prompt.Prompter, configPath string) error {
+	defer func() { _ = prompter.Close() }()
+
+	// Step 1: Command name
+	name, err := prompt.TextInputWithPrompter(prompter, "Command name (without "+constants.CommandPrefix+"):")
+	if err != nil {
+		return fmt.Errorf("cancelled by user: %w", err)
+	}
+
+	// Step 2: Aliases (optional)
+	aliasInput, err := prompt.TextInputWithPrompter(prompter, "Aliases, comma separated (optional):")
+	if err != nil {
+		return fmt.Errorf("cancelled by user: %w", err)
+	}
+
+	// Step 3: Message
+	message, err := prompt.TextInputWithPrompter(prompter, "Message to send:")
+	if err != nil {
+		return fmt.Errorf("cancelled by user: %w", err)
+	}
+
+	name = strings.TrimPrefix(strings.TrimSpace(name), constants.CommandPrefix)
+	if name == "" {
+		return errors.New("command name is required")
+	}
+
+	command := config.Command{
+		Name:    name,
+		Send:    message,
+		Aliases: parseAliases(aliasInput),
+	}
+
+	if err := saveCommandToConfigPath(command, configPath); err != nil {
+		return fmt.Errorf("failed to save command: %w", err)
+	}
+
+	_, _ = fmt.Printf("[✓] Command added: %s%s\n", constants.CommandPrefix, command.Name)
+	return nil
+}
+
+// parseAliases splits a comma separated alias list, dropping blanks and the command prefix
+func parseAliases(input string) []string {
+	var aliases []string
+	for _, alias := range strings.Split(input, ",") {
+		alias = strings.TrimPrefix(strings.TrimSpace(alias), constants.CommandPrefix)
+		if alias != "" {
+			aliases = append(aliases, alias)
+		}
+	}
+	return aliases
+}
+
+// saveCommandToConfigPath appends a command to a specific config file path
+func saveCommandToConfigPath(command config.Command, configPath string) error {
+	cfg := &config.Config{}
+	if _, err := os.Stat(configPath); err == nil {
+		cfg, err = config.LoadForEdit(configPath)
+		if err != nil {
+			return fmt.Errorf("failed to load existing config: %w", err)
+		}
+	}
+
+	cfg.Commands = append(cfg.Commands, command)
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("invalid command: %w", err)
+	}
+
+	if err := saveConfig(cfg, configPath); err != nil {
+		return err
+	}
+	return nil
+}
+
 // buildRuleFromInputs converts user inputs to a Rule struct
 func buildRuleFromInputs(pattern, toolChoice, message, generateMode string) config.Rule {
 	rule := config.Rule{
@@ -368,50 +653,394 @@ func saveRuleToConfigPath(rule config.Rule, configPath string) error {
 		// Create new config
 		cfg = &config.Config{}
 	} else {
-		// Load existing config
+		// Load existing config, which may not have any rules yet
 		var err error
-		cfg, err = config.Load(configPath)
+		cfg, err = config.LoadForEdit(configPath)
 		if err != nil {
 			return fmt.Errorf("failed to load existing config: %w", err)
 		}
 	}
 
-	// Add the new rule
+	// Add the new rule with a stable id for later remove/edit
+	if rule.ID == "" {
+		rule.ID = cfg.NewRuleID(&rule)
+	}
 	cfg.AddRule(rule)
 
 	// Save the updated config
-	if err := cfg.Save(configPath); err != nil {
-		return fmt.Errorf("failed to save config: %w", err)
+	if err := saveConfig(cfg, configPath); err != nil {
+		return err
 	}
 
 	return nil
 }
 
-// createRulesRemoveCommand creates the rule remove subcommand
-func createRulesRemoveCommand() *cobra.Command {
+// createRulesLintCommand creates the subcommand that reports duplicate and shadowed rules
+func createRulesLintCommand() *cobra.Command {
 	return &cobra.Command{
-		Use:   "remove",
-		Short: "Remove rule by index",
-		Args:  cobra.ExactArgs(1),
-		RunE: func(cmd *cobra.Command, args []string) error {
-			configPath, err := cmd.Flags().GetString("config")
+		Use:   "lint",
+		Short: "Report duplicate and shadowed rules",
+		Long: "Report rules that repeat an earlier rule's pattern, or that an earlier, broader " +
+			"rule will match first. The same warnings are included in 'bumpers validate'.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			configPath, err := configPathFromCommand(cmd)
+			if err != nil {
+				return err
+			}
+
+			data, err := config.ReadData(configPath)
 			if err != nil {
-				return fmt.Errorf("failed to get config flag: %w", err)
+				return fmt.Errorf("failed to read config: %w", err)
+			}
+			partialCfg, err := config.LoadPartial(data)
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
 			}
 
-			// Parse index argument (user provides 1-indexed)
-			userIndex, err := strconv.Atoi(args[0])
+			if len(partialCfg.RuleWarnings) == 0 {
+				_, _ = fmt.Fprintln(cmd.OutOrStdout(), "No duplicate or shadowed rules found")
+				return nil
+			}
+			for _, warning := range partialCfg.RuleWarnings {
+				_, _ = fmt.Fprintln(cmd.OutOrStdout(), warning)
+			}
+			return nil
+		},
+	}
+}
+
+// createRulesStatsCommand creates the subcommand that shows pattern complexity and match cost
+func createRulesStatsCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "stats",
+		Short: "Show pattern complexity and estimated match cost",
+		Long: "Show each rule's pattern with a complexity score based on quantifier nesting and " +
+			"alternations, whether it is anchored, and its average match time against typical inputs. " +
+			"Patterns scoring " + strconv.Itoa(patterns.HighComplexity) + " or more are flagged.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			configPath, err := configPathFromCommand(cmd)
 			if err != nil {
-				return fmt.Errorf("invalid index '%s': must be a number", args[0])
+				return err
 			}
 
-			// Validate user provided a positive index
-			if userIndex < 1 {
-				return fmt.Errorf("invalid index %d: must be 1 or greater", userIndex)
+			output, err := rulesStatsFromConfigPath(configPath)
+			if err != nil {
+				return err
 			}
+			_, _ = fmt.Fprint(cmd.OutOrStdout(), output)
+			return nil
+		},
+	}
+}
+
+// rulesStatsFromConfigPath formats pattern statistics for every rule in the config
+func rulesStatsFromConfigPath(configPath string) (string, error) {
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to load config: %w", err)
+	}
+	if len(cfg.Rules) == 0 {
+		return "No rules found in config\n", nil
+	}
 
-			// Convert to 0-indexed for internal use
-			internalIndex := userIndex - 1
+	// Expand template variables so patterns are timed as they are matched
+	var templateContext map[string]any
+	if projectRoot, rootErr := project.FindRoot(); rootErr == nil {
+		templateContext = map[string]any{"ProjectRoot": projectRoot}
+	}
+
+	var output strings.Builder
+	indexWidth := len(strconv.Itoa(len(cfg.Rules)))
+	indent := strings.Repeat(" ", indexWidth+3)
+
+	for i := range cfg.Rules {
+		pattern := cfg.Rules[i].GetMatch().Pattern
+		_, _ = fmt.Fprintf(&output, "[%0*d] Pattern: %s\n", indexWidth, i+1, pattern)
+
+		stats, err := patterns.Analyze(matcher.ExpandPattern(pattern, templateContext))
+		if err != nil {
+			_, _ = fmt.Fprintf(&output, "%sError: %v\n\n", indent, err)
+			continue
+		}
+
+		anchored := "no"
+		if stats.Anchored {
+			anchored = "yes"
+		}
+		_, _ = fmt.Fprintf(&output, "%sComplexity: %d, Anchored: %s, Match time: %s\n",
+			indent, stats.Complexity, anchored, stats.MatchTime)
+		if stats.Flagged() {
+			_, _ = fmt.Fprintf(&output, "%sWarning: high complexity, consider simplifying nested "+
+				"quantifiers or alternations\n", indent)
+		}
+		_, _ = fmt.Fprintln(&output)
+	}
+
+	return output.String(), nil
+}
+
+// createRulesStaleCommand creates the subcommand listing rules that haven't matched lately
+func createRulesStaleCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "stale",
+		Short: "List rules that haven't matched within a window",
+		Long: "List rules that haven't matched within --older-than, including rules that never " +
+			"matched, to help prune dead rules. Windows take Go durations or a number of days, " +
+			"like 30d. Rules without an id are tracked by their pattern.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			olderThanFlag, err := cmd.Flags().GetString("older-than")
+			if err != nil {
+				return fmt.Errorf("failed to get older-than flag: %w", err)
+			}
+			olderThan, err := parseAge(olderThanFlag)
+			if err != nil {
+				return err
+			}
+
+			cliApp, err := createAppFromCommand(cmd.Context(), cmd)
+			if err != nil {
+				return err
+			}
+			stale, err := cliApp.StaleRules(cmd.Context(), olderThan, time.Now())
+			if err != nil {
+				return fmt.Errorf("failed to find stale rules: %w", err)
+			}
+
+			out := cmd.OutOrStdout()
+			if len(stale) == 0 {
+				_, _ = fmt.Fprintf(out, "No rules older than %s\n", olderThanFlag)
+				return nil
+			}
+			for _, rule := range stale {
+				lastFired := "never matched"
+				if !rule.LastFired.IsZero() {
+					lastFired = "last matched " + rule.LastFired.Local().Format(time.DateTime)
+				}
+				if rule.ID == "" {
+					_, _ = fmt.Fprintf(out, "[%d] %s: %s\n", rule.Index, rule.Pattern, lastFired)
+					continue
+				}
+				_, _ = fmt.Fprintf(out, "[%d] %s (%s): %s\n", rule.Index, rule.ID, rule.Pattern, lastFired)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().String("older-than", "30d", "List rules that haven't matched within this window")
+	return cmd
+}
+
+// parseAge parses a Go duration, or a whole number of days like 30d
+func parseAge(value string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		count, err := strconv.Atoi(days)
+		if err != nil || count < 0 {
+			return 0, fmt.Errorf("invalid window '%s': days must be a whole number", value)
+		}
+		return time.Duration(count) * 24 * time.Hour, nil
+	}
+	age, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid window '%s': %w", value, err)
+	}
+	if age < 0 {
+		return 0, fmt.Errorf("invalid window '%s': must not be negative", value)
+	}
+	return age, nil
+}
+
+// createRulesMinimizeCommand creates the subcommand that combines rules differing only in pattern
+func createRulesMinimizeCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "minimize",
+		Short: "Combine rules with the same message and tool into one pattern",
+		Long: "Find rules that differ only in their pattern and propose combining each set into " +
+			"one rule whose pattern is an alternation of theirs. The changes are applied after " +
+			"confirmation, or only shown with --preview.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			configPath, err := writableConfigPathFromCommand(cmd)
+			if err != nil {
+				return err
+			}
+			preview, err := cmd.Flags().GetBool("preview")
+			if err != nil {
+				return fmt.Errorf("failed to get preview flag: %w", err)
+			}
+
+			var prompter prompt.Prompter
+			if !preview {
+				prompter = prompt.NewLinerPrompter()
+				defer func() { _ = prompter.Close() }()
+			}
+			return runRulesMinimize(cmd, configPath, prompter)
+		},
+	}
+	cmd.Flags().Bool("preview", false, "Show the proposed changes without applying them")
+	return cmd
+}
+
+// runRulesMinimize shows the rules that can be combined and, unless prompter is nil for a
+// preview, saves the combined rules once the user confirms
+func runRulesMinimize(cmd *cobra.Command, configPath string, prompter prompt.Prompter) error {
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	out := cmd.OutOrStdout()
+	merges := cfg.MinimizeRules()
+	if len(merges) == 0 {
+		_, _ = fmt.Fprintln(out, "No rules can be combined")
+		return nil
+	}
+
+	merged := 0
+	for _, merge := range merges {
+		first := &cfg.Rules[merge.Indices[0]]
+		refs := make([]string, len(merge.Indices))
+		for i, index := range merge.Indices {
+			refs[i] = strconv.Itoa(index + 1)
+		}
+		_, _ = fmt.Fprintf(out, "Rules %s (send: %q):\n", strings.Join(refs, ", "), first.Send)
+		for _, index := range merge.Indices {
+			_, _ = fmt.Fprintf(out, "  - %s\n", cfg.Rules[index].GetMatch().Pattern)
+		}
+		_, _ = fmt.Fprintf(out, "  + %s\n\n", merge.Pattern)
+		merged += len(merge.Indices)
+	}
+	summary := fmt.Sprintf("%d rules into %d", merged, len(merges))
+
+	if prompter == nil {
+		_, _ = fmt.Fprintf(out, "Would combine %s (preview, config not changed)\n", summary)
+		return nil
+	}
+
+	answer, err := prompt.TextInputWithPrompter(prompter, "Apply these changes? [y/N]")
+	if err != nil {
+		return fmt.Errorf("cancelled by user: %w", err)
+	}
+	if answer = strings.ToLower(strings.TrimSpace(answer)); answer != "y" && answer != "yes" {
+		_, _ = fmt.Fprintln(out, "No changes made")
+		return nil
+	}
+
+	cfg.ApplyMerges(merges)
+	if err := saveConfig(cfg, configPath); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(out, "[✓] Combined %s\n", summary)
+	return nil
+}
+
+// createRulesSortCommand creates the subcommand that reorders rules by a key
+func createRulesSortCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sort",
+		Short: "Reorder rules by pattern, message or priority",
+		Long: "Reorder rules alphabetically by pattern (the default) or message, or by priority, " +
+			"highest first. Disabled rules are moved to the bottom and rules with equal keys keep " +
+			"their order. The new order is shown and saved after confirmation, or saved straight " +
+			"away with --no-confirm. Rules match in config order, so sorting can change which rule " +
+			"answers an input.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			configPath, err := writableConfigPathFromCommand(cmd)
+			if err != nil {
+				return err
+			}
+			by, err := cmd.Flags().GetString("by")
+			if err != nil {
+				return fmt.Errorf("failed to get by flag: %w", err)
+			}
+			noConfirm, err := cmd.Flags().GetBool("no-confirm")
+			if err != nil {
+				return fmt.Errorf("failed to get no-confirm flag: %w", err)
+			}
+
+			var prompter prompt.Prompter
+			if !noConfirm {
+				prompter = prompt.NewLinerPrompter()
+				defer func() { _ = prompter.Close() }()
+			}
+			return runRulesSort(cmd, configPath, by, prompter)
+		},
+	}
+	cmd.Flags().String("by", config.SortByPattern, "Sort key: pattern, message or priority")
+	cmd.Flags().Bool("no-confirm", false, "Save the new order without asking")
+	return cmd
+}
+
+// runRulesSort shows the rules in their sorted order and saves it once the user confirms,
+// or straight away when prompter is nil
+func runRulesSort(cmd *cobra.Command, configPath, by string, prompter prompt.Prompter) error {
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	order, err := cfg.SortRules(by)
+	if err != nil {
+		return fmt.Errorf("failed to sort rules: %w", err)
+	}
+
+	out := cmd.OutOrStdout()
+	moved := 0
+	for i, previous := range order {
+		if previous != i {
+			moved++
+		}
+	}
+	if moved == 0 {
+		_, _ = fmt.Fprintf(out, "Rules are already sorted by %s\n", by)
+		return nil
+	}
+
+	_, _ = fmt.Fprintf(out, "New order by %s:\n", by)
+	for i, previous := range order {
+		rule := &cfg.Rules[i]
+		line := fmt.Sprintf("  %d. %s (was %d", i+1, rule.GetMatch().Pattern, previous+1)
+		if !rule.IsEnabled() {
+			line += ", disabled"
+		}
+		_, _ = fmt.Fprintln(out, line+")")
+	}
+
+	if prompter != nil {
+		answer, promptErr := prompt.TextInputWithPrompter(prompter, "Apply this order? [y/N]")
+		if promptErr != nil {
+			return fmt.Errorf("cancelled by user: %w", promptErr)
+		}
+		if answer = strings.ToLower(strings.TrimSpace(answer)); answer != "y" && answer != "yes" {
+			_, _ = fmt.Fprintln(out, "No changes made")
+			return nil
+		}
+	}
+
+	if err := saveConfig(cfg, configPath); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(out, "[✓] Sorted %d rules by %s, %d moved\n", len(cfg.Rules), by, moved)
+	return nil
+}
+
+// createRulesRemoveCommand creates the rule remove subcommand
+func createRulesRemoveCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:               "remove",
+		Short:             "Remove rule by index or id",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeRuleIndices,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			configPath, err := writableConfigPathFromCommand(cmd)
+			if err != nil {
+				return err
+			}
+
+			if err := checkRuleRef(args[0]); err != nil {
+				return err
+			}
 
 			// Check if config file exists
 			if _, statErr := os.Stat(configPath); os.IsNotExist(statErr) {
@@ -424,25 +1053,57 @@ func createRulesRemoveCommand() *cobra.Command {
 				return fmt.Errorf("failed to load config: %w", err)
 			}
 
-			// Delete the rule using 0-indexed
-			if err := cfg.DeleteRule(internalIndex); err != nil {
+			index, err := cfg.FindRule(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to delete rule: %w", err)
+			}
+			if err := cfg.DeleteRule(index); err != nil {
 				return fmt.Errorf("failed to delete rule: %w", err)
 			}
 
 			// Save updated config
-			if err := cfg.Save(configPath); err != nil {
-				return fmt.Errorf("failed to save config: %w", err)
+			if err := saveConfig(cfg, configPath); err != nil {
+				return err
 			}
 
-			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "[✓] Rule %d deleted successfully\n", userIndex)
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "[✓] Rule %s deleted successfully\n", args[0])
 			return nil
 		},
 	}
 }
 
-// runInteractiveRuleEditWithPrompter handles interactive rule editing with a custom prompter
-func runInteractiveRuleEditWithPrompter(prompter prompt.Prompter, index int) error {
-	return runInteractiveRuleEditWithPrompterAndConfigPath(prompter, index, "bumpers.yml")
+// createRulesSetEnabledCommand creates the enable-all or disable-all subcommand, which sets
+// every rule's enabled flag and saves the config
+func createRulesSetEnabledCommand(enabled bool) *cobra.Command {
+	use, action := "disable-all", "disabled"
+	if enabled {
+		use, action = "enable-all", "enabled"
+	}
+
+	return &cobra.Command{
+		Use:   use,
+		Short: fmt.Sprintf("Mark every rule %s in the config", action),
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			configPath, err := writableConfigPathFromCommand(cmd)
+			if err != nil {
+				return err
+			}
+
+			cfg, err := config.Load(configPath)
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+
+			changed := cfg.SetAllRulesEnabled(enabled)
+			if err := saveConfig(cfg, configPath); err != nil {
+				return err
+			}
+
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "[✓] %d of %d rules %s\n", changed, len(cfg.Rules), action)
+			return nil
+		},
+	}
 }
 
 // runInteractiveRuleEditWithPrompterAndConfigPath handles interactive rule editing
@@ -506,8 +1167,8 @@ func runInteractiveRuleEditWithPrompterAndConfigPath(prompter prompt.Prompter, i
 		return fmt.Errorf("failed to update rule: %w", err)
 	}
 
-	if err := cfg.Save(configPath); err != nil {
-		return fmt.Errorf("failed to save config: %w", err)
+	if err := saveConfig(cfg, configPath); err != nil {
+		return err
 	}
 
 	_, _ = fmt.Println("[✓] Rule updated in bumpers.yml")
@@ -517,28 +1178,41 @@ func runInteractiveRuleEditWithPrompterAndConfigPath(prompter prompt.Prompter, i
 // createRulesEditCommand creates the rule edit subcommand
 func createRulesEditCommand() *cobra.Command {
 	return &cobra.Command{
-		Use:   "edit",
-		Short: "Edit rule by index",
-		Args:  cobra.ExactArgs(1),
-		RunE: func(_ *cobra.Command, args []string) error {
-			// Parse index argument (user provides 1-indexed)
-			userIndex, err := strconv.Atoi(args[0])
-			if err != nil {
-				return fmt.Errorf("invalid index '%s': must be a number", args[0])
+		Use:               "edit",
+		Short:             "Edit rule by index or id",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeRuleIndices,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkRuleRef(args[0]); err != nil {
+				return err
 			}
 
-			// Validate user provided a positive index
-			if userIndex < 1 {
-				return fmt.Errorf("invalid index %d: must be 1 or greater", userIndex)
+			configPath, err := writableConfigPathFromCommand(cmd)
+			if err != nil {
+				return err
 			}
 
-			// Convert to 0-indexed for internal use
-			internalIndex := userIndex - 1
+			cfg, err := config.Load(configPath)
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+			index, err := cfg.FindRule(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to edit rule: %w", err)
+			}
 
 			p := prompt.NewLinerPrompter()
 			defer func() { _ = p.Close() }()
 
-			return runInteractiveRuleEditWithPrompter(p, internalIndex)
+			return runInteractiveRuleEditWithPrompterAndConfigPath(p, index, configPath)
 		},
 	}
 }
+
+// checkRuleRef rejects numeric rule references below 1 before the config is loaded
+func checkRuleRef(ref string) error {
+	if index, err := strconv.Atoi(ref); err == nil && index < 1 {
+		return fmt.Errorf("invalid index %d: must be 1 or greater", index)
+	}
+	return nil
+}
@@ -9,9 +9,13 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/fatih/color"
 	"github.com/stretchr/testify/require"
 	"github.com/wizzomafizzo/bumpers/internal/config"
+	"github.com/wizzomafizzo/bumpers/internal/project"
+	"github.com/wizzomafizzo/bumpers/internal/prompt"
 )
 
 func TestCreateRuleCommand(t *testing.T) {
@@ -1002,6 +1006,63 @@ func TestRuleAddNonInteractive(t *testing.T) {
 	}
 }
 
+// TestRuleAddNonInteractiveKeepsConfig tests that adding a rule keeps the existing rules,
+// settings and comments
+func TestRuleAddNonInteractiveKeepsConfig(t *testing.T) {
+	t.Parallel()
+
+	configPath := filepath.Join(t.TempDir(), "bumpers.yml")
+	original := `# Project guard rails
+settings:
+  locale: en
+
+rules:
+  # Keep deletions safe
+  - match: "^rm -rf"
+    send: "Use trash instead"
+    generate: "off"
+
+  # Tests go through just
+  - match: "^go test"
+    send: "Use just test"
+    generate: "off"
+`
+	if err := os.WriteFile(configPath, []byte(original), 0o600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	err := runNonInteractiveRuleAddWithConfigPath("^make deploy", "Deploy from CI", "^Bash$", "off", configPath)
+	if err != nil {
+		t.Fatalf("Expected non-interactive add to succeed, got: %v", err)
+	}
+
+	data, err := os.ReadFile(configPath) // #nosec G304 -- test temp file
+	if err != nil {
+		t.Fatalf("failed to read config: %v", err)
+	}
+	content := string(data)
+	if !strings.HasPrefix(content, original) {
+		t.Errorf("Expected the original config to be kept unchanged, got:\n%s", content)
+	}
+	if strings.Count(content, "# Keep deletions safe") != 1 || strings.Count(content, "# Tests go through just") != 1 {
+		t.Errorf("Expected each rule comment exactly once, got:\n%s", content)
+	}
+
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		t.Fatalf("Expected to load config file, got: %v", err)
+	}
+	if len(cfg.Rules) != 3 {
+		t.Fatalf("Expected 3 rules, got %d", len(cfg.Rules))
+	}
+	if cfg.Rules[2].GetMatch().Pattern != "^make deploy" {
+		t.Errorf("Expected the new rule last, got pattern '%s'", cfg.Rules[2].GetMatch().Pattern)
+	}
+	if cfg.Settings.Locale != "en" {
+		t.Errorf("Expected settings to be kept, got locale '%s'", cfg.Settings.Locale)
+	}
+}
+
 func TestRulesCommandRespectsConfigFlag(t *testing.T) {
 	t.Parallel()
 	tempDir := t.TempDir()
@@ -1064,6 +1125,37 @@ func TestRulesRemoveCommandRespectsConfigFlag(t *testing.T) {
 	require.Equal(t, "rule2", cfg.Rules[0].GetMatch().Pattern, "Should have rule2 remaining")
 }
 
+func TestRulesRemoveCommandKeepsComments(t *testing.T) {
+	t.Parallel()
+	configPath := filepath.Join(t.TempDir(), "bumpers.yml")
+
+	err := os.WriteFile(configPath, []byte(`# Project rules
+rules:
+  # Tests go through just
+  - match: "^go test"
+    send: "Use just test"
+
+  # Temporary, remove after the migration
+  - match: "^make"
+    send: "Use just"
+`), 0o600)
+	require.NoError(t, err)
+
+	rootCmd := createNewRootCommand()
+	rootCmd.SetOut(&bytes.Buffer{})
+	rootCmd.SetArgs([]string{"rules", "remove", "2", "--config", configPath})
+	require.NoError(t, rootCmd.Execute())
+
+	data, err := os.ReadFile(configPath) // #nosec G304 -- configPath is a test file
+	require.NoError(t, err)
+	require.Equal(t, `# Project rules
+rules:
+  # Tests go through just
+  - match: "^go test"
+    send: "Use just test"
+`, string(data))
+}
+
 func TestRulesAddCommandRespectsConfigFlag(t *testing.T) {
 	t.Parallel()
 	tempDir := t.TempDir()
@@ -1093,3 +1185,554 @@ func TestRulesAddCommandRespectsConfigFlag(t *testing.T) {
 	require.Equal(t, "test-pattern", cfg.Rules[0].GetMatch().Pattern, "Should have added test-pattern rule")
 	require.Equal(t, "test message", cfg.Rules[0].Send, "Should have correct message")
 }
+
+// TestRuleListShowsCommandAliases tests that listing includes commands and their aliases
+func TestRuleListShowsCommandAliases(t *testing.T) {
+	t.Parallel()
+
+	configPath := filepath.Join(t.TempDir(), "bumpers.yml")
+	cfg := &config.Config{
+		Commands: []config.Command{
+			{Name: "help", Send: "Help text", Aliases: []string{"h", "?"}},
+		},
+	}
+	if err := cfg.Save(configPath); err != nil {
+		t.Fatalf("Failed to save test config: %v", err)
+	}
+
+	output, err := listRulesFromConfigPath(configPath)
+	if err != nil {
+		t.Fatalf("Expected list to succeed, got: %v", err)
+	}
+
+	if !strings.Contains(output, "$help (aliases: $h, $?)") {
+		t.Errorf("Expected output to show command aliases, got: %s", output)
+	}
+}
+
+// TestRunInteractiveCommandAddWithAliases tests the interactive command flow saves aliases
+func TestRunInteractiveCommandAddWithAliases(t *testing.T) {
+	t.Parallel()
+
+	configPath := filepath.Join(t.TempDir(), "bumpers.yml")
+	mockPrompter := &MockPrompter{
+		answers: []string{
+			"help",      // Step 1: Command name
+			"h, $?",     // Step 2: Aliases
+			"Help text", // Step 3: Message
+		},
+	}
+
+	if err := runInteractiveCommandAddWithPrompterAndConfigPath(mockPrompter, configPath); err != nil {
+		t.Fatalf("Expected command add to succeed, got: %v", err)
+	}
+
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		t.Fatalf("Failed to load saved config: %v", err)
+	}
+	if len(cfg.Commands) != 1 {
+		t.Fatalf("Expected 1 command, got %d", len(cfg.Commands))
+	}
+	if got := strings.Join(cfg.Commands[0].Aliases, ","); got != "h,?" {
+		t.Errorf("Expected aliases h,?, got %q", got)
+	}
+}
+
+// TestRunInteractiveCommandAddRejectsDuplicateAlias tests alias uniqueness in the interactive flow
+func TestRunInteractiveCommandAddRejectsDuplicateAlias(t *testing.T) {
+	t.Parallel()
+
+	configPath := filepath.Join(t.TempDir(), "bumpers.yml")
+	cfg := &config.Config{Commands: []config.Command{{Name: "help", Send: "Help text"}}}
+	if err := cfg.Save(configPath); err != nil {
+		t.Fatalf("Failed to save test config: %v", err)
+	}
+
+	mockPrompter := &MockPrompter{answers: []string{"hint", "help", "Hint text"}}
+
+	err := runInteractiveCommandAddWithPrompterAndConfigPath(mockPrompter, configPath)
+	if err == nil || !strings.Contains(err.Error(), "conflicts") {
+		t.Errorf("Expected alias conflict error, got: %v", err)
+	}
+}
+
+func TestRulesLintCommand(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		config string
+		want   string
+	}{
+		{
+			name: "shadowed rule",
+			config: `rules:
+  - match: "^go "
+    send: "Use just"
+  - match: "^go test"
+    send: "Use just test"
+`,
+			want: "rule 2 (pattern '^go test') is shadowed by earlier rule 1 (pattern '^go '): move rule 2 above rule 1\n",
+		},
+		{
+			name: "no problems",
+			config: `rules:
+  - match: "^go test"
+    send: "Use just test"
+`,
+			want: "No duplicate or shadowed rules found\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			configPath := filepath.Join(t.TempDir(), "bumpers.yml")
+			if err := os.WriteFile(configPath, []byte(tt.config), 0o600); err != nil {
+				t.Fatalf("Failed to write config: %v", err)
+			}
+
+			rootCmd := createNewRootCommand()
+			var out bytes.Buffer
+			rootCmd.SetOut(&out)
+			rootCmd.SetArgs([]string{"--config", configPath, "rules", "lint"})
+			if err := rootCmd.Execute(); err != nil {
+				t.Fatalf("Expected lint to succeed, got: %v", err)
+			}
+
+			if out.String() != tt.want {
+				t.Errorf("Expected output %q, got %q", tt.want, out.String())
+			}
+		})
+	}
+}
+
+func TestRulesStatsFromConfigPath(t *testing.T) {
+	t.Parallel()
+
+	configPath := filepath.Join(t.TempDir(), "bumpers.yml")
+	err := os.WriteFile(configPath, []byte(`rules:
+  - match: "^go test"
+    send: "Use just test"
+  - match: "(a+)+b"
+    send: "Nested"
+`), 0o600)
+	require.NoError(t, err)
+
+	output, err := rulesStatsFromConfigPath(configPath)
+	require.NoError(t, err)
+
+	if !strings.Contains(output, "[1] Pattern: ^go test\n    Complexity: 0, Anchored: yes, Match time: ") {
+		t.Errorf("Expected stats for anchored rule, got:\n%s", output)
+	}
+	if !strings.Contains(output, "[2] Pattern: (a+)+b\n    Complexity: 10, Anchored: no, Match time: ") {
+		t.Errorf("Expected stats for nested rule, got:\n%s", output)
+	}
+	if strings.Count(output, "Warning: high complexity") != 1 {
+		t.Errorf("Expected only the nested rule to be flagged, got:\n%s", output)
+	}
+}
+
+func TestParseAge(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{value: "30d", want: 30 * 24 * time.Hour},
+		{value: "0d", want: 0},
+		{value: "36h", want: 36 * time.Hour},
+		{value: "1.5d", wantErr: true},
+		{value: "-1h", wantErr: true},
+		{value: "soon", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseAge(tt.value)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("parseAge(%q) expected an error", tt.value)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("parseAge(%q) = %v, %v; want %v", tt.value, got, err, tt.want)
+		}
+	}
+}
+
+func TestRulesAddDryRunPrintsYAMLWithoutSaving(t *testing.T) {
+	t.Parallel()
+	configPath := filepath.Join(t.TempDir(), "bumpers.yml")
+
+	rootCmd := createNewRootCommand()
+	var output bytes.Buffer
+	rootCmd.SetOut(&output)
+	rootCmd.SetArgs([]string{
+		"rules", "add", "--dry-run", "--pattern", "^go test",
+		"--message", "Use just test", "--generate", "once", "--config", configPath,
+	})
+
+	require.NoError(t, rootCmd.Execute())
+
+	expected := "- id: 08556a\n" +
+		"  generate: once\n" +
+		"  match: ^go test\n" +
+		"  tool: ^Bash$\n" +
+		"  send: Use just test\n"
+	require.Equal(t, expected, output.String())
+
+	_, err := os.Stat(configPath)
+	require.True(t, os.IsNotExist(err), "dry run should not write the config file")
+}
+
+func TestRulesAddAssignsIDUsableByRemove(t *testing.T) {
+	t.Parallel()
+
+	configPath := filepath.Join(t.TempDir(), "bumpers.yml")
+	cfg := &config.Config{
+		Rules: []config.Rule{
+			{ID: "no-go-test", Match: "go test.*", Send: "Use just test"},
+			{Match: "rm -rf.*", Send: "Use git clean -fd"},
+		},
+	}
+	require.NoError(t, cfg.Save(configPath))
+
+	require.NoError(t, saveRuleToConfigPath(config.Rule{Match: "^npm", Send: "Use pnpm"}, configPath))
+	cfg, err := config.Load(configPath)
+	require.NoError(t, err)
+	require.Len(t, cfg.Rules, 3)
+	addedID := cfg.Rules[2].ID
+	require.NotEmpty(t, addedID)
+
+	output, err := listRulesFromConfigPath(configPath)
+	require.NoError(t, err)
+	require.Contains(t, output, "ID: no-go-test")
+	require.Contains(t, output, "ID: "+addedID)
+
+	rootCmd := createNewRootCommand()
+	var out bytes.Buffer
+	rootCmd.SetOut(&out)
+	rootCmd.SetArgs([]string{"rules", "remove", "no-go-test", "--config", configPath})
+	require.NoError(t, rootCmd.Execute())
+	require.Contains(t, out.String(), "Rule no-go-test deleted successfully")
+
+	cfg, err = config.Load(configPath)
+	require.NoError(t, err)
+	require.Len(t, cfg.Rules, 2)
+	require.Equal(t, "rm -rf.*", cfg.Rules[0].GetMatch().Pattern)
+	require.Equal(t, addedID, cfg.Rules[1].ID, "ids survive other rules being removed")
+
+	rootCmd = createNewRootCommand()
+	rootCmd.SetOut(&out)
+	rootCmd.SetArgs([]string{"rules", "remove", "no-go-test", "--config", configPath})
+	err = rootCmd.Execute()
+	require.Error(t, err)
+	require.ErrorIs(t, err, config.ErrRuleNotFound)
+}
+
+const minimizeConfig = `rules:
+  - match: "^npm install"
+    send: "Use pnpm"
+  - match: "^npm ci"
+    send: "Use pnpm"
+  - match: "^go test"
+    send: "Use just test"
+`
+
+func TestRulesMinimizePreview(t *testing.T) {
+	t.Parallel()
+
+	configPath := filepath.Join(t.TempDir(), "bumpers.yml")
+	require.NoError(t, os.WriteFile(configPath, []byte(minimizeConfig), 0o600))
+
+	rootCmd := createNewRootCommand()
+	var out bytes.Buffer
+	rootCmd.SetOut(&out)
+	rootCmd.SetArgs([]string{"--config", configPath, "rules", "minimize", "--preview"})
+	require.NoError(t, rootCmd.Execute())
+
+	want := "Rules 1, 2 (send: \"Use pnpm\"):\n  - ^npm install\n  - ^npm ci\n  + ^npm (?:install|ci)\n\n" +
+		"Would combine 2 rules into 1 (preview, config not changed)\n"
+	if out.String() != want {
+		t.Errorf("Expected output %q, got %q", want, out.String())
+	}
+
+	data, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	if string(data) != minimizeConfig {
+		t.Error("Expected --preview to leave the config unchanged")
+	}
+}
+
+func TestRunRulesMinimizeConfirm(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		answer    string
+		wantRules int
+	}{
+		{answer: "y", wantRules: 2},
+		{answer: "", wantRules: 3},
+	}
+
+	for _, tt := range tests {
+		configPath := filepath.Join(t.TempDir(), "bumpers.yml")
+		require.NoError(t, os.WriteFile(configPath, []byte(minimizeConfig), 0o600))
+
+		cmd := createRulesMinimizeCommand()
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		require.NoError(t, runRulesMinimize(cmd, configPath, &MockPrompter{answers: []string{tt.answer}}))
+
+		cfg, err := config.Load(configPath)
+		require.NoError(t, err)
+		if len(cfg.Rules) != tt.wantRules {
+			t.Errorf("Answer %q: expected %d rules, got %d", tt.answer, tt.wantRules, len(cfg.Rules))
+		}
+	}
+}
+
+func TestRunRulesSort(t *testing.T) {
+	t.Parallel()
+
+	const sortConfig = `rules:
+  - match: "^npm"
+    send: "Use pnpm"
+  - match: "^curl"
+    send: "Avoid network calls"
+    enabled: false
+  - match: "^go test"
+    send: "Use just test"
+`
+	patterns := func(configPath string) []string {
+		cfg, err := config.Load(configPath)
+		require.NoError(t, err)
+		var got []string
+		for i := range cfg.Rules {
+			got = append(got, cfg.Rules[i].GetMatch().Pattern)
+		}
+		return got
+	}
+
+	tests := []struct {
+		name     string
+		prompter *MockPrompter
+		want     []string
+	}{
+		{name: "confirmed", prompter: &MockPrompter{answers: []string{"y"}}, want: []string{"^go test", "^npm", "^curl"}},
+		{name: "declined", prompter: &MockPrompter{answers: []string{""}}, want: []string{"^npm", "^curl", "^go test"}},
+		{name: "no confirm", want: []string{"^go test", "^npm", "^curl"}},
+	}
+
+	for _, tt := range tests {
+		configPath := filepath.Join(t.TempDir(), "bumpers.yml")
+		require.NoError(t, os.WriteFile(configPath, []byte(sortConfig), 0o600))
+
+		cmd := createRulesSortCommand()
+		var out bytes.Buffer
+		cmd.SetOut(&out)
+		var prompter prompt.Prompter
+		if tt.prompter != nil {
+			prompter = tt.prompter
+		}
+		require.NoError(t, runRulesSort(cmd, configPath, config.SortByPattern, prompter))
+
+		require.Contains(t, out.String(), "New order by pattern:\n  1. ^go test (was 3)\n  2. ^npm (was 1)\n"+
+			"  3. ^curl (was 2, disabled)\n", tt.name)
+		require.Equal(t, tt.want, patterns(configPath), tt.name)
+	}
+
+	configPath := filepath.Join(t.TempDir(), "bumpers.yml")
+	require.NoError(t, os.WriteFile(configPath, []byte(sortConfig), 0o600))
+	rootCmd := createNewRootCommand()
+	rootCmd.SetOut(&bytes.Buffer{})
+	rootCmd.SetArgs([]string{"--config", configPath, "rules", "sort", "--by", "length", "--no-confirm"})
+	require.ErrorContains(t, rootCmd.Execute(), "invalid sort key 'length'")
+}
+
+func TestRulesDisableAllAndEnableAll(t *testing.T) {
+	t.Parallel()
+
+	configPath := filepath.Join(t.TempDir(), "bumpers.yml")
+	require.NoError(t, os.WriteFile(configPath, []byte(`rules:
+  - match: "^go test"
+    send: "Use just test"
+  - match: "^npm"
+    send: "Use pnpm"
+    enabled: false
+  - match: "^rm -rf"
+    send: "Use git clean"
+`), 0o600))
+
+	run := func(name string) string {
+		rootCmd := createNewRootCommand()
+		var out bytes.Buffer
+		rootCmd.SetOut(&out)
+		rootCmd.SetArgs([]string{"--config", configPath, "rules", name})
+		require.NoError(t, rootCmd.Execute())
+		return out.String()
+	}
+
+	require.Equal(t, "[✓] 2 of 3 rules disabled\n", run("disable-all"))
+	cfg, err := config.Load(configPath)
+	require.NoError(t, err)
+	for i := range cfg.Rules {
+		require.False(t, cfg.Rules[i].IsEnabled(), "rule %d", i+1)
+	}
+	partial, err := config.LoadPartial(mustReadFile(t, configPath))
+	require.NoError(t, err)
+	require.Empty(t, partial.Rules, "disabled rules aren't matched")
+
+	require.Equal(t, "[✓] 3 of 3 rules enabled\n", run("enable-all"))
+	data := mustReadFile(t, configPath)
+	require.NotContains(t, string(data), "enabled", "enabled is the default and isn't written")
+	partial, err = config.LoadPartial(data)
+	require.NoError(t, err)
+	require.Len(t, partial.Rules, 3)
+}
+
+func TestRulesTestConfiguredRule(t *testing.T) {
+	t.Parallel()
+
+	projectRoot, err := project.FindRoot()
+	require.NoError(t, err)
+
+	configPath := filepath.Join(t.TempDir(), "bumpers.yml")
+	require.NoError(t, os.WriteFile(configPath, []byte(`rules:
+  - id: project-secrets
+    match: "^cat {{.ProjectRoot}}/secrets"
+    send: "Don't read project secrets"
+  - match:
+      pattern: "\\.env$"
+      sources: [file_path]
+    tool: "^Read$"
+    send: "Don't read env files"
+  - match:
+      pattern: "panic"
+      event: post
+    send: "Investigate the panic"
+`), 0o600))
+
+	run := func(args ...string) string {
+		rootCmd := createNewRootCommand()
+		var out bytes.Buffer
+		rootCmd.SetOut(&out)
+		rootCmd.SetArgs(append([]string{"--config", configPath, "rules", "test"}, args...))
+		require.NoError(t, rootCmd.Execute())
+		return out.String()
+	}
+
+	pattern := "Pattern: ^cat " + projectRoot + "/secrets\nSource: rule 1 \"project-secrets\" in " + configPath + "\n"
+	require.Equal(t, "[✓] Rule 1 matches\n"+pattern,
+		run("--rule", "project-secrets", "cat "+projectRoot+"/secrets/key"))
+	require.Equal(t, "[✗] Rule 1 does not match: pattern no match\n"+pattern,
+		run("--rule", "1", "cat /elsewhere/secrets/key"),
+		"the template only matches the real project root")
+
+	require.Contains(t, run("--rule", "2", "--tool", "Read", "--source", "file_path", "app/.env"),
+		"[✓] Rule 2 matches")
+	require.Contains(t, run("--rule", "2", "app/.env"),
+		"[✗] Rule 2 does not match: tool filter failed: ^Read$ does not match tool Bash")
+	require.Contains(t, run("--rule", "2", "--tool", "Read", "--source", "content", "app/.env"),
+		"[✗] Rule 2 does not match: source mismatch: rule checks file_path, tested content")
+	require.Contains(t, run("--rule", "3", "panic: nil map"),
+		"[✗] Rule 3 does not match: event mismatch: rule runs on post, tested pre")
+	require.Contains(t, run("--rule", "3", "--event", "post", "panic: nil map"), "[✓] Rule 3 matches")
+
+	require.Equal(t, "[✓] Pattern matches!\n", run("^cat", "cat file"), "raw mode still works")
+
+	rootCmd := createNewRootCommand()
+	rootCmd.SetOut(&bytes.Buffer{})
+	rootCmd.SetArgs([]string{"--config", configPath, "rules", "test", "--rule", "4", "value"})
+	require.ErrorContains(t, rootCmd.Execute(), "invalid index 4")
+}
+
+func TestRulesTestFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "bumpers.yml")
+	require.NoError(t, os.WriteFile(configPath, []byte(`rules:
+  - match: "^go test"
+    send: "Use just test"
+  - match: "\\.env$"
+    tool: "^Read$"
+    send: "Don't read env files"
+`), 0o600))
+
+	run := func(tests string) (string, error) {
+		testsPath := filepath.Join(t.TempDir(), "rules_test.yml")
+		require.NoError(t, os.WriteFile(testsPath, []byte(tests), 0o600))
+		rootCmd := createNewRootCommand()
+		var out bytes.Buffer
+		rootCmd.SetOut(&out)
+		rootCmd.SetErr(&bytes.Buffer{})
+		rootCmd.SetArgs([]string{"--config", configPath, "rules", "test-file", testsPath})
+		err := rootCmd.Execute()
+		return out.String(), err
+	}
+
+	out, err := run(`tests:
+  - name: go test is blocked
+    input: go test ./...
+    expected_rule: "^go test"
+  - name: env files can't be read
+    input: app/.env
+    tool: Read
+  - name: go build is allowed
+    input: go build
+    expected_match: false
+`)
+	require.NoError(t, err)
+	require.Equal(t, "[✓] go test is blocked\n[✓] env files can't be read\n[✓] go build is allowed\n"+
+		"\n3 passed, 0 failed\n", out)
+
+	out, err = run(`tests:
+  - name: wrong rule
+    input: go test
+    expected_rule: "^go build"
+  - name: unexpected match
+    input: go test
+    expected_match: false
+  - name: env files are fine in bash
+    input: cat app/.env
+`)
+	require.EqualError(t, err, "3 of 3 rule tests failed")
+	require.Contains(t, out, `[✗] wrong rule: expected rule "^go build", rule 1 matched (^go test)`)
+	require.Contains(t, out, "[✗] unexpected match: expected no match, rule 1 matched (^go test)")
+	require.Contains(t, out, "[✗] env files are fine in bash: expected a match, no rule matched")
+
+	_, err = run("tests: []\n")
+	require.ErrorContains(t, err, "no tests found")
+	_, err = run("tests:\n  - input: go test\n")
+	require.ErrorContains(t, err, "test 1 has no name")
+}
+
+func mustReadFile(t *testing.T, path string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return data
+}
+
+func TestRulesTestNoColor(t *testing.T) { //nolint:paralleltest // sets the global color.NoColor
+	previous := color.NoColor
+	t.Cleanup(func() { color.NoColor = previous })
+
+	run := func(args ...string) string {
+		color.NoColor = false
+		rootCmd := createNewRootCommand()
+		var out bytes.Buffer
+		rootCmd.SetOut(&out)
+		rootCmd.SetArgs(append([]string{"rules", "test", "^rm", "rm -rf /"}, args...))
+		require.NoError(t, rootCmd.Execute())
+		return out.String()
+	}
+
+	require.Contains(t, run(), "\x1b[32m[✓]\x1b[0m Pattern matches!")
+	require.Equal(t, "[✓] Pattern matches!\n", run("--no-color"))
+}
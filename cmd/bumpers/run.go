@@ -0,0 +1,70 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// runBlockedExitCode matches the exit code used when a hook blocks a tool call
+const runBlockedExitCode = 2
+
+// createRunCommand creates the command that checks a shell command against rules
+// before executing it.
+func createRunCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "run <command> [args...]",
+		Short: "Check a command against rules, then run it",
+		Long: "Check a command against the Bash rules. If a rule matches, print its message " +
+			"and exit with code 2, otherwise run the command and exit with its exit code.",
+		Args: cobra.MinimumNArgs(1),
+		// Blocks and the command's failures are already reported, main prints other errors
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE:          runRunCommand,
+	}
+
+	// Everything after the command name belongs to the command, e.g. "run rm -rf /tmp"
+	cmd.Flags().SetInterspersed(false)
+
+	return cmd
+}
+
+// runRunCommand checks args against the rules and execs them when allowed
+func runRunCommand(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	cliApp, err := createAppFromCommand(ctx, cmd)
+	if err != nil {
+		return err
+	}
+
+	message, blocked, err := cliApp.CheckCommand(ctx, strings.Join(args, " "))
+	if err != nil {
+		return fmt.Errorf("failed to check command: %w", err)
+	}
+	if blocked {
+		_, _ = fmt.Fprintln(cmd.ErrOrStderr(), message)
+		return &HookExitError{Code: runBlockedExitCode, Message: message}
+	}
+
+	//nolint:gosec // running the user's command is the purpose of this subcommand
+	command := exec.CommandContext(ctx, args[0], args[1:]...)
+	command.Stdin = cmd.InOrStdin()
+	command.Stdout = cmd.OutOrStdout()
+	command.Stderr = cmd.ErrOrStderr()
+
+	if err := command.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			// Pass the command's own exit code through without extra output
+			return &HookExitError{Code: exitErr.ExitCode(), Message: exitErr.Error()}
+		}
+		return fmt.Errorf("failed to run %s: %w", args[0], err)
+	}
+
+	return nil
+}
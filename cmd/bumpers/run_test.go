@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	testutil "github.com/wizzomafizzo/bumpers/internal/testing"
+)
+
+const runTestConfig = `rules:
+  - match: "^touch .*blocked"
+    send: "Touching blocked files is not allowed"
+`
+
+func executeRunCommand(t *testing.T, args ...string) (stdout, stderr string, err error) {
+	t.Helper()
+	ctx, _ := testutil.NewTestContext(t)
+
+	configPath := filepath.Join(t.TempDir(), "bumpers.yml")
+	require.NoError(t, os.WriteFile(configPath, []byte(runTestConfig), 0o600))
+
+	rootCmd := createNewRootCommand()
+	var out, errOut bytes.Buffer
+	rootCmd.SetOut(&out)
+	rootCmd.SetErr(&errOut)
+	rootCmd.SetArgs(append([]string{"--config", configPath, "run"}, args...))
+	err = rootCmd.ExecuteContext(ctx)
+	return out.String(), errOut.String(), err
+}
+
+func TestRunCommandBlocked(t *testing.T) {
+	t.Parallel()
+
+	target := filepath.Join(t.TempDir(), "blocked")
+	_, stderr, err := executeRunCommand(t, "touch", target)
+
+	var exitErr *HookExitError
+	require.ErrorAs(t, err, &exitErr)
+	assert.Equal(t, runBlockedExitCode, exitErr.Code)
+	assert.Equal(t, "Touching blocked files is not allowed\n", stderr, "the message is printed once")
+
+	_, statErr := os.Stat(target)
+	assert.True(t, os.IsNotExist(statErr), "blocked command must not run")
+}
+
+func TestRunCommandAllowed(t *testing.T) {
+	t.Parallel()
+
+	target := filepath.Join(t.TempDir(), "allowed")
+	_, _, err := executeRunCommand(t, "touch", target)
+	require.NoError(t, err)
+
+	_, statErr := os.Stat(target)
+	assert.NoError(t, statErr, "allowed command should run")
+}
+
+func TestRunCommandPassesFlagsAndExitCode(t *testing.T) {
+	t.Parallel()
+
+	stdout, stderr, err := executeRunCommand(t, "sh", "-c", "echo hello; echo oops >&2; exit 3")
+
+	var exitErr *HookExitError
+	require.True(t, errors.As(err, &exitErr))
+	assert.Equal(t, 3, exitErr.Code)
+	assert.Equal(t, "hello\n", stdout)
+	assert.Equal(t, "oops\n", stderr, "only the command's own output is printed")
+}
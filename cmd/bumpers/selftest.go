@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// createSelftestCommand creates the selftest command.
+func createSelftestCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "selftest",
+		Short: "Run sample hook payloads and the config's tests",
+		Long: "Run a few sample hook payloads, then each entry of the config's tests section, " +
+			"through the hook pipeline. A test passes when it's processed without error and, " +
+			"when it sets expect_match, a rule fires exactly when expected.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			app, err := createAppFromCommand(cmd.Context(), cmd.Parent())
+			if err != nil {
+				return err
+			}
+
+			results, err := app.SelfTest(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("selftest error: %w", err)
+			}
+
+			out := cmd.OutOrStdout()
+			failed := 0
+			for i := range results {
+				result := &results[i]
+				if result.Passed() {
+					_, _ = fmt.Fprintf(out, "[✓] %s\n", result.Name)
+					continue
+				}
+				failed++
+				switch {
+				case result.Err != nil:
+					_, _ = fmt.Fprintf(out, "[✗] %s: %v\n", result.Name, result.Err)
+				case result.Matched():
+					_, _ = fmt.Fprintf(out, "[✗] %s: expected no match, got %s response\n", result.Name, result.Mode)
+				default:
+					_, _ = fmt.Fprintf(out, "[✗] %s: expected a match, but no rule fired\n", result.Name)
+				}
+			}
+
+			if failed > 0 {
+				return fmt.Errorf("selftest failed: %d of %d tests failed", failed, len(results))
+			}
+			_, _ = fmt.Fprintf(out, "All %d tests passed\n", len(results))
+			return nil
+		},
+	}
+}
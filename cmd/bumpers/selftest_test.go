@@ -0,0 +1,49 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	testutil "github.com/wizzomafizzo/bumpers/internal/testing"
+)
+
+func TestSelftestCommandReportsFailures(t *testing.T) {
+	_, _ = testutil.NewTestContext(t)
+	t.Parallel()
+
+	configPath := filepath.Join(t.TempDir(), "bumpers.yml")
+	err := os.WriteFile(configPath, []byte(`rules:
+  - match: "^rm -rf"
+    send: "Use git clean"
+    generate: "off"
+tests:
+  - name: rm is blocked
+    input: {tool_name: Bash, tool_input: {command: "rm -rf build"}}
+    expect_match: true
+  - name: ls is blocked
+    input: {tool_name: Bash, tool_input: {command: "ls"}}
+    expect_match: true
+`), 0o600)
+	if err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	rootCmd := createNewRootCommand()
+	var output bytes.Buffer
+	rootCmd.SetOut(&output)
+	rootCmd.SetArgs([]string{"selftest", "--config", configPath})
+
+	err = rootCmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "1 of 5 tests failed") {
+		t.Errorf("Expected 1 of 5 tests to fail, got: %v", err)
+	}
+	if !strings.Contains(output.String(), "[✓] rm is blocked") {
+		t.Errorf("Expected passing test in output, got: %s", output.String())
+	}
+	if !strings.Contains(output.String(), "[✗] ls is blocked: expected a match, but no rule fired") {
+		t.Errorf("Expected failing test in output, got: %s", output.String())
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wizzomafizzo/bumpers/internal/app"
+)
+
+// createSimulateCommand creates the simulate command.
+func createSimulateCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "simulate SCENARIO",
+		Short: "Run a scripted session's hook events and show each decision",
+		Long: "Run the hook events listed under \"steps:\" in a YAML scenario through the hook " +
+			"pipeline in order, writing a synthetic transcript as the session goes, and print " +
+			"each decision. Steps can assert the decision with expect (allow, deny or " +
+			"informational) and the message with expect_contains; the command fails when any " +
+			"step errors or misses an expectation. The scenario's config, relative to the " +
+			"scenario file, takes precedence over --config.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			data, err := os.ReadFile(args[0]) // #nosec G304 -- path is the user's scenario file
+			if err != nil {
+				return fmt.Errorf("failed to read scenario: %w", err)
+			}
+			scenario, err := app.ParseScenario(data)
+			if err != nil {
+				return fmt.Errorf("failed to load %s: %w", args[0], err)
+			}
+
+			configPath, err := configPathFromCommand(cmd.Parent())
+			if err != nil {
+				return err
+			}
+			if scenario.Config != "" {
+				configPath = scenario.Config
+				if !filepath.IsAbs(configPath) {
+					configPath = filepath.Join(filepath.Dir(args[0]), configPath)
+				}
+			}
+			cliApp, err := createApp(cmd.Context(), configPath)
+			if err != nil {
+				return err
+			}
+
+			results, err := cliApp.Simulate(cmd.Context(), scenario)
+			if err != nil {
+				return fmt.Errorf("simulate error: %w", err)
+			}
+
+			out := cmd.OutOrStdout()
+			failed := 0
+			for i := range results {
+				if !results[i].Passed() {
+					failed++
+				}
+				writeScenarioStep(out, i+1, &results[i])
+			}
+			if failed > 0 {
+				return fmt.Errorf("simulate failed: %d of %d steps failed", failed, len(results))
+			}
+			_, _ = fmt.Fprintf(out, "All %d steps passed\n", len(results))
+			return nil
+		},
+	}
+}
+
+// writeScenarioStep prints a step's event, decision, message and expectations
+func writeScenarioStep(out io.Writer, number int, result *app.ScenarioStepResult) {
+	step := &result.Step
+	label := step.Name
+	if label == "" {
+		label = step.Event
+		if step.Tool != "" {
+			label += " " + step.Tool
+		}
+	}
+
+	if result.Err != nil {
+		_, _ = fmt.Fprintf(out, "%s %d. %s: %v\n", statusMark(false), number, label, result.Err)
+		return
+	}
+	_, _ = fmt.Fprintf(out, "%s %d. %s: %s\n", statusMark(result.Passed()), number, label, result.Decision)
+	for _, line := range strings.Split(strings.TrimSpace(result.Message), "\n") {
+		if line != "" {
+			_, _ = fmt.Fprintf(out, "   %s\n", line)
+		}
+	}
+	for _, failure := range result.Failures {
+		_, _ = fmt.Fprintf(out, "   %s\n", failure)
+	}
+}
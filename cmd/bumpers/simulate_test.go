@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	testutil "github.com/wizzomafizzo/bumpers/internal/testing"
+)
+
+func TestSimulateCommand(t *testing.T) {
+	_, _ = testutil.NewTestContext(t)
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "bumpers.yml"), []byte(`rules:
+  - match: "^go test"
+    send: "Use just test instead"
+    generate: "off"
+  - match:
+      pattern: "not related to my changes"
+      event: post
+      sources: ["#intent"]
+    send: "Check whether the failure is really unrelated"
+    generate: "off"
+commands:
+  - name: hello
+    send: "Hello from bumpers"
+session:
+  - add: "Run tests with just test"
+`), 0o600))
+
+	run := func(scenario string) (string, error) {
+		scenarioPath := filepath.Join(dir, "session.yaml")
+		require.NoError(t, os.WriteFile(scenarioPath, []byte(scenario), 0o600))
+		rootCmd := createNewRootCommand()
+		var out bytes.Buffer
+		rootCmd.SetOut(&out)
+		rootCmd.SetErr(&bytes.Buffer{})
+		rootCmd.SetArgs([]string{"simulate", scenarioPath})
+		err := rootCmd.Execute()
+		return out.String(), err
+	}
+
+	out, err := run(`config: bumpers.yml
+steps:
+  - event: SessionStart
+    expect: informational
+    expect_contains: "just test"
+  - event: UserPromptSubmit
+    prompt: "$hello"
+    expect_contains: "Hello from bumpers"
+  - event: PreToolUse
+    tool: Bash
+    input: {command: "go test ./..."}
+    expect: deny
+    expect_contains: "just test"
+  - name: tests run properly
+    event: PreToolUse
+    tool: Bash
+    input: {command: "just test"}
+    transcript:
+      - text: "Any failure here is not related to my changes"
+    expect: allow
+  - event: PostToolUse
+    tool: Bash
+    input: {command: "just test"}
+    response: "FAIL: TestParse"
+    expect: deny
+`)
+	require.NoError(t, err, out)
+	require.Contains(t, out, "[✓] 1. SessionStart: informational\n   Run tests with just test\n")
+	require.Contains(t, out, "[✓] 3. PreToolUse Bash: deny\n   Use just test instead\n")
+	require.Contains(t, out, "[✓] 4. tests run properly: allow\n")
+	require.Contains(t, out, "[✓] 5. PostToolUse Bash: deny\n   Check whether the failure is really unrelated\n")
+	require.Contains(t, out, "All 5 steps passed\n")
+
+	out, err = run(`config: bumpers.yml
+steps:
+  - event: PreToolUse
+    tool: Bash
+    input: {command: "go build"}
+    expect: deny
+    expect_contains: "just"
+`)
+	require.EqualError(t, err, "simulate failed: 1 of 1 steps failed")
+	require.Contains(t, out, "[✗] 1. PreToolUse Bash: allow\n   expected deny, got allow\n"+
+		"   expected message to contain \"just\"\n")
+
+	_, err = run("steps:\n  - event: PreToolUse\n")
+	require.ErrorContains(t, err, "step 1: PreToolUse needs a tool")
+	_, err = run("steps:\n  - event: Stop\n")
+	require.ErrorContains(t, err, `step 1: unknown event "Stop"`)
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/wizzomafizzo/bumpers/internal/rules"
+)
+
+// defaultModeArg resets the operation mode to its default
+const defaultModeArg = "default"
+
+// createStateCommand creates the command for inspecting and changing project state
+func createStateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "state",
+		Short: "Inspect and change project state",
+	}
+
+	cmd.AddCommand(
+		createStateClearCommand(),
+		createStateGetModeCommand(),
+		createStateSetModeCommand(),
+	)
+
+	return cmd
+}
+
+// createStateGetModeCommand creates the command that prints the operation mode
+func createStateGetModeCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "get-mode",
+		Short: "Show the current operation mode",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			cliApp, err := createAppFromCommand(cmd.Context(), cmd)
+			if err != nil {
+				return err
+			}
+
+			state, err := cliApp.GetOperationMode(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("failed to get operation mode: %w", err)
+			}
+
+			_, _ = fmt.Fprintln(cmd.OutOrStdout(), state.Mode)
+			return nil
+		},
+	}
+}
+
+// createStateSetModeCommand creates the command that changes the operation mode
+func createStateSetModeCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "set-mode plan|execute|default",
+		Short: "Set the operation mode",
+		Long: "Set the operation mode. In plan mode editing tools are blocked until a prompt " +
+			"contains a trigger phrase such as \"make it so\". default clears the stored mode.",
+		ValidArgs: []string{string(rules.PlanMode), string(rules.ExecuteMode), defaultModeArg},
+		Args:      cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cliApp, err := createAppFromCommand(cmd.Context(), cmd)
+			if err != nil {
+				return err
+			}
+
+			if args[0] == defaultModeArg {
+				err = cliApp.ResetOperationMode(cmd.Context())
+			} else {
+				err = cliApp.SetOperationMode(cmd.Context(), rules.OperationMode(args[0]))
+			}
+			if err != nil {
+				return fmt.Errorf("failed to set operation mode: %w", err)
+			}
+
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Operation mode set to %s\n", args[0])
+			return nil
+		},
+	}
+}
+
+// createStateClearCommand creates the command that wipes the project's state
+func createStateClearCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "clear",
+		Short: "Clear the project's stored state",
+		Long: "Clear the project's stored state, such as the operation mode, skip flag and " +
+			"rule toggles, restoring their defaults. The cache is not touched.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			cliApp, err := createAppFromCommand(cmd.Context(), cmd)
+			if err != nil {
+				return err
+			}
+
+			if err := cliApp.ClearState(cmd.Context()); err != nil {
+				return fmt.Errorf("failed to clear state: %w", err)
+			}
+
+			_, _ = fmt.Fprintln(cmd.OutOrStdout(), "State cleared")
+			return nil
+		},
+	}
+}
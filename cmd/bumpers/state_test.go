@@ -0,0 +1,42 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateStateCommand(t *testing.T) {
+	t.Parallel()
+
+	cmd := createStateCommand()
+	assert.Equal(t, "state", cmd.Use)
+
+	for _, name := range []string{"clear", "get-mode", "set-mode"} {
+		sub, _, err := cmd.Find([]string{name})
+		require.NoError(t, err, name)
+		assert.Equal(t, name, sub.Name())
+	}
+}
+
+func TestStateSetModeRejectsUnknownMode(t *testing.T) {
+	t.Parallel()
+
+	rootCmd := createNewRootCommand()
+	rootCmd.SetOut(&bytes.Buffer{})
+	rootCmd.SetErr(&bytes.Buffer{})
+	rootCmd.SetArgs([]string{"state", "set-mode", "discuss"})
+
+	err := rootCmd.Execute()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid argument")
+}
+
+func TestStateSetModeCompletesModes(t *testing.T) {
+	t.Parallel()
+
+	suggestions := runCompletion(t, "state", "set-mode", "")
+	assert.Equal(t, []string{"plan", "execute", "default"}, suggestions)
+}
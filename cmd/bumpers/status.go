@@ -4,27 +4,42 @@ import (
 	"fmt"
 
 	"github.com/spf13/cobra"
+	"github.com/wizzomafizzo/bumpers/internal/app"
 )
 
 // createStatusCommand creates the status command.
 func createStatusCommand() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "status",
 		Short: "Check hook status",
-		Long:  "Check hook status",
+		Long: "Check hook status and whether the claude CLI used for AI generation is available. " +
+			"The CLI check is cached for an hour, --refresh checks again.",
 		RunE: func(cmd *cobra.Command, _ []string) error {
-			app, err := createAppFromCommand(cmd.Context(), cmd.Parent())
+			refresh, err := cmd.Flags().GetBool("refresh")
+			if err != nil {
+				return fmt.Errorf("failed to get refresh flag: %w", err)
+			}
+
+			cliApp, err := createAppFromCommand(cmd.Context(), cmd.Parent())
 			if err != nil {
 				return err
 			}
 
-			status, err := app.Status()
+			status, err := cliApp.Status()
 			if err != nil {
 				return fmt.Errorf("failed to get status: %w", err)
 			}
 
-			_, _ = fmt.Print(status)
+			probe, err := cliApp.ClaudeStatus(cmd.Context(), refresh)
+			if err != nil {
+				return fmt.Errorf("failed to check claude CLI: %w", err)
+			}
+
+			_, _ = fmt.Fprint(cmd.OutOrStdout(), status+app.FormatClaudeStatus(probe))
 			return nil
 		},
 	}
+
+	cmd.Flags().Bool("refresh", false, "Check the claude CLI again instead of using the cached result")
+	return cmd
 }
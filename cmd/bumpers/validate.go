@@ -1,9 +1,11 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/spf13/cobra"
+	"github.com/wizzomafizzo/bumpers/internal/config"
 )
 
 // createValidateCommand creates the validate command.
@@ -19,6 +21,9 @@ func createValidateCommand() *cobra.Command {
 			}
 
 			result, err := app.ValidateConfig()
+			if errors.Is(err, config.ErrConfigNotFound) {
+				return fmt.Errorf("validation error: %w (run 'bumpers install' to create one)", err)
+			}
 			if err != nil {
 				return fmt.Errorf("validation error: %w", err)
 			}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/wizzomafizzo/bumpers/internal/version"
+)
+
+// createVersionCommand creates the version command.
+func createVersionCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "version",
+		Short: "Print the bumpers version",
+		Long: "Print the bumpers version. With --check, also compare it to the config's " +
+			"settings.required_version and fail when the range isn't satisfied.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			check, err := cmd.Flags().GetBool("check")
+			if err != nil {
+				return fmt.Errorf("failed to get check flag: %w", err)
+			}
+
+			out := cmd.OutOrStdout()
+			_, _ = fmt.Fprintf(out, "bumpers %s\n", version.Version)
+			if !check {
+				return nil
+			}
+
+			app, err := createAppFromCommand(cmd.Context(), cmd.Parent())
+			if err != nil {
+				return err
+			}
+			result, err := app.CheckVersion(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("version check failed: %w", err)
+			}
+
+			switch {
+			case result.Required == "":
+				_, _ = fmt.Fprintln(out, "No required_version is set")
+			case !result.Satisfied:
+				return fmt.Errorf("bumpers %s does not satisfy required_version %q, install a matching release",
+					result.Version, result.Required)
+			case version.IsDev(result.Version):
+				_, _ = fmt.Fprintf(out, "[✓] Development build, required_version %q not checked\n", result.Required)
+			default:
+				_, _ = fmt.Fprintf(out, "[✓] Satisfies required_version %q\n", result.Required)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool("check", false, "Fail unless the version satisfies settings.required_version")
+	return cmd
+}
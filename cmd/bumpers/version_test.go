@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	testutil "github.com/wizzomafizzo/bumpers/internal/testing"
+	"github.com/wizzomafizzo/bumpers/internal/version"
+)
+
+func TestVersionCheck(t *testing.T) { //nolint:paralleltest // sets the global build version
+	_, _ = testutil.NewTestContext(t)
+
+	previous := version.Version
+	version.Version = "v1.4.2"
+	t.Cleanup(func() { version.Version = previous })
+
+	tests := []struct {
+		name     string
+		required string
+		output   string
+		wantErr  string
+	}{
+		{name: "unset", output: "No required_version is set"},
+		{name: "satisfied", required: "^1.2", output: `[✓] Satisfies required_version "^1.2"`},
+		{
+			name:     "unsatisfied",
+			required: ">=2.0.0",
+			wantErr:  `bumpers v1.4.2 does not satisfy required_version ">=2.0.0"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), "bumpers.yml")
+			content := "rules:\n  - match: rm\n    send: no\n"
+			if tt.required != "" {
+				content += "settings:\n  required_version: \"" + tt.required + "\"\n"
+			}
+			if err := os.WriteFile(configPath, []byte(content), 0o600); err != nil {
+				t.Fatalf("Failed to write config: %v", err)
+			}
+
+			rootCmd := createNewRootCommand()
+			var output bytes.Buffer
+			rootCmd.SetOut(&output)
+			rootCmd.SetArgs([]string{"version", "--check", "--config", configPath})
+
+			err := rootCmd.Execute()
+			if !strings.Contains(output.String(), "bumpers v1.4.2\n") {
+				t.Errorf("Expected version in output, got: %s", output.String())
+			}
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Expected error %q, got: %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			if !strings.Contains(output.String(), tt.output) {
+				t.Errorf("Expected %q in output, got: %s", tt.output, output.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/wizzomafizzo/bumpers/internal/claude/transcript"
+)
+
+// watchValueMaxLen is how much of a matched value watch prints
+const watchValueMaxLen = 80
+
+// createWatchCommand creates the watch command.
+func createWatchCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "watch",
+		Short: "Follow a transcript and show what the rules would say",
+		Long: "Follow a Claude transcript like tail -f and check each tool call appended to it " +
+			"against the config's rules. When a rule would block a call, its message is printed " +
+			"with the time of the call. Watching is passive: hooks aren't involved and no state, " +
+			"audit records or AI generation come from it.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			transcriptPath, err := cmd.Flags().GetString("transcript")
+			if err != nil {
+				return fmt.Errorf("failed to get transcript flag: %w", err)
+			}
+			if transcriptPath == "" {
+				return errors.New("nothing to watch, pass --transcript PATH")
+			}
+			fromStart, err := cmd.Flags().GetBool("from-start")
+			if err != nil {
+				return fmt.Errorf("failed to get from-start flag: %w", err)
+			}
+
+			cliApp, err := createAppFromCommand(cmd.Context(), cmd.Parent())
+			if err != nil {
+				return err
+			}
+
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+			defer stop()
+
+			out := cmd.OutOrStdout()
+			_, _ = fmt.Fprintf(out, "Watching %s, press Ctrl-C to stop\n", transcriptPath)
+			err = transcript.WatchTranscript(ctx, transcriptPath, transcript.WatchOptions{FromStart: fromStart},
+				func(entry transcript.TranscriptEntry) {
+					for _, item := range entry.Message.Content {
+						if item.Type != "tool_use" {
+							continue
+						}
+						match, matchErr := cliApp.MatchToolUse(item.Name, item.Input)
+						if matchErr != nil {
+							_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Error checking %s call: %v\n", item.Name, matchErr)
+							continue
+						}
+						if match == nil {
+							continue
+						}
+						_, _ = fmt.Fprintf(out, "[%s] Rule %d matched %s %s: %s\n  %s\n",
+							entryTime(entry).Format(time.TimeOnly), match.Index, item.Name, match.Field,
+							shortValue(match.Value), strings.ReplaceAll(match.Message, "\n", "\n  "))
+					}
+				})
+			if err != nil {
+				return fmt.Errorf("failed to watch transcript: %w", err)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().String("transcript", "", "Path to the Claude transcript (.jsonl) to follow")
+	cmd.Flags().Bool("from-start", false, "Also check the tool calls already in the transcript")
+	return cmd
+}
+
+// entryTime returns when a transcript entry was written in local time, or now when its
+// timestamp is missing
+func entryTime(entry transcript.TranscriptEntry) time.Time {
+	if timestamp, err := time.Parse(time.RFC3339Nano, entry.Timestamp); err == nil {
+		return timestamp.Local()
+	}
+	return time.Now()
+}
+
+// shortValue returns the first line of value, cut to watchValueMaxLen characters
+func shortValue(value string) string {
+	value, _, cut := strings.Cut(value, "\n")
+	if runes := []rune(value); len(runes) > watchValueMaxLen {
+		return string(runes[:watchValueMaxLen]) + "..."
+	}
+	if cut {
+		return value + "..."
+	}
+	return value
+}
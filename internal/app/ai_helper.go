@@ -2,12 +2,15 @@ package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/spf13/afero"
 	ai "github.com/wizzomafizzo/bumpers/internal/claude/api"
 	"github.com/wizzomafizzo/bumpers/internal/logging"
+	"github.com/wizzomafizzo/bumpers/internal/metrics"
 	"github.com/wizzomafizzo/bumpers/internal/storage"
+	"github.com/wizzomafizzo/bumpers/internal/template"
 )
 
 // AIHelper provides shared AI generation functionality
@@ -44,17 +47,42 @@ func (h *AIHelper) getFileSystem() afero.Fs {
 	return afero.NewOsFs()
 }
 
-// ProcessAIGenerationGeneric method that accepts any type with GetGenerate()
+// generationFallback returns the message shown when AI generation fails: the original message once
+// the hook timeout is reached, otherwise generate.fallback_message
+// rendered with render (or message when unset or it fails to render), adjusted by generate.on_error
+func generationFallback(
+	ctx context.Context, generateConfig GenerateConfig, message string, render func(string) (string, error),
+) string {
+	generate := generateConfig.GetGenerate()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		logging.Get(ctx).Warn().Msg("hook timeout reached, using the original message without AI generation")
+		return message
+	}
+	fallback := message
+	if generate.FallbackMessage != "" {
+		rendered, err := render(generate.FallbackMessage)
+		if err != nil {
+			logging.Get(ctx).Error().Err(err).Msg("failed to process fallback_message template, using original message")
+		} else {
+			fallback = rendered
+		}
+	}
+	return generate.OnErrorMessage(fallback)
+}
+
+// ProcessAIGenerationGeneric method that accepts any type with GetGenerate(), generating in
+// the language of locale when it's set
 func (h *AIHelper) ProcessAIGenerationGeneric(
 	ctx context.Context,
 	generateConfig GenerateConfig,
-	message, pattern string,
+	message, pattern, locale string,
 ) (string, error) {
 	generate := generateConfig.GetGenerate()
 	// Skip if generation mode is "off"
 	if generate.Mode == "off" {
 		return message, nil
 	}
+	defer metrics.FromContext(ctx).Track(metrics.StageAIGeneration)()
 
 	// Use injected cache path (for tests) or XDG-compliant cache path (production)
 	var cachePath string
@@ -90,11 +118,17 @@ func (h *AIHelper) ProcessAIGenerationGeneric(
 	}()
 
 	// Create request
+	cacheKeyExtra, err := template.ExecuteCacheKeyTemplate(generate.CacheKeyExtra, h.projectRoot)
+	if err != nil {
+		return message, fmt.Errorf("failed to process cache_key_extra template: %w", err)
+	}
 	req := &ai.GenerateRequest{
 		OriginalMessage: message,
 		CustomPrompt:    generate.Prompt,
 		GenerateMode:    generate.Mode,
 		Pattern:         pattern,
+		CacheKeyExtra:   cacheKeyExtra,
+		Locale:          locale,
 	}
 
 	// Generate message
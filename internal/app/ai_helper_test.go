@@ -121,7 +121,7 @@ func TestProcessAIGenerationGeneric_ModeOff(t *testing.T) {
 
 	generateConfig := newMockGenerateConfig("off", "")
 
-	result, err := helper.ProcessAIGenerationGeneric(ctx, generateConfig, "original message", "pattern")
+	result, err := helper.ProcessAIGenerationGeneric(ctx, generateConfig, "original message", "pattern", "")
 
 	require.NoError(t, err)
 	require.Equal(t, "original message", result)
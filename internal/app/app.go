@@ -8,37 +8,52 @@ import (
 	"io"
 	"os"
 	"path/filepath"
-	"strings"
+	"time"
 
 	"github.com/spf13/afero"
 	apphooks "github.com/wizzomafizzo/bumpers/internal/app/hooks"
 	apptypes "github.com/wizzomafizzo/bumpers/internal/app/types"
+	"github.com/wizzomafizzo/bumpers/internal/claude"
 	ai "github.com/wizzomafizzo/bumpers/internal/claude/api"
+	"github.com/wizzomafizzo/bumpers/internal/config"
 	"github.com/wizzomafizzo/bumpers/internal/database"
 	"github.com/wizzomafizzo/bumpers/internal/hooks"
 	"github.com/wizzomafizzo/bumpers/internal/logging"
+	"github.com/wizzomafizzo/bumpers/internal/metrics"
 	"github.com/wizzomafizzo/bumpers/internal/project"
+	"github.com/wizzomafizzo/bumpers/internal/rules"
 	"github.com/wizzomafizzo/bumpers/internal/storage"
 )
 
+// ErrStateUnavailable is returned when no project state database could be opened
+var ErrStateUnavailable = errors.New("project state is unavailable: no project root or database")
+
+// ErrNoPendingApproval is returned when approving a token no pending approval has
+var ErrNoPendingApproval = errors.New("no pending approval with token")
+
 // App represents the main application with composed components
 type App struct {
 	// Core components
-	hookProcessor   apphooks.HookProcessor
-	promptHandler   PromptHandler
-	sessionManager  SessionManager
-	configValidator apptypes.ConfigValidator
-	installManager  InstallManager
+	hookProcessor       apphooks.HookProcessor
+	promptHandler       PromptHandler
+	sessionManager      SessionManager
+	configValidator     apptypes.ConfigValidator
+	installManager      InstallManager
+	notificationHandler NotificationHandler
 
 	// Database
-	dbManager *database.Manager
+	dbManager    *database.Manager
+	stateManager *storage.StateManager
 
 	// Configuration
 	fileSystem   afero.Fs
 	mockLauncher ai.MessageGenerator
+	claudeProber claude.Prober
 	configPath   string
 	workDir      string
 	projectRoot  string
+	// hookTimeout overrides settings.hook_timeout when set
+	hookTimeout time.Duration
 }
 
 func NewApp(ctx context.Context, configPath string) *App {
@@ -49,19 +64,7 @@ func NewApp(ctx context.Context, configPath string) *App {
 		projectRoot = ""
 	}
 
-	// Resolve config path relative to project root if it's relative
-	resolvedConfigPath := configPath
-	shouldResolve := projectRoot != "" && !filepath.IsAbs(configPath)
-	if shouldResolve {
-		resolvedConfigPath = filepath.Join(projectRoot, configPath)
-	}
-
-	// If using default config name, try different extensions in order
-	if shouldResolve && configPath == "bumpers.yml" {
-		if _, err := os.Stat(resolvedConfigPath); os.IsNotExist(err) {
-			resolvedConfigPath = findAlternativeConfig(projectRoot)
-		}
-	}
+	resolvedConfigPath, installConfigPath := resolveConfigPath(projectRoot, configPath)
 
 	// Create database manager
 	dbManager, stateManager := createDatabaseAndStateManager(ctx, projectRoot)
@@ -70,18 +73,20 @@ func NewApp(ctx context.Context, configPath string) *App {
 	configValidator := NewConfigValidator(resolvedConfigPath, projectRoot)
 	hookProcessor := apphooks.NewHookProcessor(configValidator, projectRoot, stateManager)
 	promptHandler := NewPromptHandler(resolvedConfigPath, projectRoot, stateManager)
-	sessionManager := NewSessionManager(resolvedConfigPath, projectRoot, nil)
-	installManager := NewInstallManager(resolvedConfigPath, "", projectRoot, nil)
+	sessionManager := NewSessionManager(resolvedConfigPath, projectRoot, nil, stateManager)
+	installManager := NewInstallManager(installConfigPath, "", projectRoot, nil)
 
 	app := &App{
-		hookProcessor:   hookProcessor,
-		promptHandler:   promptHandler,
-		sessionManager:  sessionManager,
-		configValidator: configValidator,
-		installManager:  installManager,
-		dbManager:       dbManager,
-		configPath:      resolvedConfigPath,
-		projectRoot:     projectRoot,
+		hookProcessor:       hookProcessor,
+		promptHandler:       promptHandler,
+		sessionManager:      sessionManager,
+		configValidator:     configValidator,
+		installManager:      installManager,
+		notificationHandler: NewNotificationHandler(resolvedConfigPath),
+		dbManager:           dbManager,
+		stateManager:        stateManager,
+		configPath:          resolvedConfigPath,
+		projectRoot:         projectRoot,
 	}
 
 	logging.Get(ctx).Debug().
@@ -93,15 +98,54 @@ func NewApp(ctx context.Context, configPath string) *App {
 	return app
 }
 
-func findAlternativeConfig(projectRoot string) string {
-	extensions := []string{"yaml", "toml", "json"}
-	for _, ext := range extensions {
-		candidatePath := filepath.Join(projectRoot, "bumpers."+ext)
+// resolveConfigPath resolves a relative configPath against projectRoot, looking for each
+// default config file when it is the default name. install is the single file install
+// and status work with.
+func resolveConfigPath(projectRoot, configPath string) (resolved, install string) {
+	if projectRoot == "" || filepath.IsAbs(configPath) {
+		return configPath, configPath
+	}
+
+	// If using default config name, try different extensions in order
+	if configPath == "bumpers.yml" {
+		return resolveDefaultConfig(projectRoot, os.Getenv(ConfigMergeEnv) == "1")
+	}
+
+	resolved = filepath.Join(projectRoot, configPath)
+	return resolved, resolved
+}
+
+// ConfigMergeEnv enables merging every default config file found, rather than using the first
+const ConfigMergeEnv = "BUMPERS_CONFIG_MERGE"
+
+// defaultConfigNames are the config files looked for in the project root, highest precedence first
+var defaultConfigNames = []string{"bumpers.yml", "bumpers.yaml", "bumpers.toml", "bumpers.json"}
+
+// resolveDefaultConfig returns the config path to load from projectRoot: the first default
+// config file found or, with merge set and several found, all of them joined highest
+// precedence first. With no file found, config environment variables are used if set.
+// primary is the single file install and status work with.
+func resolveDefaultConfig(projectRoot string, merge bool) (configPath, primary string) {
+	var found []string
+	for _, name := range defaultConfigNames {
+		candidatePath := filepath.Join(projectRoot, name)
 		if _, err := os.Stat(candidatePath); err == nil {
-			return candidatePath
+			found = append(found, candidatePath)
+		}
+	}
+
+	switch {
+	case len(found) == 0:
+		fallback := filepath.Join(projectRoot, defaultConfigNames[0])
+		if config.HasEnvConfig() {
+			return config.EnvConfigPath, fallback
 		}
+		return fallback, fallback
+	case merge && len(found) > 1:
+		return config.JoinPaths(found), found[0]
+	default:
+		return found[0], found[0]
 	}
-	return filepath.Join(projectRoot, "bumpers.yml") // fallback to original
 }
 
 // createDatabaseAndStateManager creates database manager and state manager for the given project root
@@ -112,9 +156,9 @@ func createDatabaseAndStateManager(ctx context.Context, projectRoot string) (
 		return nil, nil // Can't create managers without project root
 	}
 
-	// Create storage manager to get database path
+	// State is stored apart from the cache database so clearing the cache keeps it
 	storageManager := storage.New(afero.NewOsFs())
-	databasePath, err := storageManager.GetDatabasePath()
+	databasePath, err := storageManager.GetStateDatabasePath()
 	if err != nil {
 		return nil, nil // Gracefully degrade if we can't get database path
 	}
@@ -186,20 +230,22 @@ func NewAppWithWorkDir(configPath, workDir string) *App {
 	// Create specialized components with consistent projectRoot
 	configValidator := NewConfigValidator(configPath, projectRoot)
 	hookProcessor := apphooks.NewHookProcessor(configValidator, projectRoot, stateManager)
-	promptHandler := NewPromptHandler(configPath, projectRoot)
-	sessionManager := NewSessionManager(configPath, projectRoot, nil)
+	promptHandler := NewPromptHandler(configPath, projectRoot, stateManager)
+	sessionManager := NewSessionManager(configPath, projectRoot, nil, stateManager)
 	installManager := NewInstallManager(configPath, projectRoot, projectRoot, nil)
 
 	return &App{
-		hookProcessor:   hookProcessor,
-		promptHandler:   promptHandler,
-		sessionManager:  sessionManager,
-		configValidator: configValidator,
-		installManager:  installManager,
-		dbManager:       dbManager,
-		configPath:      configPath,
-		workDir:         workDir,
-		projectRoot:     projectRoot, // Use detected project root
+		hookProcessor:       hookProcessor,
+		promptHandler:       promptHandler,
+		sessionManager:      sessionManager,
+		configValidator:     configValidator,
+		installManager:      installManager,
+		notificationHandler: NewNotificationHandler(configPath),
+		dbManager:           dbManager,
+		stateManager:        stateManager,
+		configPath:          configPath,
+		workDir:             workDir,
+		projectRoot:         projectRoot, // Use detected project root
 	}
 }
 
@@ -213,31 +259,41 @@ func NewAppWithFileSystem(configPath, workDir string, fs afero.Fs) *App {
 	// Create specialized components with consistent workDir as projectRoot and injected filesystem
 	configValidator := NewConfigValidator(configPath, workDir)
 	hookProcessor := apphooks.NewHookProcessor(configValidator, workDir, stateManager)
-	promptHandler := NewPromptHandler(configPath, workDir)
-	sessionManager := NewSessionManager(configPath, workDir, fs)
+	hookProcessor.SetFileSystem(fs)
+	promptHandler := NewPromptHandler(configPath, workDir, stateManager)
+	sessionManager := NewSessionManager(configPath, workDir, fs, stateManager)
 	installManager := NewInstallManager(configPath, workDir, workDir, fs)
 
 	return &App{
-		hookProcessor:   hookProcessor,
-		promptHandler:   promptHandler,
-		sessionManager:  sessionManager,
-		configValidator: configValidator,
-		installManager:  installManager,
-		dbManager:       dbManager,
-		configPath:      configPath,
-		workDir:         workDir,
-		projectRoot:     workDir, // Ensure projectRoot is set consistently
-		fileSystem:      fs,
+		hookProcessor:       hookProcessor,
+		promptHandler:       promptHandler,
+		sessionManager:      sessionManager,
+		configValidator:     configValidator,
+		installManager:      installManager,
+		notificationHandler: NewNotificationHandler(configPath),
+		dbManager:           dbManager,
+		stateManager:        stateManager,
+		configPath:          configPath,
+		workDir:             workDir,
+		projectRoot:         workDir, // Ensure projectRoot is set consistently
+		fileSystem:          fs,
 	}
 }
 
-// ProcessHook delegates to HookProcessor
+// ProcessHook delegates to HookProcessor. With debug logging enabled, stage timings
+// and the decision are logged as a single "hook metrics" line.
 func (a *App) ProcessHook(ctx context.Context, input io.Reader) (ProcessResult, error) {
+	ctx, collector := metrics.Start(ctx)
+
 	response, err := a.processHookWithContext(ctx, input)
 	if err != nil {
+		collector.Log(ctx, "error")
 		return ProcessResult{}, err
 	}
-	return convertResponseToProcessResult(response), nil
+
+	result := convertResponseToProcessResult(response)
+	collector.Log(ctx, string(result.Mode))
+	return result, nil
 }
 
 // convertResponseToProcessResult converts legacy string responses to structured ProcessResult
@@ -246,8 +302,8 @@ func convertResponseToProcessResult(response string) ProcessResult {
 		return ProcessResult{Mode: ProcessModeAllow, Message: ""}
 	}
 
-	// Check if response is hookSpecificOutput format (should be informational)
-	if strings.Contains(response, "hookEventName") {
+	// Check if response is hookSpecificOutput or systemMessage format (should be informational)
+	if apptypes.IsStructuredResponse(response) {
 		return ProcessResult{Mode: ProcessModeInformational, Message: response}
 	}
 
@@ -263,15 +319,55 @@ func (a *App) processHookWithContext(ctx context.Context, input io.Reader) (stri
 		return "", nil
 	}
 
+	timeout := a.hookTimeoutFor(ctx)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	response, err := a.routeHook(ctx, input)
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		logger.Warn().Dur("timeout", timeout).Msg("hook processing exceeded its timeout")
+	}
+	return response, err
+}
+
+// hookTimeoutFor returns the --timeout override, or settings.hook_timeout when the config
+// loads, or the default
+func (a *App) hookTimeoutFor(ctx context.Context) time.Duration {
+	if a.hookTimeout > 0 {
+		return a.hookTimeout
+	}
+	cfg, _, err := a.configValidator.LoadConfigAndMatcher(ctx)
+	if err != nil {
+		return config.DefaultHookTimeout
+	}
+	return cfg.Settings.GetHookTimeout()
+}
+
+// SetHookTimeout overrides settings.hook_timeout for subsequent hooks; zero restores it
+func (a *App) SetHookTimeout(timeout time.Duration) {
+	a.hookTimeout = timeout
+}
+
+// routeHook detects the hook type of input and passes it to its handler
+func (a *App) routeHook(ctx context.Context, input io.Reader) (string, error) {
+	logger := logging.Get(ctx)
 	logger.Debug().Msg("processing hook input")
 
 	// Detect hook type and get raw JSON
+	stopDetect := metrics.FromContext(ctx).Track(metrics.StageDetect)
 	hookType, rawJSON, err := hooks.DetectHookType(input)
+	stopDetect()
 	if err != nil {
 		logger.Error().Err(err).Msg("Failed to detect hook type")
 		return "", fmt.Errorf("failed to detect hook type: %w", err)
 	}
-	logger.Debug().RawJSON("hook", rawJSON).Str("type", hookType.String()).Msg("received hook")
+	if hookType == hooks.PreToolUseHook || hookType == hooks.PostToolUseHook {
+		// Tool inputs are logged once the config is loaded, so they can be redacted
+		logger.Debug().Int("bytes", len(rawJSON)).Str("type", hookType.String()).Msg("received hook")
+	} else {
+		logger.Debug().RawJSON("hook", rawJSON).Str("type", hookType.String()).Msg("received hook")
+	}
+	a.warnRequiredVersion(ctx, rawJSON)
 
 	// Route to appropriate handler based on hook type using switch
 	switch hookType {
@@ -284,6 +380,9 @@ func (a *App) processHookWithContext(ctx context.Context, input io.Reader) (stri
 	case hooks.PostToolUseHook:
 		logger.Debug().Msg("processing PostToolUse hook")
 		return a.ProcessPostToolUse(ctx, rawJSON)
+	case hooks.NotificationHook:
+		logger.Debug().Msg("processing Notification hook")
+		return a.ProcessNotification(ctx, rawJSON)
 	case hooks.PreToolUseHook:
 		// Handle PreToolUse hooks
 		return a.processPreToolUse(ctx, rawJSON)
@@ -330,6 +429,15 @@ func (a *App) ProcessSessionStart(ctx context.Context, rawJSON json.RawMessage)
 	return result, nil
 }
 
+// ProcessNotification delegates to NotificationHandler
+func (a *App) ProcessNotification(ctx context.Context, rawJSON json.RawMessage) (string, error) {
+	result, err := a.notificationHandler.ProcessNotification(ctx, rawJSON)
+	if err != nil {
+		return "", fmt.Errorf("notification handler failed: %w", err)
+	}
+	return result, nil
+}
+
 // TestCommand delegates to ConfigValidator
 func (a *App) TestCommand(ctx context.Context, command string) (string, error) {
 	result, err := a.configValidator.TestCommand(ctx, command)
@@ -339,6 +447,24 @@ func (a *App) TestCommand(ctx context.Context, command string) (string, error) {
 	return result, nil
 }
 
+// TestCommandDetailed delegates to ConfigValidator
+func (a *App) TestCommandDetailed(ctx context.Context, command string) (*apptypes.TestCommandResult, error) {
+	result, err := a.configValidator.TestCommandDetailed(ctx, command)
+	if err != nil {
+		return nil, fmt.Errorf("config validator failed: %w", err)
+	}
+	return result, nil
+}
+
+// CheckCommand delegates to ConfigValidator
+func (a *App) CheckCommand(ctx context.Context, command string) (message string, blocked bool, err error) {
+	message, blocked, err = a.configValidator.CheckCommand(ctx, command)
+	if err != nil {
+		return "", false, fmt.Errorf("config validator failed: %w", err)
+	}
+	return message, blocked, nil
+}
+
 // ValidateConfig delegates to ConfigValidator
 func (a *App) ValidateConfig() (string, error) {
 	result, err := a.configValidator.ValidateConfig()
@@ -366,6 +492,68 @@ func (a *App) Status() (string, error) {
 	return result, nil
 }
 
+// GetOperationMode returns the project's stored plan/execute operation state
+func (a *App) GetOperationMode(ctx context.Context) (*rules.OperationState, error) {
+	if a.stateManager == nil {
+		return nil, ErrStateUnavailable
+	}
+	state, err := a.stateManager.GetOperationMode(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("state manager failed: %w", err)
+	}
+	return state, nil
+}
+
+// SetOperationMode stores the project's operation mode
+func (a *App) SetOperationMode(ctx context.Context, mode rules.OperationMode) error {
+	if a.stateManager == nil {
+		return ErrStateUnavailable
+	}
+	state := &rules.OperationState{Mode: mode, UpdatedAt: time.Now().Unix()}
+	if err := a.stateManager.SetOperationMode(ctx, state); err != nil {
+		return fmt.Errorf("state manager failed: %w", err)
+	}
+	return nil
+}
+
+// ClearState removes all of the project's stored state, leaving the cache untouched
+func (a *App) ClearState(ctx context.Context) error {
+	if a.stateManager == nil {
+		return ErrStateUnavailable
+	}
+	if err := a.stateManager.Clear(ctx); err != nil {
+		return fmt.Errorf("state manager failed: %w", err)
+	}
+	return nil
+}
+
+// Approve grants the pending approval with token in any session, returning the value an
+// approval: session rule now allows for the rest of that session
+func (a *App) Approve(ctx context.Context, token string) (string, error) {
+	if a.stateManager == nil {
+		return "", ErrStateUnavailable
+	}
+	approval, err := a.stateManager.Approve(ctx, "", token)
+	if err != nil {
+		return "", fmt.Errorf("state manager failed: %w", err)
+	}
+	if approval == nil {
+		return "", fmt.Errorf("%w %q", ErrNoPendingApproval, token)
+	}
+	return approval.Value, nil
+}
+
+// ResetOperationMode clears the project's stored operation mode so the default applies
+func (a *App) ResetOperationMode(ctx context.Context) error {
+	if a.stateManager == nil {
+		return ErrStateUnavailable
+	}
+	if err := a.stateManager.ClearOperationMode(ctx); err != nil {
+		return fmt.Errorf("state manager failed: %w", err)
+	}
+	return nil
+}
+
 // InstallClaudeHooks delegates to InstallManager - needed for tests
 func (a *App) installClaudeHooks() error {
 	err := a.installManager.InstallClaudeHooks()
@@ -0,0 +1,248 @@
+package app
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wizzomafizzo/bumpers/internal/claude"
+)
+
+// readAuditLog returns the JSON lines written to an audit log
+func readAuditLog(t *testing.T, path string) []map[string]any {
+	t.Helper()
+	data, err := os.ReadFile(path) // #nosec G304 -- test temp file
+	require.NoError(t, err)
+
+	var records []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		var record map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &record))
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestProcessHookRunsBlockAndAuditActions(t *testing.T) {
+	t.Parallel()
+	ctx, _ := setupTestWithContext(t)
+
+	auditPath := filepath.Join(t.TempDir(), "logs", "audit.jsonl")
+	configPath := createTempConfig(t, `settings:
+  audit_log: "`+auditPath+`"
+  redact: ["token"]
+rules:
+  - id: no-deploy
+    match: "^deploy"
+    send: "Use just deploy"
+    actions: [block, audit]
+    generate: "off"
+  - match: "^curl"
+    send: "Network call"
+    action: audit
+    generate: "off"`)
+	app := NewAppWithFileSystem(configPath, t.TempDir(), afero.NewMemMapFs())
+
+	result, err := app.ProcessHook(ctx, strings.NewReader(
+		`{"tool_name": "Bash", "tool_input": {"command": "deploy --token=`+logSecret+`"}}`))
+	require.NoError(t, err)
+	assert.Equal(t, ProcessModeBlock, result.Mode)
+	assert.Equal(t, "Use just deploy", result.Message)
+
+	result, err = app.ProcessHook(ctx, strings.NewReader(
+		`{"tool_name": "Bash", "tool_input": {"command": "curl example.com"}}`))
+	require.NoError(t, err)
+	assert.Equal(t, ProcessModeAllow, result.Mode, "a rule without the block action only audits")
+
+	records := readAuditLog(t, auditPath)
+	require.Len(t, records, 2)
+	assert.Equal(t, "pre", records[0]["event"])
+	assert.Equal(t, "Bash", records[0]["tool"])
+	assert.Equal(t, "no-deploy", records[0]["rule_id"])
+	assert.Equal(t, "deploy --token=[REDACTED]", records[0]["value"], "audited values are redacted like logs")
+	assert.Equal(t, "Use just deploy", records[0]["message"])
+	assert.Equal(t, true, records[0]["blocked"])
+	assert.Equal(t, "^curl", records[1]["pattern"])
+	assert.Equal(t, false, records[1]["blocked"])
+}
+
+func TestProcessHookPostsWebhookAction(t *testing.T) {
+	t.Parallel()
+	ctx, _ := setupTestWithContext(t)
+
+	bodies := make(chan []byte, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies <- body
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(server.Close)
+
+	configPath := createTempConfig(t, `settings:
+  webhook_url: "`+server.URL+`"
+rules:
+  - match:
+      pattern: "FAIL"
+      event: post
+    send: "Tests failed"
+    actions: [webhook, block]
+    generate: "off"`)
+	app := NewAppWithFileSystem(configPath, t.TempDir(), afero.NewMemMapFs())
+
+	result, err := app.ProcessHook(ctx, strings.NewReader(`{"hook_event_name": "PostToolUse", "tool_name": "Bash", `+
+		`"tool_input": {"command": "go test"}, "tool_response": {"output": "FAIL pkg"}}`))
+	require.NoError(t, err)
+	assert.Equal(t, "Tests failed", result.Message)
+
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(<-bodies, &record))
+	assert.Equal(t, "post", record["event"])
+	assert.Equal(t, "FAIL pkg", record["value"])
+	assert.Equal(t, true, record["blocked"])
+}
+
+func TestProcessHookAttributesRuleSource(t *testing.T) {
+	t.Parallel()
+	ctx, getLogs := setupTestWithContext(t)
+
+	configDir := t.TempDir()
+	auditPath := filepath.Join(t.TempDir(), "audit.jsonl")
+	base := filepath.Join(configDir, "a.yml")
+	team := filepath.Join(configDir, "b.yml")
+	require.NoError(t, os.WriteFile(base, []byte(`settings:
+  audit_log: "`+auditPath+`"
+  show_rule_source: true
+rules:
+  - match: "^npm"
+    send: "Use pnpm"
+  - match: "^deploy prod"
+    send: "Deploy to prod with just release"
+    actions: [block, audit]
+    generate: "off"
+`), 0o600))
+	require.NoError(t, os.WriteFile(team, []byte(`rules:
+  - id: deploy
+    match: "^deploy"
+    send: "Deploy with just deploy"
+    actions: [block, audit]
+    generate: "off"
+`), 0o600))
+	app := NewAppWithFileSystem(configDir, t.TempDir(), afero.NewMemMapFs())
+
+	result, err := app.ProcessHook(ctx, strings.NewReader(`{"tool_name": "Bash", "tool_input": {"command": "deploy staging"}}`))
+	require.NoError(t, err)
+	assert.Equal(t, "Deploy with just deploy\n\n(bumpers rule 1 \"deploy\" in "+team+")", result.Message)
+
+	result, err = app.ProcessHook(ctx, strings.NewReader(`{"tool_name": "Bash", "tool_input": {"command": "deploy prod"}}`))
+	require.NoError(t, err)
+	assert.Equal(t, "Deploy to prod with just release\n\n(bumpers rule 2 in "+base+")", result.Message)
+
+	records := readAuditLog(t, auditPath)
+	require.Len(t, records, 2)
+	assert.Equal(t, map[string]any{"file": team, "index": float64(1)}, records[0]["source"])
+	assert.Equal(t, map[string]any{"file": base, "index": float64(2)}, records[1]["source"])
+
+	logs := getLogs()
+	assert.Contains(t, logs, `"rule_file":"`+team+`","rule_index":1`)
+	assert.Contains(t, logs, `"rule_file":"`+base+`","rule_index":2`)
+}
+
+func TestProcessHookShadowRules(t *testing.T) {
+	t.Parallel()
+	ctx, getLogs := setupTestWithContext(t)
+
+	auditPath := filepath.Join(t.TempDir(), "audit.jsonl")
+	configPath := createTempConfig(t, `settings:
+  audit_log: "`+auditPath+`"
+rules:
+  - id: trial
+    match:
+      pattern: "^git push"
+      shadow: true
+    send: "Open a PR instead of pushing"
+    generate: "off"
+  - match: "^git push --force"
+    send: "No force pushing"
+    generate: "off"
+  - match:
+      pattern: "FAIL"
+      event: post
+      shadow: true
+    send: "Tests failed"
+    generate: "off"`)
+	app := NewAppWithFileSystem(configPath, t.TempDir(), afero.NewMemMapFs())
+
+	result, err := app.ProcessHook(ctx, strings.NewReader(`{"tool_name": "Bash", "tool_input": {"command": "git push"}}`))
+	require.NoError(t, err)
+	assert.Equal(t, ProcessModeAllow, result.Mode)
+	assert.Empty(t, result.Message)
+	assert.Contains(t, getLogs(), `"shadow":true`)
+
+	result, err = app.ProcessHook(ctx, strings.NewReader(`{"tool_name": "Bash", "tool_input": {"command": "git push --force"}}`))
+	require.NoError(t, err)
+	assert.Equal(t, ProcessModeBlock, result.Mode, "a shadow rule doesn't stop later rules from matching")
+	assert.Equal(t, "No force pushing", result.Message)
+
+	result, err = app.ProcessHook(ctx, strings.NewReader(`{"hook_event_name": "PostToolUse", "tool_name": "Bash", `+
+		`"tool_input": {"command": "go test"}, "tool_response": {"output": "FAIL pkg"}}`))
+	require.NoError(t, err)
+	assert.Empty(t, result.Message)
+
+	records := readAuditLog(t, auditPath)
+	require.Len(t, records, 3, "shadow matches are audited without the audit action")
+	for _, record := range records {
+		assert.Equal(t, true, record["shadow"])
+		assert.Equal(t, false, record["blocked"])
+	}
+	assert.Equal(t, "trial", records[0]["rule_id"])
+	assert.Equal(t, "Open a PR instead of pushing", records[0]["message"])
+	assert.Equal(t, "git push --force", records[1]["value"])
+	assert.Equal(t, "post", records[2]["event"])
+}
+
+func TestProcessHookShadowRulesOnlyObserve(t *testing.T) {
+	t.Parallel()
+	ctx, _ := setupTestWithContext(t)
+
+	var webhookCalls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		webhookCalls.Add(1)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(server.Close)
+
+	auditPath := filepath.Join(t.TempDir(), "audit.jsonl")
+	configPath := createTempConfig(t, `settings:
+  audit_log: "`+auditPath+`"
+  webhook_url: "`+server.URL+`"
+rules:
+  - match:
+      pattern: "^git push"
+      shadow: true
+    send: "Open a PR for {{.Command}}"
+    actions: [block, webhook]
+    generate: always`)
+	app := NewAppWithFileSystem(configPath, t.TempDir(), afero.NewMemMapFs())
+	mockLauncher := claude.SetupMockLauncherWithDefaults()
+	app.SetMockLauncher(mockLauncher)
+
+	result, err := app.ProcessHook(ctx, strings.NewReader(`{"tool_name": "Bash", "tool_input": {"command": "git push"}}`))
+	require.NoError(t, err)
+	assert.Equal(t, ProcessModeAllow, result.Mode)
+
+	assert.Zero(t, mockLauncher.GetCallCount(), "shadow rules never generate messages")
+	assert.Zero(t, webhookCalls.Load(), "shadow rules never run their webhook action")
+	records := readAuditLog(t, auditPath)
+	require.Len(t, records, 1)
+	assert.Equal(t, "Open a PR for git push", records[0]["message"], "the send template is still rendered")
+	assert.Equal(t, true, records[0]["shadow"])
+}
@@ -0,0 +1,172 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wizzomafizzo/bumpers/internal/config"
+)
+
+func TestProcessHookGlobalAllowList(t *testing.T) {
+	t.Parallel()
+	ctx, _ := setupTestWithContext(t)
+
+	projectDir := t.TempDir()
+	configPath := filepath.Join(projectDir, "bumpers.yml")
+	require.NoError(t, os.WriteFile(configPath, []byte(`allow:
+  - "go test ./internal/..."
+  - "{{.ProjectRoot}}/scratch.txt"
+rules:
+  - match: "^go test"
+    send: "Use just test"
+    generate: "off"
+  - match: "scratch|notes"
+    tool: "^Write$"
+    send: "Do not write scratch files"
+    generate: "off"`), 0o600))
+
+	app := NewAppWithWorkDir(configPath, projectDir)
+
+	tests := []struct {
+		name      string
+		input     string
+		wantBlock bool
+	}{
+		{
+			name:  "allowed command",
+			input: `{"tool_name": "Bash", "tool_input": {"command": "go test ./internal/..."}}`,
+		},
+		{
+			name:      "command not on allow list",
+			input:     `{"tool_name": "Bash", "tool_input": {"command": "go test ./..."}}`,
+			wantBlock: true,
+		},
+		{
+			name: "allowed templated path",
+			input: `{"tool_name": "Write", "tool_input": {"file_path": "` +
+				filepath.Join(projectDir, "scratch.txt") + `", "content": "x"}}`,
+		},
+		{
+			name: "path not on allow list",
+			input: `{"tool_name": "Write", "tool_input": {"file_path": "` +
+				filepath.Join(projectDir, "notes.txt") + `", "content": "x"}}`,
+			wantBlock: true,
+		},
+	}
+
+	for _, tt := range tests {
+		result, err := app.ProcessHook(ctx, strings.NewReader(tt.input))
+		require.NoError(t, err, tt.name)
+		if tt.wantBlock {
+			assert.Equal(t, ProcessModeBlock, result.Mode, tt.name)
+		} else {
+			assert.Equal(t, ProcessModeAllow, result.Mode, tt.name)
+		}
+	}
+}
+
+func TestProcessHookToolPolicyDeny(t *testing.T) {
+	t.Parallel()
+	ctx, _ := setupTestWithContext(t)
+
+	projectDir := t.TempDir()
+	configPath := filepath.Join(projectDir, "bumpers.yml")
+	require.NoError(t, os.WriteFile(configPath, []byte(`settings:
+  tool_policy: deny
+  allowed_tools: ["@readonly", "Bash", "mcp__github__.*"]
+rules:
+  - match: "^go test"
+    send: "Use just test"
+    generate: "off"`), 0o600))
+
+	app := NewAppWithWorkDir(configPath, projectDir)
+
+	tests := []struct {
+		name     string
+		input    string
+		wantMode ProcessMode
+	}{
+		{
+			name:     "read-only group",
+			input:    `{"tool_name": "Grep", "tool_input": {"pattern": "TODO"}}`,
+			wantMode: ProcessModeAllow,
+		},
+		{
+			name:     "allowed MCP server",
+			input:    `{"tool_name": "mcp__github__create_issue", "tool_input": {"title": "x"}}`,
+			wantMode: ProcessModeAllow,
+		},
+		{
+			name:     "rules still apply to allowed tools",
+			input:    `{"tool_name": "Bash", "tool_input": {"command": "go test ./..."}}`,
+			wantMode: ProcessModeBlock,
+		},
+		{
+			name:     "unknown MCP tool",
+			input:    `{"tool_name": "mcp__slack__post_message", "tool_input": {"text": "hi"}}`,
+			wantMode: ProcessModeBlock,
+		},
+		{
+			name:     "tool outside the group",
+			input:    `{"tool_name": "Write", "tool_input": {"file_path": "notes.txt", "content": "x"}}`,
+			wantMode: ProcessModeBlock,
+		},
+	}
+
+	for _, tt := range tests {
+		result, err := app.ProcessHook(ctx, strings.NewReader(tt.input))
+		require.NoError(t, err, tt.name)
+		assert.Equal(t, tt.wantMode, result.Mode, tt.name)
+	}
+
+	result, err := app.ProcessHook(ctx, strings.NewReader(
+		`{"tool_name": "mcp__slack__post_message", "tool_input": {"text": "hi"}}`))
+	require.NoError(t, err)
+	assert.Contains(t, result.Message, "Tool 'mcp__slack__post_message' is not allowed")
+	assert.Contains(t, result.Message, "settings.allowed_tools")
+}
+
+func TestProcessHookToolDeniedMessage(t *testing.T) {
+	t.Parallel()
+	ctx, _ := setupTestWithContext(t)
+
+	configPath := createTempConfig(t, `settings:
+  tool_policy: deny
+  tool_denied_message: "{{.ToolName}} needs security review"
+rules:
+  - match: "^go test"
+    send: "Use just test"
+    generate: "off"`)
+
+	result, err := NewApp(ctx, configPath).ProcessHook(ctx, strings.NewReader(
+		`{"tool_name": "WebFetch", "tool_input": {"url": "https://example.com"}}`))
+	require.NoError(t, err)
+	assert.Equal(t, ProcessModeBlock, result.Mode)
+	assert.Equal(t, "WebFetch needs security review", result.Message)
+}
+
+func TestProcessHookProfileRules(t *testing.T) {
+	t.Setenv(config.ProfileEnv, "ci")
+	ctx, _ := setupTestWithContext(t)
+
+	configPath := createTempConfig(t, `rules:
+  - match: "^go test"
+    send: "Use just test"
+    generate: "off"
+profiles:
+  ci:
+    rules:
+      - match: "^git push --force"
+        send: "No force pushes from CI"
+        generate: "off"`)
+
+	result, err := NewApp(ctx, configPath).ProcessHook(ctx, strings.NewReader(
+		`{"tool_name": "Bash", "tool_input": {"command": "git push --force origin main"}}`))
+	require.NoError(t, err)
+	assert.Equal(t, ProcessModeBlock, result.Mode)
+	assert.Contains(t, result.Message, "No force pushes from CI")
+}
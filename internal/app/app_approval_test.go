@@ -0,0 +1,159 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessHookSessionApproval(t *testing.T) {
+	t.Parallel()
+	ctx, _ := setupTestWithContext(t)
+
+	projectDir := t.TempDir()
+	configPath := filepath.Join(projectDir, "bumpers.yml")
+	require.NoError(t, os.WriteFile(configPath, []byte(`rules:
+  - match: "^terraform (apply|destroy)"
+    send: "Check the plan first"
+    approval: session
+    generate: "off"
+`), 0o600))
+	app := NewAppWithFileSystem(configPath, projectDir, afero.NewOsFs())
+
+	tokenPattern := regexp.MustCompile("\\$approve ([0-9a-f]+)`")
+	runBash := func(command string) ProcessResult {
+		result, err := app.ProcessHook(ctx, strings.NewReader(
+			`{"hook_event_name": "PreToolUse", "tool_name": "Bash", "tool_input": {"command": "`+command+`"}}`))
+		require.NoError(t, err)
+		return result
+	}
+	blockedToken := func(command string) string {
+		result := runBash(command)
+		require.Equal(t, ProcessModeBlock, result.Mode)
+		require.Contains(t, result.Message, "Check the plan first")
+		match := tokenPattern.FindStringSubmatch(result.Message)
+		require.NotNil(t, match, "block message should include an approval token: %s", result.Message)
+		return match[1]
+	}
+
+	token := blockedToken("terraform apply")
+	assert.Equal(t, token, blockedToken("terraform apply"), "retries should reuse the pending token")
+
+	_, err := app.Approve(ctx, "wrong")
+	require.ErrorIs(t, err, ErrNoPendingApproval)
+	blockedToken("terraform apply")
+
+	value, err := app.Approve(ctx, token)
+	require.NoError(t, err)
+	assert.Equal(t, "terraform apply", value)
+	assert.Equal(t, ProcessModeAllow, runBash("terraform apply").Mode)
+
+	otherToken := blockedToken("terraform destroy")
+	assert.NotEqual(t, token, otherToken)
+	_, err = app.Approve(ctx, token)
+	require.ErrorIs(t, err, ErrNoPendingApproval, "a granted token can't be reused")
+
+	result, err := app.ProcessHook(ctx, strings.NewReader(
+		`{"hook_event_name": "UserPromptSubmit", "prompt": "$approve `+otherToken+`"}`))
+	require.NoError(t, err)
+	assert.Contains(t, result.Message, "approved `terraform destroy`")
+	assert.Equal(t, ProcessModeAllow, runBash("terraform destroy").Mode)
+
+	_, err = app.ProcessSessionStart(ctx, []byte(`{"hook_event_name": "SessionStart", "source": "startup"}`))
+	require.NoError(t, err)
+	assert.NotEqual(t, token, blockedToken("terraform apply"), "approvals expire with the session")
+	blockedToken("terraform destroy")
+}
+
+func TestProcessHookSessionApprovalIsPerSession(t *testing.T) {
+	t.Parallel()
+	ctx, _ := setupTestWithContext(t)
+
+	projectDir := t.TempDir()
+	configPath := filepath.Join(projectDir, "bumpers.yml")
+	require.NoError(t, os.WriteFile(configPath, []byte(`rules:
+  - match: "^terraform apply"
+    send: "Check the plan first"
+    approval: session
+    generate: "off"
+`), 0o600))
+	app := NewAppWithFileSystem(configPath, projectDir, afero.NewOsFs())
+
+	tokenPattern := regexp.MustCompile("\\$approve ([0-9a-f]+)`")
+	runBash := func(sessionID string) ProcessResult {
+		result, err := app.ProcessHook(ctx, strings.NewReader(`{"hook_event_name": "PreToolUse", "session_id": "`+
+			sessionID+`", "tool_name": "Bash", "tool_input": {"command": "terraform apply"}}`))
+		require.NoError(t, err)
+		return result
+	}
+
+	result := runBash("session-a")
+	require.Equal(t, ProcessModeBlock, result.Mode)
+	match := tokenPattern.FindStringSubmatch(result.Message)
+	require.NotNil(t, match, "block message should include an approval token: %s", result.Message)
+	require.Equal(t, ProcessModeBlock, runBash("session-b").Mode)
+
+	// $approve only grants approvals pending in the prompt's session
+	result, err := app.ProcessHook(ctx, strings.NewReader(
+		`{"hook_event_name": "UserPromptSubmit", "session_id": "session-b", "prompt": "$approve `+match[1]+`"}`))
+	require.NoError(t, err)
+	assert.Contains(t, result.Message, "No pending bumpers approval")
+	result, err = app.ProcessHook(ctx, strings.NewReader(
+		`{"hook_event_name": "UserPromptSubmit", "session_id": "session-a", "prompt": "$approve `+match[1]+`"}`))
+	require.NoError(t, err)
+	assert.Contains(t, result.Message, "approved `terraform apply`")
+
+	assert.Equal(t, ProcessModeAllow, runBash("session-a").Mode)
+	assert.Equal(t, ProcessModeBlock, runBash("session-b").Mode, "approvals don't carry over to other sessions")
+
+	_, err = app.ProcessSessionStart(ctx, []byte(
+		`{"hook_event_name": "SessionStart", "session_id": "session-c", "source": "startup"}`))
+	require.NoError(t, err)
+	assert.Equal(t, ProcessModeAllow, runBash("session-a").Mode, "a new session keeps other sessions' approvals")
+}
+
+func TestProcessHookRefusesSelfApproval(t *testing.T) {
+	t.Parallel()
+	ctx, _ := setupTestWithContext(t)
+
+	projectDir := t.TempDir()
+	configPath := filepath.Join(projectDir, "bumpers.yml")
+	require.NoError(t, os.WriteFile(configPath, []byte(`rules:
+  - match: "^terraform apply"
+    send: "Check the plan first"
+    approval: session
+    generate: "off"
+`), 0o600))
+	app := NewAppWithFileSystem(configPath, projectDir, afero.NewOsFs())
+
+	runBash := func(command string) ProcessResult {
+		result, err := app.ProcessHook(ctx, strings.NewReader(
+			`{"hook_event_name": "PreToolUse", "tool_name": "Bash", "tool_input": {"command": "`+command+`"}}`))
+		require.NoError(t, err)
+		return result
+	}
+
+	blocked := runBash("terraform apply")
+	require.Equal(t, ProcessModeBlock, blocked.Mode)
+	assert.NotContains(t, blocked.Message, "bumpers approve", "the block message shouldn't offer a command to run")
+	token := regexp.MustCompile("\\$approve ([0-9a-f]+)`").FindStringSubmatch(blocked.Message)
+	require.NotNil(t, token, "block message should include an approval token: %s", blocked.Message)
+
+	for _, command := range []string{
+		"bumpers approve " + token[1],
+		"./bin/bumpers --config bumpers.yml approve " + token[1],
+		"cd /tmp && bumpers approve " + token[1],
+	} {
+		result := runBash(command)
+		assert.Equal(t, ProcessModeBlock, result.Mode, command)
+		assert.Contains(t, result.Message, "Approvals must come from the user", command)
+	}
+	assert.Equal(t, ProcessModeBlock, runBash("terraform apply").Mode, "the refused calls didn't approve anything")
+	assert.Equal(t, ProcessModeAllow, runBash("bumpers rules list").Mode)
+}
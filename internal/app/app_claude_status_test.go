@@ -0,0 +1,63 @@
+package app
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wizzomafizzo/bumpers/internal/claude"
+	ai "github.com/wizzomafizzo/bumpers/internal/claude/api"
+)
+
+func TestClaudeStatus(t *testing.T) {
+	ctx, _ := setupTestWithContext(t)
+	t.Setenv(ai.CacheEnv, ai.CacheBackendMemory)
+
+	tests := []struct {
+		result    claude.ProbeResult
+		name      string
+		contains  []string
+		available bool
+	}{
+		{
+			name:      "available",
+			result:    claude.ProbeResult{Status: claude.CLIAvailable, Path: "/bin/claude", Version: "2.0.0"},
+			available: true,
+			contains:  []string{"Claude CLI: AVAILABLE", "Location: /bin/claude", "Version: 2.0.0"},
+		},
+		{
+			name:     "unavailable",
+			result:   claude.ProbeResult{Status: claude.CLINotFound, Detail: "claude binary not found"},
+			contains: []string{"Claude CLI: NOT FOUND", "AI generation is skipped"},
+		},
+		{
+			name:     "auth expired",
+			result:   claude.ProbeResult{Status: claude.CLIAuthExpired, Path: "/bin/claude"},
+			contains: []string{"Claude CLI: AUTH EXPIRED", "bumpers status --refresh"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			projectDir := t.TempDir()
+			app := NewAppWithFileSystem(filepath.Join(projectDir, "bumpers.yml"), projectDir, afero.NewOsFs())
+			app.SetClaudeProber(claude.ProberFunc(func(context.Context) *claude.ProbeResult {
+				result := tt.result
+				result.CheckedAt = time.Now()
+				return &result
+			}))
+
+			result, err := app.ClaudeStatus(ctx, true)
+			require.NoError(t, err)
+			assert.Equal(t, tt.available, result.Available())
+			status := FormatClaudeStatus(result)
+			for _, want := range tt.contains {
+				assert.Contains(t, status, want)
+			}
+		})
+	}
+}
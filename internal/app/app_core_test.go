@@ -12,6 +12,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	apphooks "github.com/wizzomafizzo/bumpers/internal/app/hooks"
+	"github.com/wizzomafizzo/bumpers/internal/config"
 )
 
 const (
@@ -382,3 +383,72 @@ func TestNewApp_InitializesStateManager(t *testing.T) {
 		t.Error("Expected DefaultHookProcessor")
 	}
 }
+
+func TestResolveDefaultConfigMerge(t *testing.T) {
+	t.Parallel()
+
+	projectDir := t.TempDir()
+	yamlPath := filepath.Join(projectDir, "bumpers.yml")
+	jsonPath := filepath.Join(projectDir, "bumpers.json")
+	require.NoError(t, os.WriteFile(yamlPath, []byte(`rules:
+  - match: "^go test"
+    send: "Use just test"
+    generate: "off"`), 0o600))
+	require.NoError(t, os.WriteFile(jsonPath, []byte(
+		`{"rules": [{"match": "dangerous", "send": "This command looks dangerous!", "generate": "off"}]}`), 0o600))
+
+	configPath, primary := resolveDefaultConfig(projectDir, false)
+	assert.Equal(t, yamlPath, configPath, "without merging the first file found is used")
+	assert.Equal(t, yamlPath, primary)
+
+	configPath, primary = resolveDefaultConfig(projectDir, true)
+	assert.Equal(t, yamlPath, primary)
+
+	app := NewAppWithWorkDir(configPath, projectDir)
+	response, err := app.TestCommand(context.Background(), "go test ./...")
+	require.NoError(t, err)
+	assert.Contains(t, response, "Use just test")
+
+	response, err = app.TestCommand(context.Background(), "run dangerous thing")
+	require.NoError(t, err)
+	assert.Contains(t, response, "This command looks dangerous!")
+}
+
+func TestResolveDefaultConfigFromEnv(t *testing.T) { //nolint:paralleltest // t.Setenv() usage
+	t.Setenv("BUMPERS_RULE_1_MATCH", "^go test")
+	t.Setenv("BUMPERS_RULE_1_SEND", "Use just test")
+
+	projectDir := t.TempDir()
+	configPath, primary := resolveDefaultConfig(projectDir, false)
+	assert.Equal(t, config.EnvConfigPath, configPath)
+	assert.Equal(t, filepath.Join(projectDir, "bumpers.yml"), primary)
+
+	app := NewAppWithWorkDir(configPath, projectDir)
+	response, err := app.TestCommand(context.Background(), "go test ./...")
+	require.NoError(t, err)
+	assert.Equal(t, "Use just test", response)
+
+	// A config file still takes precedence over the environment
+	yamlPath := filepath.Join(projectDir, "bumpers.yml")
+	require.NoError(t, os.WriteFile(yamlPath, []byte("rules:\n  - match: make\n    send: Use just\n"), 0o600))
+	configPath, _ = resolveDefaultConfig(projectDir, false)
+	assert.Equal(t, yamlPath, configPath)
+}
+
+func TestStatusDistinguishesMissingAndBrokenConfig(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, "bumpers.yml")
+
+	status, err := NewAppWithWorkDir(configPath, tempDir).Status()
+	require.NoError(t, err)
+	assert.Contains(t, status, "Config file: NOT FOUND")
+
+	require.NoError(t, os.WriteFile(configPath, []byte("rules: [unclosed"), 0o600))
+	status, err = NewAppWithWorkDir(configPath, tempDir).Status()
+	require.NoError(t, err)
+	assert.Contains(t, status, "Config file: ERROR")
+	assert.Contains(t, status, "failed to parse config")
+	assert.Contains(t, status, "Line: 1")
+}
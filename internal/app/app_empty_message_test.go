@@ -0,0 +1,86 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const echoRuleHookInput = `{
+	"tool_input": {
+		"command": "go test ./...",
+		"description": "Run all tests"
+	},
+	"tool_name": "Bash"
+}`
+
+func TestProcessHookEchoMessageFallsBackToGenericBlock(t *testing.T) {
+	t.Parallel()
+	ctx, _ := setupTestWithContext(t)
+
+	configContent := `rules:
+  - match: "go test"
+    send: "{{.Command}}"
+    generate: "off"`
+
+	app := NewApp(ctx, createTempConfig(t, configContent))
+
+	result, err := app.ProcessHook(ctx, strings.NewReader(echoRuleHookInput))
+	require.NoError(t, err)
+	assert.Equal(t, "Blocked by rule 'go test'", result.Message)
+}
+
+func TestProcessHookEmptyMessageFallsBackToGenericBlock(t *testing.T) {
+	t.Parallel()
+	ctx, _ := setupTestWithContext(t)
+
+	configContent := `rules:
+  - match: "go test"
+    send: "{{if false}}never{{end}}"
+    generate: "off"`
+
+	app := NewApp(ctx, createTempConfig(t, configContent))
+
+	result, err := app.ProcessHook(ctx, strings.NewReader(echoRuleHookInput))
+	require.NoError(t, err)
+	assert.Equal(t, "Blocked by rule 'go test'", result.Message)
+}
+
+func TestProcessHookEmptyMessageAllowsWhenConfigured(t *testing.T) {
+	t.Parallel()
+	ctx, _ := setupTestWithContext(t)
+
+	configContent := `settings:
+  on_empty_message: allow
+rules:
+  - match: "go test"
+    send: "{{.Command}}"
+    generate: "off"`
+
+	app := NewApp(ctx, createTempConfig(t, configContent))
+
+	result, err := app.ProcessHook(ctx, strings.NewReader(echoRuleHookInput))
+	require.NoError(t, err)
+	assert.Equal(t, ProcessModeAllow, result.Mode)
+	assert.Empty(t, result.Message)
+}
+
+func TestValidateConfigFlagsEmptyRenderingTemplates(t *testing.T) {
+	t.Parallel()
+
+	configContent := `rules:
+  - match: "go test"
+    send: "{{.Command}}"
+  - match: "rm -rf"
+    send: "Use safer alternatives"`
+
+	validator := NewConfigValidator(createTempConfig(t, configContent), t.TempDir())
+
+	result, err := validator.ValidateConfig()
+	require.NoError(t, err)
+	assert.Contains(t, result, "Template warnings:")
+	assert.Contains(t, result, "Rule 1:")
+	assert.NotContains(t, result, "Rule 2:")
+}
@@ -0,0 +1,68 @@
+package app
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessHookEnvAssignments(t *testing.T) {
+	t.Parallel()
+	ctx, _ := setupTestWithContext(t)
+
+	configPath := createTempConfig(t, `rules:
+  - match:
+      pattern: "^make deploy"
+      strip_env: true
+    send: "Use just deploy"
+    generate: "off"
+  - match:
+      pattern: "^AWS_PROFILE=prod$"
+      sources: ["#env"]
+    send: "Production profile is not allowed"
+    generate: "off"
+  - match: "^npm publish"
+    send: "Publishing is manual"
+    generate: "off"`)
+	app := NewAppWithFileSystem(configPath, t.TempDir(), afero.NewMemMapFs())
+
+	tests := []struct {
+		name    string
+		command string
+		want    string
+	}{
+		{name: "strip_env matches past assignments", command: "FOO=bar make deploy", want: "Use just deploy"},
+		{name: "strip_env still matches plain command", command: "make deploy", want: "Use just deploy"},
+		{name: "env source matches assignment", command: "AWS_PROFILE=prod aws s3 ls", want: "Production profile is not allowed"},
+		{name: "env source matches quoted value", command: `AWS_PROFILE="prod" aws s3 ls`, want: "Production profile is not allowed"},
+		{name: "env source ignores other values", command: "AWS_PROFILE=dev aws s3 ls"},
+		{name: "assignments only", command: "AWS_PROFILE=prod", want: "Production profile is not allowed"},
+		{name: "env not stripped without opt in", command: "CI=1 npm publish"},
+		{name: "env source ignores later assignments", command: "aws s3 ls AWS_PROFILE=prod"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			input, err := json.Marshal(map[string]any{
+				"tool_name":  "Bash",
+				"tool_input": map[string]any{"command": tt.command},
+			})
+			require.NoError(t, err)
+
+			result, err := app.ProcessHook(ctx, strings.NewReader(string(input)))
+			require.NoError(t, err)
+			if tt.want == "" {
+				assert.Equal(t, ProcessModeAllow, result.Mode)
+				return
+			}
+			assert.Equal(t, ProcessModeBlock, result.Mode)
+			assert.Equal(t, tt.want, result.Message)
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apphooks "github.com/wizzomafizzo/bumpers/internal/app/hooks"
+)
+
+// writeEnvCaptureScript writes a script that records its working directory and BUMPERS_*
+// environment to outputPath
+func writeEnvCaptureScript(t *testing.T, outputPath string) string {
+	t.Helper()
+	scriptPath := filepath.Join(t.TempDir(), "capture.sh")
+	script := fmt.Sprintf("#!/bin/sh\n{ pwd; env | grep '^BUMPERS_' | sort; } > %q.tmp && mv %q.tmp %q\n",
+		outputPath, outputPath, outputPath)
+	require.NoError(t, os.WriteFile(scriptPath, []byte(script), 0o600))
+	return scriptPath
+}
+
+func TestProcessHookRunsRuleExec(t *testing.T) { //nolint:paralleltest // sets BUMPERS_ALLOW_EXEC
+	t.Setenv(apphooks.AllowExecEnv, "1")
+	ctx, _ := setupTestWithContext(t)
+
+	projectRoot := t.TempDir()
+	outputPath := filepath.Join(t.TempDir(), "env.txt")
+	scriptPath := writeEnvCaptureScript(t, outputPath)
+
+	configPath := createTempConfig(t, fmt.Sprintf(`rules:
+  - id: env-guard
+    match: "\\.env$"
+    tool: "^(Write|Edit)$"
+    send: "Don't edit .env files"
+    exec: "sh %s"
+    generate: "off"`, scriptPath))
+	app := NewAppWithFileSystem(configPath, projectRoot, afero.NewMemMapFs())
+
+	input := `{"tool_name": "Edit", "tool_input": {"file_path": "/repo/.env", "new_string": "X=1"}}`
+	result, err := app.ProcessHook(ctx, strings.NewReader(input))
+	require.NoError(t, err)
+	assert.Equal(t, ProcessModeBlock, result.Mode)
+	assert.Equal(t, "Don't edit .env files", result.Message)
+
+	var output []byte
+	require.Eventually(t, func() bool {
+		output, err = os.ReadFile(outputPath) // #nosec G304 -- test output path
+		return err == nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	resolvedRoot, err := filepath.EvalSymlinks(projectRoot)
+	require.NoError(t, err)
+	assert.Equal(t, resolvedRoot, lines[0])
+	assert.Equal(t, []string{
+		"BUMPERS_ALLOW_EXEC=1",
+		"BUMPERS_EVENT=pre",
+		"BUMPERS_MATCHED_VALUE=/repo/.env",
+		"BUMPERS_RULE_NAME=env-guard",
+	}, lines[1:])
+}
+
+func TestProcessHookSkipsRuleExecWithoutAllowExec(t *testing.T) { //nolint:paralleltest // sets BUMPERS_ALLOW_EXEC
+	t.Setenv(apphooks.AllowExecEnv, "")
+	ctx, getLogs := setupTestWithContext(t)
+
+	outputPath := filepath.Join(t.TempDir(), "env.txt")
+	scriptPath := writeEnvCaptureScript(t, outputPath)
+
+	// The project config can't enable exec
+	configPath := createTempConfig(t, fmt.Sprintf(`settings:
+  allow_exec: true
+rules:
+  - match: "^go test"
+    send: "Use just test"
+    exec: "sh %s"
+    generate: "off"`, scriptPath))
+	app := NewAppWithFileSystem(configPath, t.TempDir(), afero.NewMemMapFs())
+
+	input := `{"tool_name": "Bash", "tool_input": {"command": "go test ./..."}}`
+	result, err := app.ProcessHook(ctx, strings.NewReader(input))
+	require.NoError(t, err)
+	assert.Equal(t, ProcessModeBlock, result.Mode)
+
+	assert.Contains(t, getLogs(), "rule exec skipped, BUMPERS_ALLOW_EXEC is not set to 1")
+	_, err = os.Stat(outputPath)
+	assert.True(t, os.IsNotExist(err), "exec should not run without BUMPERS_ALLOW_EXEC")
+}
+
+func TestProcessHookLogsFailedRuleExec(t *testing.T) { //nolint:paralleltest // sets BUMPERS_ALLOW_EXEC
+	t.Setenv(apphooks.AllowExecEnv, "1")
+	ctx, getLogs := setupTestWithContext(t)
+
+	configPath := createTempConfig(t, `rules:
+  - match: "^go test"
+    send: "Use just test"
+    exec: "exit 3"
+    generate: "off"`)
+	app := NewAppWithFileSystem(configPath, t.TempDir(), afero.NewMemMapFs())
+
+	input := `{"tool_name": "Bash", "tool_input": {"command": "go test ./..."}}`
+	result, err := app.ProcessHook(ctx, strings.NewReader(input))
+	require.NoError(t, err)
+	assert.Equal(t, ProcessModeBlock, result.Mode)
+	assert.Equal(t, "Use just test", result.Message)
+
+	require.Eventually(t, func() bool {
+		return strings.Contains(getLogs(), "rule exec failed")
+	}, 5*time.Second, 10*time.Millisecond)
+}
@@ -5,6 +5,7 @@ import (
 
 	apphooks "github.com/wizzomafizzo/bumpers/internal/app/hooks"
 	apptypes "github.com/wizzomafizzo/bumpers/internal/app/types"
+	"github.com/wizzomafizzo/bumpers/internal/project"
 	"github.com/wizzomafizzo/bumpers/internal/storage"
 )
 
@@ -18,11 +19,12 @@ func NewAppFactory() *AppFactory {
 
 // AppComponents holds all the specialized components needed by App
 type AppComponents struct {
-	ConfigValidator apptypes.ConfigValidator
-	HookProcessor   apphooks.HookProcessor
-	PromptHandler   PromptHandler
-	SessionManager  SessionManager
-	InstallManager  InstallManager
+	ConfigValidator     apptypes.ConfigValidator
+	HookProcessor       apphooks.HookProcessor
+	PromptHandler       PromptHandler
+	SessionManager      SessionManager
+	InstallManager      InstallManager
+	NotificationHandler NotificationHandler
 }
 
 // CreateApp creates a new App instance using the factory pattern
@@ -32,30 +34,44 @@ func (*AppFactory) CreateApp(configPath string) *App {
 	return NewApp(ctx, configPath)
 }
 
-// CreateComponents creates all the specialized components needed by App
+// CreateComponents creates all the specialized components needed by App, loading config
+// from configPath, with installConfigPath the single file install and status work with
 func (*AppFactory) CreateComponents(
-	configPath, projectRoot string,
+	configPath, installConfigPath, projectRoot string,
 	stateManager *storage.StateManager,
 ) AppComponents {
 	configValidator := NewConfigValidator(configPath, projectRoot)
 	return AppComponents{
-		ConfigValidator: configValidator,
-		HookProcessor:   apphooks.NewHookProcessor(configValidator, projectRoot, stateManager),
-		PromptHandler:   NewPromptHandler(configPath, projectRoot, stateManager),
-		SessionManager:  NewSessionManager(configPath, projectRoot, nil),
-		InstallManager:  NewInstallManager(configPath, "", projectRoot, nil),
+		ConfigValidator:     configValidator,
+		HookProcessor:       apphooks.NewHookProcessor(configValidator, projectRoot, stateManager),
+		PromptHandler:       NewPromptHandler(configPath, projectRoot, stateManager),
+		SessionManager:      NewSessionManager(configPath, projectRoot, nil, stateManager),
+		InstallManager:      NewInstallManager(installConfigPath, "", projectRoot, nil),
+		NotificationHandler: NewNotificationHandler(configPath),
 	}
 }
 
 // CreateAppWithComponentFactory creates a new App using the component factory pattern
-func (f *AppFactory) CreateAppWithComponentFactory(_ context.Context, configPath string) *App {
-	components := f.CreateComponents(configPath, "", nil)
+func (f *AppFactory) CreateAppWithComponentFactory(ctx context.Context, configPath string) *App {
+	// State such as the operation mode is stored per project, degrading to none without a root
+	projectRoot, err := project.FindRoot()
+	if err != nil {
+		projectRoot = ""
+	}
+	resolvedConfigPath, installConfigPath := resolveConfigPath(projectRoot, configPath)
+	dbManager, stateManager := createDatabaseAndStateManager(ctx, projectRoot)
+
+	components := f.CreateComponents(resolvedConfigPath, installConfigPath, projectRoot, stateManager)
 	return &App{
-		hookProcessor:   components.HookProcessor,
-		promptHandler:   components.PromptHandler,
-		sessionManager:  components.SessionManager,
-		configValidator: components.ConfigValidator,
-		installManager:  components.InstallManager,
-		configPath:      configPath,
+		hookProcessor:       components.HookProcessor,
+		promptHandler:       components.PromptHandler,
+		sessionManager:      components.SessionManager,
+		configValidator:     components.ConfigValidator,
+		installManager:      components.InstallManager,
+		notificationHandler: components.NotificationHandler,
+		dbManager:           dbManager,
+		stateManager:        stateManager,
+		configPath:          resolvedConfigPath,
+		projectRoot:         projectRoot,
 	}
 }
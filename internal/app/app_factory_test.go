@@ -2,9 +2,13 @@ package app
 
 import (
 	"context"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 const testConfigYml = "test-config.yml"
@@ -56,7 +60,7 @@ func TestAppFactory_CreateComponents_ShouldReturnComponentsStruct(t *testing.T)
 	projectRoot := "/test/root"
 
 	// When
-	components := factory.CreateComponents(configPath, projectRoot, nil)
+	components := factory.CreateComponents(configPath, configPath, projectRoot, nil)
 
 	// Then
 	assert.NotNil(t, components)
@@ -114,3 +118,64 @@ func TestAppFactory_CreateAppWithComponentFactory_ShouldSetConfigPath(t *testing
 	assert.NotNil(t, app)
 	assert.NotEmpty(t, app.configPath, "configPath should be set on the app")
 }
+
+func TestAppFactory_CreateAppWithComponentFactory_ShouldUseProjectRoot(t *testing.T) {
+	// Not parallel: sets CLAUDE_PROJECT_DIR
+	ctx, _ := setupTestWithContext(t)
+
+	projectDir := t.TempDir()
+	t.Setenv("CLAUDE_PROJECT_DIR", projectDir)
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, ".bumpersignore"), []byte("vendor/\n"), 0o600))
+	configPath := filepath.Join(projectDir, "bumpers.yml")
+	require.NoError(t, os.WriteFile(configPath, []byte(`rules:
+  - match: "^{{.ProjectRoot}}/"
+    tool: "^Write$"
+    send: "Ask before writing project files"
+    generate: "off"`), 0o600))
+
+	app := NewAppFactory().CreateAppWithComponentFactory(ctx, configPath)
+
+	write := func(filePath string) ProcessResult {
+		result, err := app.ProcessHook(ctx, strings.NewReader(`{"tool_name": "Write", "tool_input": {"file_path": "`+
+			filePath+`", "content": "// TODO fix"}}`))
+		require.NoError(t, err)
+		return result
+	}
+
+	assert.Equal(t, ProcessModeAllow, write(filepath.Join(projectDir, "vendor", "lib.go")).Mode,
+		".bumpersignore in the project root should be loaded")
+	result := write(filepath.Join(projectDir, "internal", "app.go"))
+	assert.Equal(t, ProcessModeBlock, result.Mode, "{{.ProjectRoot}} should expand to the project root")
+	assert.Contains(t, result.Message, "Ask before writing project files")
+}
+
+func TestAppFactory_CreateAppWithComponentFactory_ShouldResolveDefaultConfig(t *testing.T) {
+	// Not parallel: sets CLAUDE_PROJECT_DIR and config environment variables
+	ctx := context.Background()
+
+	projectDir := t.TempDir()
+	t.Setenv("CLAUDE_PROJECT_DIR", projectDir)
+	t.Setenv("BUMPERS_RULE_1_MATCH", "^go test")
+	t.Setenv("BUMPERS_RULE_1_SEND", "Use just test")
+
+	app := NewAppFactory().CreateAppWithComponentFactory(ctx, "bumpers.yml")
+	response, err := app.TestCommand(ctx, "go test ./...")
+	require.NoError(t, err)
+	assert.Equal(t, "Use just test", response, "without a config file the environment should be used")
+
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, "bumpers.yml"), []byte(`rules:
+  - match: "^make"
+    send: "Use just"
+    generate: "off"`), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, "bumpers.json"), []byte(
+		`{"rules": [{"match": "dangerous", "send": "This command looks dangerous!", "generate": "off"}]}`), 0o600))
+	t.Setenv(ConfigMergeEnv, "1")
+
+	app = NewAppFactory().CreateAppWithComponentFactory(ctx, "bumpers.yml")
+	response, err = app.TestCommand(ctx, "make build")
+	require.NoError(t, err)
+	assert.Equal(t, "Use just", response)
+	response, err = app.TestCommand(ctx, "run dangerous thing")
+	require.NoError(t, err)
+	assert.Equal(t, "This command looks dangerous!", response, "BUMPERS_CONFIG_MERGE should merge bumpers.json")
+}
@@ -0,0 +1,211 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wizzomafizzo/bumpers/internal/config"
+	"github.com/wizzomafizzo/bumpers/internal/constants"
+)
+
+// failingLauncher simulates Claude being unavailable
+type failingLauncher struct{}
+
+func (failingLauncher) GenerateMessage(_ context.Context, _ string) (string, error) {
+	return "", errors.New("claude unavailable")
+}
+
+func TestRuleFallbackMessageOnGenerationFailure(t *testing.T) {
+	t.Parallel()
+	ctx, _ := setupTestWithContext(t)
+
+	configPath := createTempConfig(t, `rules:
+  - match: "^go test"
+    send: "Use just test"
+    generate:
+      mode: "always"
+      fallback_message: "AI unavailable, {{.Command}} is blocked: use just test"
+  - match: "^make"
+    send: "Use just instead of make"
+    generate: "always"`)
+	app := NewAppWithFileSystem(configPath, t.TempDir(), afero.NewMemMapFs())
+	app.SetMockLauncher(failingLauncher{})
+
+	tests := []struct {
+		command string
+		want    string
+	}{
+		{"go test ./...", "AI unavailable, go test ./... is blocked: use just test"},
+		{"make build", "Use just instead of make"},
+	}
+
+	for _, tt := range tests {
+		input := `{"tool_name": "Bash", "tool_input": {"command": "` + tt.command + `"}}`
+		result, err := app.ProcessHook(ctx, strings.NewReader(input))
+		require.NoError(t, err, tt.command)
+		assert.Equal(t, ProcessModeBlock, result.Mode, tt.command)
+		assert.Equal(t, tt.want, result.Message, tt.command)
+	}
+}
+
+func TestCommandFallbackMessageOnGenerationFailure(t *testing.T) {
+	t.Parallel()
+
+	configPath := createTempConfig(t, `commands:
+  - name: "help"
+    send: "Basic help message"
+    generate:
+      mode: "always"
+      fallback_message: "Help for {{.Name}} (AI unavailable)"`)
+	app := NewAppWithFileSystem(configPath, t.TempDir(), afero.NewMemMapFs())
+	app.SetMockLauncher(failingLauncher{})
+
+	promptHandler, ok := app.promptHandler.(*DefaultPromptHandler)
+	require.True(t, ok, "expected DefaultPromptHandler")
+	promptHandler.aiHelper.cachePath = filepath.Join(t.TempDir(), "ai_test.db")
+
+	promptJSON := `{"prompt": "` + constants.CommandPrefix + `help"}`
+	result, err := app.ProcessUserPrompt(context.Background(), json.RawMessage(promptJSON))
+	require.NoError(t, err)
+	assert.Contains(t, result, "Help for help (AI unavailable)")
+	assert.NotContains(t, result, "Basic help message")
+}
+
+func TestRuleOnErrorModes(t *testing.T) {
+	t.Parallel()
+	ctx, _ := setupTestWithContext(t)
+
+	tests := []struct {
+		name     string
+		generate string
+		want     string
+	}{
+		{
+			name:     "default falls back to send",
+			generate: `"always"`,
+			want:     "Use just test",
+		},
+		{
+			name:     "fallback",
+			generate: `{mode: "always", on_error: "fallback"}`,
+			want:     "Use just test",
+		},
+		{
+			name:     "message uses default notice",
+			generate: `{mode: "always", on_error: "message"}`,
+			want:     config.DefaultAIErrorMessage,
+		},
+		{
+			name:     "message uses configured notice",
+			generate: `{mode: "always", on_error: "message", error_message: "Claude is offline"}`,
+			want:     "Claude is offline",
+		},
+		{
+			name:     "append adds default suffix",
+			generate: `{mode: "always", on_error: "append"}`,
+			want:     "Use just test " + config.DefaultAIErrorSuffix,
+		},
+		{
+			name:     "append adds suffix to fallback_message",
+			generate: `{mode: "always", on_error: "append", fallback_message: "Run just test", error_message: "[no AI]"}`,
+			want:     "Run just test [no AI]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			configPath := createTempConfig(t, `rules:
+  - match: "^go test"
+    send: "Use just test"
+    generate: `+tt.generate)
+			app := NewAppWithFileSystem(configPath, t.TempDir(), afero.NewMemMapFs())
+			app.SetMockLauncher(failingLauncher{})
+
+			input := `{"tool_name": "Bash", "tool_input": {"command": "go test ./..."}}`
+			result, err := app.ProcessHook(ctx, strings.NewReader(input))
+			require.NoError(t, err)
+			assert.Equal(t, ProcessModeBlock, result.Mode)
+			assert.Equal(t, tt.want, result.Message)
+		})
+	}
+}
+
+func TestCommandOnErrorAppend(t *testing.T) {
+	t.Parallel()
+
+	configPath := createTempConfig(t, `commands:
+  - name: "help"
+    send: "Basic help message"
+    generate:
+      mode: "always"
+      on_error: "append"`)
+	app := NewAppWithFileSystem(configPath, t.TempDir(), afero.NewMemMapFs())
+	app.SetMockLauncher(failingLauncher{})
+
+	promptHandler, ok := app.promptHandler.(*DefaultPromptHandler)
+	require.True(t, ok, "expected DefaultPromptHandler")
+	promptHandler.aiHelper.cachePath = filepath.Join(t.TempDir(), "ai_test.db")
+
+	promptJSON := `{"prompt": "` + constants.CommandPrefix + `help"}`
+	result, err := app.ProcessUserPrompt(context.Background(), json.RawMessage(promptJSON))
+	require.NoError(t, err)
+	assert.Contains(t, result, "Basic help message "+config.DefaultAIErrorSuffix)
+}
+
+// stalledLauncher simulates Claude hanging until the hook's context ends
+type stalledLauncher struct{}
+
+func (stalledLauncher) GenerateMessage(ctx context.Context, _ string) (string, error) {
+	<-ctx.Done()
+	return "", ctx.Err()
+}
+
+func TestRuleHookTimeoutSkipsGeneration(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		settings string
+		override time.Duration
+	}{
+		{name: "settings.hook_timeout", settings: "settings:\n  hook_timeout: 50ms\n"},
+		{name: "--timeout override", settings: "settings:\n  hook_timeout: 1h\n", override: 50 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx, getLogs := setupTestWithContext(t)
+
+			configPath := createTempConfig(t, tt.settings+`rules:
+  - match: "^go test"
+    send: "Use just test"
+    generate:
+      mode: "always"
+      fallback_message: "AI unavailable"
+      on_error: "append"`)
+			app := NewAppWithFileSystem(configPath, t.TempDir(), afero.NewMemMapFs())
+			app.SetMockLauncher(stalledLauncher{})
+			app.SetHookTimeout(tt.override)
+
+			input := `{"tool_name": "Bash", "tool_input": {"command": "go test ./..."}}`
+			start := time.Now()
+			result, err := app.ProcessHook(ctx, strings.NewReader(input))
+			require.NoError(t, err)
+			assert.Less(t, time.Since(start), 10*time.Second)
+			assert.Equal(t, ProcessModeBlock, result.Mode)
+			assert.Equal(t, "Use just test", result.Message)
+			assert.Contains(t, getLogs(), "hook processing exceeded its timeout")
+		})
+	}
+}
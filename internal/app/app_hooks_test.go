@@ -3,6 +3,8 @@ package app
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -286,8 +288,12 @@ func TestProcessHookLogsErrors(t *testing.T) {
 	t.Parallel()
 	tempDir := t.TempDir()
 
-	// Use non-existent config path to trigger error
-	app := NewAppWithWorkDir("non-existent-config.yml", tempDir)
+	// Use an unparseable config to trigger error (a missing config allows instead)
+	configPath := filepath.Join(tempDir, "bumpers.yml")
+	if err := os.WriteFile(configPath, []byte("rules: [unclosed"), 0o600); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	app := NewAppWithWorkDir(configPath, tempDir)
 
 	// Create logger for the app
 	var err error
@@ -302,11 +308,11 @@ func TestProcessHookLogsErrors(t *testing.T) {
 	// This should trigger an error (logging is a side effect we can't easily test with global logger)
 	result, err := app.ProcessHook(ctx, strings.NewReader(hookInput))
 	if err == nil {
-		t.Fatalf("Expected ProcessHook to return error for non-existent config, got result: %s", result)
+		t.Fatalf("Expected ProcessHook to return error for invalid config, got result: %s", result)
 	}
 
 	// Verify the error is related to config loading
-	if !strings.Contains(err.Error(), "config") && !strings.Contains(err.Error(), "no such file") {
+	if !strings.Contains(err.Error(), "config") {
 		t.Errorf("Expected config-related error, got: %v", err)
 	}
 }
@@ -0,0 +1,54 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessHookSkipsBumpersIgnoredPaths(t *testing.T) {
+	t.Parallel()
+	ctx, _ := setupTestWithContext(t)
+
+	projectDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, ".bumpersignore"), []byte("vendor/\n"), 0o600))
+
+	configPath := filepath.Join(projectDir, "bumpers.yml")
+	require.NoError(t, os.WriteFile(configPath, []byte(`rules:
+  - match: "TODO"
+    tool: "^Write$"
+    send: "Do not leave TODOs"
+    generate: "off"`), 0o600))
+
+	app := NewAppWithWorkDir(configPath, projectDir)
+
+	tests := []struct {
+		name      string
+		filePath  string
+		wantBlock bool
+	}{
+		{"ignored path bypasses rules", filepath.Join(projectDir, "vendor", "lib.go"), false},
+		{"non-ignored path is checked", filepath.Join(projectDir, "internal", "app.go"), true},
+	}
+
+	for _, tt := range tests {
+		hookInput := `{
+			"tool_name": "Write",
+			"tool_input": {"file_path": "` + tt.filePath + `", "content": "// TODO fix"}
+		}`
+
+		result, err := app.ProcessHook(ctx, strings.NewReader(hookInput))
+		require.NoError(t, err, tt.name)
+		if tt.wantBlock {
+			assert.Equal(t, ProcessModeBlock, result.Mode, tt.name)
+			assert.Contains(t, result.Message, "Do not leave TODOs", tt.name)
+		} else {
+			assert.Equal(t, ProcessModeAllow, result.Mode, tt.name)
+			assert.Empty(t, result.Message, tt.name)
+		}
+	}
+}
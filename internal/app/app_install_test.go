@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/spf13/afero"
+	"github.com/wizzomafizzo/bumpers/internal/claude/settings"
 	"github.com/wizzomafizzo/bumpers/internal/constants"
 )
 
@@ -541,3 +542,133 @@ func TestInstallPreservesExistingHooks(t *testing.T) {
 		t.Error("Expected bumpers hook to be added")
 	}
 }
+
+func TestInstallReplacesStaleBumpersHooks(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, "bumpers.yml")
+
+	app := NewAppWithWorkDir(configPath, tempDir)
+
+	claudeDir := filepath.Join(tempDir, ".claude")
+	if err := os.MkdirAll(claudeDir, 0o750); err != nil {
+		t.Fatal(err)
+	}
+
+	// A hook left behind by a bumpers binary that has since moved
+	settingsPath := filepath.Join(claudeDir, "settings.local.json")
+	existingSettings := `{
+		"hooks": {
+			"PreToolUse": [
+				{
+					"matcher": "",
+					"hooks": [
+						{"type": "command", "command": "tdd-guard-go"},
+						{"type": "command", "command": "/old/path/bin/bumpers hook"}
+					]
+				}
+			],
+			"SessionStart": [
+				{
+					"matcher": "startup",
+					"hooks": [{"type": "command", "command": "/old/path/bin/bumpers hook"}]
+				}
+			]
+		}
+	}`
+	if err := os.WriteFile(settingsPath, []byte(existingSettings), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := app.Initialize(); err != nil {
+		t.Fatalf("Initialize failed: %v", err)
+	}
+
+	installed, err := settings.LoadFromFile(settingsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newCommand := filepath.Join(tempDir, "bin", "bumpers") + " hook"
+	preToolUse := installed.Hooks.PreToolUse
+	if len(preToolUse) != 1 || len(preToolUse[0].Hooks) != 2 {
+		t.Fatalf("Expected one PreToolUse matcher with two hooks, got %+v", preToolUse)
+	}
+	if preToolUse[0].Hooks[0].Command != "tdd-guard-go" {
+		t.Errorf("Expected tdd-guard-go hook to be preserved, got %+v", preToolUse[0].Hooks)
+	}
+	if preToolUse[0].Hooks[1].Command != newCommand {
+		t.Errorf("Expected stale bumpers hook updated to %q, got %q", newCommand, preToolUse[0].Hooks[1].Command)
+	}
+
+	sessionStart := installed.Hooks.SessionStart
+	if len(sessionStart) != 1 || len(sessionStart[0].Hooks) != 1 || sessionStart[0].Hooks[0].Command != newCommand {
+		t.Errorf("Expected a single updated SessionStart hook, got %+v", sessionStart)
+	}
+
+	content, err := os.ReadFile(settingsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(content), "/old/path") {
+		t.Errorf("Expected stale bumpers hooks to be removed, got %s", content)
+	}
+}
+
+func TestInstallNotificationHookRequiresSetting(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		configContent string
+		expectHook    bool
+	}{
+		{
+			name: "disabled by default",
+			configContent: `rules:
+  - match: "go test"
+    send: "Use just test"`,
+			expectHook: false,
+		},
+		{
+			name: "enabled via settings",
+			configContent: `settings:
+  notification_hook: true
+notifications:
+  - match: "permission"
+    send: "Remember: use just, not make"`,
+			expectHook: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			tempDir := t.TempDir()
+			configPath := filepath.Join(tempDir, "bumpers.yml")
+			fs := afero.NewMemMapFs()
+			err := afero.WriteFile(fs, configPath, []byte(tt.configContent), 0o600)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			app := NewAppWithFileSystem(configPath, tempDir, fs)
+			if err := app.Initialize(); err != nil {
+				t.Fatalf("Initialize failed: %v", err)
+			}
+
+			settingsPath := filepath.Join(tempDir, ".claude", "settings.local.json")
+			content, err := afero.ReadFile(fs, settingsPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			hasHook := strings.Contains(string(content), `"Notification"`)
+			if hasHook != tt.expectHook {
+				t.Errorf("Expected Notification hook installed = %v, got %v", tt.expectHook, hasHook)
+			}
+		})
+	}
+}
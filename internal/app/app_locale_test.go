@@ -0,0 +1,71 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wizzomafizzo/bumpers/internal/claude"
+)
+
+func TestProcessHookLocalizedSend(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		locale   string
+		expected string
+	}{
+		{name: "configured locale", locale: "ja", expected: "go test ./... ではなく just test を使ってください"},
+		{name: "falls back to en", locale: "fr", expected: "Use just test instead of go test ./..."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx, _ := setupTestWithContext(t)
+
+			configPath := createTempConfig(t, `settings:
+  locale: `+tt.locale+`
+rules:
+  - match: "^go test"
+    send:
+      en: "Use just test instead of {{.Command}}"
+      ja: "{{.Command}} ではなく just test を使ってください"
+    generate: "off"`)
+			app := NewApp(ctx, configPath)
+
+			result, err := app.ProcessHook(ctx, strings.NewReader(
+				`{"hook_event_name": "PreToolUse", "tool_name": "Bash", "tool_input": {"command": "go test ./..."}}`))
+			require.NoError(t, err)
+			assert.Equal(t, ProcessModeBlock, result.Mode)
+			assert.Equal(t, tt.expected, result.Message)
+		})
+	}
+}
+
+func TestProcessUserPromptLocalizedCommandGeneration(t *testing.T) {
+	t.Parallel()
+	ctx, _ := setupTestWithContext(t)
+
+	configPath := createTempConfig(t, `settings:
+  locale: ja
+commands:
+  - name: plan
+    send:
+      en: "Plan {{argv 1}} first"
+      ja: "まず{{argv 1}}を計画してください"
+    generate: always`)
+	app := NewApp(ctx, configPath)
+	mock := claude.NewMockLauncher()
+	mock.SetResponseForPattern(".*", "生成されたメッセージ")
+	app.SetMockLauncher(mock)
+
+	result, err := app.ProcessUserPrompt(ctx, []byte(`{"prompt": "$plan 移行"}`))
+	require.NoError(t, err)
+	assert.Contains(t, result, "生成されたメッセージ")
+	require.Len(t, mock.Calls, 1)
+	assert.Contains(t, mock.Calls[0].Prompt, "まず移行を計画してください")
+	assert.Contains(t, mock.Calls[0].Prompt, `locale "ja"`)
+}
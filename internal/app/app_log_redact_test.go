@@ -0,0 +1,213 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const logSecret = "hunter2secret"
+
+func TestProcessHookRedactsLoggedValues(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		config string
+		input  string
+		mode   ProcessMode
+		// want is a string the logs must contain in place of the secret
+		want string
+	}{
+		{
+			name: "pre rule redact",
+			config: `rules:
+  - match: "^deploy"
+    send: "Use just deploy"
+    log: redact
+    generate: "off"`,
+			input: `{"tool_name": "Bash", "tool_input": {"command": "deploy --token=` + logSecret + `"}}`,
+			mode:  ProcessModeBlock,
+			want:  "[sha256:",
+		},
+		{
+			name: "pre rule off",
+			config: `rules:
+  - match: "^deploy"
+    send: "Use just deploy"
+    log: "off"
+    generate: "off"`,
+			input: `{"tool_name": "Bash", "tool_input": {"command": "deploy --token=` + logSecret + `"}}`,
+			mode:  ProcessModeBlock,
+			want:  "Hook processing summary",
+		},
+		{
+			name: "exception",
+			config: `rules:
+  - match: "^deploy"
+    except: ["deploy --token=.*"]
+    send: "Use just deploy"
+    log: redact
+    generate: "off"`,
+			input: `{"tool_name": "Bash", "tool_input": {"command": "deploy --token=` + logSecret + `"}}`,
+			mode:  ProcessModeAllow,
+			want:  "suppressed by an except entry",
+		},
+		{
+			name: "empty message",
+			config: `rules:
+  - match: "^deploy"
+    send: "{{.Command}}"
+    log: redact
+    generate: "off"`,
+			input: `{"tool_name": "Bash", "tool_input": {"command": "deploy --token=` + logSecret + `"}}`,
+			mode:  ProcessModeBlock,
+			want:  "echoes the matched value",
+		},
+		{
+			name: "global pattern without a matching rule",
+			config: `settings:
+  log_redact_patterns: ["token=\\S+"]
+rules:
+  - match: "^rm -rf"
+    send: "No"
+    generate: "off"`,
+			input: `{"tool_name": "Bash", "tool_input": {"command": "deploy --token=` + logSecret + `"}}`,
+			mode:  ProcessModeAllow,
+			want:  "deploy --[REDACTED]",
+		},
+		{
+			name: "redact key on a matched command",
+			config: `settings:
+  redact: ["password", "token", "secret"]
+rules:
+  - match: "^deploy"
+    send: "Use just deploy"
+    generate: "off"`,
+			input: `{"tool_name": "Bash", "tool_input": {"command": "deploy --token=` + logSecret + `"}}`,
+			mode:  ProcessModeBlock,
+			want:  "deploy --token=[REDACTED]",
+		},
+		{
+			name: "redact key followed by a space",
+			config: `settings:
+  redact: ["Password"]
+rules:
+  - match: "^mysql"
+    send: "Use the read replica"
+    generate: "off"`,
+			input: `{"tool_name": "Bash", "tool_input": {"command": "mysql --user root --password ` + logSecret + `"}}`,
+			mode:  ProcessModeBlock,
+			want:  "--password [REDACTED]",
+		},
+		{
+			name: "global pattern on the allow list",
+			config: `settings:
+  log_redact_patterns: ["token=\\S+"]
+allow:
+  - "deploy --token=` + logSecret + `"
+rules:
+  - match: "^deploy"
+    send: "Use just deploy"
+    generate: "off"`,
+			input: `{"tool_name": "Bash", "tool_input": {"command": "deploy --token=` + logSecret + `"}}`,
+			mode:  ProcessModeAllow,
+			want:  "on the allow list",
+		},
+		{
+			name: "post rule off",
+			config: `rules:
+  - match:
+      pattern: "leaked"
+      event: post
+    send: "Rotate the token"
+    log: "off"
+    generate: "off"`,
+			input: `{"hook_event_name": "PostToolUse", "tool_name": "Bash", ` +
+				`"tool_input": {"command": "env"}, "tool_response": {"output": "leaked ` + logSecret + `"}}`,
+			mode: ProcessModeBlock,
+			want: "Hook processing summary",
+		},
+		{
+			name: "post rule redact",
+			config: `rules:
+  - match:
+      pattern: "leaked"
+      event: post
+    send: "Rotate the token"
+    log: redact
+    generate: "off"`,
+			input: `{"hook_event_name": "PostToolUse", "tool_name": "Bash", ` +
+				`"tool_input": {"command": "env"}, "tool_response": {"output": "leaked ` + logSecret + `"}}`,
+			mode: ProcessModeBlock,
+			want: "[sha256:",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx, getLogs := setupTestWithContext(t)
+			app := NewAppWithFileSystem(createTempConfig(t, tt.config), t.TempDir(), afero.NewMemMapFs())
+
+			result, err := app.ProcessHook(ctx, strings.NewReader(tt.input))
+			require.NoError(t, err)
+			assert.Equal(t, tt.mode, result.Mode)
+
+			logs := getLogs()
+			assert.Contains(t, logs, tt.want)
+			assert.NotContains(t, logs, logSecret)
+		})
+	}
+}
+
+func TestProcessHookLogsFullValuesByDefault(t *testing.T) {
+	t.Parallel()
+	ctx, getLogs := setupTestWithContext(t)
+
+	configPath := createTempConfig(t, `rules:
+  - match: "^deploy"
+    send: "Use just deploy"
+    generate: "off"`)
+	app := NewAppWithFileSystem(configPath, t.TempDir(), afero.NewMemMapFs())
+
+	input := `{"tool_name": "Bash", "tool_input": {"command": "deploy --token=` + logSecret + `"}}`
+	_, err := app.ProcessHook(ctx, strings.NewReader(input))
+	require.NoError(t, err)
+	assert.Contains(t, getLogs(), logSecret)
+}
+
+func TestProcessHookRedactsLoggedIntent(t *testing.T) {
+	t.Parallel()
+	ctx, getLogs := setupTestWithContext(t)
+
+	transcriptPath := filepath.Join(t.TempDir(), "transcript.jsonl")
+	transcript := `{"type":"assistant","uuid":"a1","message":{"content":[` +
+		`{"type":"text","text":"Using token ` + logSecret + ` to deploy"}]}}
+{"type":"assistant","uuid":"a2","parentUuid":"a1","message":{"content":[` +
+		`{"type":"tool_use","id":"tool1","name":"Bash","input":{"command":"deploy"}}]}}
+`
+	require.NoError(t, os.WriteFile(transcriptPath, []byte(transcript), 0o600))
+
+	configPath := createTempConfig(t, `rules:
+  - match:
+      pattern: "Using token"
+      event: post
+      sources: ["#intent"]
+    send: "Don't put tokens in messages"
+    log: "off"
+    generate: "off"`)
+	app := NewAppWithFileSystem(configPath, t.TempDir(), afero.NewMemMapFs())
+
+	input := `{"hook_event_name": "PostToolUse", "tool_name": "Bash", "tool_use_id": "tool1", ` +
+		`"transcript_path": "` + transcriptPath + `", "tool_input": {"command": "deploy"}, "tool_response": "ok"}`
+	result, err := app.ProcessHook(ctx, strings.NewReader(input))
+	require.NoError(t, err)
+	assert.Equal(t, "Don't put tokens in messages", result.Message)
+	assert.NotContains(t, getLogs(), logSecret)
+}
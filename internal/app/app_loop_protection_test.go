@@ -0,0 +1,69 @@
+package app
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessHookLoopProtection(t *testing.T) {
+	t.Parallel()
+
+	const rules = `rules:
+  - match: "^make deploy"
+    send: "Deploy failed checks, run make verify first"
+    generate: "off"
+  - match:
+      pattern: "error|failed"
+      event: post
+      sources: ["tool_response"]
+    send: "The tool reported an error"
+    generate: "off"
+`
+	tests := []struct {
+		name     string
+		settings string
+		wantPost ProcessMode
+	}{
+		{name: "default skips own message", wantPost: ProcessModeAllow},
+		{name: "disabled matches own message", settings: "settings:\n  loop_protection: false\n", wantPost: ProcessModeBlock},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx, _ := setupTestWithContext(t)
+
+			projectDir := t.TempDir()
+			configPath := filepath.Join(projectDir, "bumpers.yml")
+			require.NoError(t, os.WriteFile(configPath, []byte(tt.settings+rules), 0o600))
+			app := NewAppWithFileSystem(configPath, projectDir, afero.NewOsFs())
+
+			pre, err := app.ProcessHook(ctx, strings.NewReader(`{"session_id": "loop", "hook_event_name": "PreToolUse", `+
+				`"tool_name": "Bash", "tool_input": {"command": "make deploy"}}`))
+			require.NoError(t, err)
+			require.Equal(t, ProcessModeBlock, pre.Mode)
+
+			// A command printing the deny message, e.g. from a log, repeats bumpers' own words
+			output, err := json.Marshal("deploy.log: " + pre.Message)
+			require.NoError(t, err)
+			post, err := app.ProcessHook(ctx, strings.NewReader(`{"session_id": "loop", "hook_event_name": "PostToolUse", `+
+				`"tool_name": "Bash", "tool_response": `+string(output)+`}`))
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantPost, post.Mode)
+
+			// Other output in the session is still matched
+			other, err := app.ProcessHook(ctx, strings.NewReader(`{"session_id": "loop", "hook_event_name": "PostToolUse", `+
+				`"tool_name": "Bash", "tool_response": "build failed"}`))
+			require.NoError(t, err)
+			assert.Equal(t, ProcessModeBlock, other.Mode)
+			assert.Equal(t, "The tool reported an error", other.Message)
+		})
+	}
+}
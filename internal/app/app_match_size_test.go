@@ -0,0 +1,81 @@
+package app
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeHookInput(t *testing.T, content string) string {
+	t.Helper()
+	input, err := json.Marshal(map[string]any{
+		"tool_name":  "Write",
+		"tool_input": map[string]any{"file_path": "/tmp/big.go", "content": content},
+	})
+	require.NoError(t, err)
+	return string(input)
+}
+
+func TestProcessHookLargeContentMatchesAnchors(t *testing.T) {
+	t.Parallel()
+	ctx, _ := setupTestWithContext(t)
+
+	configContent := `settings:
+  max_match_bytes: 256
+  max_display_bytes: 64
+rules:
+  - match: "^package main"
+    tool: "^Write$"
+    send: "Head matched: {{.Command}}"
+    generate: "off"
+  - match: "// END$"
+    tool: "^Write$"
+    send: "Tail matched"
+    generate: "off"
+  - match: "SECRET"
+    tool: "^Write$"
+    send: "Secret found"
+    generate: "off"`
+	app := NewApp(ctx, createTempConfig(t, configContent))
+
+	content := "package main\n" + strings.Repeat("x", 4096)
+	result, err := app.ProcessHook(ctx, strings.NewReader(writeHookInput(t, content)))
+	require.NoError(t, err)
+	assert.Contains(t, result.Message, "Head matched: package main")
+	assert.Less(t, len(result.Message), 200, "display value should be capped")
+
+	content = strings.Repeat("y", 4096) + "\n// END"
+	result, err = app.ProcessHook(ctx, strings.NewReader(writeHookInput(t, content)))
+	require.NoError(t, err)
+	assert.Equal(t, "Tail matched", result.Message)
+
+	// Content hidden in the truncated middle is not matched
+	content = strings.Repeat("z", 2048) + "SECRET" + strings.Repeat("z", 2048)
+	result, err = app.ProcessHook(ctx, strings.NewReader(writeHookInput(t, content)))
+	require.NoError(t, err)
+	assert.Equal(t, ProcessModeAllow, result.Mode)
+}
+
+func TestProcessHookDisplayValueUntruncatedUnderCap(t *testing.T) {
+	t.Parallel()
+	ctx, _ := setupTestWithContext(t)
+
+	configContent := `settings:
+  max_match_bytes: 64
+rules:
+  - match: "^echo"
+    send: "Got: {{.Command}}"
+    generate: "off"`
+	app := NewApp(ctx, createTempConfig(t, configContent))
+
+	command := "echo " + strings.Repeat("a", 200)
+	input, err := json.Marshal(map[string]any{"tool_name": "Bash", "tool_input": map[string]any{"command": command}})
+	require.NoError(t, err)
+
+	result, err := app.ProcessHook(ctx, strings.NewReader(string(input)))
+	require.NoError(t, err)
+	assert.Equal(t, "Got: "+command, result.Message)
+}
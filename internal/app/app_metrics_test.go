@@ -0,0 +1,103 @@
+package app
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wizzomafizzo/bumpers/internal/metrics"
+)
+
+// hookMetricsLine is the "hook metrics" log line written by ProcessHook
+type hookMetricsLine struct {
+	Stages              map[string]float64 `json:"stages"`
+	Decision            string             `json:"decision"`
+	Message             string             `json:"message"`
+	Total               float64            `json:"total"`
+	RulesEvaluated      int64              `json:"rules_evaluated"`
+	TranscriptBytesRead int64              `json:"transcript_bytes_read"`
+}
+
+// findHookMetrics returns the hook metrics lines from the log output
+func findHookMetrics(t *testing.T, logOutput string) []hookMetricsLine {
+	t.Helper()
+	var lines []hookMetricsLine
+	for _, raw := range strings.Split(strings.TrimSpace(logOutput), "\n") {
+		if !strings.Contains(raw, `"message":"hook metrics"`) {
+			continue
+		}
+		var line hookMetricsLine
+		require.NoError(t, json.Unmarshal([]byte(raw), &line))
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func TestProcessHookLogsStageMetrics(t *testing.T) {
+	t.Parallel()
+	ctx, getLogs := setupTestWithContext(t)
+
+	transcriptPath := filepath.Join(t.TempDir(), "transcript.jsonl")
+	transcript := `{"type":"assistant","message":{"content":[{"type":"text","text":"Running the tests now"}]}}` + "\n"
+	require.NoError(t, os.WriteFile(transcriptPath, []byte(transcript), 0o600))
+
+	configPath := createTempConfig(t, `rules:
+  - match: "^make"
+    send: "Use just"
+    generate: "off"
+  - match: "^go test"
+    send: "Use just test for {{.Command}}"
+    generate: "off"`)
+	app := NewAppWithFileSystem(configPath, t.TempDir(), afero.NewMemMapFs())
+
+	input := `{"tool_name": "Bash", "transcript_path": "` + transcriptPath + `", ` +
+		`"tool_input": {"command": "go test ./..."}}`
+	result, err := app.ProcessHook(ctx, strings.NewReader(input))
+	require.NoError(t, err)
+	require.Equal(t, ProcessModeBlock, result.Mode)
+
+	lines := findHookMetrics(t, getLogs())
+	require.Len(t, lines, 1, "expected exactly one metrics line per hook")
+	line := lines[0]
+
+	assert.Equal(t, string(ProcessModeBlock), line.Decision)
+	assert.Equal(t, int64(2), line.RulesEvaluated)
+	assert.Equal(t, int64(len(transcript)), line.TranscriptBytesRead)
+
+	var sum float64
+	for _, stage := range []string{
+		metrics.StageDetect, metrics.StageState, metrics.StageConfigLoad,
+		metrics.StageIntent, metrics.StageMatch, metrics.StageTemplate,
+	} {
+		require.Contains(t, line.Stages, stage)
+		sum += line.Stages[stage]
+	}
+	assert.NotContains(t, line.Stages, metrics.StageAIGeneration, "generation is off")
+	assert.Positive(t, line.Total)
+	assert.LessOrEqual(t, sum, line.Total, "stages must not overlap")
+}
+
+func TestProcessHookMetricsAllowDecision(t *testing.T) {
+	t.Parallel()
+	ctx, getLogs := setupTestWithContext(t)
+
+	configPath := createTempConfig(t, `rules:
+  - match: "^make"
+    send: "Use just"
+    generate: "off"`)
+	app := NewAppWithFileSystem(configPath, t.TempDir(), afero.NewMemMapFs())
+
+	_, err := app.ProcessHook(ctx, strings.NewReader(`{"tool_name": "Bash", "tool_input": {"command": "ls"}}`))
+	require.NoError(t, err)
+
+	lines := findHookMetrics(t, getLogs())
+	require.Len(t, lines, 1)
+	assert.Equal(t, string(ProcessModeAllow), lines[0].Decision)
+	assert.Equal(t, int64(1), lines[0].RulesEvaluated)
+	assert.Zero(t, lines[0].TranscriptBytesRead)
+}
@@ -0,0 +1,146 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wizzomafizzo/bumpers/internal/version"
+)
+
+const missingConfigWarning = "config file not found, allowing tool calls until it is restored"
+
+func TestProcessHookAllowsWhenConfigDeleted(t *testing.T) {
+	t.Parallel()
+	ctx, getLogs := setupTestWithContext(t)
+
+	configPath := createTempConfig(t, `rules:
+  - match: "^go test"
+    send: "Use just test"
+    generate: "off"`)
+	app := NewAppWithFileSystem(configPath, t.TempDir(), afero.NewMemMapFs())
+
+	preInput := `{"session_id": "s1", "tool_name": "Bash", "tool_input": {"command": "go test ./..."}}`
+	result, err := app.ProcessHook(ctx, strings.NewReader(preInput))
+	require.NoError(t, err)
+	assert.Equal(t, ProcessModeBlock, result.Mode)
+
+	require.NoError(t, os.Remove(configPath))
+
+	for range 2 {
+		result, err = app.ProcessHook(ctx, strings.NewReader(preInput))
+		require.NoError(t, err)
+		assert.Equal(t, ProcessModeAllow, result.Mode)
+	}
+
+	postInput := `{"session_id": "s1", "hook_event_name": "PostToolUse", "tool_name": "Bash", ` +
+		`"tool_input": {"command": "go test ./..."}, "tool_response": "FAIL"}`
+	result, err = app.ProcessHook(ctx, strings.NewReader(postInput))
+	require.NoError(t, err)
+	assert.Equal(t, ProcessModeAllow, result.Mode)
+
+	assert.Equal(t, 1, strings.Count(getLogs(), missingConfigWarning), "warning should be logged once per session")
+
+	// A new session warns again
+	_, err = app.ProcessHook(ctx, strings.NewReader(strings.Replace(preInput, "s1", "s2", 1)))
+	require.NoError(t, err)
+	assert.Equal(t, 2, strings.Count(getLogs(), missingConfigWarning))
+}
+
+func TestProcessHookPromptAndNotificationAllowWithoutConfig(t *testing.T) {
+	t.Parallel()
+	ctx, _ := setupTestWithContext(t)
+
+	configPath := filepath.Join(t.TempDir(), "bumpers.yml")
+	app := NewAppWithFileSystem(configPath, t.TempDir(), afero.NewMemMapFs())
+
+	for _, input := range []string{
+		`{"session_id": "s1", "hook_event_name": "UserPromptSubmit", "prompt": "$test"}`,
+		`{"session_id": "s1", "hook_event_name": "Notification", "message": "Claude needs your permission"}`,
+	} {
+		result, err := app.ProcessHook(ctx, strings.NewReader(input))
+		require.NoError(t, err, input)
+		assert.Equal(t, ProcessModeAllow, result.Mode, input)
+		assert.Empty(t, result.Message, input)
+	}
+}
+
+func TestProcessHookConfigErrorsStillFail(t *testing.T) {
+	t.Parallel()
+	ctx, _ := setupTestWithContext(t)
+
+	preInput := `{"session_id": "s1", "tool_name": "Bash", "tool_input": {"command": "go test ./..."}}`
+
+	t.Run("parse failure", func(t *testing.T) {
+		t.Parallel()
+		configPath := createTempConfig(t, "rules: [unclosed")
+		app := NewAppWithFileSystem(configPath, t.TempDir(), afero.NewMemMapFs())
+
+		_, err := app.ProcessHook(ctx, strings.NewReader(preInput))
+		require.Error(t, err)
+	})
+
+	t.Run("permission denied", func(t *testing.T) {
+		t.Parallel()
+		if os.Geteuid() == 0 {
+			t.Skip("file permissions are not enforced for root")
+		}
+		configPath := createTempConfig(t, `rules:
+  - match: "^go test"
+    send: "Use just test"`)
+		require.NoError(t, os.Chmod(configPath, 0o000))
+		app := NewAppWithFileSystem(configPath, t.TempDir(), afero.NewMemMapFs())
+
+		_, err := app.ProcessHook(ctx, strings.NewReader(preInput))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "permission denied")
+	})
+
+	t.Run("unreadable path", func(t *testing.T) {
+		t.Parallel()
+		// A path under a regular file fails with ENOTDIR rather than ENOENT
+		configPath := filepath.Join(createTempConfig(t, "rules: []"), "bumpers.yml")
+		app := NewAppWithFileSystem(configPath, t.TempDir(), afero.NewMemMapFs())
+
+		_, err := app.ProcessHook(ctx, strings.NewReader(preInput))
+		require.Error(t, err)
+	})
+}
+
+func TestProcessHookWarnsUnsatisfiedVersion(t *testing.T) { //nolint:paralleltest // sets the global build version
+	ctx, getLogs := setupTestWithContext(t)
+
+	previous := version.Version
+	version.Version = "1.0.0"
+	t.Cleanup(func() { version.Version = previous })
+
+	configPath := createTempConfig(t, `settings:
+  required_version: ">=1.2.0"
+rules:
+  - match: "^go test"
+    send: "Use just test"
+    generate: "off"`)
+	app := NewAppWithFileSystem(configPath, t.TempDir(), afero.NewMemMapFs())
+
+	const warning = "bumpers version does not satisfy settings.required_version"
+	preInput := `{"session_id": "s1", "tool_name": "Bash", "tool_input": {"command": "go test ./..."}}`
+	for range 2 {
+		result, err := app.ProcessHook(ctx, strings.NewReader(preInput))
+		require.NoError(t, err)
+		assert.Equal(t, ProcessModeBlock, result.Mode, "the warning doesn't change the decision")
+	}
+	assert.Equal(t, 1, strings.Count(getLogs(), warning), "warning should be logged once per session")
+
+	_, err := app.ProcessHook(ctx, strings.NewReader(strings.Replace(preInput, "s1", "s2", 1)))
+	require.NoError(t, err)
+	assert.Equal(t, 2, strings.Count(getLogs(), warning))
+
+	version.Version = "1.2.0"
+	_, err = app.ProcessHook(ctx, strings.NewReader(strings.Replace(preInput, "s1", "s3", 1)))
+	require.NoError(t, err)
+	assert.Equal(t, 2, strings.Count(getLogs(), warning))
+}
@@ -0,0 +1,66 @@
+package app
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const notificationConfig = `notifications:
+  - match: "permission to use Bash"
+    send: "Remember: use just, not make"
+  - match: "waiting for your input"
+    send: "Idle: {{.Message}}"`
+
+func TestProcessHookRoutesNotification(t *testing.T) {
+	t.Parallel()
+	ctx, getLogs := setupTestWithContext(t)
+
+	configPath := createTempConfig(t, notificationConfig)
+	app := NewApp(ctx, configPath)
+
+	input := `{
+		"session_id": "abc123",
+		"hook_event_name": "Notification",
+		"message": "Claude needs your permission to use Bash"
+	}`
+
+	result, err := app.ProcessHook(ctx, strings.NewReader(input))
+	require.NoError(t, err)
+
+	assert.Equal(t, ProcessModeInformational, result.Mode)
+	assert.JSONEq(t, `{"systemMessage":"Remember: use just, not make"}`, result.Message)
+	assert.Contains(t, getLogs(), "processing Notification hook")
+}
+
+func TestProcessNotificationWithTemplate(t *testing.T) {
+	t.Parallel()
+	ctx, _ := setupTestWithContext(t)
+
+	configPath := createTempConfig(t, notificationConfig)
+	app := NewApp(ctx, configPath)
+
+	input := `{"hook_event_name": "Notification", "message": "Claude is waiting for your input"}`
+
+	result, err := app.ProcessNotification(ctx, []byte(input))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"systemMessage":"Idle: Claude is waiting for your input"}`, result)
+}
+
+func TestProcessNotificationNoMatchAllows(t *testing.T) {
+	t.Parallel()
+	ctx, _ := setupTestWithContext(t)
+
+	configPath := createTempConfig(t, notificationConfig)
+	app := NewApp(ctx, configPath)
+
+	input := `{"hook_event_name": "Notification", "message": "Claude needs your permission to use Write"}`
+
+	result, err := app.ProcessHook(context.Background(), strings.NewReader(input))
+	require.NoError(t, err)
+	assert.Equal(t, ProcessModeAllow, result.Mode)
+	assert.Empty(t, result.Message)
+}
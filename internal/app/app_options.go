@@ -45,18 +45,20 @@ func NewAppWithOptions(ctx context.Context, opts AppOptions) (*App, error) {
 
 	hookProcessor := apphooks.NewHookProcessor(configValidator, projectRoot, stateManager)
 	promptHandler := NewPromptHandler(opts.ConfigPath, projectRoot, stateManager)
-	sessionManager := NewSessionManager(opts.ConfigPath, projectRoot, nil)
+	sessionManager := NewSessionManager(opts.ConfigPath, projectRoot, nil, stateManager)
 	installManager := NewInstallManager(opts.ConfigPath, opts.WorkDir, projectRoot, nil)
 
 	return &App{
-		hookProcessor:   hookProcessor,
-		promptHandler:   promptHandler,
-		sessionManager:  sessionManager,
-		configValidator: configValidator,
-		installManager:  installManager,
-		dbManager:       dbManager,
-		configPath:      opts.ConfigPath,
-		workDir:         opts.WorkDir,
-		projectRoot:     projectRoot,
+		hookProcessor:       hookProcessor,
+		promptHandler:       promptHandler,
+		sessionManager:      sessionManager,
+		configValidator:     configValidator,
+		installManager:      installManager,
+		notificationHandler: NewNotificationHandler(opts.ConfigPath),
+		dbManager:           dbManager,
+		stateManager:        stateManager,
+		configPath:          opts.ConfigPath,
+		workDir:             opts.WorkDir,
+		projectRoot:         projectRoot,
 	}, nil
 }
@@ -667,3 +667,38 @@ func createToolUseTestScenario(t *testing.T, transcriptContent, configContent st
 
 	return transcriptPath, app
 }
+
+func TestPostToolUseExceptAndArgLimits(t *testing.T) {
+	t.Parallel()
+	ctx, _ := setupTestWithContext(t)
+
+	configPath := createTempConfig(t, `rules:
+  - match:
+      pattern: "FAIL"
+      event: post
+      sources: ["output"]
+    except: ["FAIL flaky_test"]
+    send: "Tests failed"
+    generate: "off"
+  - match:
+      pattern: "panic:"
+      event: post
+      sources: ["stderr"]
+      max_args: 2
+    send: "Short panic"
+    generate: "off"`)
+	app := NewApp(ctx, configPath)
+
+	process := func(field, output string) string {
+		t.Helper()
+		result, err := app.ProcessPostToolUse(ctx, json.RawMessage(`{"hook_event_name": "PostToolUse", `+
+			`"tool_name": "Bash", "tool_response": {"`+field+`": "`+output+`"}}`))
+		require.NoError(t, err)
+		return result
+	}
+
+	assert.Equal(t, "Tests failed", process("output", "FAIL pkg"))
+	assert.Empty(t, process("output", "FAIL flaky_test"), "except entries apply to post rules")
+	assert.Equal(t, "Short panic", process("stderr", "panic: nil map"))
+	assert.Empty(t, process("stderr", "panic: runtime error: index out of range"), "max_args applies to post rules")
+}
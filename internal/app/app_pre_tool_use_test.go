@@ -2,12 +2,14 @@ package app
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/wizzomafizzo/bumpers/internal/claude"
@@ -396,3 +398,349 @@ func TestExtractAndLogIntent_ReturnsIntentContent(t *testing.T) {
 
 	_ = getLogOutput // Suppress unused variable warning
 }
+
+func TestPreToolUseSendTemplateToolNameAndMatchedField(t *testing.T) {
+	t.Parallel()
+	ctx, _ := setupTestWithContext(t)
+
+	configPath := createTempConfig(t, `rules:
+  - match: "\\.env$"
+    tool: "^(Write|Edit)$"
+    send: "The {{.ToolName}} tool should not touch .env files ({{.MatchedField}}: {{.Command}})"
+    generate: "off"
+  - match:
+      pattern: "^rm"
+      sources: ["command"]
+    send: "{{.ToolName}}/{{.MatchedField}}"
+    generate: "off"`)
+	app := NewAppWithWorkDir(configPath, t.TempDir())
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{
+			input: `{"tool_name": "Write", "tool_input": {"file_path": "/app/.env", "content": "X=1"}}`,
+			want:  "The Write tool should not touch .env files (file_path: /app/.env)",
+		},
+		{
+			input: `{"tool_name": "Bash", "tool_input": {"command": "rm -rf build"}}`,
+			want:  "Bash/command",
+		},
+	}
+
+	for _, tt := range tests {
+		result, err := app.ProcessHook(ctx, strings.NewReader(tt.input))
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, result.Message)
+	}
+}
+
+func TestProcessHookListFormSend(t *testing.T) {
+	t.Parallel()
+	ctx, _ := setupTestWithContext(t)
+
+	configPath := createTempConfig(t, `rules:
+  - match: "^go test"
+    send:
+      - "Don't run {{.Command}} directly."
+      - "Use just test instead."
+    generate: "off"`)
+	app := NewApp(ctx, configPath)
+
+	input := `{"tool_name": "Bash", "tool_input": {"command": "go test ./..."}}`
+	result, err := app.ProcessHook(ctx, strings.NewReader(input))
+	require.NoError(t, err)
+	assert.Equal(t, ProcessModeBlock, result.Mode)
+	assert.Equal(t, "Don't run go test ./... directly.\nUse just test instead.", result.Message)
+}
+
+func TestProcessHookSourceFieldRegex(t *testing.T) {
+	t.Parallel()
+	ctx, _ := setupTestWithContext(t)
+
+	configPath := createTempConfig(t, `rules:
+  - match:
+      pattern: "\\.env$"
+      source_field_regex: ".*_path$"
+    tool: ".*"
+    send: "Matched {{.MatchedField}}"
+    generate: "off"`)
+	app := NewApp(ctx, configPath)
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{
+			input: `{"tool_name": "Edit", "tool_input": {"file_path": "/repo/.env", "new_string": "x"}}`,
+			want:  "Matched file_path",
+		},
+		{
+			input: `{"tool_name": "Move", "tool_input": {"old_path": "/repo/a.txt", "new_path": "/repo/.env"}}`,
+			want:  "Matched new_path",
+		},
+		{
+			// Only keys are selected by the regex, so a matching value elsewhere is ignored
+			input: `{"tool_name": "Bash", "tool_input": {"command": "cat /repo/.env"}}`,
+			want:  "",
+		},
+	}
+
+	for _, tt := range tests {
+		result, err := app.ProcessHook(ctx, strings.NewReader(tt.input))
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, result.Message)
+	}
+}
+
+func TestProcessHookMatchFields(t *testing.T) {
+	t.Parallel()
+	ctx, _ := setupTestWithContext(t)
+
+	configPath := createTempConfig(t, `rules:
+  - match:
+      pattern: "^prod$"
+      fields:
+        force: true
+        replicas: 3
+    tool: "^Deploy$"
+    send: "Don't force deploys to prod"
+    generate: "off"`)
+	app := NewApp(ctx, configPath)
+
+	tests := []struct {
+		name  string
+		input string
+		want  ProcessMode
+	}{
+		{
+			name:  "matching fields",
+			input: `{"tool_name": "Deploy", "tool_input": {"target": "prod", "force": true, "replicas": 3}}`,
+			want:  ProcessModeBlock,
+		},
+		{
+			name:  "false boolean",
+			input: `{"tool_name": "Deploy", "tool_input": {"target": "prod", "force": false, "replicas": 3}}`,
+			want:  ProcessModeAllow,
+		},
+		{
+			name:  "string isn't a boolean lookalike",
+			input: `{"tool_name": "Deploy", "tool_input": {"target": "prod", "force": "yes", "replicas": 3}}`,
+			want:  ProcessModeAllow,
+		},
+		{
+			name:  "missing field",
+			input: `{"tool_name": "Deploy", "tool_input": {"target": "prod", "replicas": 3}}`,
+			want:  ProcessModeAllow,
+		},
+		{
+			name:  "pattern still applies",
+			input: `{"tool_name": "Deploy", "tool_input": {"target": "staging", "force": true, "replicas": 3.0}}`,
+			want:  ProcessModeAllow,
+		},
+	}
+
+	for _, tt := range tests {
+		result, err := app.ProcessHook(ctx, strings.NewReader(tt.input))
+		require.NoError(t, err, tt.name)
+		assert.Equal(t, tt.want, result.Mode, tt.name)
+	}
+}
+
+func TestProcessHookBatchedToolCalls(t *testing.T) {
+	t.Parallel()
+	ctx, getLogs := setupTestWithContext(t)
+
+	configPath := createTempConfig(t, `rules:
+  - match: "^make"
+    send: "Use just"
+    generate: "off"
+  - match: "^go test"
+    send: "Use just test for {{.Command}}"
+    generate: "off"`)
+	app := NewApp(ctx, configPath)
+
+	input := `{"hook_event_name": "PreToolUse", "session_id": "s1", "tool_calls": [` +
+		`{"tool_name": "Bash", "tool_use_id": "call_blocked", "tool_input": {"command": "go test ./..."}},` +
+		`{"tool_name": "Bash", "tool_use_id": "call_allowed", "tool_input": {"command": "ls -la"}}]}`
+	result, err := app.ProcessHook(ctx, strings.NewReader(input))
+	require.NoError(t, err)
+	assert.Equal(t, ProcessModeInformational, result.Mode)
+
+	var response struct {
+		HookSpecificOutput struct {
+			ToolCalls map[string]struct {
+				PermissionDecision       string `json:"permissionDecision"`
+				PermissionDecisionReason string `json:"permissionDecisionReason"`
+			} `json:"toolCalls"`
+			HookEventName string `json:"hookEventName"`
+		} `json:"hookSpecificOutput"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(result.Message), &response))
+	output := response.HookSpecificOutput
+	assert.Equal(t, "PreToolUse", output.HookEventName)
+	require.Len(t, output.ToolCalls, 1, "calls no rule blocked get no decision")
+	assert.Equal(t, "deny", output.ToolCalls["call_blocked"].PermissionDecision)
+	assert.Equal(t, "Use just test for go test ./...", output.ToolCalls["call_blocked"].PermissionDecisionReason)
+	assert.NotContains(t, output.ToolCalls, "call_allowed")
+
+	// Each call runs through the rule pipeline once: two rules checked per call
+	lines := findHookMetrics(t, getLogs())
+	require.Len(t, lines, 1)
+	assert.Equal(t, int64(4), lines[0].RulesEvaluated)
+}
+
+func TestProcessHookBatchedToolCallsNoneBlocked(t *testing.T) {
+	t.Parallel()
+	ctx, _ := setupTestWithContext(t)
+
+	configPath := createTempConfig(t, `rules:
+  - match: "^make"
+    send: "Use just"
+    generate: "off"`)
+	app := NewApp(ctx, configPath)
+
+	input := `{"hook_event_name": "PreToolUse", "tool_calls": [` +
+		`{"tool_name": "Bash", "tool_use_id": "call_1", "tool_input": {"command": "ls"}},` +
+		`{"tool_name": "Bash", "tool_use_id": "call_2", "tool_input": {"command": "pwd"}}]}`
+	result, err := app.ProcessHook(ctx, strings.NewReader(input))
+	require.NoError(t, err)
+	assert.Equal(t, ProcessModeAllow, result.Mode, "a batch with nothing blocked gives no opinion")
+	assert.Empty(t, result.Message)
+}
+
+func TestPreToolUseIntentScanLines(t *testing.T) {
+	t.Parallel()
+
+	filler := strings.Repeat(`{"type":"system","content":"compacting"}`+"\n", 5)
+	transcriptPath := createTempTranscript(t, `{"type":"assistant","message":{"content":[`+
+		`{"type":"text","text":"Time to deploy to production"}]}}`+"\n"+filler)
+
+	tests := []struct {
+		name     string
+		settings string
+		expected string
+	}{
+		{name: "unbounded", expected: "Deploys need a review"},
+		{name: "old intent beyond the limit", settings: "settings:\n  intent_scan_lines: 3\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx, _ := setupTestWithContext(t)
+
+			configPath := createTempConfig(t, tt.settings+`rules:
+  - match:
+      pattern: "deploy to production"
+      sources: ["#intent"]
+      max_intent_depth: 0
+    send: "Deploys need a review"
+    generate: "off"`)
+			app := NewApp(ctx, configPath)
+
+			result, err := app.ProcessHook(ctx, strings.NewReader(fmt.Sprintf(`{
+				"transcript_path": "%s",
+				"tool_name": "Bash",
+				"tool_input": {"command": "make release"}
+			}`, transcriptPath)))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result.Message)
+		})
+	}
+}
+
+func TestPreToolUsePrevOutputSource(t *testing.T) {
+	t.Parallel()
+	ctx, _ := setupTestWithContext(t)
+
+	configPath := createTempConfig(t, `rules:
+  - match:
+      pattern: "^--- FAIL"
+      sources: ["#prev_output"]
+    tool: "^Bash$"
+    send: "The last test run failed, fix it before committing"
+    generate: "off"`)
+	app := NewApp(ctx, configPath)
+
+	transcriptPath := createTempTranscript(t, `{"type":"assistant","uuid":"a1","message":{"content":[`+
+		`{"type":"tool_use","id":"tool1","name":"Bash","input":{"command":"go test ./..."}}]}}
+{"type":"user","uuid":"u1","parentUuid":"a1","message":{"content":[`+
+		`{"type":"tool_result","tool_use_id":"tool1","content":"--- FAIL: TestParse (0.00s)\nFAIL"}]}}
+`)
+	input := fmt.Sprintf(`{
+		"hook_event_name": "PreToolUse",
+		"transcript_path": %q,
+		"tool_name": "Bash",
+		"tool_input": {"command": "git commit -m wip"}
+	}`, transcriptPath)
+
+	result, err := app.ProcessHook(ctx, strings.NewReader(input))
+	require.NoError(t, err)
+	assert.Equal(t, ProcessModeBlock, result.Mode)
+	assert.Contains(t, result.Message, "The last test run failed")
+
+	passingPath := createTempTranscript(t, `{"type":"user","uuid":"u1","message":{"content":[`+
+		`{"type":"tool_result","tool_use_id":"tool1","content":"ok  \tgithub.com/x/y"}]}}
+`)
+	input = fmt.Sprintf(`{
+		"hook_event_name": "PreToolUse",
+		"transcript_path": %q,
+		"tool_name": "Bash",
+		"tool_input": {"command": "git commit -m wip"}
+	}`, passingPath)
+
+	result, err = app.ProcessHook(ctx, strings.NewReader(input))
+	require.NoError(t, err)
+	assert.Equal(t, ProcessModeAllow, result.Mode, "a passing previous run doesn't block")
+}
+
+func TestProcessHookSessionIDTemplate(t *testing.T) {
+	t.Parallel()
+	ctx, _ := setupTestWithContext(t)
+
+	projectDir := t.TempDir()
+	configPath := filepath.Join(projectDir, "bumpers.yml")
+	require.NoError(t, os.WriteFile(configPath, []byte(`rules:
+  - match: "^rm -rf"
+    send: "Session {{.SessionIDShort}}: this command was blocked"
+    generate: "off"
+  - match:
+      pattern: "FAILED"
+      event: post
+      sources: ["output"]
+    tool: "^Bash$"
+    send: "Tests failed in {{.SessionID}}"
+    generate: "off"
+`), 0o600))
+	app := NewAppWithFileSystem(configPath, projectDir, afero.NewOsFs())
+
+	result, err := app.ProcessHook(ctx, strings.NewReader(`{"hook_event_name": "PreToolUse", `+
+		`"session_id": "4f6c2a1e-93b7-4d8e-a0f5-2c1b9e7d3a60", "tool_name": "Bash", "tool_input": {"command": "rm -rf /"}}`))
+	require.NoError(t, err)
+	assert.Equal(t, ProcessModeBlock, result.Mode)
+	assert.Contains(t, result.Message, "Session 4f6c2a1e: this command was blocked")
+
+	result, err = app.ProcessHook(ctx, strings.NewReader(`{"hook_event_name": "PostToolUse", `+
+		`"session_id": "abc123", "tool_name": "Bash", "tool_response": {"output": "1 FAILED"}}`))
+	require.NoError(t, err)
+	assert.Contains(t, result.Message, "Tests failed in abc123")
+}
+
+func TestProcessHookBlocksMessageMentioningSystemMessage(t *testing.T) {
+	t.Parallel()
+	ctx, _ := setupTestWithContext(t)
+
+	configPath := createTempConfig(t, `rules:
+  - match: "^rm -rf"
+    send: "Never rm -rf; set systemMessage instead"
+    generate: "off"`)
+	app := NewApp(ctx, configPath)
+
+	result, err := app.ProcessHook(ctx, strings.NewReader(
+		`{"hook_event_name": "PreToolUse", "tool_name": "Bash", "tool_input": {"command": "rm -rf /x"}}`))
+	require.NoError(t, err)
+	assert.Equal(t, ProcessModeBlock, result.Mode, "a plain message naming systemMessage still blocks")
+	assert.Equal(t, "Never rm -rf; set systemMessage instead", result.Message)
+}
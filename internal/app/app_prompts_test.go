@@ -96,6 +96,22 @@ commands:
 			want:    "",
 			wantErr: false,
 		},
+		{
+			name:  fmt.Sprintf("Command index (%s2)", constants.CommandPrefix),
+			input: fmt.Sprintf(`{"prompt": "%s2"}`, constants.CommandPrefix),
+			want: `{"hookSpecificOutput":{"hookEventName":"UserPromptSubmit",` +
+				`"additionalContext":"Project Status: All systems operational"}}`,
+		},
+		{
+			name:  fmt.Sprintf("Command index zero (%s0)", constants.CommandPrefix),
+			input: fmt.Sprintf(`{"prompt": "%s0"}`, constants.CommandPrefix),
+			want:  "",
+		},
+		{
+			name:  fmt.Sprintf("Signed command index (%s+1)", constants.CommandPrefix),
+			input: fmt.Sprintf(`{"prompt": "%s+1"}`, constants.CommandPrefix),
+			want:  "",
+		},
 		{
 			name: fmt.Sprintf("Invalid command index (%s5)", constants.CommandPrefix),
 			input: fmt.Sprintf(`{
@@ -342,3 +358,68 @@ func TestProcessUserPromptWithNoArguments(t *testing.T) {
 		t.Errorf("Expected %q, got %q", expected, additionalContext)
 	}
 }
+
+func TestProcessUserPromptWithCommandAlias(t *testing.T) {
+	t.Parallel()
+	ctx, _ := setupTestWithContext(t)
+
+	configContent := `commands:
+  - name: "help"
+    aliases: ["h", "?"]
+    send: "Help text"
+    generate: "off"`
+
+	configPath := createTempConfig(t, configContent)
+	app := NewApp(ctx, configPath)
+
+	for _, name := range []string{"help", "h", "?"} {
+		promptJSON := `{"prompt": "` + constants.CommandPrefix + name + `"}`
+		result, err := app.ProcessUserPrompt(ctx, json.RawMessage(promptJSON))
+		if err != nil {
+			t.Fatalf("ProcessUserPrompt failed for %q: %v", name, err)
+		}
+		assert.Contains(t, result, "Help text", "Expected %q to trigger the help command", name)
+	}
+}
+
+func TestProcessUserPromptCachesCommandGenerationByArgs(t *testing.T) {
+	t.Parallel()
+	ctx, getLogs := setupTestWithContext(t)
+
+	configContent := `commands:
+  - name: "summarize"
+    aliases: ["sum"]
+    send: "Summarize the code"
+    generate: "once"`
+
+	app := NewAppWithFileSystem(createTempConfig(t, configContent), t.TempDir(), afero.NewMemMapFs())
+	mockLauncher := claude.SetupMockLauncherWithDefaults()
+	mockLauncher.SetResponseForPattern("", "Generated summary")
+	app.SetMockLauncher(mockLauncher)
+
+	promptHandler, ok := app.promptHandler.(*DefaultPromptHandler)
+	require.True(t, ok, "expected DefaultPromptHandler")
+	promptHandler.aiHelper.cachePath = filepath.Join(t.TempDir(), "ai_test.db")
+
+	run := func(command string) {
+		t.Helper()
+		promptJSON, err := json.Marshal(map[string]string{"prompt": constants.CommandPrefix + command})
+		require.NoError(t, err)
+		result, err := app.ProcessUserPrompt(ctx, promptJSON)
+		require.NoError(t, err)
+		require.Contains(t, result, "Generated summary")
+	}
+
+	run("summarize src/")
+	require.Equal(t, 1, mockLauncher.GetCallCount())
+
+	// The same arguments are served from cache, whatever the alias or spacing
+	run("summarize src/")
+	run(`sum   "src/"`)
+	require.Equal(t, 1, mockLauncher.GetCallCount())
+	require.Contains(t, getLogs(), `"cache":"hit"`)
+
+	// Different arguments regenerate even though the rendered message is the same
+	run("summarize docs/")
+	require.Equal(t, 2, mockLauncher.GetCallCount())
+}
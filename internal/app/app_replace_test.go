@@ -0,0 +1,82 @@
+package app
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wizzomafizzo/bumpers/internal/claude"
+)
+
+// decodeReplacement returns the permission decision and reason from a PreToolUse JSON output
+func decodeReplacement(t *testing.T, message string) (decision, reason string) {
+	t.Helper()
+	var output struct {
+		HookSpecificOutput struct {
+			HookEventName            string `json:"hookEventName"`
+			PermissionDecision       string `json:"permissionDecision"`
+			PermissionDecisionReason string `json:"permissionDecisionReason"`
+		} `json:"hookSpecificOutput"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(message), &output), "message: %s", message)
+	assert.Equal(t, "PreToolUse", output.HookSpecificOutput.HookEventName)
+	return output.HookSpecificOutput.PermissionDecision, output.HookSpecificOutput.PermissionDecisionReason
+}
+
+func TestPreToolUseReplaceTemplate(t *testing.T) {
+	t.Parallel()
+	ctx, _ := setupTestWithContext(t)
+
+	configPath := createTempConfig(t, `rules:
+  - match: "^go test"
+    send: "Use just test instead of {{.Command}}"
+    replace: "just test {{slice .Command 8}}"
+    generate: "off"
+  - match: "^make"
+    send: "Use just"
+    replace: "{{ if false }}just{{ end }}"
+    generate: "off"`)
+	app := NewAppWithWorkDir(configPath, t.TempDir())
+
+	result, err := app.ProcessHook(ctx, strings.NewReader(
+		`{"tool_name": "Bash", "tool_input": {"command": "go test ./internal/..."}}`))
+	require.NoError(t, err)
+	assert.Equal(t, ProcessModeInformational, result.Mode)
+
+	decision, reason := decodeReplacement(t, result.Message)
+	assert.Equal(t, "deny", decision)
+	assert.Equal(t, "Use just test instead of go test ./internal/...\n\n"+
+		"Run exactly: `just test ./internal/...`", reason)
+
+	// An empty replacement falls back to the plain blocking message
+	result, err = app.ProcessHook(ctx, strings.NewReader(`{"tool_name": "Bash", "tool_input": {"command": "make all"}}`))
+	require.NoError(t, err)
+	assert.Equal(t, ProcessModeBlock, result.Mode)
+	assert.Equal(t, "Use just", result.Message)
+}
+
+func TestPreToolUseReplaceWithGenerate(t *testing.T) {
+	t.Parallel()
+	ctx, _ := setupTestWithContext(t)
+
+	configPath := createTempConfig(t, `rules:
+  - match: "^go test"
+    send: "Use just test"
+    replace: "just test"
+    generate: "always"`)
+	app := NewAppWithWorkDir(configPath, t.TempDir())
+
+	mockLauncher := claude.SetupMockLauncherWithDefaults()
+	mockLauncher.SetResponseForPattern("", "The project runs tests through just")
+	app.SetMockLauncher(mockLauncher)
+
+	result, err := app.ProcessHook(ctx, strings.NewReader(`{"tool_name": "Bash", "tool_input": {"command": "go test ./..."}}`))
+	require.NoError(t, err)
+
+	// Generation rewrites the message, never the replacement command
+	decision, reason := decodeReplacement(t, result.Message)
+	assert.Equal(t, "deny", decision)
+	assert.Equal(t, "The project runs tests through just\n\nRun exactly: `just test`", reason)
+}
@@ -0,0 +1,107 @@
+package app
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessHookResolvePaths(t *testing.T) {
+	t.Parallel()
+	ctx, _ := setupTestWithContext(t)
+
+	projectDir, err := filepath.EvalSymlinks(t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Join(projectDir, "secrets"), 0o750))
+	require.NoError(t, os.MkdirAll(filepath.Join(projectDir, "src", "pkg"), 0o750))
+	require.NoError(t, os.Symlink(filepath.Join(projectDir, "secrets"), filepath.Join(projectDir, "src", "config")))
+
+	configPath := createTempConfig(t, `rules:
+  - match:
+      pattern: "^{{.ProjectRoot}}/secrets/"
+      resolve_paths: true
+    tool: "^(Read|Edit)$"
+    send: "Blocked {{.Command}}"
+    generate: "off"
+  - match:
+      pattern: "^/etc/"
+      resolve_paths: true
+    tool: "^Read$"
+    send: "Outside the project"
+    generate: "off"
+  - match: "^{{.ProjectRoot}}/src/config/"
+    tool: "^Read$"
+    send: "Unresolved match"
+    generate: "off"`)
+	app := NewAppWithFileSystem(configPath, projectDir, afero.NewOsFs())
+
+	tests := []struct {
+		name string
+		tool string
+		path string
+		cwd  string
+		want string
+	}{
+		{
+			name: "symlink into protected directory",
+			tool: "Edit",
+			path: filepath.Join(projectDir, "src", "config", "key.pem"),
+			want: "Blocked " + filepath.Join(projectDir, "secrets", "key.pem"),
+		},
+		{
+			name: "dot dot escaping the project",
+			tool: "Read",
+			path: filepath.Join(projectDir, "src", strings.Repeat("../", 20), "etc", "passwd"),
+			want: "Outside the project",
+		},
+		{
+			name: "relative path from subdirectory cwd",
+			tool: "Read",
+			path: "../../secrets/token",
+			cwd:  filepath.Join(projectDir, "src", "pkg"),
+			want: "Blocked " + filepath.Join(projectDir, "secrets", "token"),
+		},
+		{
+			name: "missing file is cleaned",
+			tool: "Read",
+			path: "src/./missing/../../secrets/new.txt",
+			want: "Blocked " + filepath.Join(projectDir, "secrets", "new.txt"),
+		},
+		{
+			name: "unrelated path",
+			tool: "Read",
+			path: filepath.Join(projectDir, "src", "pkg", "main.go"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			event := map[string]any{
+				"tool_name":  tt.tool,
+				"tool_input": map[string]any{"file_path": tt.path},
+			}
+			if tt.cwd != "" {
+				event["cwd"] = tt.cwd
+			}
+			input, err := json.Marshal(event)
+			require.NoError(t, err)
+
+			result, err := app.ProcessHook(ctx, strings.NewReader(string(input)))
+			require.NoError(t, err)
+			if tt.want == "" {
+				assert.Equal(t, ProcessModeAllow, result.Mode)
+				return
+			}
+			assert.Equal(t, ProcessModeBlock, result.Mode)
+			assert.Equal(t, tt.want, result.Message)
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package app
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessHookOutputSelect(t *testing.T) {
+	t.Parallel()
+	ctx, _ := setupTestWithContext(t)
+
+	const rules = `rules:
+  - match: "^git"
+    tool: ".*"
+    send: "General git rule"
+    generate: "off"
+  - match: "^git push --force"
+    tool: "^Bash$"
+    send: "Never force push"
+    generate: "off"`
+
+	tests := []struct {
+		name   string
+		output string
+		want   string
+	}{
+		{name: "default picks first", output: "", want: "General git rule"},
+		{name: "first picks first", output: "output:\n  select: first\n", want: "General git rule"},
+		{name: "specific picks most specific", output: "output:\n  select: specific\n", want: "Never force push"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			configPath := createTempConfig(t, tt.output+rules)
+			app := NewAppWithFileSystem(configPath, t.TempDir(), afero.NewMemMapFs())
+
+			input := `{"tool_name": "Bash", "tool_input": {"command": "git push --force origin main"}}`
+			result, err := app.ProcessHook(ctx, strings.NewReader(input))
+			require.NoError(t, err)
+			assert.Equal(t, ProcessModeBlock, result.Mode)
+			assert.Equal(t, tt.want, result.Message)
+		})
+	}
+}
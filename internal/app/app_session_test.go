@@ -163,6 +163,62 @@ func TestProcessSessionStartWorksWithClear(t *testing.T) {
 	}
 }
 
+func TestProcessSessionStartSessionSources(t *testing.T) {
+	t.Parallel()
+	ctx, _ := setupTestWithContext(t)
+
+	configPath := createTempConfig(t, `settings:
+  session_sources: ["startup", "resume"]
+session:
+  - add: "Filtered note"
+    generate: "off"`)
+	app := NewApp(ctx, configPath)
+
+	expectedJSON := `{"hookSpecificOutput":{"hookEventName":"SessionStart",` +
+		`"additionalContext":"Filtered note"}}`
+	for source, want := range map[string]string{"startup": expectedJSON, "resume": expectedJSON, "clear": ""} {
+		input := `{"session_id": "abc123", "hook_event_name": "SessionStart", "source": "` + source + `"}`
+		result, err := app.ProcessHook(context.Background(), strings.NewReader(input))
+		if err != nil {
+			t.Fatalf("ProcessHook failed for SessionStart %s: %v", source, err)
+		}
+		if result.Message != want {
+			t.Errorf("Expected %q for source %s, got %q", want, source, result.Message)
+		}
+	}
+}
+
+func TestProcessSessionStartNoteSources(t *testing.T) {
+	t.Parallel()
+	ctx, _ := setupTestWithContext(t)
+
+	configPath := createTempConfig(t, `session:
+  - add: "Welcome back"
+    sources: ["resume"]
+    generate: "off"
+  - add: "Fresh start"
+    generate: "off"`)
+	app := NewApp(ctx, configPath)
+
+	wrap := func(context string) string {
+		return `{"hookSpecificOutput":{"hookEventName":"SessionStart","additionalContext":"` + context + `"}}`
+	}
+	for source, want := range map[string]string{
+		"resume":  wrap("Welcome back"),
+		"startup": wrap("Fresh start"),
+		"compact": "",
+	} {
+		input := `{"session_id": "abc123", "hook_event_name": "SessionStart", "source": "` + source + `"}`
+		result, err := app.ProcessHook(context.Background(), strings.NewReader(input))
+		if err != nil {
+			t.Fatalf("ProcessHook failed for SessionStart %s: %v", source, err)
+		}
+		if result.Message != want {
+			t.Errorf("Expected %q for source %s, got %q", want, source, result.Message)
+		}
+	}
+}
+
 func TestProcessSessionStartWithTemplate(t *testing.T) {
 	t.Parallel()
 	ctx, _ := setupTestWithContext(t)
@@ -342,3 +398,141 @@ func TestProcessSessionStartWithAIGeneration(t *testing.T) {
 		t.Error("Expected mock launcher to be called for AI generation")
 	}
 }
+
+func TestProcessSessionStartAddFile(t *testing.T) {
+	t.Parallel()
+	ctx, getLogs := setupTestWithContext(t)
+
+	// Notes are read through the app's filesystem
+	projectDir := t.TempDir()
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, filepath.Join(projectDir, "CLAUDE.md"),
+		[]byte("# Conventions\nUse just for builds\n"), 0o600))
+	require.NoError(t, afero.WriteFile(fs, filepath.Join(filepath.Dir(projectDir), "secret.md"),
+		[]byte("outside the project"), 0o600))
+	require.NoError(t, afero.WriteFile(fs, filepath.Join(projectDir, "BIG.md"),
+		[]byte(strings.Repeat("a", 40<<10)), 0o600))
+
+	configPath := createTempConfig(t, `session:
+  - add: "Inline note"
+  - add_file: "CLAUDE.md"
+  - add_file: "MISSING.md"
+  - add_file: "../secret.md"
+  - add_file: "BIG.md"`)
+	app := NewAppWithFileSystem(configPath, projectDir, fs)
+
+	result, err := app.ProcessSessionStart(ctx, json.RawMessage(sessionStartHookInput))
+	require.NoError(t, err)
+
+	var response map[string]HookSpecificOutput
+	require.NoError(t, json.Unmarshal([]byte(result), &response))
+	assert.Equal(t, "Inline note\n# Conventions\nUse just for builds\n"+strings.Repeat("a", 32<<10)+"\n[truncated]",
+		response["hookSpecificOutput"].AdditionalContext, "files outside the project are skipped, large ones cut off")
+	assert.Contains(t, getLogs(), "session note file not found")
+	assert.Contains(t, getLogs(), "path is outside the project root")
+}
+
+func TestProcessSessionStartAddAndAddFileConflict(t *testing.T) {
+	t.Parallel()
+	ctx, _ := setupTestWithContext(t)
+
+	configPath := createTempConfig(t, `session:
+  - add: "Inline note"
+    add_file: "CLAUDE.md"`)
+	app := NewAppWithFileSystem(configPath, t.TempDir(), afero.NewMemMapFs())
+
+	_, err := app.ProcessSessionStart(ctx, json.RawMessage(sessionStartHookInput))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "add and add_file cannot both be set")
+}
+
+func TestProcessSessionStartSessionEventRules(t *testing.T) {
+	t.Parallel()
+	ctx, _ := setupTestWithContext(t)
+
+	configContent := `rules:
+  - match: "startup"
+    send: "Pre rule, not for sessions"
+    generate: "off"
+  - match:
+      pattern: "^(startup|clear)$"
+      event: "session"
+    send: "Fresh session from {{.Command}}"
+    generate: "off"
+  - match:
+      pattern: "^resume$"
+      event: "session"
+    send: "Welcome back"
+    generate: "off"
+session:
+  - add: "Remember to run tests first"
+    generate: "off"`
+
+	configPath := createTempConfig(t, configContent)
+	app := NewApp(ctx, configPath)
+
+	tests := []struct {
+		source   string
+		expected string
+	}{
+		{"startup", "Remember to run tests first\nFresh session from startup"},
+		{"clear", "Remember to run tests first\nFresh session from clear"},
+		{"resume", "Welcome back"},
+		{"compact", ""},
+	}
+
+	for _, tt := range tests {
+		input := `{"session_id": "abc123", "hook_event_name": "SessionStart", "source": "` + tt.source + `"}`
+		result, err := app.ProcessSessionStart(ctx, json.RawMessage(input))
+		require.NoError(t, err, tt.source)
+
+		if tt.expected == "" {
+			assert.Empty(t, result, tt.source)
+			continue
+		}
+		var response struct {
+			HookSpecificOutput HookSpecificOutput `json:"hookSpecificOutput"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(result), &response), tt.source)
+		assert.Equal(t, tt.expected, response.HookSpecificOutput.AdditionalContext, tt.source)
+	}
+}
+
+func TestProcessSessionStartOncePerSessionNote(t *testing.T) {
+	t.Parallel()
+	ctx, _ := setupTestWithContext(t)
+
+	configPath := createTempConfig(t, `session:
+  - add: "Read CONTRIBUTING.md before starting"
+    once_per_session: true
+    generate: "off"
+  - add_file: "NOTES.md"
+    once_per_session: true
+    generate: "off"
+  - add: "Remember to run tests first"
+    generate: "off"`)
+	projectDir := t.TempDir()
+	fs := afero.NewMemMapFs()
+	app := NewAppWithFileSystem(configPath, projectDir, fs)
+
+	additionalContext := func(sessionID, source string) string {
+		input := `{"session_id": "` + sessionID + `", "hook_event_name": "SessionStart", "source": "` + source + `"}`
+		result, err := app.ProcessSessionStart(ctx, json.RawMessage(input))
+		require.NoError(t, err)
+		var response struct {
+			HookSpecificOutput HookSpecificOutput `json:"hookSpecificOutput"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(result), &response))
+		return response.HookSpecificOutput.AdditionalContext
+	}
+
+	both := "Read CONTRIBUTING.md before starting\nRemember to run tests first"
+	assert.Equal(t, both, additionalContext("s1", "startup"))
+	assert.Equal(t, "Remember to run tests first", additionalContext("s1", "clear"))
+	assert.Equal(t, both, additionalContext("s2", "startup"), "a new session shows the note again")
+
+	// A note that couldn't be shown isn't marked, so it's shown once its file exists
+	require.NoError(t, afero.WriteFile(fs, filepath.Join(projectDir, "NOTES.md"), []byte("Check NOTES.md"), 0o600))
+	assert.Equal(t, "Check NOTES.md\nRemember to run tests first", additionalContext("s1", "startup"))
+	assert.Equal(t, "Remember to run tests first", additionalContext("s1", "clear"))
+}
@@ -0,0 +1,81 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wizzomafizzo/bumpers/internal/rules"
+)
+
+func TestAppOperationModeBlocksEditsUntilTriggerPhrase(t *testing.T) {
+	t.Parallel()
+	ctx, _ := setupTestWithContext(t)
+
+	projectDir := t.TempDir()
+	configPath := filepath.Join(projectDir, "bumpers.yml")
+	require.NoError(t, os.WriteFile(configPath, []byte(`rules:
+  - match: "never-matches"
+    send: "unused"`), 0o600))
+
+	app := NewAppWithFileSystem(configPath, projectDir, afero.NewOsFs())
+
+	state, err := app.GetOperationMode(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, rules.ExecuteMode, state.Mode)
+
+	require.NoError(t, app.SetOperationMode(ctx, rules.PlanMode))
+	state, err = app.GetOperationMode(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, rules.PlanMode, state.Mode)
+
+	editInput := `{
+		"tool_name": "Edit",
+		"tool_input": {"file_path": "` + filepath.Join(projectDir, "main.go") + `", "old_string": "a", "new_string": "b"}
+	}`
+	result, err := app.ProcessHook(ctx, strings.NewReader(editInput))
+	require.NoError(t, err)
+	assert.Equal(t, ProcessModeBlock, result.Mode)
+	assert.Contains(t, result.Message, "plan mode")
+
+	_, err = app.ProcessHook(ctx, strings.NewReader(`{"hook_event_name": "UserPromptSubmit", "prompt": "ok, make it so"}`))
+	require.NoError(t, err)
+
+	state, err = app.GetOperationMode(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, rules.ExecuteMode, state.Mode)
+
+	result, err = app.ProcessHook(ctx, strings.NewReader(editInput))
+	require.NoError(t, err)
+	assert.Equal(t, ProcessModeAllow, result.Mode)
+}
+
+func TestAppResetOperationMode(t *testing.T) {
+	t.Parallel()
+	ctx, _ := setupTestWithContext(t)
+
+	projectDir := t.TempDir()
+	app := NewAppWithFileSystem(filepath.Join(projectDir, "bumpers.yml"), projectDir, afero.NewOsFs())
+
+	require.NoError(t, app.SetOperationMode(ctx, rules.PlanMode))
+	require.NoError(t, app.ResetOperationMode(ctx))
+
+	state, err := app.GetOperationMode(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, rules.DefaultState().Mode, state.Mode)
+}
+
+func TestAppOperationModeWithoutState(t *testing.T) {
+	t.Parallel()
+	ctx, _ := setupTestWithContext(t)
+
+	app := &App{}
+
+	_, err := app.GetOperationMode(ctx)
+	require.ErrorIs(t, err, ErrStateUnavailable)
+	require.ErrorIs(t, app.SetOperationMode(ctx, rules.PlanMode), ErrStateUnavailable)
+}
@@ -0,0 +1,39 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// approveCommandName is the built-in prompt command granting a pending approval. A config
+// command with the same name or alias takes precedence.
+const approveCommandName = "approve"
+
+// approveCommandUsage is returned for $approve without a token
+const approveCommandUsage = "Usage: $approve <token> to allow a command bumpers blocked pending approval"
+
+// processApproveCommand handles "$approve <token>" sent in sessionID, telling Claude the
+// approved command can now be run. Only that session's pending approvals are considered.
+func (p *DefaultPromptHandler) processApproveCommand(ctx context.Context, args, sessionID string) (string, error) {
+	token := strings.TrimSpace(args)
+	var message string
+	switch {
+	case token == "":
+		message = approveCommandUsage
+	case p.stateManager == nil:
+		message = "bumpers approvals are unavailable: " + ErrStateUnavailable.Error()
+	default:
+		approval, err := p.stateManager.Approve(ctx, sessionID, token)
+		if err != nil {
+			return "", fmt.Errorf("failed to approve: %w", err)
+		}
+		if approval == nil {
+			message = fmt.Sprintf("No pending bumpers approval has token %s", token)
+			break
+		}
+		message = fmt.Sprintf("The user approved `%s` for the rest of this session, it can now be run", approval.Value)
+	}
+
+	return p.createHookResponse(ctx, message)
+}
@@ -31,6 +31,17 @@ func parseCommandAndArgs(input string) (commandName, args string) {
 	return commandName, args
 }
 
+// commandCacheKey identifies a command invocation for AI generation caching by the
+// command's configured name and its parsed arguments, so aliases, spacing and quoting
+// that don't change the arguments share cached responses
+func commandCacheKey(name string, argv []string) string {
+	parts := []string{name}
+	if len(argv) > 1 {
+		parts = append(parts, argv[1:]...)
+	}
+	return strings.Join(parts, "\x00")
+}
+
 // buildArgv constructs the argv array from command name and arguments
 func buildArgv(commandName, args string) []string {
 	if commandName == "" {
@@ -0,0 +1,83 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/afero"
+	"github.com/wizzomafizzo/bumpers/internal/claude"
+	ai "github.com/wizzomafizzo/bumpers/internal/claude/api"
+	"github.com/wizzomafizzo/bumpers/internal/logging"
+	"github.com/wizzomafizzo/bumpers/internal/storage"
+)
+
+// SetClaudeProber replaces the prober ClaudeStatus uses to check the claude CLI, for tests
+func (a *App) SetClaudeProber(prober claude.Prober) {
+	a.claudeProber = prober
+}
+
+// ClaudeStatus returns whether the claude CLI AI generation launches is usable. The result
+// is cached in the AI cache for claude.ProbeTTL, where AI generation also reads it to skip
+// launching a CLI known to be unavailable; refresh probes again regardless.
+func (a *App) ClaudeStatus(ctx context.Context, refresh bool) (*claude.ProbeResult, error) {
+	prober := a.claudeProber
+	if prober == nil {
+		prober = claude.NewCLIProber(claude.NewLauncher(nil))
+	}
+
+	cachePath, err := storage.New(afero.NewOsFs()).GetDatabasePath()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database path: %w", err)
+	}
+	cache, err := ai.OpenCache(ctx, cachePath, a.projectRoot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open cache: %w", err)
+	}
+	defer func() {
+		if closeErr := cache.Close(); closeErr != nil {
+			logging.Get(ctx).Error().Err(closeErr).Msg("failed to close cache")
+		}
+	}()
+
+	result, err := ai.ProbeCLI(ctx, cache, prober, refresh)
+	if err != nil {
+		return nil, fmt.Errorf("failed to probe claude CLI: %w", err)
+	}
+	return result, nil
+}
+
+// FormatClaudeStatus renders a probe result in the style of the status report
+func FormatClaudeStatus(result *claude.ProbeResult) string {
+	var status strings.Builder
+	writeString := func(s string) {
+		_, _ = status.WriteString(s)
+	}
+
+	switch result.Status {
+	case claude.CLIAvailable:
+		writeString("Claude CLI: AVAILABLE\n")
+	case claude.CLINotFound:
+		writeString("Claude CLI: NOT FOUND\n")
+	case claude.CLIBroken:
+		writeString("Claude CLI: ERROR\n")
+	case claude.CLIAuthExpired:
+		writeString("Claude CLI: AUTH EXPIRED\n")
+	default:
+		writeString(fmt.Sprintf("Claude CLI: %s\n", strings.ToUpper(string(result.Status))))
+	}
+	if result.Path != "" {
+		writeString(fmt.Sprintf("   Location: %s\n", result.Path))
+	}
+	if result.Version != "" {
+		writeString(fmt.Sprintf("   Version: %s\n", result.Version))
+	}
+	if result.Detail != "" {
+		writeString(fmt.Sprintf("   Detail: %s\n", result.Detail))
+	}
+	if !result.Available() {
+		writeString("   AI generation is skipped until the CLI is fixed, check with: bumpers status --refresh\n")
+	}
+	writeString(fmt.Sprintf("   Checked: %s\n", result.CheckedAt.Local().Format("2006-01-02 15:04:05")))
+	return status.String()
+}
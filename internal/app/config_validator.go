@@ -2,11 +2,15 @@ package app
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
-	"os"
 	"strings"
+	"sync"
+	"time"
 
+	apptypes "github.com/wizzomafizzo/bumpers/internal/app/types"
 	"github.com/wizzomafizzo/bumpers/internal/config"
 	"github.com/wizzomafizzo/bumpers/internal/logging"
 	"github.com/wizzomafizzo/bumpers/internal/matcher"
@@ -23,6 +27,8 @@ type ConfigValidator interface {
 	ConfigLoader
 	ValidateConfig() (string, error)
 	TestCommand(ctx context.Context, command string) (string, error)
+	TestCommandDetailed(ctx context.Context, command string) (*apptypes.TestCommandResult, error)
+	CheckCommand(ctx context.Context, command string) (string, bool, error)
 }
 
 // CommandTester handles command testing against rules
@@ -32,13 +38,46 @@ type CommandTester interface {
 
 // DefaultConfigValidator implements ConfigValidator
 type DefaultConfigValidator struct {
+	cache       *configCache
 	configPath  string
 	projectRoot string
 }
 
+// configCache holds the last parsed config, keyed by the config source's mtime and size
+type configCache struct {
+	modTime time.Time
+	config  *config.Config
+	matcher *matcher.RuleMatcher
+	size    int64
+	mu      sync.RWMutex
+}
+
+// get returns the cached config and matcher if the source mtime and size still match
+func (cc *configCache) get(modTime time.Time, size int64) (*config.Config, *matcher.RuleMatcher, bool) {
+	cc.mu.RLock()
+	defer cc.mu.RUnlock()
+
+	if cc.config == nil || !cc.modTime.Equal(modTime) || cc.size != size {
+		return nil, nil, false
+	}
+	return cc.config, cc.matcher, true
+}
+
+// set stores a freshly parsed config and matcher for the given source mtime and size
+func (cc *configCache) set(modTime time.Time, size int64, cfg *config.Config, ruleMatcher *matcher.RuleMatcher) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	cc.modTime = modTime
+	cc.size = size
+	cc.config = cfg
+	cc.matcher = ruleMatcher
+}
+
 // NewConfigValidator creates a new ConfigValidator
 func NewConfigValidator(configPath, projectRoot string) *DefaultConfigValidator {
 	return &DefaultConfigValidator{
+		cache:       &configCache{},
 		configPath:  configPath,
 		projectRoot: projectRoot,
 	}
@@ -47,23 +86,46 @@ func NewConfigValidator(configPath, projectRoot string) *DefaultConfigValidator
 // loadPartialConfig loads and parses the configuration file
 func (c *DefaultConfigValidator) loadPartialConfig(ctx context.Context) (*config.PartialConfig, error) {
 	logging.Get(ctx).Debug().Str("config_path", c.configPath).Msg("loading config file")
-	data, err := os.ReadFile(c.configPath)
+	source, err := config.ReadSource(c.configPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read config from %s: %w", c.configPath, err)
 	}
 
-	partialCfg, err := config.LoadPartial(data)
+	partialCfg, err := config.LoadSource(source)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load config from %s: %w", c.configPath, err)
 	}
+	if !config.IsMerged(c.configPath) {
+		partialCfg.SetRuleOrigins(c.configPath)
+	}
 
 	return partialCfg, nil
 }
 
-// LoadConfigAndMatcher loads configuration and creates a rule matcher
+// ConfigHash returns a hash of the config's content, which changes whenever any of its
+// sources does
+func (c *DefaultConfigValidator) ConfigHash() (string, error) {
+	data, err := config.ReadData(c.configPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read config from %s: %w", c.configPath, err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// LoadConfigAndMatcher loads configuration and creates a rule matcher. The parsed
+// result is cached and reused until the config source's mtime or size changes.
 func (c *DefaultConfigValidator) LoadConfigAndMatcher(
 	ctx context.Context,
 ) (*config.Config, *matcher.RuleMatcher, error) {
+	modTime, size, statErr := config.SourceStat(c.configPath)
+	if statErr == nil {
+		if cfg, ruleMatcher, ok := c.cache.get(modTime, size); ok {
+			logging.Get(ctx).Debug().Str("config_path", c.configPath).Msg("using cached config")
+			return cfg, ruleMatcher, nil
+		}
+	}
+
 	partialCfg, err := c.loadPartialConfig(ctx)
 	if err != nil {
 		return nil, nil, err
@@ -74,25 +136,60 @@ func (c *DefaultConfigValidator) LoadConfigAndMatcher(
 		warning := &partialCfg.ValidationWarnings[i]
 		logging.Get(ctx).Warn().
 			Int("rule_index", warning.RuleIndex).
+			Int("line", warning.Line).
 			Str("pattern", warning.Rule.GetMatch().Pattern).
 			Err(warning.Error).
 			Msg("invalid rule skipped")
 	}
+	for _, warning := range partialCfg.CommandWarnings {
+		logging.Get(ctx).Warn().Msg(warning)
+	}
 
 	ruleMatcher, err := matcher.NewRuleMatcher(partialCfg.Rules)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to create rule matcher: %w", err)
 	}
+	ruleMatcher.SetSelectMode(partialCfg.Output.Select)
+
+	if statErr == nil {
+		c.cache.set(modTime, size, &partialCfg.Config, ruleMatcher)
+	}
 
 	return &partialCfg.Config, ruleMatcher, nil
 }
 
+// TestCommand returns the message of the rule blocking a shell command, or "Command allowed"
 func (c *DefaultConfigValidator) TestCommand(ctx context.Context, command string) (string, error) {
-	// Load config and match rules
-	_, ruleMatcher, err := c.LoadConfigAndMatcher(ctx)
+	result, err := c.TestCommandDetailed(ctx, command)
 	if err != nil {
 		return "", err
 	}
+	if !result.Matched {
+		return "Command allowed", nil
+	}
+	return result.Message, nil
+}
+
+// CheckCommand matches a shell command against the Bash rules, returning the rule's
+// rendered message and whether the command is blocked
+func (c *DefaultConfigValidator) CheckCommand(ctx context.Context, command string) (string, bool, error) {
+	result, err := c.TestCommandDetailed(ctx, command)
+	if err != nil {
+		return "", false, err
+	}
+	return result.Message, result.Matched, nil
+}
+
+// TestCommandDetailed matches a shell command against the Bash rules, returning the matched
+// rule's position, pattern and rendered message
+func (c *DefaultConfigValidator) TestCommandDetailed(
+	ctx context.Context, command string,
+) (*apptypes.TestCommandResult, error) {
+	// Load config and match rules
+	cfg, ruleMatcher, err := c.LoadConfigAndMatcher(ctx)
+	if err != nil {
+		return nil, err
+	}
 
 	// Create template context with project information
 	templateContext := make(map[string]any)
@@ -104,19 +201,36 @@ func (c *DefaultConfigValidator) TestCommand(ctx context.Context, command string
 	if err != nil {
 		if errors.Is(err, matcher.ErrNoRuleMatch) {
 			// No rule matched, command is allowed
-			return "Command allowed", nil
+			return &apptypes.TestCommandResult{}, nil
 		}
-		return "", fmt.Errorf("failed to match rule for command '%s': %w", command, err)
+		return nil, fmt.Errorf("failed to match rule for command '%s': %w", command, err)
 	}
 
 	// Process template with rule context including shared variables
 	// rule is guaranteed to be non-nil here based on matcher logic
-	processedMessage, err := template.ExecuteRuleTemplate(rule.Send, command)
+	processedMessage, err := template.ExecuteRuleTemplate(rule.Send, template.RuleContext{
+		Command:      command,
+		ToolName:     "Bash",
+		MatchedField: "command",
+	})
 	if err != nil {
-		return "", fmt.Errorf("failed to process rule template: %w", err)
+		return nil, fmt.Errorf("failed to process rule template: %w", err)
 	}
 
-	return processedMessage, nil
+	result := &apptypes.TestCommandResult{
+		Matched:      true,
+		Pattern:      rule.GetMatch().Pattern,
+		Message:      processedMessage,
+		MatchedField: "command",
+	}
+	// The matcher returns pointers into the config's rules
+	for i := range cfg.Rules {
+		if &cfg.Rules[i] == rule {
+			result.RuleIndex = i + 1
+			break
+		}
+	}
+	return result, nil
 }
 
 func (c *DefaultConfigValidator) ValidateConfig() (string, error) {
@@ -137,10 +251,15 @@ func (c *DefaultConfigValidator) ValidateConfig() (string, error) {
 		_, _ = result.WriteString(fmt.Sprintf(
 			"Configuration partially valid: %d valid rules, %d invalid rules\n\nInvalid rules:\n",
 			validCount, invalidCount))
+		showPositions := !config.IsMerged(c.configPath)
 		for i := range partialCfg.ValidationWarnings {
 			warning := &partialCfg.ValidationWarnings[i]
-			_, _ = result.WriteString(fmt.Sprintf("  Rule %d: %s (pattern: '%s')\n",
-				warning.RuleIndex+1, warning.Error.Error(), warning.Rule.Match))
+			position := ""
+			if showPositions && warning.Line > 0 {
+				position = fmt.Sprintf(" (line %d, column %d)", warning.Line, warning.Column)
+			}
+			_, _ = result.WriteString(fmt.Sprintf("  Rule %d%s: %s (pattern: '%s')\n",
+				warning.RuleIndex+1, position, warning.Error.Error(), warning.Rule.Match))
 		}
 	}
 
@@ -152,5 +271,51 @@ func (c *DefaultConfigValidator) ValidateConfig() (string, error) {
 		}
 	}
 
+	if len(partialCfg.RuleWarnings) > 0 {
+		_, _ = result.WriteString("\n\nRule warnings:\n")
+		for _, warning := range partialCfg.RuleWarnings {
+			_, _ = result.WriteString(fmt.Sprintf("  %s\n", warning))
+		}
+	}
+
+	if len(partialCfg.CommandWarnings) > 0 {
+		_, _ = result.WriteString("\n\nCommand warnings:\n")
+		for _, warning := range partialCfg.CommandWarnings {
+			_, _ = result.WriteString(fmt.Sprintf("  %s\n", warning))
+		}
+	}
+
+	// Dry-run templates to flag rules that would render no usable guidance
+	if emptyWarnings := findEmptyRuleTemplates(partialCfg.Rules); len(emptyWarnings) > 0 {
+		_, _ = result.WriteString("\n\nTemplate warnings:\n")
+		for _, warning := range emptyWarnings {
+			_, _ = result.WriteString(warning)
+		}
+	}
+
 	return result.String(), nil
 }
+
+// emptyTemplateSampleCommand is the command used when dry-running rule templates
+const emptyTemplateSampleCommand = "sample-command --flag value"
+
+// findEmptyRuleTemplates renders each rule's send template with a sample command
+// and reports rules whose message would be empty or just echo the command
+func findEmptyRuleTemplates(rules []config.Rule) []string {
+	var warnings []string
+	for i := range rules {
+		rule := &rules[i]
+		rendered, err := template.ExecuteRuleTemplate(rule.Send, template.RuleContext{
+			Command:      emptyTemplateSampleCommand,
+			ToolName:     "Bash",
+			MatchedField: "command",
+		})
+		if err != nil || !config.IsNoOpMessage(rendered, emptyTemplateSampleCommand) {
+			continue
+		}
+		warnings = append(warnings, fmt.Sprintf(
+			"  Rule %d: send template may render an empty or no-op message (pattern: '%s')\n",
+			i+1, rule.GetMatch().Pattern))
+	}
+	return warnings
+}
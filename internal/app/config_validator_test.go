@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -121,6 +122,37 @@ func TestDefaultConfigValidator_TestCommand_WithMatch(t *testing.T) {
 	assert.Equal(t, "Use just test instead", result)
 }
 
+func TestDefaultConfigValidator_TestCommandDetailed(t *testing.T) {
+	t.Parallel()
+
+	configPath := filepath.Join(t.TempDir(), "bumpers.yml")
+	configContent := `rules:
+  - match: "^npm"
+    send: "Use pnpm"
+  - match: "go test.*"
+    send: "Use just test instead"
+`
+	require.NoError(t, os.WriteFile(configPath, []byte(configContent), 0o600))
+
+	validator := NewConfigValidator(configPath, "/test/project")
+
+	result, err := validator.TestCommandDetailed(context.Background(), "go test ./...")
+	require.NoError(t, err)
+	assert.True(t, result.Matched)
+	assert.Equal(t, 2, result.RuleIndex)
+	assert.Equal(t, "go test.*", result.Pattern)
+	assert.Equal(t, "Use just test instead", result.Message)
+	assert.Equal(t, "command", result.MatchedField)
+
+	result, err = validator.TestCommandDetailed(context.Background(), "make build")
+	require.NoError(t, err)
+	assert.False(t, result.Matched)
+	assert.Zero(t, result.RuleIndex)
+	assert.Empty(t, result.Pattern)
+	assert.Empty(t, result.Message)
+	assert.Empty(t, result.MatchedField)
+}
+
 func TestDefaultConfigValidator_ValidateConfig_ValidConfig(t *testing.T) {
 	t.Parallel()
 
@@ -140,3 +172,167 @@ func TestDefaultConfigValidator_ValidateConfig_ValidConfig(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, "Configuration is valid", result)
 }
+
+func TestDefaultConfigValidator_ValidateConfig_InvalidRulePosition(t *testing.T) {
+	t.Parallel()
+
+	configPath := filepath.Join(t.TempDir(), "bumpers.yml")
+	require.NoError(t, os.WriteFile(configPath, []byte(`# project rules
+rules:
+  - match: "^go test"
+    send: "Use just test"
+  -   match: "[unclosed"
+      send: "Never matches"
+`), 0o600))
+
+	validator := NewConfigValidator(configPath, "/test/project")
+
+	result, err := validator.ValidateConfig()
+
+	require.NoError(t, err)
+	assert.Contains(t, result, "Rule 2 (line 5, column 7): ")
+	assert.Contains(t, result, "(pattern: '[unclosed')")
+}
+
+func TestDefaultConfigValidator_ValidateConfig_DuplicateCommandNames(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, "bumpers.yml")
+
+	configContent := `commands:
+  - name: "help"
+    send: "Help"
+  - name: "help"
+    send: "Other help"
+`
+
+	err := os.WriteFile(configPath, []byte(configContent), 0o600)
+	require.NoError(t, err)
+
+	validator := NewConfigValidator(configPath, "/test/project")
+
+	result, err := validator.ValidateConfig()
+
+	require.NoError(t, err)
+	assert.Contains(t, result, "Command warnings:")
+	assert.Contains(t, result, "command 2 name 'help' duplicates command 1 and will be ignored")
+}
+
+func TestDefaultConfigValidator_ValidateConfig_ShadowedRules(t *testing.T) {
+	t.Parallel()
+
+	configPath := filepath.Join(t.TempDir(), "bumpers.yml")
+	require.NoError(t, os.WriteFile(configPath, []byte(`rules:
+  - match: "^go "
+    send: "Use just"
+  - match: "^go test"
+    send: "Use just test"
+`), 0o600))
+
+	validator := NewConfigValidator(configPath, "/test/project")
+
+	result, err := validator.ValidateConfig()
+
+	require.NoError(t, err)
+	assert.Contains(t, result, "Rule warnings:")
+	assert.Contains(t, result, "rule 2 (pattern '^go test') is shadowed by earlier rule 1")
+}
+
+func TestDefaultConfigValidator_LoadConfigAndMatcher_UsesCache(t *testing.T) {
+	t.Parallel()
+
+	configPath := filepath.Join(t.TempDir(), "bumpers.yml")
+	require.NoError(t, os.WriteFile(configPath, []byte(testRuleConfig), 0o600))
+
+	validator := NewConfigValidator(configPath, "/test/project")
+
+	first, firstMatcher, err := validator.LoadConfigAndMatcher(context.Background())
+	require.NoError(t, err)
+	second, secondMatcher, err := validator.LoadConfigAndMatcher(context.Background())
+	require.NoError(t, err)
+
+	assert.Same(t, first, second)
+	assert.Same(t, firstMatcher, secondMatcher)
+}
+
+func TestDefaultConfigValidator_LoadConfigAndMatcher_InvalidatesOnModTime(t *testing.T) {
+	t.Parallel()
+
+	configPath := filepath.Join(t.TempDir(), "bumpers.yml")
+	require.NoError(t, os.WriteFile(configPath, []byte(testRuleConfig), 0o600))
+
+	validator := NewConfigValidator(configPath, "/test/project")
+
+	first, _, err := validator.LoadConfigAndMatcher(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "Use just test instead", first.Rules[0].Send)
+
+	// Same size content so only the mtime differs
+	updated := `rules:
+  - match: "go test.*"
+    send: "Use just tests instead"
+`
+	require.NoError(t, os.WriteFile(configPath, []byte(updated), 0o600))
+	future := time.Now().Add(time.Hour)
+	require.NoError(t, os.Chtimes(configPath, future, future))
+
+	second, _, err := validator.LoadConfigAndMatcher(context.Background())
+	require.NoError(t, err)
+	assert.NotSame(t, first, second)
+	assert.Equal(t, "Use just tests instead", second.Rules[0].Send)
+}
+
+// BenchmarkLoadConfigAndMatcher benchmarks config loading with the mtime cache
+func BenchmarkLoadConfigAndMatcher(b *testing.B) {
+	configPath := filepath.Join(b.TempDir(), "bumpers.yml")
+	if err := os.WriteFile(configPath, []byte(testRuleConfig), 0o600); err != nil {
+		b.Fatalf("Failed to write config file: %v", err)
+	}
+
+	validator := NewConfigValidator(configPath, "/test/project")
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, _, err := validator.LoadConfigAndMatcher(context.Background()); err != nil {
+			b.Fatalf("LoadConfigAndMatcher failed: %v", err)
+		}
+	}
+}
+
+// BenchmarkLoadConfigAndMatcherUncached benchmarks config loading without the cache
+func BenchmarkLoadConfigAndMatcherUncached(b *testing.B) {
+	configPath := filepath.Join(b.TempDir(), "bumpers.yml")
+	if err := os.WriteFile(configPath, []byte(testRuleConfig), 0o600); err != nil {
+		b.Fatalf("Failed to write config file: %v", err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		validator := NewConfigValidator(configPath, "/test/project")
+		if _, _, err := validator.LoadConfigAndMatcher(context.Background()); err != nil {
+			b.Fatalf("LoadConfigAndMatcher failed: %v", err)
+		}
+	}
+}
+
+func TestDefaultConfigValidator_ConfigDir(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "01-go.yml"), []byte(testRuleConfig), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "02-rm.yml"), []byte(`rules:
+  - match: "rm -rf"
+    send: "Use safer deletion"
+`), 0o600))
+
+	validator := NewConfigValidator(dir, "/test/project")
+
+	result, err := validator.TestCommand(context.Background(), "rm -rf /")
+	require.NoError(t, err)
+	assert.Equal(t, "Use safer deletion", result)
+
+	result, err = validator.ValidateConfig()
+	require.NoError(t, err)
+	assert.Equal(t, "Configuration is valid", result)
+}
@@ -0,0 +1,177 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/wizzomafizzo/bumpers/internal/config"
+	"github.com/wizzomafizzo/bumpers/internal/logging"
+	"github.com/wizzomafizzo/bumpers/internal/storage"
+)
+
+// webhookTimeout bounds how long posting a match to settings.webhook_url may take
+const webhookTimeout = 5 * time.Second
+
+// matchRecord is the JSON a rule's audit and webhook actions write for a match
+type matchRecord struct {
+	Time    string `json:"time"`
+	Event   string `json:"event"`
+	Tool    string `json:"tool"`
+	Field   string `json:"field,omitempty"`
+	RuleID  string `json:"rule_id,omitempty"`
+	Pattern string `json:"pattern"`
+	// Source is the config file and position the rule was defined at
+	Source *config.RuleOrigin `json:"source,omitempty"`
+	// Value is the matched value, redacted like logged values and left out when the
+	// rule's log mode is off
+	Value   string `json:"value,omitempty"`
+	Message string `json:"message,omitempty"`
+	Blocked bool   `json:"blocked"`
+	// Shadow marks a match of a shadow rule, whose message would have blocked the tool
+	Shadow bool `json:"shadow,omitempty"`
+}
+
+// runRuleActions runs the matched rule's actions in order for a match described by record,
+// whose event, tool, field, value and message the caller sets. It returns the message when
+// one of the actions is block and "" otherwise. Audit and webhook failures are logged
+// without changing the hook's decision.
+func (*DefaultHookProcessor) runRuleActions(
+	ctx context.Context, rule *config.Rule, settings *config.Settings, record matchRecord,
+) string {
+	actions := rule.GetActions()
+	record = completeMatchRecord(ctx, rule, settings, record)
+	logRuleMatch(ctx, rule, record, actions)
+
+	for _, action := range actions {
+		var err error
+		switch action {
+		case config.ActionAudit:
+			err = writeAuditRecord(settings, record)
+		case config.ActionWebhook:
+			err = postWebhookRecord(ctx, settings, record)
+		}
+		if err != nil {
+			logging.Get(ctx).Warn().Err(err).Str("pattern", record.Pattern).Str("action", action).
+				Msg("rule action failed")
+		}
+	}
+
+	if !record.Blocked {
+		return ""
+	}
+	return record.Message
+}
+
+// auditShadowMatch logs a shadow rule's match and writes it to the audit log with the
+// message it would have sent. Shadow rules only observe, so none of their actions run.
+func auditShadowMatch(ctx context.Context, rule *config.Rule, settings *config.Settings, record matchRecord) {
+	record = completeMatchRecord(ctx, rule, settings, record)
+	logRuleMatch(ctx, rule, record, []string{config.ActionAudit})
+	if err := writeAuditRecord(settings, record); err != nil {
+		logging.Get(ctx).Warn().Err(err).Str("pattern", record.Pattern).Msg("failed to audit shadow rule match")
+	}
+}
+
+// completeMatchRecord fills in the rule's details and whether it blocked, redacting the value.
+// Shadow rules never block.
+func completeMatchRecord(
+	ctx context.Context, rule *config.Rule, settings *config.Settings, record matchRecord,
+) matchRecord {
+	match := rule.GetMatch()
+	record.Time = time.Now().UTC().Format(time.RFC3339)
+	record.RuleID = rule.ID
+	record.Pattern = match.Pattern
+	record.Source = rule.Origin
+	record.Shadow = match.Shadow
+	record.Blocked = !match.Shadow && rule.HasAction(config.ActionBlock) && record.Message != ""
+	record.Value, _ = redactorFrom(ctx).value(rule, record.Value)
+	if record.Blocked && settings != nil && settings.ShowRuleSource && rule.Origin != nil {
+		record.Message += "\n\n(bumpers " + rule.DescribeOrigin() + ")"
+	}
+	return record
+}
+
+// logRuleMatch logs a match of rule running actions, at info level for shadow rules
+func logRuleMatch(ctx context.Context, rule *config.Rule, record matchRecord, actions []string) {
+	logger := logging.Get(ctx)
+	event := logger.Debug()
+	if record.Shadow {
+		event = logger.Info().Bool("shadow", true)
+	}
+	event = event.
+		Str("pattern", record.Pattern).
+		Str("rule_id", record.RuleID).
+		Strs("actions", actions)
+	if rule.Origin != nil {
+		event = event.Str("rule_file", rule.Origin.File).Int("rule_index", rule.Origin.Index)
+	}
+	event.Msg("rule matched")
+}
+
+// writeAuditRecord appends record as a JSON line to settings.audit_log, or the default
+// audit log in the bumpers state directory
+func writeAuditRecord(settings *config.Settings, record matchRecord) error {
+	path := ""
+	if settings != nil {
+		path = settings.AuditLog
+	}
+	if path == "" {
+		var err error
+		if path, err = storage.New(afero.NewOsFs()).GetAuditLogPath(); err != nil {
+			return fmt.Errorf("failed to get audit log path: %w", err)
+		}
+	} else if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return fmt.Errorf("failed to create audit log directory: %w", err)
+	}
+
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit record: %w", err)
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600) // #nosec G304 -- path is from config
+	if err != nil {
+		return fmt.Errorf("failed to open audit log %s: %w", path, err)
+	}
+	defer func() { _ = file.Close() }()
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit log %s: %w", path, err)
+	}
+	return nil
+}
+
+// postWebhookRecord posts record as JSON to settings.webhook_url
+func postWebhookRecord(ctx context.Context, settings *config.Settings, record matchRecord) error {
+	if settings == nil || settings.WebhookURL == "" {
+		return errors.New("webhook action requires settings.webhook_url")
+	}
+	body, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook record: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, webhookTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, settings.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post webhook: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
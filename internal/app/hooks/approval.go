@@ -0,0 +1,99 @@
+package hooks
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+
+	"github.com/wizzomafizzo/bumpers/internal/config"
+	"github.com/wizzomafizzo/bumpers/internal/constants"
+	"github.com/wizzomafizzo/bumpers/internal/hooks"
+	"github.com/wizzomafizzo/bumpers/internal/logging"
+	"github.com/wizzomafizzo/bumpers/internal/storage"
+)
+
+// approvalTokenBytes is the number of random bytes in an approval token, hex encoded
+const approvalTokenBytes = 4
+
+// approveCommandPattern matches a shell command running bumpers approve, allowing global
+// flags between the two and a path before the binary
+var approveCommandPattern = regexp.MustCompile(`\bbumpers\b[^;&|\n]*\sapprove\b`)
+
+// selfApprovalMessage is the response to a tool call running bumpers approve. Approvals
+// must come from the user, so Claude can't approve the commands it was blocked from running.
+var selfApprovalMessage = fmt.Sprintf("Approvals must come from the user. Ask them to send "+
+	"`%sapprove TOKEN` instead of running bumpers approve.", constants.CommandPrefix)
+
+// selfApproval reports whether a tool call runs bumpers approve
+func selfApproval(event *hooks.HookEvent) bool {
+	if event.ToolName != "Bash" {
+		return false
+	}
+	command, _ := event.ToolInput["command"].(string)
+	return approveCommandPattern.MatchString(command)
+}
+
+// sessionApproval checks the approval for value matched by an approval: session rule in
+// sessionID. It reports whether value was approved in that session and, when it wasn't, returns the token to
+// approve it with, storing a pending approval the first time. The token is "" when approvals
+// can't be stored, leaving the rule to block as usual.
+func (h *DefaultHookProcessor) sessionApproval(
+	ctx context.Context, rule *config.Rule, value, sessionID string,
+) (approved bool, token string) {
+	logger := logging.Get(ctx)
+	if h.stateManager == nil {
+		logger.Warn().Str("pattern", rule.GetMatch().Pattern).Msg("approvals need project state, blocking without one")
+		return false, ""
+	}
+
+	hash := approvalHash(rule, value)
+	approval, err := h.stateManager.GetApproval(ctx, sessionID, hash)
+	if err != nil {
+		logger.Warn().Err(err).Msg("failed to get approval, blocking")
+		return false, ""
+	}
+	if approval != nil {
+		if approval.Approved {
+			logger.Debug().Str("pattern", rule.GetMatch().Pattern).Msg("value was approved this session, allowing")
+		}
+		return approval.Approved, approval.Token
+	}
+
+	token, err = newApprovalToken()
+	if err != nil {
+		logger.Warn().Err(err).Msg("failed to create approval token, blocking")
+		return false, ""
+	}
+	approval = &storage.Approval{Token: token, Value: value}
+	if err := h.stateManager.SetApproval(ctx, sessionID, hash, approval); err != nil {
+		logger.Warn().Err(err).Msg("failed to store pending approval, blocking")
+		return false, ""
+	}
+	return false, token
+}
+
+// approvalHash identifies an approval within its session by the rule's pattern and the exact
+// value it matched, so approving one value doesn't allow others matched by the same rule
+func approvalHash(rule *config.Rule, value string) string {
+	sum := sha256.Sum256([]byte(rule.GetMatch().Pattern + "\x00" + value))
+	return hex.EncodeToString(sum[:])
+}
+
+// newApprovalToken returns a short random token for a pending approval
+func newApprovalToken() (string, error) {
+	token := make([]byte, approvalTokenBytes)
+	if _, err := rand.Read(token); err != nil {
+		return "", fmt.Errorf("failed to read random bytes: %w", err)
+	}
+	return hex.EncodeToString(token), nil
+}
+
+// approvalInstructions tells the user how to approve a blocked value with token. Only the
+// prompt command is named, since Claude can't send prompts but can run commands.
+func approvalInstructions(token string) string {
+	return fmt.Sprintf("\n\nTo allow this exact command for the rest of the session, the user can "+
+		"send `%sapprove %s`.", constants.CommandPrefix, token)
+}
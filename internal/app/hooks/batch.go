@@ -0,0 +1,114 @@
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/wizzomafizzo/bumpers/internal/constants"
+	"github.com/wizzomafizzo/bumpers/internal/logging"
+)
+
+// batchToolCalls splits a batched PreToolUse payload into one event per entry of its
+// tool_calls array. Each entry inherits the payload's other fields, such as session_id and
+// transcript_path, unless it sets them itself. It reports false for a single-call payload.
+func batchToolCalls(rawJSON json.RawMessage) ([]json.RawMessage, bool, error) {
+	var payload map[string]json.RawMessage
+	if err := json.Unmarshal(rawJSON, &payload); err != nil {
+		return nil, false, fmt.Errorf("failed to parse hook input: %w", err)
+	}
+
+	var entries []map[string]json.RawMessage
+	if err := json.Unmarshal(payload[constants.FieldToolCalls], &entries); err != nil || entries == nil {
+		return nil, false, nil //nolint:nilerr // a missing or non-array tool_calls is a single call
+	}
+	delete(payload, constants.FieldToolCalls)
+
+	calls := make([]json.RawMessage, 0, len(entries))
+	for _, entry := range entries {
+		call := make(map[string]json.RawMessage, len(payload)+len(entry))
+		for key, value := range payload {
+			call[key] = value
+		}
+		for key, value := range entry {
+			call[key] = value
+		}
+		data, err := json.Marshal(call)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to build batched tool call: %w", err)
+		}
+		calls = append(calls, data)
+	}
+	return calls, true, nil
+}
+
+// batchDecision is one tool call's result in a batched PreToolUse response
+type batchDecision struct {
+	PermissionDecision       string `json:"permissionDecision"`                 //nolint:tagliatelle // Claude Code API format
+	PermissionDecisionReason string `json:"permissionDecisionReason,omitempty"` //nolint:tagliatelle // Claude Code API format
+}
+
+// batchOutput is the PreToolUse JSON output for a batch, keyed by tool_use_id
+type batchOutput struct {
+	ToolCalls     map[string]batchDecision `json:"toolCalls"`     //nolint:tagliatelle // Claude Code API format
+	HookEventName string                   `json:"hookEventName"` //nolint:tagliatelle // Claude Code API format
+}
+
+// processPreToolUseBatch evaluates each tool call independently through the PreToolUse
+// pipeline and combines the results, keyed by tool_use_id or, when an entry has none, its
+// position in tool_calls. Calls no rule blocked are left out, like a single call's empty
+// response, so Claude Code's usual permission checks still apply to them; when none were
+// blocked the response is empty.
+func (h *DefaultHookProcessor) processPreToolUseBatch(ctx context.Context, calls []json.RawMessage) (string, error) {
+	logging.Get(ctx).Debug().Int("tool_calls", len(calls)).Msg("processing batched PreToolUse hook")
+
+	output := batchOutput{
+		ToolCalls:     make(map[string]batchDecision, len(calls)),
+		HookEventName: constants.PreToolUseEvent,
+	}
+	for i, call := range calls {
+		var ids struct {
+			ToolUseID string `json:"tool_use_id"`
+		}
+		_ = json.Unmarshal(call, &ids) // call was just marshaled from a JSON object
+		key := ids.ToolUseID
+		if key == "" {
+			key = strconv.Itoa(i)
+		}
+
+		message, _, err := h.processPreToolUseEvent(ctx, call)
+		if err != nil {
+			return "", fmt.Errorf("failed to process tool call %s: %w", key, err)
+		}
+		if message != "" {
+			output.ToolCalls[key] = batchDecisionFor(message)
+		}
+	}
+	if len(output.ToolCalls) == 0 {
+		return "", nil
+	}
+
+	response, err := json.Marshal(map[string]any{"hookSpecificOutput": output})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal batch response: %w", err)
+	}
+	return string(response), nil
+}
+
+// batchDecisionFor converts a single call's non-empty PreToolUse response into its batch
+// decision: a JSON decision such as a rule's replacement keeps its decision and reason, and
+// any other message denies it with the message as the reason
+func batchDecisionFor(message string) batchDecision {
+	var structured struct {
+		HookSpecificOutput preToolUseOutput `json:"hookSpecificOutput"` //nolint:tagliatelle // Claude Code API format
+	}
+	if err := json.Unmarshal([]byte(message), &structured); err == nil &&
+		structured.HookSpecificOutput.PermissionDecision != "" {
+		return batchDecision{
+			PermissionDecision:       structured.HookSpecificOutput.PermissionDecision,
+			PermissionDecisionReason: structured.HookSpecificOutput.PermissionDecisionReason,
+		}
+	}
+	return batchDecision{PermissionDecision: "deny", PermissionDecisionReason: message}
+}
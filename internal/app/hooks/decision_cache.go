@@ -0,0 +1,203 @@
+package hooks
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	apptypes "github.com/wizzomafizzo/bumpers/internal/app/types"
+	"github.com/wizzomafizzo/bumpers/internal/config"
+	"github.com/wizzomafizzo/bumpers/internal/constants"
+	"github.com/wizzomafizzo/bumpers/internal/logging"
+	"github.com/wizzomafizzo/bumpers/internal/storage"
+)
+
+// processPreToolUseCached returns the cached block for an exact repeat of a recent tool
+// call, or runs the PreToolUse pipeline and caches a block for settings.decision_cache_ttl.
+// Claude often retries a blocked command several times in a row, and each retry would
+// otherwise load the config, match and render the message again. A hit still records the
+// rule's fired time and the message for loop protection.
+func (h *DefaultHookProcessor) processPreToolUseCached(ctx context.Context, rawJSON json.RawMessage) (string, error) {
+	sessionID := hookSessionID(rawJSON)
+	key, revision, cacheable := h.decisionKey(ctx, rawJSON)
+	if cacheable {
+		if decision := h.cachedDecision(ctx, sessionID, key, revision); decision != nil {
+			h.recordRuleFired(ctx, decision.RuleKey)
+			h.recordOwnMessage(ctx, sessionID, decision.Message)
+			return decision.Message, nil
+		}
+	}
+
+	message, matchedRule, err := h.processPreToolUseEvent(ctx, rawJSON)
+	if err == nil && cacheable {
+		ruleKey := ""
+		if matchedRule != nil {
+			ruleKey = matchedRule.TrackingKey()
+		}
+		h.cacheDecision(ctx, sessionID, key, revision, message, ruleKey)
+	}
+	return message, err
+}
+
+// decisionKey identifies a tool call's decision within its session by the tool, exact input,
+// working directory and the content of the config and .bumpersignore, returning it with the
+// current state revision. It reports false when decisions can't be cached, e.g. without
+// project state or a config validator that can hash the config.
+func (h *DefaultHookProcessor) decisionKey(ctx context.Context, rawJSON json.RawMessage) (
+	key string, revision int64, ok bool,
+) {
+	hasher, isHasher := h.configValidator.(apptypes.ConfigHasher)
+	if h.stateManager == nil || !isHasher {
+		return "", 0, false
+	}
+
+	logger := logging.Get(ctx)
+	var event struct {
+		ToolInput map[string]any `json:"tool_input"`
+		ToolName  string         `json:"tool_name"`
+		Cwd       string         `json:"cwd"`
+	}
+	if err := json.Unmarshal(rawJSON, &event); err != nil {
+		return "", 0, false
+	}
+	// Maps marshal with sorted keys, so equal inputs always hash the same
+	input, err := json.Marshal(event.ToolInput)
+	if err != nil {
+		return "", 0, false
+	}
+	configHash, err := hasher.ConfigHash()
+	if err != nil {
+		logger.Debug().Err(err).Msg("failed to hash config, not caching decision")
+		return "", 0, false
+	}
+	ignoreHash, err := h.ignoreFileHash()
+	if err != nil {
+		logger.Debug().Err(err).Msg("failed to hash ignore file, not caching decision")
+		return "", 0, false
+	}
+	revision, err = h.stateManager.Revision(ctx)
+	if err != nil {
+		logger.Debug().Err(err).Msg("failed to get state revision, not caching decision")
+		return "", 0, false
+	}
+
+	fields := []string{event.ToolName, string(input), event.Cwd, configHash, ignoreHash}
+	sum := sha256.Sum256([]byte(strings.Join(fields, "\x00")))
+	return hex.EncodeToString(sum[:]), revision, true
+}
+
+// ignoreFileHash returns the hash of the project's .bumpersignore, empty when there is none
+func (h *DefaultHookProcessor) ignoreFileHash() (string, error) {
+	if h.projectRoot == "" {
+		return "", nil
+	}
+	data, err := os.ReadFile(filepath.Join(h.projectRoot, constants.IgnoreFilename))
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read ignore file: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// cachedDecision returns the decision cached for key in sessionID, or nil when there is none,
+// it expired or the state deciding tool calls changed since it was made
+func (h *DefaultHookProcessor) cachedDecision(
+	ctx context.Context, sessionID, key string, revision int64,
+) *storage.Decision {
+	decision, err := h.stateManager.GetDecision(ctx, sessionID, key)
+	if err != nil {
+		logging.Get(ctx).Debug().Err(err).Msg("failed to get cached decision")
+		return nil
+	}
+	if decision == nil || decision.Revision != revision || !h.currentTime().Before(decision.ExpiresAt) {
+		return nil
+	}
+
+	logging.Get(ctx).Debug().Msg("repeated tool call, using cached block")
+	return decision
+}
+
+// cacheDecision stores message and the tracking key of the rule that sent it as the decision for key
+// in sessionID until settings.decision_cache_ttl passes. Only blocks are cached, so allowed
+// calls don't leave a row each. Nothing is cached when the TTL is 0, when a pre rule reads
+// the transcript or has when.files conditions, whose results change between otherwise
+// identical calls, or when a pre rule has side effects a cached decision would skip.
+func (h *DefaultHookProcessor) cacheDecision(
+	ctx context.Context, sessionID, key string, revision int64, message, ruleKey string,
+) {
+	if message == "" {
+		return
+	}
+	cfg, _, err := h.configValidator.LoadConfigAndMatcher(ctx)
+	if err != nil || cfg == nil {
+		return
+	}
+	ttl := cfg.Settings.GetDecisionCacheTTL()
+	preRules := h.filterPreEventRules(cfg.Rules)
+	if ttl <= 0 || readsTranscript(preRules) || hasFileConditions(preRules) || hasSideEffects(preRules) {
+		return
+	}
+
+	decision := &storage.Decision{
+		Message:   message,
+		RuleKey:   ruleKey,
+		Revision:  revision,
+		ExpiresAt: h.currentTime().Add(ttl),
+	}
+	if err := h.stateManager.SetDecision(ctx, sessionID, key, decision); err != nil {
+		logging.Get(ctx).Debug().Err(err).Msg("failed to cache decision")
+	}
+}
+
+// readsTranscript reports whether any of the rules matches the #intent or #prev_output source
+func readsTranscript(ruleList []config.Rule) bool {
+	for i := range ruleList {
+		sources := ruleList[i].GetMatch().Sources
+		if slices.Contains(sources, intentFieldName) || slices.Contains(sources, prevOutputFieldName) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasFileConditions reports whether any of the rules has when.files conditions
+func hasFileConditions(ruleList []config.Rule) bool {
+	for i := range ruleList {
+		if ruleList[i].HasFileConditions() {
+			return true
+		}
+	}
+	return false
+}
+
+// hasSideEffects reports whether any of the rules does more on a match than block: runs
+// audit or webhook actions or an exec command, is a shadow rule or asks for approval
+func hasSideEffects(ruleList []config.Rule) bool {
+	for i := range ruleList {
+		rule := &ruleList[i]
+		if rule.Exec != "" || rule.Approval != "" || rule.GetMatch().Shadow ||
+			!slices.Equal(rule.GetActions(), []string{config.ActionBlock}) {
+			return true
+		}
+	}
+	return false
+}
+
+// currentTime returns the processor's clock, the real time unless a test replaced it
+func (h *DefaultHookProcessor) currentTime() time.Time {
+	if h.now != nil {
+		return h.now()
+	}
+	return time.Now()
+}
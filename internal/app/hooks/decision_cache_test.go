@@ -0,0 +1,265 @@
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wizzomafizzo/bumpers/internal/config"
+	"github.com/wizzomafizzo/bumpers/internal/matcher"
+	"github.com/wizzomafizzo/bumpers/internal/storage"
+)
+
+// testDecisionCacheTTL turns the decision cache on, which is off by default
+const testDecisionCacheTTL = "30s"
+
+// hashingConfigValidator serves cfg under a config hash the test controls, so the rules can
+// change without the hash changing
+type hashingConfigValidator struct {
+	MockConfigValidator
+	cfg  *config.Config
+	hash string
+}
+
+func (v *hashingConfigValidator) LoadConfigAndMatcher(_ context.Context) (*config.Config, *matcher.RuleMatcher, error) {
+	return v.cfg, nil, nil
+}
+
+func (v *hashingConfigValidator) ConfigHash() (string, error) {
+	return v.hash, nil
+}
+
+// setSend replaces the config with one blocking "make deploy" with message
+func (v *hashingConfigValidator) setSend(message, ttl string) {
+	v.cfg = &config.Config{
+		Settings: config.Settings{DecisionCacheTTL: ttl},
+		Rules:    []config.Rule{{Match: "^make deploy", Send: message, Generate: "off"}},
+	}
+}
+
+func newDecisionCacheProcessor(t *testing.T, ttl string) (
+	processor *DefaultHookProcessor, validator *hashingConfigValidator, state *storage.StateManager, now *time.Time,
+) {
+	t.Helper()
+	state, err := storage.NewStateManager(filepath.Join(t.TempDir(), "state.db"), "test-project")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = state.Close() })
+
+	validator = &hashingConfigValidator{hash: "v1"}
+	validator.setSend("first", ttl)
+	processor = NewHookProcessor(validator, testProjectRoot, state)
+	now = new(time.Time)
+	*now = time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	processor.now = func() time.Time { return *now }
+	return processor, validator, state, now
+}
+
+var deployCall = json.RawMessage(`{"hook_event_name": "PreToolUse", "tool_name": "Bash", ` +
+	`"tool_input": {"command": "make deploy"}}`)
+
+func TestDecisionCacheTTL(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	processor, validator, _, now := newDecisionCacheProcessor(t, testDecisionCacheTTL)
+
+	process := func() string {
+		message, err := processor.ProcessPreToolUse(ctx, deployCall)
+		require.NoError(t, err)
+		return message
+	}
+
+	assert.Equal(t, "first", process())
+	validator.setSend("second", testDecisionCacheTTL)
+	*now = now.Add(29 * time.Second)
+	assert.Equal(t, "first", process(), "an exact repeat within the TTL reuses the decision")
+
+	other, err := processor.ProcessPreToolUse(ctx, json.RawMessage(
+		`{"hook_event_name": "PreToolUse", "tool_name": "Bash", "tool_input": {"command": "make deploy prod"}}`))
+	require.NoError(t, err)
+	assert.Equal(t, "second", other, "other input isn't served from the cache")
+
+	*now = now.Add(time.Second)
+	assert.Equal(t, "second", process(), "decisions expire after the TTL")
+}
+
+func TestDecisionCacheBypass(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	tests := []struct {
+		change func(t *testing.T, validator *hashingConfigValidator, state *storage.StateManager)
+		name   string
+	}{
+		{name: "skip flag", change: func(t *testing.T, _ *hashingConfigValidator, state *storage.StateManager) {
+			t.Helper()
+			require.NoError(t, state.SetSkipNext(ctx, true))
+			require.NoError(t, state.SetSkipNext(ctx, false))
+		}},
+		{name: "rules enabled", change: func(t *testing.T, _ *hashingConfigValidator, state *storage.StateManager) {
+			t.Helper()
+			require.NoError(t, state.SetRulesEnabled(ctx, false))
+			require.NoError(t, state.SetRulesEnabled(ctx, true))
+		}},
+		{name: "config hash", change: func(_ *testing.T, validator *hashingConfigValidator, _ *storage.StateManager) {
+			validator.hash = "v2"
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			processor, validator, state, _ := newDecisionCacheProcessor(t, testDecisionCacheTTL)
+
+			message, err := processor.ProcessPreToolUse(ctx, deployCall)
+			require.NoError(t, err)
+			require.Equal(t, "first", message)
+
+			validator.setSend("second", testDecisionCacheTTL)
+			tt.change(t, validator, state)
+			message, err = processor.ProcessPreToolUse(ctx, deployCall)
+			require.NoError(t, err)
+			assert.Equal(t, "second", message)
+		})
+	}
+}
+
+func TestDecisionCacheDisabled(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	for _, ttl := range []string{"", "0s"} {
+		processor, validator, _, _ := newDecisionCacheProcessor(t, ttl)
+
+		message, err := processor.ProcessPreToolUse(ctx, deployCall)
+		require.NoError(t, err)
+		require.Equal(t, "first", message)
+
+		validator.setSend("second", ttl)
+		message, err = processor.ProcessPreToolUse(ctx, deployCall)
+		require.NoError(t, err)
+		assert.Equal(t, "second", message, "ttl %q leaves the cache off", ttl)
+	}
+}
+
+func TestDecisionCacheSideEffects(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	processor, validator, _, _ := newDecisionCacheProcessor(t, testDecisionCacheTTL)
+	auditPath := filepath.Join(t.TempDir(), "audit.jsonl")
+	validator.cfg.Settings.AuditLog = auditPath
+	validator.cfg.Rules[0].Actions = []string{config.ActionBlock, config.ActionAudit}
+
+	for range 3 {
+		message, err := processor.ProcessPreToolUse(ctx, deployCall)
+		require.NoError(t, err)
+		assert.Equal(t, "first", message)
+	}
+
+	data, err := os.ReadFile(auditPath) // #nosec G304 -- test temp file
+	require.NoError(t, err)
+	assert.Len(t, strings.Split(strings.TrimSpace(string(data)), "\n"), 3,
+		"every call writes an audit record when a rule has side effects")
+}
+
+func TestDecisionCacheHitRecordsRuleFired(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	processor, validator, state, now := newDecisionCacheProcessor(t, testDecisionCacheTTL)
+	validator.cfg.Rules[0].ID = "deploy"
+
+	_, err := processor.ProcessPreToolUse(ctx, deployCall)
+	require.NoError(t, err)
+	*now = now.Add(time.Second)
+	_, err = processor.ProcessPreToolUse(ctx, deployCall)
+	require.NoError(t, err)
+
+	fired, err := state.RuleFiredTimes(ctx)
+	require.NoError(t, err)
+	assert.True(t, fired["deploy"].Equal(*now), "a cache hit still records when the rule fired")
+}
+
+func TestDecisionCacheSession(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	processor, validator, _, _ := newDecisionCacheProcessor(t, testDecisionCacheTTL)
+
+	call := func(sessionID string) string {
+		message, err := processor.ProcessPreToolUse(ctx, json.RawMessage(`{"hook_event_name": "PreToolUse", `+
+			`"session_id": "`+sessionID+`", "tool_name": "Bash", "tool_input": {"command": "make deploy"}}`))
+		require.NoError(t, err)
+		return message
+	}
+
+	assert.Equal(t, "first", call("session-a"))
+	validator.setSend("second", testDecisionCacheTTL)
+	assert.Equal(t, "first", call("session-a"))
+	assert.Equal(t, "second", call("session-b"), "decisions aren't shared between sessions")
+}
+
+func TestDecisionCacheOnlyBlocks(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	processor, validator, _, _ := newDecisionCacheProcessor(t, testDecisionCacheTTL)
+
+	validator.setSend("first", testDecisionCacheTTL)
+	validator.cfg.Rules[0].Match = "^make release"
+	message, err := processor.ProcessPreToolUse(ctx, deployCall)
+	require.NoError(t, err)
+	require.Empty(t, message)
+
+	validator.setSend("second", testDecisionCacheTTL)
+	message, err = processor.ProcessPreToolUse(ctx, deployCall)
+	require.NoError(t, err)
+	assert.Equal(t, "second", message, "an allowed call isn't cached, so a rule added since blocks it")
+}
+
+func TestDecisionCacheKeyIncludesCwdAndIgnoreFile(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	processor, validator, _, _ := newDecisionCacheProcessor(t, testDecisionCacheTTL)
+	projectDir := t.TempDir()
+	processor.projectRoot = projectDir
+
+	call := func(cwd string) string {
+		message, err := processor.ProcessPreToolUse(ctx, json.RawMessage(`{"hook_event_name": "PreToolUse", `+
+			`"cwd": "`+cwd+`", "tool_name": "Bash", "tool_input": {"command": "make deploy"}}`))
+		require.NoError(t, err)
+		return message
+	}
+
+	assert.Equal(t, "first", call(projectDir))
+	validator.setSend("second", testDecisionCacheTTL)
+	assert.Equal(t, "first", call(projectDir))
+	assert.Equal(t, "second", call(filepath.Join(projectDir, "sub")), "another cwd isn't served from the cache")
+
+	require.NoError(t, os.WriteFile(filepath.Join(projectDir, ".bumpersignore"), []byte("vendor/\n"), 0o600))
+	validator.setSend("third", testDecisionCacheTTL)
+	assert.Equal(t, "third", call(projectDir), "changing .bumpersignore drops cached decisions")
+}
+
+func TestDecisionCacheClearedPerSession(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	processor, validator, state, _ := newDecisionCacheProcessor(t, testDecisionCacheTTL)
+
+	call := func(sessionID string) string {
+		message, err := processor.ProcessPreToolUse(ctx, json.RawMessage(`{"hook_event_name": "PreToolUse", `+
+			`"session_id": "`+sessionID+`", "tool_name": "Bash", "tool_input": {"command": "make deploy"}}`))
+		require.NoError(t, err)
+		return message
+	}
+
+	assert.Equal(t, "first", call("session_a"))
+	assert.Equal(t, "first", call("session1a"))
+	validator.setSend("second", testDecisionCacheTTL)
+
+	require.NoError(t, state.ClearDecisions(ctx, "session_a"))
+	assert.Equal(t, "second", call("session_a"))
+	assert.Equal(t, "first", call("session1a"), "clearing one session keeps other sessions' decisions")
+}
@@ -0,0 +1,73 @@
+package hooks
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"strconv"
+	"time"
+
+	"github.com/wizzomafizzo/bumpers/internal/config"
+	"github.com/wizzomafizzo/bumpers/internal/logging"
+	"github.com/wizzomafizzo/bumpers/internal/matcher"
+)
+
+// AllowExecEnv must be set to 1 for rules to run their exec command. It's read from the
+// environment rather than the config, so a project's config can't turn exec on by itself.
+const AllowExecEnv = "BUMPERS_ALLOW_EXEC"
+
+const (
+	// execTimeout bounds how long a rule's exec command may run
+	execTimeout = 30 * time.Second
+	// execValueMaxBytes caps the matched value passed in BUMPERS_MATCHED_VALUE
+	execValueMaxBytes = 4 << 10
+)
+
+// execWrapper runs the command in $1 under sh and kills it after $2 seconds. The wrapper
+// enforces the timeout itself so it still applies after the hook process has exited.
+const execWrapper = `sh -c "$1" & pid=$!; (sleep "$2"; kill "$pid" 2>/dev/null) & watchdog=$!; ` +
+	`wait "$pid"; status=$?; kill "$watchdog" 2>/dev/null; exit "$status"`
+
+// startRuleExec starts the matched rule's exec command in the background when
+// BUMPERS_ALLOW_EXEC is 1. It never waits for the command, so it can't delay or change the
+// hook's decision; failures are only logged.
+func (h *DefaultHookProcessor) startRuleExec(ctx context.Context, rule *config.Rule, event, matchedValue string) {
+	if rule.Exec == "" {
+		return
+	}
+	logger := logging.Get(ctx)
+	pattern := rule.GetMatch().Pattern
+	if os.Getenv(AllowExecEnv) != "1" {
+		logger.Debug().Str("pattern", pattern).Msg("rule exec skipped, " + AllowExecEnv + " is not set to 1")
+		return
+	}
+	name := rule.ID
+	if name == "" {
+		name = pattern
+	}
+
+	value, _ := matcher.SampleValue(matchedValue, execValueMaxBytes)
+
+	// #nosec G204 -- exec commands come from the project config and require BUMPERS_ALLOW_EXEC
+	cmd := exec.Command("sh", "-c", execWrapper, "bumpers-exec", rule.Exec,
+		strconv.Itoa(int(execTimeout/time.Second)))
+	cmd.Dir = h.projectRoot
+	cmd.Env = append(os.Environ(),
+		"BUMPERS_RULE_NAME="+name,
+		"BUMPERS_MATCHED_VALUE="+value,
+		"BUMPERS_EVENT="+event,
+	)
+
+	if err := cmd.Start(); err != nil {
+		logger.Warn().Err(err).Str("pattern", pattern).Str("exec", rule.Exec).Msg("failed to start rule exec")
+		return
+	}
+	logger.Debug().Str("pattern", pattern).Str("exec", rule.Exec).Int("pid", cmd.Process.Pid).Msg("started rule exec")
+
+	// Failures are logged if the command finishes while bumpers is still running
+	go func() {
+		if err := cmd.Wait(); err != nil {
+			logger.Warn().Err(err).Str("pattern", pattern).Str("exec", rule.Exec).Msg("rule exec failed")
+		}
+	}()
+}
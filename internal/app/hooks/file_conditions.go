@@ -0,0 +1,59 @@
+package hooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/afero"
+	"github.com/wizzomafizzo/bumpers/internal/config"
+	"github.com/wizzomafizzo/bumpers/internal/logging"
+)
+
+// fileConditionsHold reports whether all of a rule's when.files conditions hold against the
+// project root. It's checked before the rule's pattern; filesystem errors make a condition
+// false so a broken check never blocks anything.
+func (h *DefaultHookProcessor) fileConditionsHold(ctx context.Context, rule *config.Rule) bool {
+	if !rule.HasFileConditions() {
+		return true
+	}
+	fs := h.getFileSystem()
+	for i := range rule.When.Files {
+		condition := &rule.When.Files[i]
+		held, err := h.fileConditionHolds(fs, condition)
+		if err != nil {
+			logging.Get(ctx).Warn().Err(err).
+				Str("pattern", rule.GetMatch().Pattern).
+				Str("path", condition.Path).
+				Str("glob", condition.Glob).
+				Msg("failed to check when.files condition, treating it as false")
+			return false
+		}
+		if !held {
+			return false
+		}
+	}
+	return true
+}
+
+func (h *DefaultHookProcessor) fileConditionHolds(fs afero.Fs, condition *config.FileCondition) (bool, error) {
+	if condition.Glob != "" {
+		matches, err := afero.Glob(fs, filepath.Join(h.projectRoot, condition.Glob))
+		if err != nil {
+			return false, fmt.Errorf("failed to glob %s: %w", condition.Glob, err)
+		}
+		return len(matches) > condition.MinCount(), nil
+	}
+
+	_, err := fs.Stat(filepath.Join(h.projectRoot, condition.Path))
+	switch {
+	case err == nil:
+		return condition.ShouldExist(), nil
+	case errors.Is(err, os.ErrNotExist):
+		return !condition.ShouldExist(), nil
+	default:
+		return false, fmt.Errorf("failed to stat %s: %w", condition.Path, err)
+	}
+}
@@ -0,0 +1,110 @@
+package hooks
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wizzomafizzo/bumpers/internal/config"
+)
+
+// statErrorFs fails every Stat, like a directory the hook can't read
+type statErrorFs struct {
+	afero.Fs
+}
+
+func (statErrorFs) Stat(_ string) (os.FileInfo, error) {
+	return nil, os.ErrPermission
+}
+
+func whenFilesRule(conditions ...config.FileCondition) *config.Rule {
+	return &config.Rule{
+		Match:    "^npm publish",
+		Send:     "Releases need approval",
+		Generate: "off",
+		When:     &config.When{Files: conditions},
+	}
+}
+
+func TestFileConditionsHold(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, filepath.Join(testProjectRoot, ".release-approved"), nil, 0o600))
+	for _, name := range []string{"001.sql", "002.sql", "notes.md"} {
+		require.NoError(t, afero.WriteFile(fs, filepath.Join(testProjectRoot, "migrations", name), nil, 0o600))
+	}
+	processor := NewHookProcessor(&MockConfigValidator{}, testProjectRoot, nil)
+	processor.SetFileSystem(fs)
+
+	no := false
+	one, two := 1, 2
+	tests := []struct {
+		name      string
+		condition config.FileCondition
+		want      bool
+	}{
+		{name: "exists", condition: config.FileCondition{Path: ".release-approved"}, want: true},
+		{name: "missing", condition: config.FileCondition{Path: ".missing"}, want: false},
+		{name: "not exists", condition: config.FileCondition{Path: ".release-approved", Exists: &no}, want: false},
+		{name: "missing not exists", condition: config.FileCondition{Path: ".missing", Exists: &no}, want: true},
+		{name: "glob any", condition: config.FileCondition{Glob: "migrations/*.sql"}, want: true},
+		{name: "glob count_gt", condition: config.FileCondition{Glob: "migrations/*.sql", CountGt: &one}, want: true},
+		{name: "glob count_gt not met", condition: config.FileCondition{Glob: "migrations/*.sql", CountGt: &two}, want: false},
+		{name: "glob none", condition: config.FileCondition{Glob: "migrations/*.py"}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, processor.fileConditionsHold(ctx, whenFilesRule(tt.condition)))
+		})
+	}
+
+	both := whenFilesRule(config.FileCondition{Path: ".release-approved"}, config.FileCondition{Glob: "*.lock"})
+	assert.False(t, processor.fileConditionsHold(ctx, both), "every condition must hold")
+	assert.True(t, processor.fileConditionsHold(ctx, &config.Rule{Match: "x"}), "rules without conditions always hold")
+}
+
+func TestFileConditionsFilesystemError(t *testing.T) {
+	t.Parallel()
+
+	processor := NewHookProcessor(&MockConfigValidator{}, testProjectRoot, nil)
+	processor.SetFileSystem(statErrorFs{afero.NewMemMapFs()})
+
+	no := false
+	rule := whenFilesRule(config.FileCondition{Path: ".release-approved", Exists: &no})
+	assert.False(t, processor.fileConditionsHold(context.Background(), rule),
+		"a condition that can't be checked is false, even when it wants a missing file")
+
+	_, err := processor.fileConditionHolds(processor.getFileSystem(), &rule.When.Files[0])
+	assert.True(t, errors.Is(err, os.ErrPermission))
+}
+
+func TestPreToolUseWhenFiles(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	processor, validator, _, _ := newDecisionCacheProcessor(t, testDecisionCacheTTL)
+
+	no := false
+	validator.cfg = &config.Config{
+		Rules: []config.Rule{*whenFilesRule(config.FileCondition{Path: ".release-approved", Exists: &no})},
+	}
+	fs := afero.NewMemMapFs()
+	processor.SetFileSystem(fs)
+
+	publish := []byte(`{"hook_event_name": "PreToolUse", "tool_name": "Bash", "tool_input": {"command": "npm publish"}}`)
+	message, err := processor.ProcessPreToolUse(ctx, publish)
+	require.NoError(t, err)
+	assert.Equal(t, "Releases need approval", message)
+
+	require.NoError(t, afero.WriteFile(fs, filepath.Join(testProjectRoot, ".release-approved"), nil, 0o600))
+	message, err = processor.ProcessPreToolUse(ctx, publish)
+	require.NoError(t, err)
+	assert.Empty(t, message, "the marker file allows the command, without a cached decision getting in the way")
+}
@@ -7,8 +7,12 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/spf13/afero"
 	apptypes "github.com/wizzomafizzo/bumpers/internal/app/types"
@@ -17,14 +21,34 @@ import (
 	"github.com/wizzomafizzo/bumpers/internal/config"
 	"github.com/wizzomafizzo/bumpers/internal/constants"
 	"github.com/wizzomafizzo/bumpers/internal/hooks"
+	"github.com/wizzomafizzo/bumpers/internal/ignore"
 	"github.com/wizzomafizzo/bumpers/internal/logging"
 	"github.com/wizzomafizzo/bumpers/internal/matcher"
+	"github.com/wizzomafizzo/bumpers/internal/metrics"
 	"github.com/wizzomafizzo/bumpers/internal/rules"
 	"github.com/wizzomafizzo/bumpers/internal/storage"
 	"github.com/wizzomafizzo/bumpers/internal/template"
 )
 
-const intentFieldName = "#intent"
+const (
+	intentFieldName = "#intent"
+	// envFieldName is a synthetic source holding a Bash command's leading VAR=value
+	// assignments as KEY=VALUE lines
+	envFieldName = "#env"
+	// prevOutputFieldName is a synthetic source holding the most recent tool_result in the
+	// transcript, the output of the tool call before this one
+	prevOutputFieldName = "#prev_output"
+)
+
+// fieldMatch is the event field whose value triggered a rule match
+type fieldMatch struct {
+	Name  string
+	Value string
+}
+
+// pathInputFields are the tool_input fields holding file paths, checked against
+// .bumpersignore and the global allow list
+var pathInputFields = []string{"file_path", "path", "notebook_path"}
 
 // HookProcessor handles all hook-related processing including pre/post tool use
 type HookProcessor interface {
@@ -38,7 +62,12 @@ type DefaultHookProcessor struct {
 	configValidator apptypes.ConfigValidator
 	aiGenerator     ai.MessageGenerator
 	stateManager    *storage.StateManager
-	projectRoot     string
+	ignoreMatcher   *ignore.Matcher
+	fileSystem      afero.Fs
+	// now is the clock cached decisions expire by, time.Now when nil
+	now         func() time.Time
+	projectRoot string
+	ignoreOnce  sync.Once
 }
 
 // NewHookProcessor creates a new HookProcessor
@@ -73,7 +102,8 @@ func (h *DefaultHookProcessor) ProcessHook(ctx context.Context, input io.Reader)
 		logger.Error().Err(err).Msg("Failed to detect hook type")
 		return apptypes.ProcessResult{}, fmt.Errorf("failed to detect hook type: %w", err)
 	}
-	logger.Debug().RawJSON("hook", rawJSON).Str("type", hookType.String()).Msg("received hook")
+	// Tool inputs are logged once the config is loaded, so they can be redacted
+	logger.Debug().Int("bytes", len(rawJSON)).Str("type", hookType.String()).Msg("received hook")
 
 	// Route to appropriate handler based on hook type and convert response to ProcessResult
 	var response string
@@ -98,6 +128,7 @@ func (h *DefaultHookProcessor) shouldSkipProcessing(ctx context.Context) bool {
 	if h.stateManager == nil {
 		return false
 	}
+	defer metrics.FromContext(ctx).Track(metrics.StageState)()
 
 	logger := logging.Get(ctx)
 
@@ -134,23 +165,71 @@ func (h *DefaultHookProcessor) ProcessPreToolUse(ctx context.Context, rawJSON js
 		return "", nil
 	}
 
+	if calls, ok, err := batchToolCalls(rawJSON); err != nil {
+		return "", err
+	} else if ok {
+		return h.processPreToolUseBatch(ctx, calls)
+	}
+	return h.processPreToolUseCached(ctx, rawJSON)
+}
+
+// processPreToolUseEvent runs the rule pipeline for a single tool call, recording any
+// message it sends for loop protection. It also returns the rule that matched, or nil.
+func (h *DefaultHookProcessor) processPreToolUseEvent(
+	ctx context.Context, rawJSON json.RawMessage,
+) (string, *config.Rule, error) {
+	message, matchedRule, err := h.evaluatePreToolUseEvent(ctx, rawJSON)
+	if err == nil {
+		h.recordOwnMessage(ctx, hookSessionID(rawJSON), message)
+	}
+	return message, matchedRule, err
+}
+
+// evaluatePreToolUseEvent returns the response to a single tool call and the rule that
+// matched it, if any
+func (h *DefaultHookProcessor) evaluatePreToolUseEvent(
+	ctx context.Context, rawJSON json.RawMessage,
+) (string, *config.Rule, error) {
+	logger := logging.Get(ctx)
+
 	var event hooks.HookEvent
 	if unmarshalErr := json.Unmarshal(rawJSON, &event); unmarshalErr != nil {
-		return "", fmt.Errorf("failed to parse hook input: %w", unmarshalErr)
+		return "", nil, fmt.Errorf("failed to parse hook input: %w", unmarshalErr)
+	}
+
+	// Claude can't grant its own approvals, whatever the config says
+	if selfApproval(&event) {
+		logger.Warn().Msg("blocking tool call running bumpers approve")
+		return selfApprovalMessage, nil, nil
 	}
 
 	// Check operation state - block editing tools if in plan mode
-	if h.stateManager != nil {
-		operationState, err := h.stateManager.GetOperationMode(ctx)
-		if err != nil {
-			logger.Debug().Err(err).Msg("Failed to get operation state, proceeding with normal processing")
-		} else if operationState != nil && operationState.Mode == rules.PlanMode &&
-			h.isEditingTool(event.ToolName) {
-			message := "You're currently in plan mode. Please discuss your planned changes first, " +
-				"then use a trigger phrase like 'make it so', 'go ahead', or 'proceed' to enter " +
-				"execute mode."
-			return message, nil
-		}
+	if message := h.planModeMessage(ctx, event.ToolName); message != "" {
+		return message, nil, nil
+	}
+
+	// Load config and create matcher
+	stopConfigLoad := metrics.FromContext(ctx).Track(metrics.StageConfigLoad)
+	cfg, _, err := h.configValidator.LoadConfigAndMatcher(ctx)
+	stopConfigLoad()
+	if h.configMissing(ctx, event.SessionID, err) {
+		return "", nil, nil
+	}
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	redactor := newLogRedactor(&cfg.Settings)
+	ctx = withLogRedactor(ctx, redactor)
+
+	// Tools outside allowed_tools are blocked under tool_policy: deny, even for ignored paths
+	if !cfg.Settings.ToolAllowed(event.ToolName) {
+		message, deniedErr := toolDeniedMessage(ctx, &cfg.Settings, &event)
+		return message, nil, deniedErr
+	}
+
+	// Skip rule evaluation entirely for paths listed in .bumpersignore
+	if h.isIgnoredPath(ctx, &event) {
+		return "", nil, nil
 	}
 
 	// Extract intent from transcript if available
@@ -159,35 +238,327 @@ func (h *DefaultHookProcessor) ProcessPreToolUse(ctx context.Context, rawJSON js
 		intentContent = h.ExtractAndLogIntent(ctx, &event)
 	}
 
-	// Log summary of available sources for rule matching
-	logger.Debug().
-		Str("hook_type", constants.PreToolUseEvent).
-		Str("tool_name", event.ToolName).
-		Str("extracted_intent", intentContent).
-		Interface("tool_input", event.ToolInput).
-		Msg("Hook processing summary - sources available for rule matching")
-
-	// Load config and create matcher
-	cfg, _, err := h.configValidator.LoadConfigAndMatcher(ctx)
-	if err != nil {
-		return "", fmt.Errorf("failed to load config: %w", err)
+	// Global allow list is checked before any rule matching
+	if h.isAllowlisted(ctx, cfg.Allow, &event) {
+		return "", nil, nil
 	}
 
+	// Cap oversized values so large Write contents don't slow down matching
+	stopMatch := metrics.FromContext(ctx).Track(metrics.StageMatch)
+	originals := capToolInput(ctx, &event, cfg.Settings.GetMaxMatchBytes())
+
 	// Filter and process pre-event rules
 	preRules := h.filterPreEventRules(cfg.Rules)
 	ruleMatcher, err := matcher.NewRuleMatcher(preRules)
 	if err != nil {
-		return "", fmt.Errorf("failed to create rule matcher: %w", err)
+		stopMatch()
+		return "", nil, fmt.Errorf("failed to create rule matcher: %w", err)
 	}
+	ruleMatcher.SetSelectMode(cfg.Output.Select)
 
 	// Find matching rule
-	matchedRule, matchedValue := h.findMatchingPreRule(ctx, preRules, ruleMatcher, &event)
+	matchedRule, matched := h.findMatchingPreRule(ctx, preRules, ruleMatcher, &event, cfg.Output.Select)
+	stopMatch()
+
+	// Log summary of available sources, redacted by the matched rule's log mode
+	logger.Debug().
+		Str("hook_type", constants.PreToolUseEvent).
+		Str("tool_name", event.ToolName).
+		Fields(redactor.fields(redactor.summaryRule(matchedRule), map[string]any{
+			"extracted_intent": intentContent,
+			"tool_input":       event.ToolInput,
+		})).
+		Msg("Hook processing summary - sources available for rule matching")
+
+	h.recordShadowMatches(ctx, preRules, ruleMatcher, &event, originals, &cfg.Settings)
 	if matchedRule == nil {
-		return "", nil
+		return "", nil, nil
 	}
 
 	// Process and return response
-	return h.processMatchedRule(ctx, matchedRule, matchedValue)
+	ruleCtx := template.RuleContext{
+		Command:      displayMatchedValue(matched.Value, originals, cfg.Settings.GetMaxDisplayBytes()),
+		ToolName:     event.ToolName,
+		MatchedField: matched.Name,
+		SessionID:    event.SessionID,
+	}
+	approvalToken := ""
+	if matchedRule.Approval == config.ApprovalSession {
+		var approved bool
+		if approved, approvalToken = h.sessionApproval(ctx, matchedRule, matched.Value, event.SessionID); approved {
+			return "", nil, nil
+		}
+	}
+	message, err := h.processMatchedRule(ctx, matchedRule, ruleCtx, &cfg.Settings)
+	if err == nil && message != "" && approvalToken != "" {
+		message += approvalInstructions(approvalToken)
+	}
+	if err == nil && message != "" && matchedRule.Replace != "" {
+		message, err = replacementResponse(ctx, matchedRule, ruleCtx, message)
+	}
+	if err == nil {
+		h.startRuleExec(ctx, matchedRule, "pre", ruleCtx.Command)
+	}
+	return message, matchedRule, err
+}
+
+// configMissing reports whether err means the config file doesn't exist, e.g. after checking
+// out a branch without one. Such hooks are allowed, with a warning logged once per session.
+func (h *DefaultHookProcessor) configMissing(ctx context.Context, sessionID string, err error) bool {
+	if !errors.Is(err, config.ErrConfigNotFound) {
+		return false
+	}
+
+	warn := true
+	if h.stateManager != nil {
+		marked, markErr := h.stateManager.MarkMissingConfigWarned(ctx, sessionID)
+		if markErr != nil {
+			logging.Get(ctx).Debug().Err(markErr).Msg("failed to record missing config warning")
+		}
+		warn = markErr != nil || marked
+	}
+
+	if warn {
+		logging.Get(ctx).Warn().Err(err).Msg("config file not found, allowing tool calls until it is restored")
+	} else {
+		logging.Get(ctx).Debug().Err(err).Msg("config file not found, allowing tool call")
+	}
+	return true
+}
+
+// replacementPrefix starts the line naming a rule's replacement command, so tools reading
+// the deny reason can find it: "Run exactly: `just test`"
+const replacementPrefix = "Run exactly: "
+
+// preToolUseOutput is the PreToolUse JSON output denying a tool call with a reason
+type preToolUseOutput struct {
+	HookEventName            string `json:"hookEventName"`            //nolint:tagliatelle // Claude Code API format
+	PermissionDecision       string `json:"permissionDecision"`       //nolint:tagliatelle // Claude Code API format
+	PermissionDecisionReason string `json:"permissionDecisionReason"` //nolint:tagliatelle // Claude Code API format
+}
+
+// replacementResponse renders the rule's replace template and returns a PreToolUse deny
+// decision whose reason is message followed by the exact command to run instead. Claude Code
+// can't rewrite a Bash command from a hook, so the replacement is suggested, not applied.
+func replacementResponse(
+	ctx context.Context, matchedRule *config.Rule, ruleCtx template.RuleContext, message string,
+) (string, error) {
+	stopTemplate := metrics.FromContext(ctx).Track(metrics.StageTemplate)
+	replacement, err := template.ExecuteRuleTemplate(matchedRule.Replace, ruleCtx)
+	stopTemplate()
+	if err != nil {
+		return "", fmt.Errorf("failed to process replace template: %w", err)
+	}
+	replacement = strings.TrimSpace(replacement)
+	if replacement == "" {
+		logging.Get(ctx).Warn().
+			Str("pattern", matchedRule.GetMatch().Pattern).
+			Msg("rule replace template rendered empty, sending message only")
+		return message, nil
+	}
+
+	response, err := json.Marshal(map[string]any{
+		"hookSpecificOutput": preToolUseOutput{
+			HookEventName:            constants.PreToolUseEvent,
+			PermissionDecision:       "deny",
+			PermissionDecisionReason: fmt.Sprintf("%s\n\n%s`%s`", message, replacementPrefix, replacement),
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal replacement response: %w", err)
+	}
+	return string(response), nil
+}
+
+// planModeMessage returns the message blocking an editing tool while in plan mode, or ""
+func (h *DefaultHookProcessor) planModeMessage(ctx context.Context, toolName string) string {
+	if h.stateManager == nil {
+		return ""
+	}
+	defer metrics.FromContext(ctx).Track(metrics.StageState)()
+
+	operationState, err := h.stateManager.GetOperationMode(ctx)
+	if err != nil {
+		logging.Get(ctx).Debug().Err(err).Msg("Failed to get operation state, proceeding with normal processing")
+		return ""
+	}
+	if operationState == nil || operationState.Mode != rules.PlanMode || !h.isEditingTool(toolName) {
+		return ""
+	}
+	return "You're currently in plan mode. Please discuss your planned changes first, " +
+		"then use a trigger phrase like 'make it so' or 'go ahead' to enter " +
+		"execute mode."
+}
+
+// capToolInput replaces string tool inputs longer than maxBytes with a head+tail sample
+// and returns the original values keyed by their sample
+func capToolInput(ctx context.Context, event *hooks.HookEvent, maxBytes int) map[string]string {
+	originals := make(map[string]string)
+	capped := make(map[string]any, len(event.ToolInput))
+	for key, value := range event.ToolInput {
+		capped[key] = value
+
+		strValue, ok := value.(string)
+		if !ok {
+			continue
+		}
+		sampled, truncated := matcher.SampleValue(strValue, maxBytes)
+		if !truncated {
+			continue
+		}
+
+		logging.Get(ctx).Debug().
+			Str("field", key).
+			Int("original_bytes", len(strValue)).
+			Int("max_match_bytes", maxBytes).
+			Msg("tool input exceeds max_match_bytes, matching against head and tail sample")
+		capped[key] = sampled
+		originals[sampled] = strValue
+	}
+	event.ToolInput = capped
+	return originals
+}
+
+// displayMatchedValue returns the value exposed to templates: the original untruncated
+// value when it fits within maxBytes, otherwise a sample of it
+func displayMatchedValue(matchedValue string, originals map[string]string, maxBytes int) string {
+	original, ok := originals[matchedValue]
+	if !ok {
+		original = matchedValue
+	}
+	display, _ := matcher.SampleValue(original, maxBytes)
+	return display
+}
+
+// loadIgnoreMatcher parses the project's .bumpersignore once per processor
+func (h *DefaultHookProcessor) loadIgnoreMatcher(ctx context.Context) *ignore.Matcher {
+	h.ignoreOnce.Do(func() {
+		if h.projectRoot == "" {
+			return
+		}
+		ignorePath := filepath.Join(h.projectRoot, constants.IgnoreFilename)
+		matcher, err := ignore.Load(ignorePath)
+		if err != nil {
+			logging.Get(ctx).Warn().Err(err).Str("path", ignorePath).Msg("failed to load ignore file")
+			return
+		}
+		h.ignoreMatcher = matcher
+	})
+	return h.ignoreMatcher
+}
+
+// isIgnoredPath reports whether any path in the tool input matches .bumpersignore
+func (h *DefaultHookProcessor) isIgnoredPath(ctx context.Context, event *hooks.HookEvent) bool {
+	defer metrics.FromContext(ctx).Track(metrics.StageMatch)()
+
+	ignoreMatcher := h.loadIgnoreMatcher(ctx)
+	if ignoreMatcher.Empty() {
+		return false
+	}
+
+	for _, field := range pathInputFields {
+		path, ok := event.ToolInput[field].(string)
+		if !ok || !ignoreMatcher.MatchPath(h.projectRoot, path) {
+			continue
+		}
+		logging.Get(ctx).Debug().
+			Str("field", field).
+			Str("path", path).
+			Msg("path matches .bumpersignore, skipping rule evaluation")
+		return true
+	}
+	return false
+}
+
+// templateContext returns the context used to expand match, except and allow patterns
+func (h *DefaultHookProcessor) templateContext() map[string]any {
+	templateContext := make(map[string]any)
+	if h.projectRoot != "" {
+		templateContext["ProjectRoot"] = h.projectRoot
+	}
+	return templateContext
+}
+
+// toolDeniedMessage renders settings.tool_denied_message for a tool blocked by tool_policy
+func toolDeniedMessage(ctx context.Context, settings *config.Settings, event *hooks.HookEvent) (string, error) {
+	logging.Get(ctx).Debug().Str("tool_name", event.ToolName).Msg("tool is not in allowed_tools, denied by tool_policy")
+	message, err := template.ExecuteRuleTemplate(settings.GetToolDeniedMessage(), template.RuleContext{
+		ToolName:  event.ToolName,
+		SessionID: event.SessionID,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to process tool_denied_message template: %w", err)
+	}
+	return message, nil
+}
+
+// isAllowlisted reports whether the Bash command or a file tool path exactly matches
+// an entry in the global allow list
+func (h *DefaultHookProcessor) isAllowlisted(ctx context.Context, allow []string, event *hooks.HookEvent) bool {
+	if len(allow) == 0 {
+		return false
+	}
+	defer metrics.FromContext(ctx).Track(metrics.StageMatch)()
+
+	templateContext := h.templateContext()
+	for _, entry := range allow {
+		expanded := matcher.ExpandPattern(entry, templateContext)
+
+		if command, ok := event.ToolInput["command"].(string); ok && event.ToolName == "Bash" {
+			if strings.TrimSpace(command) == strings.TrimSpace(expanded) {
+				logging.Get(ctx).Debug().
+					Fields(redactorFrom(ctx).fields(nil, map[string]any{"allow": entry, "command": command})).
+					Msg("command is on the allow list, skipping rule evaluation")
+				return true
+			}
+			continue
+		}
+
+		for _, field := range pathInputFields {
+			path, ok := event.ToolInput[field].(string)
+			if !ok || !h.pathsEqual(path, expanded) {
+				continue
+			}
+			logging.Get(ctx).Debug().
+				Fields(redactorFrom(ctx).fields(nil, map[string]any{"allow": entry, "path": path})).
+				Msg("path is on the allow list, skipping rule evaluation")
+			return true
+		}
+	}
+	return false
+}
+
+// pathsEqual compares paths after cleaning, resolving a relative allow entry against the project root
+func (h *DefaultHookProcessor) pathsEqual(path, entry string) bool {
+	if path == "" || entry == "" {
+		return false
+	}
+	path = filepath.Clean(path)
+	if filepath.Clean(entry) == path {
+		return true
+	}
+	if !filepath.IsAbs(entry) && h.projectRoot != "" {
+		return filepath.Join(h.projectRoot, entry) == path
+	}
+	return false
+}
+
+// newSingleRuleMatcher creates a matcher for one rule that traces exception hits
+func (*DefaultHookProcessor) newSingleRuleMatcher(ctx context.Context, rule *config.Rule) (*matcher.RuleMatcher, error) {
+	ruleMatcher, err := matcher.NewRuleMatcher([]config.Rule{*rule})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create rule matcher: %w", err)
+	}
+	ruleMatcher.SetExceptionHook(func(rule *config.Rule, value, exception string) {
+		redactor := redactorFrom(ctx)
+		redactor.noteExcepted(rule)
+		logging.Get(ctx).Debug().
+			Str("pattern", rule.GetMatch().Pattern).
+			Fields(redactor.fields(rule, map[string]any{"value": value})).
+			Str("exception", exception).
+			Msg("rule matched but was suppressed by an except entry")
+	})
+	return ruleMatcher, nil
 }
 
 // filterPreEventRules filters rules for pre events
@@ -205,13 +576,69 @@ func (*DefaultHookProcessor) filterPreEventRules(ruleList []config.Rule) []confi
 	return preRules
 }
 
+// cachedSettings returns the (cached) config's settings, or the defaults when it can't be loaded
+func (h *DefaultHookProcessor) cachedSettings(ctx context.Context) *config.Settings {
+	if h.configValidator == nil {
+		return &config.Settings{}
+	}
+	cfg, _, err := h.configValidator.LoadConfigAndMatcher(ctx)
+	if err != nil || cfg == nil {
+		return &config.Settings{}
+	}
+	return &cfg.Settings
+}
+
+// findRecentIntent extracts the most recent intent from the last maxDepth transcript lines,
+// bounded by settings.intent_scan_lines, reading only recent lines of large transcripts
+func (h *DefaultHookProcessor) findRecentIntent(
+	ctx context.Context, transcriptPath string, maxDepth int,
+) (string, error) {
+	settings := h.cachedSettings(ctx)
+	intent, err := transcript.FindRecentToolUseAndExtractIntentWithLimit(
+		ctx, transcriptPath, settings.GetMaxIntentTokens(), settings.LimitIntentDepth(maxDepth))
+	if intentReadCanceled(ctx, err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to extract recent intent: %w", err)
+	}
+	return intent, nil
+}
+
+// intentByToolUseID extracts the intent for the tool use with toolUseID from the last
+// maxDepth transcript lines, bounded by settings.intent_scan_lines
+func (h *DefaultHookProcessor) intentByToolUseID(
+	ctx context.Context, transcriptPath, toolUseID string, maxDepth int,
+) (string, error) {
+	depth := h.cachedSettings(ctx).LimitIntentDepth(maxDepth)
+	intent, err := transcript.ExtractIntentByToolUseIDWithContext(ctx, transcriptPath, toolUseID, depth)
+	if intentReadCanceled(ctx, err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to extract intent by tool use ID: %w", err)
+	}
+	return intent, nil
+}
+
+// intentReadCanceled reports whether a transcript read was cut short by ctx, which is
+// treated as there being no intent rather than as a hook error
+func intentReadCanceled(ctx context.Context, err error) bool {
+	if err == nil || (!errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)) {
+		return false
+	}
+	logging.Get(ctx).Debug().Err(err).Msg("transcript read canceled, continuing without intent")
+	return true
+}
+
 // ExtractAndLogIntent extracts and logs intent content from transcript (public for testing)
-func (*DefaultHookProcessor) ExtractAndLogIntent(ctx context.Context, event *hooks.HookEvent) string {
+func (h *DefaultHookProcessor) ExtractAndLogIntent(ctx context.Context, event *hooks.HookEvent) string {
 	if event.TranscriptPath == "" {
 		return ""
 	}
+	defer metrics.FromContext(ctx).Track(metrics.StageIntent)()
 
-	intentContent, err := transcript.FindRecentToolUseAndExtractIntent(ctx, event.TranscriptPath)
+	intentContent, err := h.findRecentIntent(ctx, event.TranscriptPath, config.DefaultMaxIntentDepth)
 	if err != nil {
 		logging.Get(ctx).Debug().Err(err).
 			Str("transcript_path", event.TranscriptPath).
@@ -221,32 +648,98 @@ func (*DefaultHookProcessor) ExtractAndLogIntent(ctx context.Context, event *hoo
 
 	logging.Get(ctx).Debug().
 		Str("transcript_path", event.TranscriptPath).
-		Str("extracted_intent", intentContent).
+		Int("intent_length", len(intentContent)).
 		Msg("Intent extracted from transcript for hook processing")
 
 	return intentContent
 }
 
-// findMatchingPreRule finds the first rule that matches the event
+// findMatchingPreRule finds the rule that matches the event: the first in config order,
+// or the most specific when selectMode is config.SelectSpecific. Shadow rules are skipped.
 func (h *DefaultHookProcessor) findMatchingPreRule(
 	ctx context.Context, preRules []config.Rule, ruleMatcher *matcher.RuleMatcher, event *hooks.HookEvent,
-) (rule *config.Rule, matchedField string) {
+	selectMode string,
+) (rule *config.Rule, matched fieldMatch) {
+	collector := metrics.FromContext(ctx)
+	bestScore := -1
 	for i := range preRules {
-		rule := &preRules[i]
+		if preRules[i].GetMatch().Shadow {
+			continue
+		}
+		collector.Add(metrics.RulesEvaluated, 1)
+		matchedRule, field := h.checkRuleSources(ctx, &preRules[i], ruleMatcher, event)
+		if matchedRule == nil {
+			continue
+		}
+		if selectMode != config.SelectSpecific {
+			return matchedRule, field
+		}
 
-		if matchedRule, matchedValue := h.checkRuleSources(ctx, rule, ruleMatcher, event); matchedRule != nil {
-			return matchedRule, matchedValue
+		// Earlier rules win ties, so only a strictly higher score replaces the match
+		if score := matcher.Specificity(matchedRule); score > bestScore {
+			rule, matched, bestScore = matchedRule, field, score
 		}
 	}
-	return nil, ""
+	if rule != nil {
+		logging.Get(ctx).Debug().
+			Str("pattern", rule.GetMatch().Pattern).
+			Int("specificity", bestScore).
+			Msg("selected most specific matching rule")
+	}
+	return rule, matched
+}
+
+// recordShadowMatches logs and audits every shadow rule matching the event with the message it
+// would have sent, rendered from its send template without AI generation. Shadow rules never
+// change the hook's response or run their actions.
+func (h *DefaultHookProcessor) recordShadowMatches(
+	ctx context.Context, preRules []config.Rule, ruleMatcher *matcher.RuleMatcher, event *hooks.HookEvent,
+	originals map[string]string, settings *config.Settings,
+) {
+	for i := range preRules {
+		if !preRules[i].GetMatch().Shadow {
+			continue
+		}
+		shadowRule, matched := h.checkRuleSources(ctx, &preRules[i], ruleMatcher, event)
+		if shadowRule == nil {
+			continue
+		}
+		ruleCtx := template.RuleContext{
+			Command:      displayMatchedValue(matched.Value, originals, settings.GetMaxDisplayBytes()),
+			ToolName:     event.ToolName,
+			MatchedField: matched.Name,
+			SessionID:    event.SessionID,
+		}
+		message, err := template.ExecuteRuleTemplate(shadowRule.Send, ruleCtx)
+		if err != nil {
+			logging.Get(ctx).Warn().Err(err).Str("pattern", shadowRule.GetMatch().Pattern).
+				Msg("failed to render shadow rule message")
+		}
+		auditShadowMatch(ctx, shadowRule, settings, matchRecord{
+			Event:   "pre",
+			Tool:    ruleCtx.ToolName,
+			Field:   ruleCtx.MatchedField,
+			Value:   ruleCtx.Command,
+			Message: message,
+		})
+	}
 }
 
 // checkRuleSources checks if rule matches using sources or fallback behavior
 func (h *DefaultHookProcessor) checkRuleSources(
 	ctx context.Context, rule *config.Rule, ruleMatcher *matcher.RuleMatcher, event *hooks.HookEvent,
-) (matchedRule *config.Rule, matchedField string) {
+) (matchedRule *config.Rule, matched fieldMatch) {
 	match := rule.GetMatch()
-	if len(match.Sources) > 0 {
+	if !toolInputHasFields(event.ToolInput, match.Fields) || !h.fileConditionsHold(ctx, rule) {
+		return nil, fieldMatch{}
+	}
+	if match.StripEnv {
+		event = withoutEnvAssignments(event)
+	}
+	if match.ResolvePaths {
+		event = h.withResolvedPaths(event)
+	}
+	if len(match.Sources) > 0 || match.SourceFieldRegex != "" {
 		return h.checkSpecificSources(ctx, rule, ruleMatcher, event)
 	}
 	return h.checkOriginalBehavior(ctx, rule, event)
@@ -255,17 +748,60 @@ func (h *DefaultHookProcessor) checkRuleSources(
 // checkSpecificSources checks only specified source fields
 func (h *DefaultHookProcessor) checkSpecificSources(
 	ctx context.Context, rule *config.Rule, ruleMatcher *matcher.RuleMatcher, event *hooks.HookEvent,
-) (matchedRule *config.Rule, matchedField string) {
+) (matchedRule *config.Rule, matched fieldMatch) {
 	match := rule.GetMatch()
 	for _, fieldName := range match.Sources {
-		if matched, content := h.checkIntentSource(ctx, fieldName, rule, ruleMatcher, event); matched {
-			return rule, content
+		if ok, content := h.checkIntentSource(ctx, fieldName, rule, ruleMatcher, event); ok {
+			return rule, fieldMatch{Name: fieldName, Value: content}
+		}
+		if ok, content := h.checkEnvSource(ctx, fieldName, rule, ruleMatcher, event); ok {
+			return rule, fieldMatch{Name: fieldName, Value: content}
+		}
+		if ok, content := h.checkPrevOutputSource(ctx, fieldName, rule, ruleMatcher, event); ok {
+			return rule, fieldMatch{Name: fieldName, Value: content}
 		}
-		if matched, content := h.checkToolInputSource(fieldName, rule, ruleMatcher, event); matched {
-			return rule, content
+		if ok, content := h.checkToolInputSource(ctx, fieldName, rule, ruleMatcher, event); ok {
+			return rule, fieldMatch{Name: fieldName, Value: content}
 		}
 	}
-	return nil, ""
+	for _, fieldName := range fieldsMatchingKey(match.SourceFieldRegex, event.ToolInput) {
+		if ok, content := h.checkToolInputSource(ctx, fieldName, rule, ruleMatcher, event); ok {
+			return rule, fieldMatch{Name: fieldName, Value: content}
+		}
+	}
+	return nil, fieldMatch{}
+}
+
+// toolInputHasFields reports whether every field in fields is present in toolInput with the
+// same value once stringified, so match.fields can compare booleans and numbers
+func toolInputHasFields(toolInput map[string]any, fields map[string]string) bool {
+	for name, want := range fields {
+		value, exists := toolInput[name]
+		if !exists || config.FieldString(value) != want {
+			return false
+		}
+	}
+	return true
+}
+
+// fieldsMatchingKey returns the tool_input keys matching keyPattern in sorted order, so
+// the first matching field is the same on every run
+func fieldsMatchingKey(keyPattern string, toolInput map[string]any) []string {
+	if keyPattern == "" {
+		return nil
+	}
+	keyRe, err := regexp.Compile(keyPattern)
+	if err != nil {
+		return nil
+	}
+	var fields []string
+	for key := range toolInput {
+		if keyRe.MatchString(key) {
+			fields = append(fields, key)
+		}
+	}
+	slices.Sort(fields)
+	return fields
 }
 
 // checkIntentSource handles #intent source field
@@ -281,25 +817,83 @@ func (h *DefaultHookProcessor) checkIntentSource(
 
 	var intentContent string
 	var err error
+	match := rule.GetMatch()
+	maxDepth := match.GetMaxIntentDepth()
 
 	if event.ToolUseID != "" {
 		// Use precise tool-use-ID based extraction
-		intentContent, err = transcript.ExtractIntentByToolUseIDWithContext(
-			ctx, event.TranscriptPath, event.ToolUseID)
+		intentContent, err = h.intentByToolUseID(ctx, event.TranscriptPath, event.ToolUseID, maxDepth)
 	} else {
 		// Use new reliable method that scans backwards for recent tool use
-		intentContent, err = transcript.FindRecentToolUseAndExtractIntent(ctx, event.TranscriptPath)
+		intentContent, err = h.findRecentIntent(ctx, event.TranscriptPath, maxDepth)
 	}
 
 	if err != nil || strings.TrimSpace(intentContent) == "" {
 		return false, ""
 	}
-	return h.matchRuleContent(intentContent, rule, ruleMatcher, event.ToolName)
+	return h.matchRuleContent(ctx, intentContent, rule, ruleMatcher, event.ToolName)
+}
+
+// checkEnvSource handles the #env source field
+func (h *DefaultHookProcessor) checkEnvSource(
+	ctx context.Context, fieldName string, rule *config.Rule, ruleMatcher *matcher.RuleMatcher, event *hooks.HookEvent,
+) (matched bool, content string) {
+	if fieldName != envFieldName || event.ToolName != "Bash" {
+		return false, ""
+	}
+	command, ok := event.ToolInput["command"].(string)
+	if !ok {
+		return false, ""
+	}
+	env, _ := matcher.SplitEnvAssignments(command)
+	if len(env) == 0 {
+		return false, ""
+	}
+	return h.matchRuleContent(ctx, strings.Join(env, "\n"), rule, ruleMatcher, event.ToolName)
+}
+
+// checkPrevOutputSource handles the #prev_output source field, searching the same transcript
+// lines as #intent
+func (h *DefaultHookProcessor) checkPrevOutputSource(
+	ctx context.Context, fieldName string, rule *config.Rule, ruleMatcher *matcher.RuleMatcher, event *hooks.HookEvent,
+) (matched bool, content string) {
+	if fieldName != prevOutputFieldName || event.TranscriptPath == "" {
+		return false, ""
+	}
+	match := rule.GetMatch()
+	depth := h.cachedSettings(ctx).LimitIntentDepth(match.GetMaxIntentDepth())
+	output, err := transcript.FindPreviousToolOutput(ctx, event.TranscriptPath, depth)
+	if err != nil {
+		logging.Get(ctx).Debug().Err(err).Str("transcript_path", event.TranscriptPath).
+			Msg("no previous tool output for #prev_output")
+		return false, ""
+	}
+	if strings.TrimSpace(output) == "" {
+		return false, ""
+	}
+	return h.matchRuleContent(ctx, output, rule, ruleMatcher, event.ToolName)
+}
+
+// withoutEnvAssignments returns a copy of a Bash event with leading VAR=value
+// assignments removed from its command
+func withoutEnvAssignments(event *hooks.HookEvent) *hooks.HookEvent {
+	command, ok := event.ToolInput["command"].(string)
+	if event.ToolName != "Bash" || !ok {
+		return event
+	}
+
+	stripped := *event
+	stripped.ToolInput = make(map[string]any, len(event.ToolInput))
+	for key, value := range event.ToolInput {
+		stripped.ToolInput[key] = value
+	}
+	_, stripped.ToolInput["command"] = matcher.SplitEnvAssignments(command)
+	return &stripped
 }
 
 // checkToolInputSource handles regular ToolInput fields
 func (h *DefaultHookProcessor) checkToolInputSource(
-	fieldName string, rule *config.Rule, ruleMatcher *matcher.RuleMatcher, event *hooks.HookEvent,
+	ctx context.Context, fieldName string, rule *config.Rule, ruleMatcher *matcher.RuleMatcher, event *hooks.HookEvent,
 ) (matched bool, content string) {
 	value, exists := event.ToolInput[fieldName]
 	if !exists {
@@ -309,26 +903,20 @@ func (h *DefaultHookProcessor) checkToolInputSource(
 	if !ok {
 		return false, ""
 	}
-	return h.matchRuleContent(strValue, rule, ruleMatcher, event.ToolName)
+	return h.matchRuleContent(ctx, strValue, rule, ruleMatcher, event.ToolName)
 }
 
 // matchRuleContent checks if content matches rule pattern
 func (h *DefaultHookProcessor) matchRuleContent(
-	content string, rule *config.Rule, _ *matcher.RuleMatcher, toolName string,
+	ctx context.Context, content string, rule *config.Rule, _ *matcher.RuleMatcher, toolName string,
 ) (matched bool, matchedContent string) {
-	// Create template context with project information
-	templateContext := make(map[string]any)
-	if h.projectRoot != "" {
-		templateContext["ProjectRoot"] = h.projectRoot
-	}
-
 	// Create a temporary matcher with just this single rule to test if content matches
-	tempMatcher, err := matcher.NewRuleMatcher([]config.Rule{*rule})
+	tempMatcher, err := h.newSingleRuleMatcher(ctx, rule)
 	if err != nil {
 		return false, ""
 	}
 
-	foundRule, err := tempMatcher.MatchWithContext(content, toolName, templateContext)
+	foundRule, err := tempMatcher.MatchWithContext(content, toolName, h.templateContext())
 	isMatch := err == nil && foundRule != nil
 	if isMatch {
 		return true, content
@@ -338,34 +926,34 @@ func (h *DefaultHookProcessor) matchRuleContent(
 
 // checkOriginalBehavior uses original matching behavior for backward compatibility
 func (h *DefaultHookProcessor) checkOriginalBehavior(ctx context.Context, rule *config.Rule, event *hooks.HookEvent) (
-	matchedRule *config.Rule, matchedField string,
+	matchedRule *config.Rule, matched fieldMatch,
 ) {
-	tempMatcher, err := matcher.NewRuleMatcher([]config.Rule{*rule})
+	tempMatcher, err := h.newSingleRuleMatcher(ctx, rule)
 	if err != nil {
-		return nil, ""
+		return nil, fieldMatch{}
 	}
 
-	foundRule, foundValue, err := h.findMatchingRule(ctx, tempMatcher, event)
+	foundRule, found, err := h.findMatchingRule(ctx, tempMatcher, event)
 	if err == nil && foundRule != nil {
-		return foundRule, foundValue
+		return foundRule, found
 	}
-	return nil, ""
+	return nil, fieldMatch{}
 }
 
 func (h *DefaultHookProcessor) findMatchingRule(
 	ctx context.Context, ruleMatcher *matcher.RuleMatcher, event *hooks.HookEvent,
-) (*config.Rule, string, error) {
+) (*config.Rule, fieldMatch, error) {
 	fieldsToCheck := h.getFieldsToCheck(ctx, event)
 
 	for _, key := range fieldsToCheck {
 		if rule, value, err := h.tryMatchField(key, event, ruleMatcher); err != nil {
-			return nil, "", err
+			return nil, fieldMatch{}, err
 		} else if rule != nil {
-			return rule, value, nil
+			return rule, fieldMatch{Name: key, Value: value}, nil
 		}
 	}
 
-	return nil, "", nil
+	return nil, fieldMatch{}, nil
 }
 
 // getFieldsToCheck determines which fields to check for a given tool
@@ -402,13 +990,7 @@ func (h *DefaultHookProcessor) tryMatchField(
 		return nil, "", nil
 	}
 
-	// Create template context with project information
-	templateContext := make(map[string]any)
-	if h.projectRoot != "" {
-		templateContext["ProjectRoot"] = h.projectRoot
-	}
-
-	rule, err := ruleMatcher.MatchWithContext(strValue, event.ToolName, templateContext)
+	rule, err := ruleMatcher.MatchWithContext(strValue, event.ToolName, h.templateContext())
 	if err != nil {
 		if errors.Is(err, matcher.ErrNoRuleMatch) {
 			return nil, "", nil // Try next field
@@ -419,12 +1001,49 @@ func (h *DefaultHookProcessor) tryMatchField(
 	return rule, strValue, nil
 }
 
-// processMatchedRule processes template and AI generation for matched rule
+// processMatchedRule renders the matched pre rule's message when it has the block action,
+// then runs its actions in order
 func (h *DefaultHookProcessor) processMatchedRule(
-	ctx context.Context, matchedRule *config.Rule, matchedValue string,
+	ctx context.Context, matchedRule *config.Rule, ruleCtx template.RuleContext, settings *config.Settings,
+) (string, error) {
+	h.recordRuleFired(ctx, matchedRule.TrackingKey())
+	message := ""
+	if matchedRule.HasAction(config.ActionBlock) {
+		var err error
+		if message, err = h.renderRuleMessage(ctx, matchedRule, ruleCtx, settings); err != nil {
+			return "", err
+		}
+	}
+	return h.runRuleActions(ctx, matchedRule, settings, matchRecord{
+		Event:   "pre",
+		Tool:    ruleCtx.ToolName,
+		Field:   ruleCtx.MatchedField,
+		Value:   ruleCtx.Command,
+		Message: message,
+	}), nil
+}
+
+// recordRuleFired stores when the rule with ruleKey, its tracking key, last matched, for
+// bumpers rules stale
+func (h *DefaultHookProcessor) recordRuleFired(ctx context.Context, ruleKey string) {
+	if h.stateManager == nil || ruleKey == "" {
+		return
+	}
+	if err := h.stateManager.RecordRuleFired(ctx, ruleKey, h.currentTime()); err != nil {
+		logging.Get(ctx).Debug().Err(err).Str("rule_key", ruleKey).Msg("failed to record rule fired time")
+	}
+}
+
+// renderRuleMessage processes template and AI generation for matched rule
+func (h *DefaultHookProcessor) renderRuleMessage(
+	ctx context.Context, matchedRule *config.Rule, ruleCtx template.RuleContext, settings *config.Settings,
 ) (string, error) {
+	matchedValue := ruleCtx.Command
+
 	// Process template with rule context including shared variables
-	processedMessage, err := template.ExecuteRuleTemplate(matchedRule.Send, matchedValue)
+	stopTemplate := metrics.FromContext(ctx).Track(metrics.StageTemplate)
+	processedMessage, err := template.ExecuteRuleTemplate(matchedRule.Send, ruleCtx)
+	stopTemplate()
 	if err != nil {
 		return "", fmt.Errorf("failed to process rule template: %w", err)
 	}
@@ -432,14 +1051,63 @@ func (h *DefaultHookProcessor) processMatchedRule(
 	// Apply AI generation if configured
 	finalMessage, err := h.processAIGeneration(ctx, matchedRule, processedMessage, matchedValue)
 	if err != nil {
-		// Log error but don't fail the hook - fallback to original message
-		logging.Get(ctx).Error().Err(err).Msg("AI generation failed, using original message")
-		return processedMessage, nil
+		// Log error but don't fail the hook - fallback to fallback_message or the original message
+		logging.Get(ctx).Error().Err(err).Msg("AI generation failed, using fallback message")
+		finalMessage = generationFallback(ctx, matchedRule.GetGenerate(), processedMessage,
+			func(fallback string) (string, error) {
+				return template.ExecuteRuleTemplate(fallback, ruleCtx)
+			})
+	}
+
+	if config.IsNoOpMessage(finalMessage, matchedValue) {
+		return handleNoOpMessage(ctx, matchedRule, matchedValue, settings), nil
 	}
 
 	return finalMessage, nil
 }
 
+// generationFallback returns the message shown when AI generation fails: the original message once
+// the hook timeout is reached, otherwise generate.fallback_message
+// rendered with render (or message when unset or it fails to render), adjusted by generate.on_error
+func generationFallback(
+	ctx context.Context, generate config.Generate, message string, render func(string) (string, error),
+) string {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		logging.Get(ctx).Warn().Msg("hook timeout reached, using the original message without AI generation")
+		return message
+	}
+	fallback := message
+	if generate.FallbackMessage != "" {
+		rendered, err := render(generate.FallbackMessage)
+		if err != nil {
+			logging.Get(ctx).Error().Err(err).Msg("failed to process fallback_message template, using original message")
+		} else {
+			fallback = rendered
+		}
+	}
+	return generate.OnErrorMessage(fallback)
+}
+
+// handleNoOpMessage replaces a message that gives no guidance with a generic block message,
+// or allows the command when settings.on_empty_message is "allow"
+func handleNoOpMessage(
+	ctx context.Context, matchedRule *config.Rule, matchedValue string, settings *config.Settings,
+) string {
+	pattern := matchedRule.GetMatch().Pattern
+	allow := settings != nil && settings.AllowOnEmptyMessage()
+
+	logging.Get(ctx).Warn().
+		Str("pattern", pattern).
+		Fields(redactorFrom(ctx).fields(matchedRule, map[string]any{"matched_value": matchedValue})).
+		Bool("allow", allow).
+		Msg("rule message is empty or echoes the matched value")
+
+	if allow {
+		return ""
+	}
+	return fmt.Sprintf("Blocked by rule '%s'", pattern)
+}
+
 // processAIGeneration applies AI generation to a message if configured
 func (h *DefaultHookProcessor) processAIGeneration(
 	ctx context.Context, rule *config.Rule, message, _ string,
@@ -449,6 +1117,7 @@ func (h *DefaultHookProcessor) processAIGeneration(
 	if generate.Mode == "off" {
 		return message, nil
 	}
+	defer metrics.FromContext(ctx).Track(metrics.StageAIGeneration)()
 
 	// Use XDG-compliant database path
 	storageManager := storage.New(afero.NewOsFs())
@@ -476,11 +1145,17 @@ func (h *DefaultHookProcessor) processAIGeneration(
 
 	// Create request
 	match := rule.GetMatch()
+	cacheKeyExtra, err := template.ExecuteCacheKeyTemplate(generate.CacheKeyExtra, h.projectRoot)
+	if err != nil {
+		return message, fmt.Errorf("failed to process cache_key_extra template: %w", err)
+	}
 	req := &ai.GenerateRequest{
 		OriginalMessage: message,
 		CustomPrompt:    generate.Prompt,
 		GenerateMode:    generate.Mode,
 		Pattern:         match.Pattern,
+		CacheKeyExtra:   cacheKeyExtra,
+		Locale:          h.cachedSettings(ctx).Locale,
 	}
 
 	// Generate message
@@ -494,8 +1169,26 @@ func (h *DefaultHookProcessor) processAIGeneration(
 
 // ProcessPostToolUse processes post-tool-use hook events
 
-func (*DefaultHookProcessor) extractPostToolContent(
-	ctx context.Context, rawJSON json.RawMessage,
+// postIntentDepth returns the deepest max_intent_depth of the post rules, since the intent
+// is extracted once for all of them; 0 means one of them searches the whole transcript
+func postIntentDepth(rules []config.Rule) int {
+	depth := config.DefaultMaxIntentDepth
+	for i := range rules {
+		match := rules[i].GetMatch()
+		if match.Event != "post" {
+			continue
+		}
+		ruleDepth := match.GetMaxIntentDepth()
+		if ruleDepth == 0 {
+			return 0
+		}
+		depth = max(depth, ruleDepth)
+	}
+	return depth
+}
+
+func (h *DefaultHookProcessor) extractPostToolContent(
+	ctx context.Context, rawJSON json.RawMessage, maxDepth int,
 ) (*apptypes.PostToolContent, error) {
 	logger := logging.Get(ctx)
 
@@ -508,10 +1201,12 @@ func (*DefaultHookProcessor) extractPostToolContent(
 	transcriptPath, _ := event["transcript_path"].(string) //nolint:revive // intentionally ignoring ok value
 	toolName, _ := event["tool_name"].(string)             //nolint:revive // intentionally ignoring ok value
 	toolUseID, _ := event["tool_use_id"].(string)          //nolint:revive // intentionally ignoring ok value
+	sessionID, _ := event["session_id"].(string)           //nolint:revive // intentionally ignoring ok value
 	toolResponse := event[constants.FieldToolResponse]
 
 	content := &apptypes.PostToolContent{
 		ToolName:      toolName,
+		SessionID:     sessionID,
 		ToolOutputMap: make(map[string]any),
 	}
 
@@ -525,16 +1220,16 @@ func (*DefaultHookProcessor) extractPostToolContent(
 		var err error
 
 		if toolUseID != "" {
-			intent, err = transcript.ExtractIntentByToolUseIDWithContext(ctx, transcriptPath, toolUseID)
+			intent, err = h.intentByToolUseID(ctx, transcriptPath, toolUseID, maxDepth)
 		} else {
-			intent, err = transcript.FindRecentToolUseAndExtractIntent(ctx, transcriptPath)
+			intent, err = h.findRecentIntent(ctx, transcriptPath, maxDepth)
 		}
 		if err != nil {
 			logger.Debug().Err(err).Str("transcript_path", transcriptPath).Msg("Failed to extract intent")
 		} else {
 			logger.Debug().
 				Str("transcript_path", transcriptPath).
-				Str("extracted_intent", intent).
+				Int("intent_length", len(intent)).
 				Msg("FindRecentToolUseAndExtractIntent extracted content from transcript")
 		}
 		content.Intent = intent
@@ -576,16 +1271,43 @@ func (h *DefaultHookProcessor) ProcessPostToolUse(ctx context.Context, rawJSON j
 	}
 
 	// Load config for rule matching
+	stopConfigLoad := metrics.FromContext(ctx).Track(metrics.StageConfigLoad)
 	cfg, _, err := h.configValidator.LoadConfigAndMatcher(ctx)
+	stopConfigLoad()
 	if err != nil {
+		var event hooks.HookEvent
+		_ = json.Unmarshal(rawJSON, &event)
+		if h.configMissing(ctx, event.SessionID, err) {
+			return "", nil
+		}
 		return "", fmt.Errorf("failed to load config: %w", err)
 	}
 
-	content, err := h.extractPostToolContent(ctx, rawJSON)
+	redactor := newLogRedactor(&cfg.Settings)
+	ctx = withLogRedactor(ctx, redactor)
+
+	content, err := h.extractPostToolContent(ctx, rawJSON, postIntentDepth(cfg.Rules))
 	if err != nil {
 		return "", err
 	}
+	sessionID := hookSessionID(rawJSON)
+	if cfg.Settings.LoopProtectionEnabled() {
+		h.dropOwnMessageOutput(ctx, sessionID, content)
+	}
 
+	// Skip if no content to match against (neither intent nor tool response)
+	if content.Intent == "" && len(content.ToolOutputMap) == 0 {
+		logger.Debug().Msg("No content to match against - skipping PostToolUse processing")
+		return "", nil
+	}
+
+	// Check each rule for post-tool-use matching
+	stopMatch := metrics.FromContext(ctx).Track(metrics.StageMatch)
+	rule, contentToMatch := h.findMatchingPostRule(ctx, cfg.Rules, content)
+	stopMatch()
+	h.recordShadowPostMatches(ctx, cfg.Rules, content, &cfg.Settings)
+
+	// Log summary of available sources, redacted by the matched rule's log mode
 	sources := make([]string, 0, len(content.ToolOutputMap))
 	for key := range content.ToolOutputMap {
 		sources = append(sources, key)
@@ -593,43 +1315,127 @@ func (h *DefaultHookProcessor) ProcessPostToolUse(ctx context.Context, rawJSON j
 	logger.Debug().
 		Str("hook_type", constants.PostToolUseEvent).
 		Str("tool_name", content.ToolName).
-		Str("extracted_intent", content.Intent).
+		Fields(redactor.fields(rule, map[string]any{
+			"extracted_intent": content.Intent,
+			"matched_value":    contentToMatch,
+		})).
 		Int("tool_response_field_count", len(content.ToolOutputMap)).
 		Interface("tool_response_sources", sources).
 		Msg("Hook processing summary - sources available for rule matching")
 
-	// Skip if no content to match against (neither intent nor tool response)
-	if content.Intent == "" && len(content.ToolOutputMap) == 0 {
-		logger.Debug().Msg("No content to match against - skipping PostToolUse processing")
+	if rule == nil {
 		return "", nil
 	}
 
-	// Check each rule for post-tool-use matching
-	for i := range cfg.Rules {
-		rule := &cfg.Rules[i]
+	result, err := h.processMatchedPostRule(ctx, rule, content, contentToMatch, &cfg.Settings)
+	if err != nil {
+		return "", err
+	}
+	h.startRuleExec(ctx, rule, "post", contentToMatch)
+	h.recordOwnMessage(ctx, sessionID, result)
+	return result, nil
+}
+
+// processMatchedPostRule renders the rule's message using the existing template system,
+// then runs its actions
+func (h *DefaultHookProcessor) processMatchedPostRule(
+	ctx context.Context, rule *config.Rule, content *apptypes.PostToolContent, contentToMatch string,
+	settings *config.Settings,
+) (string, error) {
+	h.recordRuleFired(ctx, rule.TrackingKey())
+	var result string
+	if rule.HasAction(config.ActionBlock) {
+		var err error
+		stopTemplate := metrics.FromContext(ctx).Track(metrics.StageTemplate)
+		result, err = template.ExecuteRuleTemplate(rule.Send, template.RuleContext{
+			Command:   contentToMatch,
+			ToolName:  content.ToolName,
+			SessionID: content.SessionID,
+		})
+		stopTemplate()
+		if err != nil {
+			return "", fmt.Errorf("failed to execute rule template: %w", err)
+		}
+	}
+	return h.runRuleActions(ctx, rule, settings, matchRecord{
+		Event:   "post",
+		Tool:    content.ToolName,
+		Value:   contentToMatch,
+		Message: result,
+	}), nil
+}
+
+// recordShadowPostMatches logs and audits every shadow rule matching the post-tool content,
+// without changing the hook's response or running the rules' actions
+func (h *DefaultHookProcessor) recordShadowPostMatches(
+	ctx context.Context, ruleList []config.Rule, content *apptypes.PostToolContent, settings *config.Settings,
+) {
+	for i := range ruleList {
+		rule := &ruleList[i]
+		if !rule.GetMatch().Shadow {
+			continue
+		}
+		contentToMatch, hasMatch := h.determineRuleContentMatch(rule, content)
+		if !hasMatch {
+			continue
+		}
+		if matched, err := h.matchRulePattern(ctx, rule, contentToMatch, content.ToolName); err != nil || !matched {
+			continue
+		}
+		message, err := template.ExecuteRuleTemplate(rule.Send, template.RuleContext{
+			Command:   contentToMatch,
+			ToolName:  content.ToolName,
+			SessionID: content.SessionID,
+		})
+		if err != nil {
+			logging.Get(ctx).Warn().Err(err).Str("pattern", rule.GetMatch().Pattern).
+				Msg("failed to render shadow rule message")
+		}
+		auditShadowMatch(ctx, rule, settings, matchRecord{
+			Event:   "post",
+			Tool:    content.ToolName,
+			Value:   contentToMatch,
+			Message: message,
+		})
+	}
+}
+
+// findMatchingPostRule returns the first rule whose pattern matches the post-tool content,
+// along with the content it matched. Shadow rules are skipped.
+func (h *DefaultHookProcessor) findMatchingPostRule(
+	ctx context.Context, ruleList []config.Rule, content *apptypes.PostToolContent,
+) (rule *config.Rule, matchedContent string) {
+	collector := metrics.FromContext(ctx)
+	for i := range ruleList {
+		rule := &ruleList[i]
+		if rule.GetMatch().Shadow {
+			continue
+		}
 		contentToMatch, hasMatch := h.determineRuleContentMatch(rule, content)
 		if !hasMatch {
 			continue
 		}
 
 		// Check if pattern matches the selected content
+		collector.Add(metrics.RulesEvaluated, 1)
 		if matched, err := h.matchRulePattern(ctx, rule, contentToMatch, content.ToolName); err == nil && matched {
-			// Process and return the rule's message using existing template system
-			result, err := template.ExecuteRuleTemplate(rule.Send, contentToMatch)
-			if err != nil {
-				return "", fmt.Errorf("failed to execute rule template: %w", err)
-			}
-			return result, nil
+			return rule, contentToMatch
 		}
 	}
-
-	return "", nil
+	return nil, ""
 }
 
-// matchRulePattern checks if a rule's pattern matches the given content
-func (*DefaultHookProcessor) matchRulePattern(
+// matchRulePattern checks if a rule's tool and match accept the given content, applying word
+// and argument counts and except entries like pre rules. Post rules match any tool unless
+// tool is set.
+func (h *DefaultHookProcessor) matchRulePattern(
 	ctx context.Context, rule *config.Rule, content, toolName string,
 ) (bool, error) {
+	// File conditions are checked first, skipping the regexes when they don't hold
+	if !h.fileConditionsHold(ctx, rule) {
+		return false, nil
+	}
+
 	// Check tool pattern if specified (similar to existing matcher logic)
 	toolPattern := rule.Tool
 	if toolPattern != "" {
@@ -643,15 +1449,17 @@ func (*DefaultHookProcessor) matchRulePattern(
 		}
 	}
 
-	// Check content pattern
-	match := rule.GetMatch()
-	contentRe, err := regexp.Compile(match.Pattern)
+	// Check the content with the rule's match, skipping soft-disabled rules
+	if _, ok := rule.EffectiveMatch(); !ok {
+		return false, nil
+	}
+	ruleMatcher, err := h.newSingleRuleMatcher(ctx, rule)
 	if err != nil {
-		logging.Get(ctx).Debug().Err(err).Str("pattern", match.Pattern).Msg("invalid content pattern")
-		return false, fmt.Errorf("failed to compile content pattern %q: %w", match.Pattern, err)
+		logging.Get(ctx).Debug().Err(err).Str("pattern", rule.GetMatch().Pattern).Msg("invalid content pattern")
+		return false, err
 	}
-
-	return contentRe.MatchString(content), nil
+	matchedRule, err := ruleMatcher.MatchValue(content, h.templateContext())
+	return err == nil && matchedRule != nil, nil
 }
 
 // isEditingTool checks if the given tool name is an editing tool that should be blocked in discussion mode
@@ -2,10 +2,13 @@ package hooks
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	apptypes "github.com/wizzomafizzo/bumpers/internal/app/types"
 	"github.com/wizzomafizzo/bumpers/internal/config"
 	"github.com/wizzomafizzo/bumpers/internal/hooks"
 	"github.com/wizzomafizzo/bumpers/internal/matcher"
@@ -81,6 +84,26 @@ func TestHookProcessor_ExtractAndLogIntent_EmptyTranscriptPath(t *testing.T) {
 	assert.Empty(t, result)
 }
 
+func TestHookProcessor_CanceledTranscriptReadMeansNoIntent(t *testing.T) {
+	t.Parallel()
+
+	transcriptPath := filepath.Join(t.TempDir(), "transcript.jsonl")
+	require.NoError(t, os.WriteFile(transcriptPath, []byte(`{"type":"assistant","uuid":"a1",`+
+		`"message":{"content":[{"type":"text","text":"Run the tests"}]}}`+"\n"), 0o600))
+
+	processor := NewHookProcessor(&MockConfigValidator{}, testProjectRoot, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	intent, err := processor.findRecentIntent(ctx, transcriptPath, 0)
+	require.NoError(t, err)
+	assert.Empty(t, intent)
+
+	intent, err = processor.intentByToolUseID(ctx, transcriptPath, "tool1", 0)
+	require.NoError(t, err)
+	assert.Empty(t, intent)
+}
+
 // Mock implementation for testing
 type MockConfigValidator struct{}
 
@@ -96,6 +119,14 @@ func (*MockConfigValidator) TestCommand(_ context.Context, _ string) (string, er
 	return "", nil
 }
 
+func (*MockConfigValidator) TestCommandDetailed(_ context.Context, _ string) (*apptypes.TestCommandResult, error) {
+	return &apptypes.TestCommandResult{}, nil
+}
+
+func (*MockConfigValidator) CheckCommand(_ context.Context, _ string) (string, bool, error) {
+	return "", false, nil
+}
+
 func TestHookProcessor_DefaultToolFieldsBehavior(t *testing.T) {
 	t.Parallel()
 
@@ -131,7 +162,7 @@ func TestHookProcessor_DefaultToolFieldsBehavior(t *testing.T) {
 	// Since command="ls" doesn't match pattern "rm -rf", there should be no match
 	// This test will fail initially because current behavior checks all fields
 	assert.Nil(t, matchedRule, "EXPECTED: Should not match because command field doesn't match pattern")
-	assert.Empty(t, matchedValue, "EXPECTED: Should return empty string when no match")
+	assert.Empty(t, matchedValue.Value, "EXPECTED: Should return empty string when no match")
 }
 
 func TestHookProcessor_UnknownToolUsesAllFields(t *testing.T) {
@@ -164,7 +195,8 @@ func TestHookProcessor_UnknownToolUsesAllFields(t *testing.T) {
 	matchedRule, matchedValue, err := processor.findMatchingRule(ctx, ruleMatcher, bashEvent)
 	require.NoError(t, err)
 	assert.NotNil(t, matchedRule, "Bash should match in command field")
-	assert.Equal(t, "test-pattern found here", matchedValue, "Should match command field")
+	assert.Equal(t, "test-pattern found here", matchedValue.Value, "Should match command field")
+	assert.Equal(t, "command", matchedValue.Name, "Should report the matched field")
 
 	// Now test unknown tool - should still check all fields
 	unknownEvent := &hooks.HookEvent{
@@ -178,7 +210,8 @@ func TestHookProcessor_UnknownToolUsesAllFields(t *testing.T) {
 	matchedRule2, matchedValue2, err := processor.findMatchingRule(ctx, ruleMatcher, unknownEvent)
 	require.NoError(t, err)
 	assert.NotNil(t, matchedRule2, "Unknown tool should check all fields")
-	assert.Equal(t, "test-pattern found here", matchedValue2, "Should match the custom field")
+	assert.Equal(t, "test-pattern found here", matchedValue2.Value, "Should match the custom field")
+	assert.Equal(t, "custom_field", matchedValue2.Name, "Should report the custom field")
 }
 
 func TestHookProcessor_EditToolUsesDefaultFields(t *testing.T) {
@@ -211,7 +244,7 @@ func TestHookProcessor_EditToolUsesDefaultFields(t *testing.T) {
 	matchedRule, matchedValue, err := processor.findMatchingRule(ctx, ruleMatcher, editEvent)
 	require.NoError(t, err)
 	assert.Nil(t, matchedRule, "Edit should ignore old_string field")
-	assert.Empty(t, matchedValue, "Should not match since only default fields are checked")
+	assert.Empty(t, matchedValue.Value, "Should not match since only default fields are checked")
 }
 
 func TestHookProcessor_LogsWarningForUnmappedTools(t *testing.T) {
@@ -243,7 +276,7 @@ func TestHookProcessor_LogsWarningForUnmappedTools(t *testing.T) {
 	matchedRule, matchedValue, err := processor.findMatchingRule(ctx, ruleMatcher, unmappedEvent)
 	require.NoError(t, err)
 	assert.NotNil(t, matchedRule, "Should still match by falling back to all fields")
-	assert.Equal(t, "test-pattern", matchedValue, "Should find match in custom field")
+	assert.Equal(t, "test-pattern", matchedValue.Value, "Should find match in custom field")
 
 	// TODO: Once logging is implemented, verify that a warning was logged
 }
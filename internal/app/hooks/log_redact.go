@@ -0,0 +1,144 @@
+package hooks
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"strings"
+
+	"github.com/wizzomafizzo/bumpers/internal/config"
+)
+
+// redactedPatternText replaces matches of settings.log_redact_patterns, and values after
+// settings.redact keys, in logged values
+const redactedPatternText = "[REDACTED]"
+
+// logRedactor applies settings.log_redact_patterns, settings.redact and each rule's log
+// mode to values before they are logged
+type logRedactor struct {
+	patterns []*regexp.Regexp
+	// keyPatterns match a settings.redact key and the value after it, keeping the key
+	keyPatterns []*regexp.Regexp
+	// excepted is the first rule whose match was suppressed by an except entry, so the
+	// values it matched are still logged by its log mode
+	excepted *config.Rule
+}
+
+type logRedactorKey struct{}
+
+// newLogRedactor compiles the settings' redact patterns and keys, skipping invalid ones
+func newLogRedactor(settings *config.Settings) *logRedactor {
+	redactor := &logRedactor{}
+	if settings == nil {
+		return redactor
+	}
+	for _, pattern := range settings.LogRedactPatterns {
+		if re, err := regexp.Compile(pattern); err == nil {
+			redactor.patterns = append(redactor.patterns, re)
+		}
+	}
+	for _, key := range settings.Redact {
+		if key = strings.TrimSpace(key); key != "" {
+			redactor.keyPatterns = append(redactor.keyPatterns, redactKeyPattern(key))
+		}
+	}
+	return redactor
+}
+
+// redactKeyPattern matches key, case-insensitively and inside a longer name such as
+// --api-token or DB_PASSWORD, followed by "=", ":" or whitespace and the value after it.
+// The first group is everything before the value.
+func redactKeyPattern(key string) *regexp.Regexp {
+	return regexp.MustCompile(`(?i)([\w.-]*` + regexp.QuoteMeta(key) +
+		`[\w.-]*["']?(?:\s*[=:]\s*|\s+)["']?)[^\s"'&;|,]+`)
+}
+
+// withLogRedactor returns a context whose log sites redact values with redactor
+func withLogRedactor(ctx context.Context, redactor *logRedactor) context.Context {
+	return context.WithValue(ctx, logRedactorKey{}, redactor)
+}
+
+// redactorFrom returns the context's redactor, or one that only applies rule log modes
+// before the config is loaded
+func redactorFrom(ctx context.Context) *logRedactor {
+	if redactor, ok := ctx.Value(logRedactorKey{}).(*logRedactor); ok {
+		return redactor
+	}
+	return &logRedactor{}
+}
+
+// value returns value as it should be logged for rule (nil when no rule matched),
+// reporting false when rule's log mode is off
+func (r *logRedactor) value(rule *config.Rule, value string) (string, bool) {
+	mode := config.LogFull
+	if rule != nil {
+		mode = rule.GetLog()
+	}
+	switch mode {
+	case config.LogOff:
+		return "", false
+	case config.LogRedact:
+		sum := sha256.Sum256([]byte(value))
+		return "[sha256:" + hex.EncodeToString(sum[:6]) + "]", true
+	}
+	for _, re := range r.patterns {
+		value = re.ReplaceAllString(value, redactedPatternText)
+	}
+	for _, re := range r.keyPatterns {
+		value = re.ReplaceAllString(value, "${1}"+redactedPatternText)
+	}
+	return value, true
+}
+
+// noteExcepted records a rule that matched but was suppressed by an except entry
+func (r *logRedactor) noteExcepted(rule *config.Rule) {
+	if r.excepted == nil {
+		r.excepted = rule
+	}
+}
+
+// summaryRule returns the rule whose log mode applies to the hook's summary: the matched
+// rule, or the first excepted rule when none matched
+func (r *logRedactor) summaryRule(matched *config.Rule) *config.Rule {
+	if matched != nil {
+		return matched
+	}
+	return r.excepted
+}
+
+// fields returns values with their strings redacted for rule, ready for a log event's
+// Fields. It's empty when rule's log mode is off.
+func (r *logRedactor) fields(rule *config.Rule, values map[string]any) map[string]any {
+	if rule != nil && rule.GetLog() == config.LogOff {
+		return map[string]any{}
+	}
+	redacted := make(map[string]any, len(values))
+	for key, value := range values {
+		redacted[key] = r.redactAny(rule, value)
+	}
+	return redacted
+}
+
+// redactAny redacts the strings in a decoded JSON value, such as the edits of a MultiEdit
+func (r *logRedactor) redactAny(rule *config.Rule, value any) any {
+	switch v := value.(type) {
+	case string:
+		logged, _ := r.value(rule, v)
+		return logged
+	case map[string]any:
+		redacted := make(map[string]any, len(v))
+		for key, item := range v {
+			redacted[key] = r.redactAny(rule, item)
+		}
+		return redacted
+	case []any:
+		redacted := make([]any, len(v))
+		for i, item := range v {
+			redacted[i] = r.redactAny(rule, item)
+		}
+		return redacted
+	default:
+		return value
+	}
+}
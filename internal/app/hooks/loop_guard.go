@@ -0,0 +1,68 @@
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	apptypes "github.com/wizzomafizzo/bumpers/internal/app/types"
+	"github.com/wizzomafizzo/bumpers/internal/logging"
+)
+
+// hookSessionID returns the session_id of a hook payload, or "" if it has none
+func hookSessionID(rawJSON json.RawMessage) string {
+	var event struct {
+		SessionID string `json:"session_id"`
+	}
+	_ = json.Unmarshal(rawJSON, &event) // payloads were already parsed by their handler
+	return event.SessionID
+}
+
+// recordOwnMessage stores the text of a hook response bumpers sent in sessionID, so post
+// rules can skip tool output that repeats it, unless settings.loop_protection is off
+func (h *DefaultHookProcessor) recordOwnMessage(ctx context.Context, sessionID, response string) {
+	if h.stateManager == nil || response == "" || !h.cachedSettings(ctx).LoopProtectionEnabled() {
+		return
+	}
+
+	// JSON decisions, such as a rule's replacement, are recorded by the reason Claude sees
+	message := strings.TrimSpace(batchDecisionFor(response).PermissionDecisionReason)
+	if message == "" {
+		return
+	}
+	if err := h.stateManager.RecordOwnMessage(ctx, sessionID, message); err != nil {
+		logging.Get(ctx).Debug().Err(err).Msg("failed to record own message for loop protection")
+	}
+}
+
+// dropOwnMessageOutput removes the tool output fields containing a message bumpers sent
+// earlier in sessionID, so a post rule can't match bumpers' own guidance and fire again
+// on it. The intent is kept, since it's Claude's own reasoning.
+func (h *DefaultHookProcessor) dropOwnMessageOutput(
+	ctx context.Context, sessionID string, content *apptypes.PostToolContent,
+) {
+	if h.stateManager == nil || len(content.ToolOutputMap) == 0 {
+		return
+	}
+
+	logger := logging.Get(ctx)
+	messages, err := h.stateManager.OwnMessages(ctx, sessionID)
+	if err != nil {
+		logger.Debug().Err(err).Msg("failed to get own messages for loop protection")
+		return
+	}
+
+	for key, value := range content.ToolOutputMap {
+		output, ok := value.(string)
+		if !ok {
+			continue
+		}
+		for _, message := range messages {
+			if strings.Contains(output, message) {
+				logger.Debug().Str("field", key).Msg("tool output contains a bumpers message, skipping it")
+				delete(content.ToolOutputMap, key)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package hooks
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/afero"
+	"github.com/wizzomafizzo/bumpers/internal/hooks"
+)
+
+// maxSymlinkHops bounds how many symlinks resolveSymlinks follows, so a link loop ends
+const maxSymlinkHops = 40
+
+// SetFileSystem sets the filesystem used to follow symlinks for match.resolve_paths
+func (h *DefaultHookProcessor) SetFileSystem(fs afero.Fs) {
+	h.fileSystem = fs
+}
+
+func (h *DefaultHookProcessor) getFileSystem() afero.Fs {
+	if h.fileSystem == nil {
+		return afero.NewOsFs()
+	}
+	return h.fileSystem
+}
+
+// withResolvedPaths returns a copy of event with its path fields made absolute against the
+// event's cwd, or the project root without one, cleaned and with symlinks followed
+func (h *DefaultHookProcessor) withResolvedPaths(event *hooks.HookEvent) *hooks.HookEvent {
+	base := event.WorkingDirectory
+	if base == "" {
+		base = h.projectRoot
+	}
+
+	resolved := *event
+	resolved.ToolInput = make(map[string]any, len(event.ToolInput))
+	for key, value := range event.ToolInput {
+		resolved.ToolInput[key] = value
+	}
+	for _, field := range pathInputFields {
+		path, ok := event.ToolInput[field].(string)
+		if !ok || path == "" {
+			continue
+		}
+		if !filepath.IsAbs(path) && base != "" {
+			path = filepath.Join(base, path)
+		}
+		resolved.ToolInput[field] = resolveSymlinks(h.getFileSystem(), filepath.Clean(path))
+	}
+	return &resolved
+}
+
+// resolveSymlinks follows symlinks in an absolute, cleaned path one component at a time.
+// Components that don't exist are kept as they are, and the path is returned unchanged
+// when fs can't read links.
+func resolveSymlinks(fs afero.Fs, path string) string {
+	lstater, canLstat := fs.(afero.Lstater)
+	reader, canReadlink := fs.(afero.LinkReader)
+	if !canLstat || !canReadlink || !filepath.IsAbs(path) {
+		return path
+	}
+
+	resolved := string(filepath.Separator)
+	remaining := strings.Split(strings.TrimPrefix(path, string(filepath.Separator)), string(filepath.Separator))
+	for hops := 0; len(remaining) > 0; {
+		next := filepath.Join(resolved, remaining[0])
+		remaining = remaining[1:]
+
+		info, lstatCalled, err := lstater.LstatIfPossible(next)
+		if err != nil || !lstatCalled {
+			return filepath.Join(append([]string{next}, remaining...)...)
+		}
+		if info.Mode()&os.ModeSymlink == 0 {
+			resolved = next
+			continue
+		}
+
+		hops++
+		target, err := reader.ReadlinkIfPossible(next)
+		if err != nil || hops > maxSymlinkHops {
+			return filepath.Join(append([]string{next}, remaining...)...)
+		}
+		if !filepath.IsAbs(target) {
+			target = filepath.Join(resolved, target)
+		}
+		resolved = string(filepath.Separator)
+		remaining = append(
+			strings.Split(strings.TrimPrefix(filepath.Clean(target), string(filepath.Separator)), string(filepath.Separator)),
+			remaining...,
+		)
+	}
+	return resolved
+}
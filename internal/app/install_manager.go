@@ -1,6 +1,7 @@
 package app
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -8,8 +9,10 @@ import (
 
 	"github.com/spf13/afero"
 	"github.com/wizzomafizzo/bumpers/internal/claude/settings"
+	"github.com/wizzomafizzo/bumpers/internal/claude/transcript"
 	"github.com/wizzomafizzo/bumpers/internal/config"
 	"github.com/wizzomafizzo/bumpers/internal/constants"
+	"github.com/wizzomafizzo/bumpers/internal/version"
 )
 
 const bumpersCommandName = "bumpers"
@@ -98,20 +101,62 @@ func (i *DefaultInstallManager) Status() (string, error) {
 
 	writeString("Bumpers Status:\n")
 	writeString("===============\n\n")
+	writeString(fmt.Sprintf("Version: %s\n", version.Version))
 
 	// Check config file
 	fs := i.getFileSystem()
-	if _, err := fs.Stat(i.configPath); os.IsNotExist(err) {
+	_, statErr := fs.Stat(i.configPath)
+	partialCfg, loadErr := i.loadConfig()
+	switch {
+	case os.IsNotExist(statErr):
 		writeString("Config file: NOT FOUND\n")
 		writeString(fmt.Sprintf("   Expected: %s\n", i.configPath))
-	} else {
+	case loadErr != nil:
+		writeString("Config file: ERROR\n")
+		writeString(fmt.Sprintf("   Location: %s\n", i.configPath))
+		writeString(fmt.Sprintf("   Error: %v\n", loadErr))
+		var parseErr *config.ParseError
+		if errors.As(loadErr, &parseErr) && parseErr.Line > 0 {
+			writeString(fmt.Sprintf("   Line: %d\n", parseErr.Line))
+		}
+	default:
 		writeString("Config file: EXISTS\n")
 		writeString(fmt.Sprintf("   Location: %s\n", i.configPath))
+		if required := partialCfg.Settings.RequiredVersion; required != "" {
+			writeString(fmt.Sprintf("   Required version: %s (%s)\n", required, versionState(required)))
+		}
+	}
+
+	if tokens, ok := i.latestTranscriptTokens(); ok {
+		writeString(fmt.Sprintf("Transcript size: ~%d tokens\n", tokens))
 	}
 
 	return status.String(), nil
 }
 
+// latestTranscriptTokens estimates the size of the project's most recent Claude transcript
+func (i *DefaultInstallManager) latestTranscriptTokens() (int, bool) {
+	if i.projectRoot == "" {
+		return 0, false
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return 0, false
+	}
+
+	dir := transcript.ProjectTranscriptDir(filepath.Join(home, constants.ClaudeDir), i.projectRoot)
+	path, err := transcript.LatestTranscript(dir)
+	if err != nil {
+		return 0, false
+	}
+
+	tokens, err := transcript.CountTokens(path)
+	if err != nil {
+		return 0, false
+	}
+	return tokens, true
+}
+
 // setupClaudeDirectory ensures .claude directory exists and returns settings
 func (i *DefaultInstallManager) setupClaudeDirectory(workingDir string) (*settings.Settings, string, error) {
 	claudeDir := filepath.Join(workingDir, constants.ClaudeDir)
@@ -245,31 +290,39 @@ func (i *DefaultInstallManager) InstallClaudeHooks() error {
 		return err
 	}
 
-	// Add PreToolUse hook (preserves existing hooks with same matcher)
-	err = claudeSettings.AddOrAppendHook(settings.PreToolUseEvent, "", hookCmd)
+	// Add PreToolUse hook (replaces stale bumpers hooks, preserves other hooks)
+	err = claudeSettings.ReplaceOrAppendHook(settings.PreToolUseEvent, "", hookCmd, isBumpersHook)
 	if err != nil {
 		return fmt.Errorf("failed to add bumpers PreToolUse hook to Claude settings: %w", err)
 	}
 
-	// Add PostToolUse hook (preserves existing hooks with same matcher)
-	err = claudeSettings.AddOrAppendHook(settings.PostToolUseEvent, "", hookCmd)
+	// Add PostToolUse hook (replaces stale bumpers hooks, preserves other hooks)
+	err = claudeSettings.ReplaceOrAppendHook(settings.PostToolUseEvent, "", hookCmd, isBumpersHook)
 	if err != nil {
 		return fmt.Errorf("failed to add bumpers PostToolUse hook to Claude settings: %w", err)
 	}
 
-	// Add UserPromptSubmit hook (preserves existing hooks with same matcher)
-	err = claudeSettings.AddOrAppendHook(settings.UserPromptSubmitEvent, "", hookCmd)
+	// Add UserPromptSubmit hook (replaces stale bumpers hooks, preserves other hooks)
+	err = claudeSettings.ReplaceOrAppendHook(settings.UserPromptSubmitEvent, "", hookCmd, isBumpersHook)
 	if err != nil {
 		return fmt.Errorf("failed to add bumpers UserPromptSubmit hook to Claude settings: %w", err)
 	}
 
 	// Add SessionStart hook for startup and clear events
 	sessionMatcher := constants.SessionSourceStartup + "|" + constants.SessionSourceClear
-	err = claudeSettings.AddOrAppendHook(settings.SessionStartEvent, sessionMatcher, hookCmd)
+	err = claudeSettings.ReplaceOrAppendHook(settings.SessionStartEvent, sessionMatcher, hookCmd, isBumpersHook)
 	if err != nil {
 		return fmt.Errorf("failed to add bumpers SessionStart hook to Claude settings: %w", err)
 	}
 
+	// Add Notification hook only when opted in via config settings
+	if i.notificationHookEnabled() {
+		err = claudeSettings.ReplaceOrAppendHook(settings.NotificationEvent, "", hookCmd, isBumpersHook)
+		if err != nil {
+			return fmt.Errorf("failed to add bumpers Notification hook to Claude settings: %w", err)
+		}
+	}
+
 	// Save settings using injected filesystem
 	fs := i.getFileSystem()
 	err = settings.SaveToFileWithFS(fs, claudeSettings, localPath)
@@ -278,3 +331,74 @@ func (i *DefaultInstallManager) InstallClaudeHooks() error {
 	}
 	return nil
 }
+
+// isBumpersHook reports whether command runs a bumpers hook, from any install path
+func isBumpersHook(command settings.HookCommand) bool {
+	fields := strings.Fields(command.Command)
+	return command.Type == "command" && len(fields) >= 2 &&
+		filepath.Base(fields[0]) == bumpersCommandName && fields[1] == "hook"
+}
+
+// notificationHookEnabled reports whether the bumpers config enables the Notification hook
+func (i *DefaultInstallManager) notificationHookEnabled() bool {
+	data, err := i.readConfig()
+	if err != nil {
+		return false
+	}
+
+	cfg, err := config.LoadFromYAML(data)
+	if err != nil {
+		return false
+	}
+
+	return cfg.Settings.NotificationHook
+}
+
+// loadConfig loads the config as hooks do, returning why it can't be used by them. Invalid
+// rules are skipped by hooks, so only read and parse failures count.
+func (i *DefaultInstallManager) loadConfig() (*config.PartialConfig, error) {
+	data, err := i.readConfig()
+	if err != nil {
+		return nil, err
+	}
+	partialCfg, err := config.LoadPartial(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+	return partialCfg, nil
+}
+
+// versionState describes whether the running binary meets a required_version constraint
+func versionState(required string) string {
+	satisfied, err := version.Satisfies(version.Version, required)
+	switch {
+	case err != nil:
+		return fmt.Sprintf("invalid: %v", err)
+	case !satisfied:
+		return "NOT SATISFIED"
+	case version.IsDev(version.Version):
+		return "not checked for dev builds"
+	default:
+		return "satisfied"
+	}
+}
+
+// readConfig reads the bumpers config through the injected filesystem
+func (i *DefaultInstallManager) readConfig() ([]byte, error) {
+	fs := i.getFileSystem()
+
+	// Config directories are merged from disk by the config package
+	if info, statErr := fs.Stat(i.configPath); statErr == nil && info.IsDir() {
+		data, err := config.ReadData(i.configPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read config: %w", err)
+		}
+		return data, nil
+	}
+
+	data, err := afero.ReadFile(fs, i.configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+	return data, nil
+}
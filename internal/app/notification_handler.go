@@ -0,0 +1,88 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/wizzomafizzo/bumpers/internal/config"
+	"github.com/wizzomafizzo/bumpers/internal/logging"
+	"github.com/wizzomafizzo/bumpers/internal/template"
+)
+
+// NotificationHandler handles Claude Code notification events
+type NotificationHandler interface {
+	ProcessNotification(ctx context.Context, rawJSON json.RawMessage) (string, error)
+}
+
+// DefaultNotificationHandler implements NotificationHandler
+type DefaultNotificationHandler struct {
+	configPath string
+}
+
+// NewNotificationHandler creates a new NotificationHandler
+func NewNotificationHandler(configPath string) *DefaultNotificationHandler {
+	return &DefaultNotificationHandler{
+		configPath: configPath,
+	}
+}
+
+func (n *DefaultNotificationHandler) ProcessNotification(
+	ctx context.Context, rawJSON json.RawMessage,
+) (string, error) {
+	logger := logging.Get(ctx)
+	logger.Debug().Msg("processing Notification hook")
+
+	var event NotificationEvent
+	if err := json.Unmarshal(rawJSON, &event); err != nil {
+		return "", fmt.Errorf("failed to parse Notification event: %w", err)
+	}
+
+	cfg, err := config.Load(n.configPath)
+	if errors.Is(err, config.ErrConfigNotFound) {
+		logger.Debug().Err(err).Msg("config file not found, skipping notifications")
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to load config: %w", err)
+	}
+
+	// Collect guidance from every notification entry matching the message
+	messages := make([]string, 0, len(cfg.Notifications))
+	for i := range cfg.Notifications {
+		notification := &cfg.Notifications[i]
+
+		re, compileErr := regexp.Compile(notification.Match)
+		if compileErr != nil {
+			logger.Warn().Err(compileErr).Str("pattern", notification.Match).Msg("invalid notification pattern")
+			continue
+		}
+		if !re.MatchString(event.Message) {
+			continue
+		}
+
+		processedMessage, templateErr := template.ExecuteNotificationTemplate(notification.Send, event.Message)
+		if templateErr != nil {
+			return "", fmt.Errorf("failed to process notification template: %w", templateErr)
+		}
+		messages = append(messages, processedMessage)
+	}
+
+	if len(messages) == 0 {
+		return "", nil
+	}
+
+	response := NotificationOutput{
+		SystemMessage: strings.Join(messages, "\n"),
+	}
+
+	responseJSON, err := json.Marshal(response)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal response: %w", err)
+	}
+
+	return string(responseJSON), nil
+}
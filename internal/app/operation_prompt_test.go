@@ -49,6 +49,22 @@ func TestProcessUserPrompt_TriggerPhraseDetection(t *testing.T) {
 	require.Equal(t, 1, newState.TriggerCount)
 }
 
+func TestProcessUserPrompt_TriggerPhraseIgnoredOutsidePlanMode(t *testing.T) {
+	t.Parallel()
+
+	handler, stateManager := createTestPromptHandler(t)
+	ctx := context.Background()
+
+	handled, _, err := handler.handleAlignmentTriggers(ctx, "go ahead and run the tests")
+	require.NoError(t, err)
+	require.False(t, handled, "trigger phrases should only be consumed in plan mode")
+
+	state, err := stateManager.GetOperationMode(ctx)
+	require.NoError(t, err)
+	require.Equal(t, rules.ExecuteMode, state.Mode)
+	require.Equal(t, 0, state.TriggerCount)
+}
+
 // createTestPromptHandler creates a test prompt handler with state manager
 func createTestPromptHandler(t *testing.T) (*DefaultPromptHandler, *storage.StateManager) {
 	t.Helper()
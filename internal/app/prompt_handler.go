@@ -3,7 +3,9 @@ package app
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -71,7 +73,7 @@ func (p *DefaultPromptHandler) ProcessUserPrompt(ctx context.Context, rawJSON js
 		return "", nil // Not a command, pass through
 	}
 
-	return p.processCommand(ctx, commandStr)
+	return p.processCommand(ctx, commandStr, event.SessionID)
 }
 
 // parsePromptEvent parses the raw JSON into a UserPromptEvent
@@ -113,8 +115,8 @@ func (*DefaultPromptHandler) extractCommand(prompt string) (commandStr string, i
 	return strings.TrimPrefix(prompt, constants.CommandPrefix), true
 }
 
-// processCommand handles the main command processing logic
-func (p *DefaultPromptHandler) processCommand(ctx context.Context, commandStr string) (string, error) {
+// processCommand handles the main command processing logic for a prompt sent in sessionID
+func (p *DefaultPromptHandler) processCommand(ctx context.Context, commandStr, sessionID string) (string, error) {
 	logger := logging.Get(ctx)
 	logger.Debug().Str("command_str", commandStr).Msg("extracted command string")
 
@@ -133,14 +135,29 @@ func (p *DefaultPromptHandler) processCommand(ctx context.Context, commandStr st
 
 	// Load config to get commands
 	cfg, err := config.Load(p.configPath)
+	if errors.Is(err, config.ErrConfigNotFound) {
+		logger.Debug().Err(err).Msg("config file not found, passing prompt through")
+		return "", nil
+	}
 	if err != nil {
 		logger.Error().Err(err).Str("config_path", p.configPath).Msg("Failed to load config")
 		return "", fmt.Errorf("failed to load config: %w", err)
 	}
 
-	// Find command by name
+	// Find command by name, then by its 1-based index in the commands list
 	matchedCommand, commandMessage, found := p.findCommandInConfig(cfg.Commands, commandName)
 	if !found {
+		if matchedCommand, found = commandByIndex(cfg.Commands, commandName); found {
+			commandName, commandMessage = matchedCommand.Name, matchedCommand.Send
+		}
+	}
+	if !found {
+		switch commandName {
+		case rulesCommandName:
+			return p.processRulesCommand(ctx, cfg, args)
+		case approveCommandName:
+			return p.processApproveCommand(ctx, args, sessionID)
+		}
 		return "", nil // Command not found, pass through
 	}
 
@@ -153,29 +170,49 @@ func (p *DefaultPromptHandler) processCommand(ctx context.Context, commandStr st
 		return "", fmt.Errorf("failed to process command template: %w", err)
 	}
 
-	// Apply AI generation if configured
-	finalMessage, err := p.aiHelper.ProcessAIGenerationGeneric(ctx, matchedCommand, processedMessage, commandStr)
+	// Apply AI generation if configured. Responses are cached by the command and its
+	// arguments as well as the rendered message, so identical invocations reuse them.
+	cacheKey := commandCacheKey(matchedCommand.Name, argv)
+	logger.Debug().
+		Str("command_name", matchedCommand.Name).
+		Strs("cache_args", argv[1:]).
+		Str("generate_mode", matchedCommand.GetGenerate().Mode).
+		Msg("command generation cache key")
+	finalMessage, err := p.aiHelper.ProcessAIGenerationGeneric(
+		ctx, matchedCommand, processedMessage, cacheKey, cfg.Settings.Locale)
 	if err != nil {
-		// Log error but don't fail the hook - fallback to original message
-		logger.Error().Err(err).Msg("AI generation failed, using original message")
-		finalMessage = processedMessage
+		// Log error but don't fail the hook - fallback to fallback_message or the original message
+		logger.Error().Err(err).Msg("AI generation failed, using fallback message")
+		finalMessage = generationFallback(ctx, matchedCommand, processedMessage, func(fallback string) (string, error) {
+			return template.ExecuteCommandTemplateWithArgs(fallback, commandName, args, argv)
+		})
 	}
 
 	return p.createHookResponse(ctx, finalMessage)
 }
 
-// findCommandInConfig searches for a command by name in the config
+// findCommandInConfig searches for a command by name or alias in the config
 func (*DefaultPromptHandler) findCommandInConfig(
 	commands []config.Command, commandName string,
 ) (*config.Command, string, bool) {
 	for _, cmd := range commands {
-		if cmd.Name == commandName {
+		if cmd.Matches(commandName) {
 			return &cmd, cmd.Send, true
 		}
 	}
 	return nil, "", false
 }
 
+// commandByIndex returns the command at the 1-based index ref, such as "2" from $2. Zero,
+// out of range and anything but plain digits aren't an index.
+func commandByIndex(commands []config.Command, ref string) (*config.Command, bool) {
+	index, err := strconv.ParseUint(ref, 10, 0)
+	if err != nil || index == 0 || index > uint64(len(commands)) {
+		return nil, false
+	}
+	return &commands[index-1], true
+}
+
 // createHookResponse creates the final JSON response for Claude Code hooks
 func (*DefaultPromptHandler) createHookResponse(ctx context.Context, message string) (string, error) {
 	logger := logging.Get(ctx)
@@ -211,9 +248,9 @@ func (p *DefaultPromptHandler) processBuiltinCommand(ctx context.Context, comman
 		dbPath = p.testDBPath
 	} else {
 		storageManager := storage.New(afero.NewOsFs())
-		dbPath, err = storageManager.GetDatabasePath()
+		dbPath, err = storageManager.GetStateDatabasePath()
 		if err != nil {
-			return "", fmt.Errorf("failed to get database path: %w", err)
+			return "", fmt.Errorf("failed to get state database path: %w", err)
 		}
 	}
 
@@ -247,9 +284,18 @@ func (p *DefaultPromptHandler) handleAlignmentTriggers(
 	ctx context.Context, prompt string,
 ) (handled bool, response string, err error) {
 	if rules.DetectTriggerPhrase(prompt) {
+		current, err := p.stateManager.GetOperationMode(ctx)
+		if err != nil {
+			return false, "", fmt.Errorf("failed to get alignment mode: %w", err)
+		}
+		// Trigger phrases only matter while planning, otherwise the prompt is processed normally
+		if current.Mode != rules.PlanMode {
+			return false, "", nil
+		}
+
 		newState := &rules.OperationState{
 			Mode:         rules.ExecuteMode,
-			TriggerCount: 1,
+			TriggerCount: current.TriggerCount + 1,
 			UpdatedAt:    time.Now().Unix(),
 		}
 		if err := p.stateManager.SetOperationMode(ctx, newState); err != nil {
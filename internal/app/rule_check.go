@@ -0,0 +1,173 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/wizzomafizzo/bumpers/internal/config"
+	"github.com/wizzomafizzo/bumpers/internal/constants"
+	"github.com/wizzomafizzo/bumpers/internal/matcher"
+)
+
+// RuleCheckInput is a value to test one configured rule against, with the tool, event and
+// source field it would arrive in. Empty fields default to a Bash command before it runs.
+type RuleCheckInput struct {
+	Value  string
+	Tool   string
+	Event  string
+	Source string
+}
+
+// RuleCheck is the outcome of testing one configured rule against a value
+type RuleCheck struct {
+	Rule *config.Rule
+	// Pattern is the rule's pattern after template expansion with the project context
+	Pattern string
+	// Reason explains why the rule didn't match, empty when it did
+	Reason string
+	// Source names the rule's position and config file, see config.Rule.DescribeOrigin
+	Source string
+	// Index is the rule's 1-based position in the config
+	Index   int
+	Matched bool
+}
+
+// CheckRule tests the rule referenced by index or id against input the way a hook would:
+// the tool filter, event and source are checked first, then the value is matched with the
+// project's template context
+func (a *App) CheckRule(ref string, input RuleCheckInput) (RuleCheck, error) {
+	cfg, err := config.Load(a.configPath)
+	if err != nil {
+		return RuleCheck{}, fmt.Errorf("failed to load config: %w", err)
+	}
+	if !config.IsMerged(a.configPath) {
+		cfg.SetRuleOrigins(a.configPath)
+	}
+	index, err := cfg.FindRule(ref)
+	if err != nil {
+		return RuleCheck{}, fmt.Errorf("failed to find rule: %w", err)
+	}
+	if input.Tool == "" {
+		input.Tool = "Bash"
+	}
+	if input.Event == "" {
+		input.Event = "pre"
+	}
+
+	rule := &cfg.Rules[index]
+	templateContext := a.ruleTemplateContext()
+	check := RuleCheck{
+		Rule:    rule,
+		Index:   index + 1,
+		Pattern: matcher.ExpandPattern(rule.GetMatch().Pattern, templateContext),
+		Source:  rule.DescribeOrigin(),
+	}
+	check.Matched, check.Reason, err = checkRuleInput(rule, input, templateContext)
+	return check, err
+}
+
+// ruleTemplateContext returns the template context rule patterns are expanded with
+func (a *App) ruleTemplateContext() map[string]any {
+	templateContext := make(map[string]any)
+	if a.projectRoot != "" {
+		templateContext["ProjectRoot"] = a.projectRoot
+	}
+	return templateContext
+}
+
+// checkRuleInput tests rule's filters, then its pattern, against input, returning why it
+// didn't match when it doesn't
+func checkRuleInput(
+	rule *config.Rule, input RuleCheckInput, templateContext map[string]any,
+) (matched bool, reason string, err error) {
+	if reason = ruleFilterMismatch(rule, input); reason != "" {
+		return false, reason, nil
+	}
+
+	if rule.GetMatch().StripEnv && input.Tool == "Bash" {
+		_, input.Value = matcher.SplitEnvAssignments(input.Value)
+	}
+	ruleMatcher, err := matcher.NewRuleMatcher([]config.Rule{*rule})
+	if err != nil {
+		return false, "", fmt.Errorf("failed to create rule matcher: %w", err)
+	}
+	_, err = ruleMatcher.MatchWithContext(input.Value, input.Tool, templateContext)
+	switch {
+	case err == nil:
+		return true, "", nil
+	case errors.Is(err, matcher.ErrNoRuleMatch):
+		return false, patternMismatch(rule, input.Value, templateContext), nil
+	default:
+		return false, "", fmt.Errorf("failed to match rule: %w", err)
+	}
+}
+
+// ruleFilterMismatch returns why rule can't apply to input's tool, event or source before
+// its pattern is checked, or "" when it can
+func ruleFilterMismatch(rule *config.Rule, input RuleCheckInput) string {
+	match, active := rule.EffectiveMatch()
+	if !rule.IsEnabled() {
+		return "rule is disabled"
+	}
+	if !active {
+		return "rule is soft-disabled by an empty pattern"
+	}
+	if match.Event != input.Event {
+		return fmt.Sprintf("event mismatch: rule runs on %s, tested %s", match.Event, input.Event)
+	}
+
+	toolPattern := rule.Tool
+	if toolPattern == "" {
+		toolPattern = "^Bash$"
+	}
+	if toolRe, err := regexp.Compile("(?i)" + toolPattern); err != nil || !toolRe.MatchString(input.Tool) {
+		return fmt.Sprintf("tool filter failed: %s does not match tool %s", toolPattern, input.Tool)
+	}
+
+	if input.Source == "" {
+		return ""
+	}
+	if len(match.Sources) > 0 || match.SourceFieldRegex != "" {
+		if slices.Contains(match.Sources, input.Source) {
+			return ""
+		}
+		if keyRe, err := regexp.Compile(match.SourceFieldRegex); err == nil &&
+			match.SourceFieldRegex != "" && keyRe.MatchString(input.Source) {
+			return ""
+		}
+		return fmt.Sprintf("source mismatch: rule checks %s, tested %s", describeSources(match), input.Source)
+	}
+	if fields, known := constants.DefaultToolFields[input.Tool]; known && match.Event == "pre" &&
+		!slices.Contains(fields, input.Source) {
+		return fmt.Sprintf("source mismatch: %s checks %s by default, tested %s",
+			input.Tool, strings.Join(fields, ", "), input.Source)
+	}
+	return ""
+}
+
+// describeSources lists a rule's sources and source_field_regex for a mismatch reason
+func describeSources(match config.Match) string {
+	sources := slices.Clone(match.Sources)
+	if match.SourceFieldRegex != "" {
+		sources = append(sources, "fields matching "+match.SourceFieldRegex)
+	}
+	return strings.Join(sources, ", ")
+}
+
+// patternMismatch returns why a value that passed rule's filters still didn't match it
+func patternMismatch(rule *config.Rule, value string, templateContext map[string]any) string {
+	if exception, excepted := matcher.MatchException(rule, value, templateContext); excepted {
+		return fmt.Sprintf("suppressed by except entry %q", exception)
+	}
+	match := rule.GetMatch()
+	if match.HasWordCountLimits() && !match.WordCountWithinLimits(len(strings.Fields(value))) {
+		return "word count outside min_word_count/max_word_count"
+	}
+	if match.HasArgLimits() {
+		return "pattern no match, or the arguments after it are outside min_args/max_args"
+	}
+	return "pattern no match"
+}
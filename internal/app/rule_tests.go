@@ -0,0 +1,108 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/wizzomafizzo/bumpers/internal/config"
+	"github.com/wizzomafizzo/bumpers/internal/constants"
+	"gopkg.in/yaml.v3"
+)
+
+// RuleTestCase is one named case of a rule test file: a value sent to a tool, and the rule
+// expected to block it, if any
+type RuleTestCase struct {
+	// ExpectedMatch defaults to true, set it to false for values no rule should block
+	ExpectedMatch *bool  `yaml:"expected_match,omitempty"`
+	Name          string `yaml:"name"`
+	Input         string `yaml:"input"`
+	// Tool defaults to Bash, Input is sent in the tool's first default field
+	Tool string `yaml:"tool,omitempty"`
+	// ExpectedRule is the pattern of the rule that should match, any rule when empty
+	ExpectedRule string `yaml:"expected_rule,omitempty"`
+}
+
+// RuleTestFile is a table of rule test cases, read by ParseRuleTests
+type RuleTestFile struct {
+	Tests []RuleTestCase `yaml:"tests"`
+}
+
+// RuleTestResult is the outcome of one rule test case
+type RuleTestResult struct {
+	// Match is the rule that blocked the input, nil when none did
+	Match *ToolUseMatch
+	Case  RuleTestCase
+	// Reason explains why the case failed, empty when it passed
+	Reason string
+	Passed bool
+}
+
+// ParseRuleTests reads a YAML rule test file
+func ParseRuleTests(data []byte) ([]RuleTestCase, error) {
+	var file RuleTestFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse rule tests: %w", err)
+	}
+	if len(file.Tests) == 0 {
+		return nil, errors.New("no tests found, list them under tests:")
+	}
+	for i := range file.Tests {
+		if file.Tests[i].Name == "" {
+			return nil, fmt.Errorf("test %d has no name", i+1)
+		}
+	}
+	return file.Tests, nil
+}
+
+// RunRuleTests checks each case against the config the way MatchToolUse checks a tool call
+func (a *App) RunRuleTests(cases []RuleTestCase) ([]RuleTestResult, error) {
+	cfg, err := config.Load(a.configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	results := make([]RuleTestResult, 0, len(cases))
+	for i := range cases {
+		testCase := cases[i]
+		tool := testCase.Tool
+		if tool == "" {
+			tool = "Bash"
+		}
+		match, err := a.matchToolUse(cfg, tool, map[string]any{ruleTestField(tool): testCase.Input})
+		if err != nil {
+			return nil, fmt.Errorf("test %q: %w", testCase.Name, err)
+		}
+
+		result := RuleTestResult{Case: testCase, Match: match}
+		result.Reason = ruleTestFailure(&testCase, match)
+		result.Passed = result.Reason == ""
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// ruleTestField is the tool input field a test case's input is sent in
+func ruleTestField(tool string) string {
+	if fields := constants.DefaultToolFields[tool]; len(fields) > 0 {
+		return fields[0]
+	}
+	return "command"
+}
+
+// ruleTestFailure returns why match doesn't meet testCase's expectations, or "" when it does
+func ruleTestFailure(testCase *RuleTestCase, match *ToolUseMatch) string {
+	expectMatch := testCase.ExpectedMatch == nil || *testCase.ExpectedMatch
+	switch {
+	case !expectMatch && match != nil:
+		return fmt.Sprintf("expected no match, rule %d matched (%s)", match.Index, match.Rule.GetMatch().Pattern)
+	case !expectMatch:
+		return ""
+	case match == nil:
+		return "expected a match, no rule matched"
+	case testCase.ExpectedRule != "" && match.Rule.GetMatch().Pattern != testCase.ExpectedRule:
+		return fmt.Sprintf("expected rule %q, rule %d matched (%s)",
+			testCase.ExpectedRule, match.Index, match.Rule.GetMatch().Pattern)
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,140 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/wizzomafizzo/bumpers/internal/config"
+	"github.com/wizzomafizzo/bumpers/internal/matcher"
+	"github.com/wizzomafizzo/bumpers/internal/template"
+)
+
+const (
+	// rulesCommandName is the built-in prompt command summarizing active rules. A config
+	// command with the same name or alias takes precedence.
+	rulesCommandName = "rules"
+	// rulesCommandMaxBytes caps the context the rules command adds to the prompt
+	rulesCommandMaxBytes = 4000
+)
+
+// rulesCommandUsage is returned for unknown $rules subcommands
+const rulesCommandUsage = "Usage: $rules to list active rules, $rules test <command> to check a command"
+
+// processRulesCommand handles "$rules" and "$rules test <command>"
+func (p *DefaultPromptHandler) processRulesCommand(
+	ctx context.Context, cfg *config.Config, args string,
+) (string, error) {
+	var message string
+	switch subcommand, rest, _ := strings.Cut(strings.TrimSpace(args), " "); subcommand {
+	case "":
+		message = summarizeRules(cfg.Rules)
+	case "test":
+		command := strings.TrimSpace(rest)
+		if command == "" {
+			message = rulesCommandUsage
+			break
+		}
+		tested, err := p.testRulesCommand(cfg, command)
+		if err != nil {
+			return "", err
+		}
+		message = tested
+	default:
+		message = rulesCommandUsage
+	}
+
+	return p.createHookResponse(ctx, capMessageLines(message, rulesCommandMaxBytes))
+}
+
+// summarizeRules describes each rule on one line: pattern, tool scope, event and sources
+func summarizeRules(rules []config.Rule) string {
+	if len(rules) == 0 {
+		return "No bumpers rules are active"
+	}
+
+	lines := make([]string, 0, len(rules)+1)
+	lines = append(lines, fmt.Sprintf("Active bumpers rules (%d):", len(rules)))
+	for i := range rules {
+		rule := &rules[i]
+		match := rule.GetMatch()
+		tool := rule.Tool
+		if tool == "" {
+			tool = "^Bash$"
+		}
+
+		line := fmt.Sprintf("%d. %s (tool: %s, event: %s", i+1, match.Pattern, tool, match.Event)
+		if len(match.Sources) > 0 {
+			line += ", sources: " + strings.Join(match.Sources, ",")
+		}
+		lines = append(lines, line+")")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// testRulesCommand reports which rule, if any, would block a Bash command and its message
+func (p *DefaultPromptHandler) testRulesCommand(cfg *config.Config, command string) (string, error) {
+	ruleMatcher, err := matcher.NewRuleMatcher(cfg.Rules)
+	if err != nil {
+		return "", fmt.Errorf("failed to create rule matcher: %w", err)
+	}
+	ruleMatcher.SetSelectMode(cfg.Output.Select)
+
+	templateContext := make(map[string]any)
+	if p.projectRoot != "" {
+		templateContext["ProjectRoot"] = p.projectRoot
+	}
+
+	rule, err := ruleMatcher.MatchWithContext(command, "Bash", templateContext)
+	if errors.Is(err, matcher.ErrNoRuleMatch) {
+		return fmt.Sprintf("No rule would block: %s", command), nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to match rule for command '%s': %w", command, err)
+	}
+
+	message, err := template.ExecuteRuleTemplate(rule.Send, template.RuleContext{
+		Command:      command,
+		ToolName:     "Bash",
+		MatchedField: "command",
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to process rule template: %w", err)
+	}
+
+	index := 0
+	for i := range cfg.Rules {
+		if &cfg.Rules[i] == rule {
+			index = i + 1
+			break
+		}
+	}
+	return fmt.Sprintf("Rule %d (%s) would block: %s\nMessage: %s",
+		index, rule.GetMatch().Pattern, command, message), nil
+}
+
+// capMessageLines truncates message to at most maxBytes, cutting at a line boundary and
+// noting how many lines were left out
+func capMessageLines(message string, maxBytes int) string {
+	if len(message) <= maxBytes {
+		return message
+	}
+
+	lines := strings.Split(message, "\n")
+	omittedNote := func(n int) string { return fmt.Sprintf("... (%d lines omitted)", n) }
+	noteBudget := len(omittedNote(len(lines)))
+
+	var kept strings.Builder
+	for i, line := range lines {
+		if kept.Len()+len(line)+1+noteBudget > maxBytes {
+			if i == 0 {
+				// A single oversized line is cut mid-line
+				return message[:max(0, maxBytes-noteBudget-1)] + "\n" + omittedNote(1)
+			}
+			return kept.String() + omittedNote(len(lines)-i)
+		}
+		_, _ = kept.WriteString(line + "\n")
+	}
+	return strings.TrimSuffix(kept.String(), "\n")
+}
@@ -0,0 +1,106 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rulesCommandContext runs a $rules prompt and returns the additionalContext it adds
+func rulesCommandContext(t *testing.T, configContent, prompt string) string {
+	t.Helper()
+	ctx, _ := setupTestWithContext(t)
+
+	handler := NewPromptHandler(createTempConfig(t, configContent), t.TempDir())
+	result, err := handler.ProcessUserPrompt(ctx, json.RawMessage(fmt.Sprintf(`{"prompt": %q}`, prompt)))
+	require.NoError(t, err)
+
+	var response HookResponse
+	require.NoError(t, json.Unmarshal([]byte(result), &response), "result: %s", result)
+	assert.Equal(t, "UserPromptSubmit", response.HookSpecificOutput.HookEventName)
+	return response.HookSpecificOutput.AdditionalContext
+}
+
+const rulesCommandConfig = `rules:
+  - match: "^go test"
+    send: "Use just test instead of {{.Command}}"
+  - match:
+      pattern: "\\.env$"
+      sources: ["file_path"]
+    tool: "^(Write|Edit)$"
+    send: "Don't edit env files"
+  - match:
+      pattern: "FAIL"
+      event: "post"
+    send: "Tests failed"`
+
+func TestRulesCommandSummary(t *testing.T) {
+	t.Parallel()
+
+	summary := rulesCommandContext(t, rulesCommandConfig, "$rules")
+	assert.Equal(t, `Active bumpers rules (3):
+1. ^go test (tool: ^Bash$, event: pre)
+2. \.env$ (tool: ^(Write|Edit)$, event: pre, sources: file_path)
+3. FAIL (tool: ^Bash$, event: post)`, summary)
+}
+
+func TestRulesCommandTest(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		prompt string
+		want   string
+	}{
+		{
+			prompt: "$rules test go test ./...",
+			want:   "Rule 1 (^go test) would block: go test ./...\nMessage: Use just test instead of go test ./...",
+		},
+		{prompt: "$rules test ls -la", want: "No rule would block: ls -la"},
+		{prompt: "$rules test", want: rulesCommandUsage},
+		{prompt: "$rules bogus", want: rulesCommandUsage},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, rulesCommandContext(t, rulesCommandConfig, tt.prompt), tt.prompt)
+	}
+}
+
+func TestRulesCommandUserCommandTakesPrecedence(t *testing.T) {
+	t.Parallel()
+
+	got := rulesCommandContext(t, `commands:
+  - name: "rules"
+    send: "Project rules live in CONTRIBUTING.md"`, "$rules")
+	assert.Equal(t, "Project rules live in CONTRIBUTING.md", got)
+}
+
+func TestRulesCommandOutputIsCapped(t *testing.T) {
+	t.Parallel()
+
+	var config strings.Builder
+	_, _ = config.WriteString("rules:\n")
+	for i := range 200 {
+		_, _ = fmt.Fprintf(&config, "  - match: \"^command-number-%03d\"\n    send: \"Blocked\"\n", i)
+	}
+
+	summary := rulesCommandContext(t, config.String(), "$rules")
+	assert.LessOrEqual(t, len(summary), rulesCommandMaxBytes)
+	assert.Contains(t, summary, "1. ^command-number-000")
+	assert.Regexp(t, `\.\.\. \(\d+ lines omitted\)$`, summary)
+}
+
+func TestCapMessageLines(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "short", capMessageLines("short", 100))
+	assert.Equal(t, "line one\nline two\n... (3 lines omitted)",
+		capMessageLines("line one\nline two\nline three\nline four\nline five", 40))
+
+	capped := capMessageLines(strings.Repeat("x", 50), 30)
+	assert.LessOrEqual(t, len(capped), 30)
+	assert.True(t, strings.HasSuffix(capped, "... (1 lines omitted)"), capped)
+}
@@ -0,0 +1,109 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/wizzomafizzo/bumpers/internal/config"
+)
+
+// selfTestSamples are hook payloads every selftest runs to check each hook type is
+// processed without error, whatever the config's rules decide
+var selfTestSamples = []config.Test{
+	{
+		Name: "sample PreToolUse Bash",
+		Input: map[string]any{
+			"tool_name":  "Bash",
+			"tool_input": map[string]any{"command": "echo bumpers selftest"},
+		},
+	},
+	{
+		Name: "sample PreToolUse Read",
+		Input: map[string]any{
+			"tool_name":  "Read",
+			"tool_input": map[string]any{"file_path": "README.md"},
+		},
+	},
+	{
+		Name: "sample UserPromptSubmit",
+		Input: map[string]any{
+			"hook_event_name": "UserPromptSubmit",
+			"prompt":          "bumpers selftest",
+		},
+	},
+}
+
+// SelfTestResult is the outcome of running one test payload through ProcessHook
+type SelfTestResult struct {
+	Err error
+	// ExpectMatch is the test's expect_match, nil when it only checks for errors
+	ExpectMatch *bool
+	Name        string
+	Mode        ProcessMode
+	Message     string
+}
+
+// Matched reports whether the hook blocked or responded to the payload
+func (r *SelfTestResult) Matched() bool {
+	return r.Mode != "" && r.Mode != ProcessModeAllow
+}
+
+// Passed reports whether the payload was processed without error and, when the test has
+// an expectation, whether a rule fired as expected
+func (r *SelfTestResult) Passed() bool {
+	if r.Err != nil {
+		return false
+	}
+	return r.ExpectMatch == nil || *r.ExpectMatch == r.Matched()
+}
+
+// SelfTest runs the built-in sample payloads and then the config's tests through
+// ProcessHook, returning one result per payload
+func (a *App) SelfTest(ctx context.Context) ([]SelfTestResult, error) {
+	cfg, _, err := a.configValidator.LoadConfigAndMatcher(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	tests := make([]config.Test, 0, len(selfTestSamples)+len(cfg.Tests))
+	tests = append(tests, selfTestSamples...)
+	for i, test := range cfg.Tests {
+		if test.Name == "" {
+			test.Name = fmt.Sprintf("test %d", i+1)
+		}
+		tests = append(tests, test)
+	}
+
+	results := make([]SelfTestResult, 0, len(tests))
+	for i := range tests {
+		results = append(results, a.runSelfTest(ctx, &tests[i]))
+	}
+	return results, nil
+}
+
+// runSelfTest processes one test's input as a hook payload
+func (a *App) runSelfTest(ctx context.Context, test *config.Test) SelfTestResult {
+	result := SelfTestResult{Name: test.Name, ExpectMatch: test.ExpectMatch}
+	if len(test.Input) == 0 {
+		result.Err = errors.New("input is required")
+		return result
+	}
+
+	input, err := json.Marshal(test.Input)
+	if err != nil {
+		result.Err = fmt.Errorf("failed to marshal input: %w", err)
+		return result
+	}
+
+	processed, err := a.ProcessHook(ctx, bytes.NewReader(input))
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	result.Mode = processed.Mode
+	result.Message = processed.Message
+	return result
+}
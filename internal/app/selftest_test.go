@@ -0,0 +1,59 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelfTest(t *testing.T) {
+	t.Parallel()
+	ctx, _ := setupTestWithContext(t)
+
+	projectDir := t.TempDir()
+	configPath := filepath.Join(projectDir, "bumpers.yml")
+	require.NoError(t, os.WriteFile(configPath, []byte(`rules:
+  - match: "^go test"
+    send: "Use just test"
+    generate: "off"
+tests:
+  - name: go test is blocked
+    input: {tool_name: Bash, tool_input: {command: "go test ./..."}}
+    expect_match: true
+  - input: {tool_name: Bash, tool_input: {command: "just test"}}
+    expect_match: false
+  - name: wrong expectation
+    input: {tool_name: Bash, tool_input: {command: "ls"}}
+    expect_match: true
+  - name: no expectation
+    input: {tool_name: Bash, tool_input: {command: "go test"}}
+`), 0o600))
+
+	app := NewAppWithWorkDir(configPath, projectDir)
+	results, err := app.SelfTest(ctx)
+	require.NoError(t, err)
+	require.Len(t, results, len(selfTestSamples)+4)
+
+	for _, result := range results[:len(selfTestSamples)] {
+		assert.True(t, result.Passed(), "sample %q: %v", result.Name, result.Err)
+	}
+
+	tests := results[len(selfTestSamples):]
+	assert.Equal(t, "go test is blocked", tests[0].Name)
+	assert.True(t, tests[0].Passed())
+	assert.Equal(t, ProcessModeBlock, tests[0].Mode)
+	assert.Contains(t, tests[0].Message, "Use just test")
+
+	assert.Equal(t, "test 2", tests[1].Name)
+	assert.True(t, tests[1].Passed())
+
+	assert.False(t, tests[2].Passed())
+	assert.False(t, tests[2].Matched())
+	require.NoError(t, tests[2].Err)
+
+	assert.True(t, tests[3].Passed(), "tests without expect_match only check for errors")
+	assert.True(t, tests[3].Matched())
+}
@@ -2,15 +2,22 @@ package app
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
+	"regexp"
 	"strings"
+	"time"
 
 	"github.com/spf13/afero"
 	ai "github.com/wizzomafizzo/bumpers/internal/claude/api"
 	"github.com/wizzomafizzo/bumpers/internal/config"
 	"github.com/wizzomafizzo/bumpers/internal/constants"
 	"github.com/wizzomafizzo/bumpers/internal/logging"
+	"github.com/wizzomafizzo/bumpers/internal/matcher"
 	"github.com/wizzomafizzo/bumpers/internal/storage"
 	"github.com/wizzomafizzo/bumpers/internal/template"
 )
@@ -23,43 +30,53 @@ type SessionManager interface {
 
 // DefaultSessionManager implements SessionManager
 type DefaultSessionManager struct {
-	fileSystem afero.Fs
-	aiHelper   *AIHelper
-	cache      *ai.Cache
-	configPath string
+	fileSystem   afero.Fs
+	aiHelper     *AIHelper
+	cache        ai.Cache
+	stateManager *storage.StateManager
+	configPath   string
 }
 
 // SessionManagerOptions configures SessionManager construction
 type SessionManagerOptions struct {
-	FileSystem  afero.Fs
-	Cache       *ai.Cache
-	ConfigPath  string
-	ProjectRoot string
+	FileSystem afero.Fs
+	Cache      ai.Cache
+	// StateManager holds the project's approvals, own messages and cached decisions, cleared when a new session starts
+	StateManager *storage.StateManager
+	ConfigPath   string
+	ProjectRoot  string
 }
 
 // NewSessionManager creates a new SessionManager (maintains backward compatibility)
-func NewSessionManager(configPath, projectRoot string, fileSystem afero.Fs) *DefaultSessionManager {
-	return NewSessionManagerFromOptions(SessionManagerOptions{
+func NewSessionManager(
+	configPath, projectRoot string, fileSystem afero.Fs, stateManager ...*storage.StateManager,
+) *DefaultSessionManager {
+	opts := SessionManagerOptions{
 		ConfigPath:  configPath,
 		ProjectRoot: projectRoot,
 		FileSystem:  fileSystem,
 		Cache:       nil,
-	})
+	}
+	if len(stateManager) > 0 {
+		opts.StateManager = stateManager[0]
+	}
+	return NewSessionManagerFromOptions(opts)
 }
 
 // NewSessionManagerFromOptions creates a new SessionManager with options pattern
 func NewSessionManagerFromOptions(opts SessionManagerOptions) *DefaultSessionManager {
 	return &DefaultSessionManager{
-		configPath: opts.ConfigPath,
-		fileSystem: opts.FileSystem,
-		aiHelper:   NewAIHelper(AIHelperOptions{ProjectRoot: opts.ProjectRoot, FileSystem: opts.FileSystem}),
-		cache:      opts.Cache,
+		configPath:   opts.ConfigPath,
+		fileSystem:   opts.FileSystem,
+		aiHelper:     NewAIHelper(AIHelperOptions{ProjectRoot: opts.ProjectRoot, FileSystem: opts.FileSystem}),
+		cache:        opts.Cache,
+		stateManager: opts.StateManager,
 	}
 }
 
 // NewSessionManagerWithCache creates a new SessionManager with shared cache instance
 func NewSessionManagerWithCache(
-	configPath, projectRoot string, fileSystem afero.Fs, cache *ai.Cache,
+	configPath, projectRoot string, fileSystem afero.Fs, cache ai.Cache,
 ) *DefaultSessionManager {
 	return NewSessionManagerFromOptions(SessionManagerOptions{
 		ConfigPath:  configPath,
@@ -75,7 +92,7 @@ func (s *DefaultSessionManager) SetMockAIGenerator(generator ai.MessageGenerator
 }
 
 // SetCacheForTesting sets the cache instance for testing
-func (s *DefaultSessionManager) SetCacheForTesting(cache *ai.Cache) {
+func (s *DefaultSessionManager) SetCacheForTesting(cache ai.Cache) {
 	s.cache = cache
 }
 
@@ -97,46 +114,60 @@ func (s *DefaultSessionManager) ProcessSessionStart(ctx context.Context, rawJSON
 		return "", fmt.Errorf("failed to parse SessionStart event: %w", err)
 	}
 
-	// Only process startup and clear sources
-	if event.Source != constants.SessionSourceStartup && event.Source != constants.SessionSourceClear {
-		return "", nil
-	}
+	// Caches and approvals reset with new sessions. Each note picks its sources, by default
+	// settings.session_sources, and session rules see every source
+	newSession := event.Source == constants.SessionSourceStartup || event.Source == constants.SessionSourceClear
 
-	// Clear session-based cache entries when a new session starts
-	if cacheErr := s.ClearSessionCache(ctx); cacheErr != nil {
-		// Log error but don't fail the hook - cache clearing is non-critical
-		logger.Warn().Err(cacheErr).Msg("failed to clear session cache")
+	if newSession {
+		// Clear session-based cache entries when a new session starts
+		if cacheErr := s.ClearSessionCache(ctx); cacheErr != nil {
+			// Log error but don't fail the hook - cache clearing is non-critical
+			logger.Warn().Err(cacheErr).Msg("failed to clear session cache")
+		}
+		// Approvals last for one session, and other sessions in the project keep theirs
+		if s.stateManager != nil {
+			if approvalErr := s.stateManager.ClearApprovals(ctx, event.SessionID); approvalErr != nil {
+				logger.Warn().Err(approvalErr).Msg("failed to clear approvals")
+			}
+			// Messages kept for loop protection only matter to the session that sent them
+			if messageErr := s.stateManager.ClearOwnMessages(ctx); messageErr != nil {
+				logger.Warn().Err(messageErr).Msg("failed to clear own messages")
+			}
+			if decisionErr := s.stateManager.ClearDecisions(ctx, event.SessionID); decisionErr != nil {
+				logger.Warn().Err(decisionErr).Msg("failed to clear cached decisions")
+			}
+		}
 	}
 
-	// Load config to get notes
+	// Load config to get notes and session rules
 	cfg, err := config.Load(s.configPath)
 	if err != nil {
+		if errors.Is(err, config.ErrConfigNotFound) {
+			logger.Debug().Err(err).Msg("config file not found, skipping session notes and rules")
+			return "", nil
+		}
+		if !newSession {
+			// Other sources only matter to session rules, so a bad config doesn't fail them
+			logger.Debug().Err(err).Str("source", event.Source).Msg("failed to load config, skipping session rules")
+			return "", nil
+		}
 		return "", fmt.Errorf("failed to load config: %w", err)
 	}
 
-	// If no notes, return empty
-	if len(cfg.Session) == 0 {
-		return "", nil
+	messages, err := s.noteMessages(ctx, cfg, event.Source, event.SessionID)
+	if err != nil {
+		return "", err
 	}
 
-	// Process and concatenate all note messages
-	messages := make([]string, 0, len(cfg.Session))
-	for _, note := range cfg.Session {
-		// Process template with note context including shared variables
-		processedMessage, templateErr := template.ExecuteNoteTemplate(note.Add)
-		if templateErr != nil {
-			return "", fmt.Errorf("failed to process note template: %w", templateErr)
-		}
-
-		// Apply AI generation if configured
-		finalMessage, genErr := s.aiHelper.ProcessAIGenerationGeneric(ctx, &note, processedMessage, "")
-		if genErr != nil {
-			// Log error but don't fail the hook - fallback to original message
-			logger.Error().Err(genErr).Msg("AI generation failed, using original message")
-			finalMessage = processedMessage
-		}
+	ruleMessages, err := s.sessionRuleMessages(ctx, cfg.Rules, &event, cfg.Settings.Locale)
+	if err != nil {
+		return "", err
+	}
+	messages = append(messages, ruleMessages...)
 
-		messages = append(messages, finalMessage)
+	// If no notes or rules apply, return empty
+	if len(messages) == 0 {
+		return "", nil
 	}
 
 	additionalContext := strings.Join(messages, "\n")
@@ -161,6 +192,153 @@ func (s *DefaultSessionManager) ProcessSessionStart(ctx context.Context, rawJSON
 	return string(responseJSON), nil
 }
 
+// noteMessages renders every session note added on source, skipping notes whose add_file
+// can't be read and once_per_session notes already shown in sessionID
+func (s *DefaultSessionManager) noteMessages(
+	ctx context.Context, cfg *config.Config, source, sessionID string,
+) ([]string, error) {
+	messages := make([]string, 0, len(cfg.Session))
+	for _, note := range cfg.Session {
+		if !note.ShowsOn(source, &cfg.Settings) {
+			continue
+		}
+		if note.OncePerSession && s.noteShown(ctx, &note, sessionID) {
+			continue
+		}
+
+		processedMessage, ok, err := s.noteMessage(ctx, &note)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+
+		// Apply AI generation if configured
+		finalMessage, genErr := s.aiHelper.ProcessAIGenerationGeneric(ctx, &note, processedMessage, "", cfg.Settings.Locale)
+		if genErr != nil {
+			// Log error but don't fail the hook - fallback to fallback_message or the original message
+			logging.Get(ctx).Error().Err(genErr).Msg("AI generation failed, using fallback message")
+			finalMessage = generationFallback(ctx, &note, processedMessage, template.ExecuteNoteTemplate)
+		}
+
+		messages = append(messages, finalMessage)
+		if note.OncePerSession {
+			s.markNoteShown(ctx, &note, sessionID)
+		}
+	}
+	return messages, nil
+}
+
+// sessionRuleMessages renders the send of every event: session rule whose pattern matches
+// the event's source, in config order, generating in the language of locale
+func (s *DefaultSessionManager) sessionRuleMessages(
+	ctx context.Context, rules []config.Rule, event *SessionStartEvent, locale string,
+) ([]string, error) {
+	source := event.Source
+	var messages []string
+	for i := range rules {
+		rule := &rules[i]
+		match := rule.GetMatch()
+		if match.Event != config.EventSession {
+			continue
+		}
+
+		re, err := regexp.Compile(match.AnchoredPattern())
+		if err != nil || !re.MatchString(source) {
+			continue
+		}
+		if _, excepted := matcher.MatchException(rule, source, nil); excepted {
+			continue
+		}
+
+		ruleCtx := template.RuleContext{Command: source, MatchedField: "source", SessionID: event.SessionID}
+		message, err := template.ExecuteRuleTemplate(rule.Send, ruleCtx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to process session rule template: %w", err)
+		}
+
+		finalMessage, genErr := s.aiHelper.ProcessAIGenerationGeneric(ctx, rule, message, match.Pattern, locale)
+		if genErr != nil {
+			logging.Get(ctx).Error().Err(genErr).Msg("AI generation failed, using fallback message")
+			finalMessage = generationFallback(ctx, rule, message, func(msg string) (string, error) {
+				return template.ExecuteRuleTemplate(msg, ruleCtx)
+			})
+		}
+
+		messages = append(messages, finalMessage)
+	}
+	return messages, nil
+}
+
+// noteShownTTL is how long a once_per_session note stays hidden in a session after it's
+// shown, so marks of sessions that are never resumed don't pile up
+const noteShownTTL = 7 * 24 * time.Hour
+
+// noteShown reports whether a once_per_session note was already shown in sessionID. Notes
+// are always shown when there is no session ID or state manager, or the state can't be read.
+func (s *DefaultSessionManager) noteShown(ctx context.Context, note *config.Session, sessionID string) bool {
+	if sessionID == "" || s.stateManager == nil {
+		return false
+	}
+
+	shown, err := s.stateManager.NoteShown(ctx, sessionID, noteHash(note))
+	if err != nil {
+		logging.Get(ctx).Warn().Err(err).Msg("failed to read shown notes, showing once_per_session note")
+		return false
+	}
+	if shown {
+		logging.Get(ctx).Debug().Str("session_id", sessionID).Msg("skipping note already shown this session")
+	}
+	return shown
+}
+
+// markNoteShown records that a once_per_session note was shown in sessionID. Marks are kept
+// in the state database rather than the cache, whose session entries are cleared on every
+// startup and clear.
+func (s *DefaultSessionManager) markNoteShown(ctx context.Context, note *config.Session, sessionID string) {
+	if sessionID == "" || s.stateManager == nil {
+		return
+	}
+
+	err := s.stateManager.MarkNoteShown(ctx, sessionID, noteHash(note), time.Now().Add(noteShownTTL))
+	if err != nil {
+		logging.Get(ctx).Warn().Err(err).Msg("failed to record once_per_session note")
+	}
+}
+
+// noteHash identifies a session note by its add and add_file
+func noteHash(note *config.Session) string {
+	sum := sha256.Sum256([]byte(note.Add + "\x00" + note.AddFile))
+	return hex.EncodeToString(sum[:8])
+}
+
+// noteMessage returns the text a session note adds: the rendered add template, or the
+// contents of add_file, read like the file template function: only from within the project
+// root and cut off at 32KB. It reports false when add_file names a file that can't be read.
+func (s *DefaultSessionManager) noteMessage(ctx context.Context, note *config.Session) (string, bool, error) {
+	if note.AddFile == "" {
+		// Process template with note context including shared variables
+		processedMessage, err := template.ExecuteNoteTemplate(note.Add)
+		if err != nil {
+			return "", false, fmt.Errorf("failed to process note template: %w", err)
+		}
+		return processedMessage, true, nil
+	}
+
+	content, err := template.ProjectFile(s.getFileSystem(), s.aiHelper.projectRoot, note.AddFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		logging.Get(ctx).Debug().Str("add_file", note.AddFile).Msg("session note file not found, skipping note")
+		return "", false, nil
+	}
+	if err != nil {
+		logging.Get(ctx).Warn().Err(err).Str("add_file", note.AddFile).Msg("failed to read session note file, skipping note")
+		return "", false, nil
+	}
+
+	return strings.TrimSpace(content), true, nil
+}
+
 // ClearSessionCache clears all session-based cached AI generation entries
 func (s *DefaultSessionManager) ClearSessionCache(ctx context.Context) error {
 	// Use shared cache instance if available
@@ -185,7 +363,7 @@ func (s *DefaultSessionManager) ClearSessionCache(ctx context.Context) error {
 	}
 
 	// Create cache instance with project context
-	cache, err := ai.NewCacheWithProject(ctx, cachePath, s.aiHelper.projectRoot)
+	cache, err := ai.OpenCache(ctx, cachePath, s.aiHelper.projectRoot)
 	if err != nil {
 		return fmt.Errorf("failed to create cache: %w", err)
 	}
@@ -0,0 +1,317 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/wizzomafizzo/bumpers/internal/claude/transcript"
+	"gopkg.in/yaml.v3"
+)
+
+// Expectations a simulated step can set with expect, named after the hook's response
+const (
+	ExpectAllow         = "allow"
+	ExpectDeny          = "deny"
+	ExpectInformational = "informational"
+)
+
+// Scenario is a scripted Claude session: hook events run in order through ProcessHook, with
+// a synthetic transcript written as the session goes
+type Scenario struct {
+	// Config is the config file the scenario runs against, relative to the scenario file
+	Config string `yaml:"config,omitempty"`
+	// SessionID is sent with every event, a new one is made for each run when empty
+	SessionID string         `yaml:"session_id,omitempty"`
+	Steps     []ScenarioStep `yaml:"steps"`
+}
+
+// ScenarioStep is one hook event of a scenario, with optional assertions on the response
+type ScenarioStep struct {
+	// Input is the tool input of PreToolUse and PostToolUse events
+	Input map[string]any `yaml:"input,omitempty"`
+	// Response is the tool_response of PostToolUse events
+	Response any    `yaml:"response,omitempty"`
+	Name     string `yaml:"name,omitempty"`
+	// Event is SessionStart, UserPromptSubmit, PreToolUse, PostToolUse or Notification
+	Event string `yaml:"event"`
+	Tool  string `yaml:"tool,omitempty"`
+	// Prompt is the UserPromptSubmit prompt or the Notification message
+	Prompt string `yaml:"prompt,omitempty"`
+	// Source is the SessionStart source, default startup
+	Source string `yaml:"source,omitempty"`
+	// Expect is allow, deny or informational
+	Expect         string `yaml:"expect,omitempty"`
+	ExpectContains string `yaml:"expect_contains,omitempty"`
+	// Transcript lines are appended to the synthetic transcript before the event is sent
+	Transcript []ScenarioMessage `yaml:"transcript,omitempty"`
+}
+
+// ScenarioMessage is a message appended to a scenario's synthetic transcript
+type ScenarioMessage struct {
+	// Role is assistant (default) or user
+	Role     string `yaml:"role,omitempty"`
+	Text     string `yaml:"text,omitempty"`
+	Thinking string `yaml:"thinking,omitempty"`
+}
+
+// ScenarioStepResult is the decision for one step of a scenario
+type ScenarioStepResult struct {
+	Err  error
+	Step ScenarioStep
+	// Decision is allow, deny or informational
+	Decision string
+	// Message is the text the hook showed, taken out of its JSON output when informational
+	Message string
+	// Failures lists the step's expectations that weren't met
+	Failures []string
+}
+
+// Passed reports whether the step ran without error and met its expectations
+func (r *ScenarioStepResult) Passed() bool {
+	return r.Err == nil && len(r.Failures) == 0
+}
+
+// ParseScenario reads a YAML scenario and checks its steps
+func ParseScenario(data []byte) (*Scenario, error) {
+	var scenario Scenario
+	if err := yaml.Unmarshal(data, &scenario); err != nil {
+		return nil, fmt.Errorf("failed to parse scenario: %w", err)
+	}
+	if len(scenario.Steps) == 0 {
+		return nil, errors.New("no steps found, list them under steps:")
+	}
+	for i := range scenario.Steps {
+		if err := scenario.Steps[i].validate(); err != nil {
+			return nil, fmt.Errorf("step %d: %w", i+1, err)
+		}
+	}
+	return &scenario, nil
+}
+
+func (s *ScenarioStep) validate() error {
+	switch s.Event {
+	case "PreToolUse", "PostToolUse":
+		if s.Tool == "" {
+			return fmt.Errorf("%s needs a tool", s.Event)
+		}
+	case "UserPromptSubmit", "SessionStart", "Notification":
+	default:
+		return fmt.Errorf("unknown event %q: must be one of SessionStart, UserPromptSubmit, "+
+			"PreToolUse, PostToolUse, Notification", s.Event)
+	}
+	switch s.Expect {
+	case "", ExpectAllow, ExpectDeny, ExpectInformational:
+	default:
+		return fmt.Errorf("invalid expect %q: must be %s, %s or %s",
+			s.Expect, ExpectAllow, ExpectDeny, ExpectInformational)
+	}
+	return nil
+}
+
+// Simulate runs the scenario's steps through ProcessHook in order. Each step's transcript
+// messages, and for tool events a tool_use entry, are appended to a transcript file the
+// events point at, so intent is read as it would be in a real session. Hooks record state
+// as usual under the scenario's session ID.
+func (a *App) Simulate(ctx context.Context, scenario *Scenario) ([]ScenarioStepResult, error) {
+	dir, err := os.MkdirTemp("", "bumpers-simulate-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transcript directory: %w", err)
+	}
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+
+	sessionID := scenario.SessionID
+	if sessionID == "" {
+		sessionID = fmt.Sprintf("simulate-%d", time.Now().UnixNano())
+	}
+	writer := &scenarioTranscript{path: filepath.Join(dir, sessionID+".jsonl")}
+
+	results := make([]ScenarioStepResult, 0, len(scenario.Steps))
+	for i := range scenario.Steps {
+		step := scenario.Steps[i]
+		result := ScenarioStepResult{Step: step}
+		result.Decision, result.Message, result.Err = a.simulateStep(ctx, writer, sessionID, i+1, &step)
+		if result.Err == nil {
+			result.Failures = step.check(result.Decision, result.Message)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// simulateStep writes step's transcript entries and sends its event
+func (a *App) simulateStep(
+	ctx context.Context, writer *scenarioTranscript, sessionID string, number int, step *ScenarioStep,
+) (decision, message string, err error) {
+	for _, line := range step.Transcript {
+		if err := writer.appendMessage(line); err != nil {
+			return "", "", err
+		}
+	}
+
+	event := map[string]any{
+		"hook_event_name": step.Event,
+		"session_id":      sessionID,
+		"transcript_path": writer.path,
+	}
+	switch step.Event {
+	case "SessionStart":
+		event["source"] = step.Source
+		if step.Source == "" {
+			event["source"] = "startup"
+		}
+	case "UserPromptSubmit":
+		event["prompt"] = step.Prompt
+		if err := writer.appendMessage(ScenarioMessage{Role: "user", Text: step.Prompt}); err != nil {
+			return "", "", err
+		}
+	case "Notification":
+		event["message"] = step.Prompt
+	case "PreToolUse", "PostToolUse":
+		toolUseID, err := writer.toolUseFor(step, number)
+		if err != nil {
+			return "", "", err
+		}
+		event["tool_name"] = step.Tool
+		event["tool_input"] = step.Input
+		event["tool_use_id"] = toolUseID
+		if step.Event == "PostToolUse" {
+			event["tool_response"] = step.Response
+		}
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to marshal event: %w", err)
+	}
+	processed, err := a.ProcessHook(ctx, bytes.NewReader(payload))
+	if err != nil {
+		return "", "", err
+	}
+
+	switch processed.Mode {
+	case ProcessModeBlock:
+		return ExpectDeny, processed.Message, nil
+	case ProcessModeInformational:
+		return ExpectInformational, informationalText(processed.Message), nil
+	default:
+		return ExpectAllow, processed.Message, nil
+	}
+}
+
+// check returns the expectations the step's decision and message don't meet
+func (s *ScenarioStep) check(decision, message string) []string {
+	var failures []string
+	if s.Expect != "" && s.Expect != decision {
+		failures = append(failures, fmt.Sprintf("expected %s, got %s", s.Expect, decision))
+	}
+	if s.ExpectContains != "" && !strings.Contains(message, s.ExpectContains) {
+		failures = append(failures, fmt.Sprintf("expected message to contain %q", s.ExpectContains))
+	}
+	return failures
+}
+
+// informationalText returns the context or system message of an informational hook
+// response, or the response itself when it's neither
+func informationalText(message string) string {
+	var response struct {
+		HookSpecificOutput *HookSpecificOutput `json:"hookSpecificOutput"` //nolint:tagliatelle // Claude Code API format
+		SystemMessage      string              `json:"systemMessage"`      //nolint:tagliatelle // Claude Code API format
+	}
+	if err := json.Unmarshal([]byte(message), &response); err != nil {
+		return message
+	}
+	if response.HookSpecificOutput != nil {
+		return response.HookSpecificOutput.AdditionalContext
+	}
+	if response.SystemMessage != "" {
+		return response.SystemMessage
+	}
+	return message
+}
+
+// scenarioTranscript appends Claude transcript entries for a scenario, each the child of the
+// one before
+type scenarioTranscript struct {
+	// pending maps tool names to the tool_use ID of a PreToolUse not yet followed by its
+	// PostToolUse
+	pending  map[string]string
+	path     string
+	lastUUID string
+	count    int
+}
+
+// appendMessage writes message as a text and thinking entry
+func (w *scenarioTranscript) appendMessage(message ScenarioMessage) error {
+	role := message.Role
+	if role == "" {
+		role = "assistant"
+	}
+	var content []transcript.ContentItem
+	if message.Thinking != "" {
+		content = append(content, transcript.ContentItem{Type: "thinking", Thinking: message.Thinking})
+	}
+	if message.Text != "" {
+		content = append(content, transcript.ContentItem{Type: "text", Text: message.Text})
+	}
+	return w.append(role, content)
+}
+
+// toolUseFor returns the tool_use ID of step's call, writing its tool_use entry unless a
+// PostToolUse follows the PreToolUse that already wrote it
+func (w *scenarioTranscript) toolUseFor(step *ScenarioStep, number int) (string, error) {
+	if w.pending == nil {
+		w.pending = make(map[string]string)
+	}
+	if id, ok := w.pending[step.Tool]; ok && step.Event == "PostToolUse" {
+		delete(w.pending, step.Tool)
+		return id, nil
+	}
+
+	id := fmt.Sprintf("toolu_simulate_%d", number)
+	item := transcript.ContentItem{Type: "tool_use", ID: id, Name: step.Tool, Input: step.Input}
+	if err := w.append("assistant", []transcript.ContentItem{item}); err != nil {
+		return "", err
+	}
+	if step.Event == "PreToolUse" {
+		w.pending[step.Tool] = id
+	}
+	return id, nil
+}
+
+func (w *scenarioTranscript) append(role string, content []transcript.ContentItem) error {
+	w.count++
+	entry := transcript.TranscriptEntry{
+		Type:       role,
+		UUID:       fmt.Sprintf("simulate-%d", w.count),
+		ParentUUID: w.lastUUID,
+		Timestamp:  time.Now().UTC().Format(time.RFC3339),
+		Message:    transcript.MessageContent{Role: role, Content: content},
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal transcript entry: %w", err)
+	}
+
+	file, err := os.OpenFile(w.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open transcript: %w", err)
+	}
+	_, err = file.Write(append(data, '\n'))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write transcript: %w", err)
+	}
+	w.lastUUID = entry.UUID
+	return nil
+}
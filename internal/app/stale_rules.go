@@ -0,0 +1,54 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/wizzomafizzo/bumpers/internal/config"
+)
+
+// StaleRule is a rule that hasn't matched within a window, see App.StaleRules
+type StaleRule struct {
+	// LastFired is when the rule last matched, zero if it never has
+	LastFired time.Time
+	// ID is the rule's id, empty when it has none
+	ID      string
+	Pattern string
+	// Index is the rule's 1-based position in the config
+	Index int
+}
+
+// StaleRules returns the rules that haven't matched since olderThan before now, including
+// those that never matched, in config order. Rules without an id are tracked by their
+// pattern, so changing it starts them over as never matched.
+func (a *App) StaleRules(ctx context.Context, olderThan time.Duration, now time.Time) ([]StaleRule, error) {
+	if a.stateManager == nil {
+		return nil, ErrStateUnavailable
+	}
+	cfg, err := config.Load(a.configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	fired, err := a.stateManager.RuleFiredTimes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("state manager failed: %w", err)
+	}
+
+	cutoff := now.Add(-olderThan)
+	var stale []StaleRule
+	for i := range cfg.Rules {
+		rule := &cfg.Rules[i]
+		lastFired, ok := fired[rule.TrackingKey()]
+		if ok && lastFired.After(cutoff) {
+			continue
+		}
+		stale = append(stale, StaleRule{
+			Index:     i + 1,
+			ID:        rule.ID,
+			Pattern:   rule.GetMatch().Pattern,
+			LastFired: lastFired,
+		})
+	}
+	return stale, nil
+}
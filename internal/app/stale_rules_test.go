@@ -0,0 +1,88 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStaleRules(t *testing.T) {
+	t.Parallel()
+	ctx, _ := setupTestWithContext(t)
+
+	projectDir := t.TempDir()
+	configPath := filepath.Join(projectDir, "bumpers.yml")
+	require.NoError(t, os.WriteFile(configPath, []byte(`rules:
+  - id: recent
+    match: "^go test"
+    send: "Use just test"
+  - id: old
+    match: "^rm -rf"
+    send: "Use git clean"
+  - id: never
+    match: "^curl"
+    send: "Use the API client"
+  - match: "^npm"
+    send: "Use pnpm"
+`), 0o600))
+	app := NewAppWithFileSystem(configPath, projectDir, afero.NewOsFs())
+	require.NotNil(t, app.stateManager)
+
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, app.stateManager.RecordRuleFired(ctx, "recent", now.Add(-2*24*time.Hour)))
+	require.NoError(t, app.stateManager.RecordRuleFired(ctx, "old", now.Add(-45*24*time.Hour)))
+
+	stale, err := app.StaleRules(ctx, 30*24*time.Hour, now)
+	require.NoError(t, err)
+	require.Len(t, stale, 3)
+	assert.Equal(t, "old", stale[0].ID)
+	assert.Equal(t, 2, stale[0].Index)
+	assert.Equal(t, "^rm -rf", stale[0].Pattern)
+	assert.True(t, stale[0].LastFired.Equal(now.Add(-45*24*time.Hour)))
+	assert.Equal(t, "never", stale[1].ID)
+	assert.True(t, stale[1].LastFired.IsZero())
+	assert.Empty(t, stale[2].ID, "rules without an id are tracked by their pattern")
+	assert.Equal(t, "^npm", stale[2].Pattern)
+
+	stale, err = app.StaleRules(ctx, 24*time.Hour, now)
+	require.NoError(t, err)
+	assert.Len(t, stale, 4, "a shorter window makes the recent rule stale too")
+}
+
+func TestProcessHookRecordsRuleFired(t *testing.T) {
+	t.Parallel()
+	ctx, _ := setupTestWithContext(t)
+
+	projectDir := t.TempDir()
+	configPath := filepath.Join(projectDir, "bumpers.yml")
+	require.NoError(t, os.WriteFile(configPath, []byte(`rules:
+  - id: no-rm
+    match: "^rm -rf"
+    send: "Use git clean"
+    generate: "off"
+  - match: "^npm"
+    send: "Use pnpm"
+    generate: "off"
+`), 0o600))
+	app := NewAppWithFileSystem(configPath, projectDir, afero.NewOsFs())
+
+	stale, err := app.StaleRules(ctx, time.Hour, time.Now())
+	require.NoError(t, err)
+	require.Len(t, stale, 2)
+
+	for _, command := range []string{"rm -rf build", "npm install"} {
+		_, err = app.ProcessHook(ctx, strings.NewReader(
+			`{"hook_event_name": "PreToolUse", "tool_name": "Bash", "tool_input": {"command": "`+command+`"}}`))
+		require.NoError(t, err)
+	}
+
+	stale, err = app.StaleRules(ctx, time.Hour, time.Now())
+	require.NoError(t, err)
+	assert.Empty(t, stale, "matched rules aren't stale, with or without an id")
+}
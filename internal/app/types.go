@@ -4,7 +4,8 @@ import "github.com/wizzomafizzo/bumpers/internal/config"
 
 // UserPromptEvent represents a user prompt submission event
 type UserPromptEvent struct {
-	Prompt string `json:"prompt"`
+	Prompt    string `json:"prompt"`
+	SessionID string `json:"session_id"`
 }
 
 // HookSpecificOutput represents the hook-specific output structure
@@ -41,6 +42,18 @@ type SessionStartEvent struct {
 	Source        string `json:"source"`
 }
 
+// NotificationEvent represents a Claude Code notification event
+type NotificationEvent struct {
+	SessionID     string `json:"session_id"`
+	HookEventName string `json:"hook_event_name"`
+	Message       string `json:"message"`
+}
+
+// NotificationOutput represents the response shape for notification hooks
+type NotificationOutput struct {
+	SystemMessage string `json:"systemMessage"` //nolint:tagliatelle // Claude Code API format
+}
+
 // GenerateConfig interface for types that have GetGenerate method
 type GenerateConfig interface {
 	GetGenerate() config.Generate
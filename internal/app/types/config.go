@@ -17,4 +17,25 @@ type ConfigValidator interface {
 	ConfigLoader
 	ValidateConfig() (string, error)
 	TestCommand(ctx context.Context, command string) (string, error)
+	TestCommandDetailed(ctx context.Context, command string) (*TestCommandResult, error)
+	CheckCommand(ctx context.Context, command string) (string, bool, error)
+}
+
+// TestCommandResult is how the rules treat a shell command, for tooling that needs more
+// than TestCommand's message
+type TestCommandResult struct {
+	// Pattern is the matched rule's pattern, and Message its rendered send
+	Pattern string `json:"pattern,omitempty"`
+	Message string `json:"message,omitempty"`
+	// MatchedField is the tool_input field the pattern matched, "command" for shell commands
+	MatchedField string `json:"matched_field,omitempty"`
+	// RuleIndex is the matched rule's 1-based position in the config, or 0 without a match
+	RuleIndex int  `json:"rule_index,omitempty"`
+	Matched   bool `json:"matched"`
+}
+
+// ConfigHasher is implemented by config validators that can hash the config's content
+// without parsing it, so cached decisions can be checked before the config is loaded
+type ConfigHasher interface {
+	ConfigHash() (string, error)
 }
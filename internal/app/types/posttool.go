@@ -5,4 +5,5 @@ type PostToolContent struct {
 	Intent        string
 	ToolOutputMap map[string]any
 	ToolName      string
+	SessionID     string
 }
@@ -1,6 +1,6 @@
 package apptypes
 
-import "strings"
+import "encoding/json"
 
 // ProcessResult represents the result of processing a hook event
 type ProcessResult struct {
@@ -23,11 +23,24 @@ func ConvertResponseToProcessResult(response string) ProcessResult {
 		return ProcessResult{Mode: ProcessModeAllow, Message: ""}
 	}
 
-	// Check if response is hookSpecificOutput format (should be informational)
-	if strings.Contains(response, "hookEventName") {
+	// Check if response is hookSpecificOutput or systemMessage format (should be informational)
+	if IsStructuredResponse(response) {
 		return ProcessResult{Mode: ProcessModeInformational, Message: response}
 	}
 
 	// Otherwise it's a blocking response
 	return ProcessResult{Mode: ProcessModeBlock, Message: response}
 }
+
+// IsStructuredResponse reports whether response is Claude Code JSON output, an object with a
+// top-level hookSpecificOutput or systemMessage key, rather than a plain message that may
+// just mention those names
+func IsStructuredResponse(response string) bool {
+	var output map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(response), &output); err != nil {
+		return false
+	}
+	_, hasHookOutput := output["hookSpecificOutput"]
+	_, hasSystemMessage := output["systemMessage"]
+	return hasHookOutput || hasSystemMessage
+}
@@ -13,3 +13,24 @@ func TestProcessResult_String(t *testing.T) {
 		t.Errorf("expected 'test message', got %s", result.Message)
 	}
 }
+
+func TestConvertResponseToProcessResult(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		response string
+		want     ProcessMode
+	}{
+		{response: "", want: ProcessModeAllow},
+		{response: "Never rm -rf; set systemMessage instead", want: ProcessModeBlock},
+		{response: "hookEventName is not a command", want: ProcessModeBlock},
+		{response: `{"systemMessage": "Remember to use just"}`, want: ProcessModeInformational},
+		{response: `{"hookSpecificOutput": {"hookEventName": "PreToolUse"}}`, want: ProcessModeInformational},
+		{response: `{"message": "systemMessage"}`, want: ProcessModeBlock},
+	}
+	for _, tt := range tests {
+		if got := ConvertResponseToProcessResult(tt.response).Mode; got != tt.want {
+			t.Errorf("ConvertResponseToProcessResult(%q) mode = %s, want %s", tt.response, got, tt.want)
+		}
+	}
+}
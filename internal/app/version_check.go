@@ -0,0 +1,71 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/wizzomafizzo/bumpers/internal/logging"
+	"github.com/wizzomafizzo/bumpers/internal/version"
+)
+
+// VersionCheck is the running binary's version compared to settings.required_version
+type VersionCheck struct {
+	Version  string
+	Required string
+	// Satisfied is true when no version is required or the binary meets the constraint
+	Satisfied bool
+}
+
+// CheckVersion compares the running binary's version to the config's required_version
+func (a *App) CheckVersion(ctx context.Context) (VersionCheck, error) {
+	check := VersionCheck{Version: version.Version, Satisfied: true}
+
+	cfg, _, err := a.configValidator.LoadConfigAndMatcher(ctx)
+	if err != nil {
+		return check, fmt.Errorf("failed to load config: %w", err)
+	}
+	check.Required = cfg.Settings.RequiredVersion
+	if check.Required == "" {
+		return check, nil
+	}
+
+	check.Satisfied, err = version.Satisfies(check.Version, check.Required)
+	if err != nil {
+		return check, fmt.Errorf("invalid required_version: %w", err)
+	}
+	return check, nil
+}
+
+// warnRequiredVersion logs a warning, once per session, when the running binary doesn't
+// meet the config's required_version. Hooks are processed as usual either way.
+func (a *App) warnRequiredVersion(ctx context.Context, rawJSON json.RawMessage) {
+	check, err := a.CheckVersion(ctx)
+	if err != nil {
+		logging.Get(ctx).Debug().Err(err).Msg("skipped required_version check")
+		return
+	}
+	if check.Satisfied {
+		return
+	}
+
+	var event struct {
+		SessionID string `json:"session_id"`
+	}
+	_ = json.Unmarshal(rawJSON, &event) // the hook type was detected from valid JSON
+
+	if a.stateManager != nil {
+		marked, markErr := a.stateManager.MarkVersionWarned(ctx, event.SessionID)
+		if markErr != nil {
+			logging.Get(ctx).Debug().Err(markErr).Msg("failed to record version warning")
+		}
+		if markErr == nil && !marked {
+			return
+		}
+	}
+
+	logging.Get(ctx).Warn().
+		Str("version", check.Version).
+		Str("required_version", check.Required).
+		Msg("bumpers version does not satisfy settings.required_version")
+}
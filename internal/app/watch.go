@@ -0,0 +1,84 @@
+package app
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/wizzomafizzo/bumpers/internal/config"
+	"github.com/wizzomafizzo/bumpers/internal/constants"
+	"github.com/wizzomafizzo/bumpers/internal/template"
+)
+
+// ToolUseMatch is the rule that would answer a tool call, with the message it would send
+type ToolUseMatch struct {
+	Rule    *config.Rule
+	Field   string
+	Value   string
+	Message string
+	// Index is the rule's 1-based position in the config
+	Index int
+}
+
+// MatchToolUse finds the first rule that would block a tool call, such as one read from a
+// transcript, checking each of the call's string fields the way CheckRule does. Nothing is
+// recorded: approvals, actions, exec and AI generation are skipped, and shadow rules, which
+// never block, aren't checked. It returns nil when no rule matches.
+func (a *App) MatchToolUse(toolName string, toolInput map[string]any) (*ToolUseMatch, error) {
+	cfg, err := config.Load(a.configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	return a.matchToolUse(cfg, toolName, toolInput)
+}
+
+// matchToolUse is MatchToolUse against an already loaded config
+func (a *App) matchToolUse(cfg *config.Config, toolName string, toolInput map[string]any) (*ToolUseMatch, error) {
+	fields := toolUseFields(toolName, toolInput)
+	templateContext := a.ruleTemplateContext()
+	for i := range cfg.Rules {
+		rule := &cfg.Rules[i]
+		if rule.GetMatch().Shadow || !rule.HasAction(config.ActionBlock) {
+			continue
+		}
+		for _, field := range fields {
+			value, _ := toolInput[field].(string)
+			input := RuleCheckInput{Value: value, Tool: toolName, Event: "pre", Source: field}
+			matched, _, err := checkRuleInput(rule, input, templateContext)
+			if err != nil {
+				return nil, err
+			}
+			if !matched {
+				continue
+			}
+
+			message, err := template.ExecuteRuleTemplate(rule.Send, template.RuleContext{
+				Command:      value,
+				ToolName:     toolName,
+				MatchedField: field,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to process rule template: %w", err)
+			}
+			return &ToolUseMatch{Rule: rule, Index: i + 1, Field: field, Value: value, Message: message}, nil
+		}
+	}
+	return nil, nil //nolint:nilnil // No matching rule returns nil match and nil error
+}
+
+// toolUseFields lists the string fields of toolInput, the tool's default fields first
+func toolUseFields(toolName string, toolInput map[string]any) []string {
+	defaults := constants.DefaultToolFields[toolName]
+	var fields []string
+	for _, field := range defaults {
+		if _, ok := toolInput[field].(string); ok {
+			fields = append(fields, field)
+		}
+	}
+	for _, field := range slices.Sorted(maps.Keys(toolInput)) {
+		if _, ok := toolInput[field].(string); ok && !slices.Contains(defaults, field) {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
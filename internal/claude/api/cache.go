@@ -4,14 +4,52 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"os"
 	"time"
 
 	"github.com/wizzomafizzo/bumpers/internal/database"
 	_ "modernc.org/sqlite"
 )
 
-// Cache represents the AI message cache
-type Cache struct {
+// CacheEnv selects the cache backend: "memory" keeps entries in the process only, for tests
+// and stateless runs such as CI; unset or "sqlite" uses the cache database
+const CacheEnv = "BUMPERS_CACHE"
+
+// Cache backends selectable with CacheEnv
+const (
+	CacheBackendSQLite = "sqlite"
+	CacheBackendMemory = "memory"
+)
+
+// Cache stores generated messages and other entries by key
+type Cache interface {
+	// Get returns the entry stored for key, or nil on a miss
+	Get(ctx context.Context, key string) (*CacheEntry, error)
+	Put(ctx context.Context, key string, entry *CacheEntry) error
+	// ClearSessionCache removes the entries that expire, which last a session
+	ClearSessionCache(ctx context.Context) error
+	Close() error
+}
+
+// OpenCache opens the cache backend selected by CacheEnv, using the database at dbPath
+// with project context unless the memory backend is selected
+func OpenCache(ctx context.Context, dbPath, projectID string) (Cache, error) {
+	switch backend := os.Getenv(CacheEnv); backend {
+	case "", CacheBackendSQLite:
+		cache, err := NewCacheWithProject(ctx, dbPath, projectID)
+		if err != nil {
+			return nil, err
+		}
+		return cache, nil
+	case CacheBackendMemory:
+		return NewMemoryCache(), nil
+	default:
+		return nil, fmt.Errorf("invalid %s %q: must be %q or %q", CacheEnv, backend, CacheBackendSQLite, CacheBackendMemory)
+	}
+}
+
+// SQLCache is the AI message cache stored in the cache database
+type SQLCache struct {
 	db        *sql.DB
 	manager   *database.Manager
 	storage   map[string]*CacheEntry
@@ -19,14 +57,14 @@ type Cache struct {
 }
 
 // newCacheInstance creates a cache instance with common initialization logic
-func newCacheInstance(ctx context.Context, dbPath, projectID string) (*Cache, error) {
+func newCacheInstance(ctx context.Context, dbPath, projectID string) (*SQLCache, error) {
 	// Create database manager
 	manager, err := database.NewManager(ctx, dbPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create database manager: %w", err)
 	}
 
-	cache := &Cache{
+	cache := &SQLCache{
 		db:        manager.DB(),
 		projectID: projectID,
 		manager:   manager,
@@ -37,22 +75,22 @@ func newCacheInstance(ctx context.Context, dbPath, projectID string) (*Cache, er
 }
 
 // NewCache creates a new cache instance
-func NewCache(ctx context.Context, dbPath string) (*Cache, error) {
+func NewCache(ctx context.Context, dbPath string) (*SQLCache, error) {
 	return newCacheInstance(ctx, dbPath, "")
 }
 
 // NewCacheWithProject creates a new cache instance with project context
-func NewCacheWithProject(ctx context.Context, dbPath, projectID string) (*Cache, error) {
+func NewCacheWithProject(ctx context.Context, dbPath, projectID string) (*SQLCache, error) {
 	return newCacheInstance(ctx, dbPath, projectID)
 }
 
 // NewCacheWithDB creates a new cache instance with a database connection
-func NewCacheWithDB(_ any, _ string) (*Cache, error) {
+func NewCacheWithDB(_ any, _ string) (*SQLCache, error) {
 	return nil, nil //nolint:nilnil // Stub function not implemented
 }
 
 // Close closes the cache
-func (c *Cache) Close() error {
+func (c *SQLCache) Close() error {
 	if err := c.db.Close(); err != nil {
 		return fmt.Errorf("failed to close cache database: %w", err)
 	}
@@ -60,7 +98,7 @@ func (c *Cache) Close() error {
 }
 
 // Put stores an entry in the cache
-func (c *Cache) Put(ctx context.Context, key string, entry *CacheEntry) error {
+func (c *SQLCache) Put(ctx context.Context, key string, entry *CacheEntry) error {
 	var expiresAt *int64
 	if entry.ExpiresAt != nil {
 		timestamp := entry.ExpiresAt.Unix()
@@ -77,7 +115,7 @@ func (c *Cache) Put(ctx context.Context, key string, entry *CacheEntry) error {
 }
 
 // Get retrieves an entry from the cache
-func (c *Cache) Get(ctx context.Context, key string) (*CacheEntry, error) {
+func (c *SQLCache) Get(ctx context.Context, key string) (*CacheEntry, error) {
 	// Try memory first
 	entry, exists := c.storage[key]
 	if exists {
@@ -106,7 +144,7 @@ func (c *Cache) Get(ctx context.Context, key string) (*CacheEntry, error) {
 }
 
 // ClearSessionCache clears all cached entries with "session" generate mode
-func (c *Cache) ClearSessionCache(ctx context.Context) error {
+func (c *SQLCache) ClearSessionCache(ctx context.Context) error {
 	// Clear in-memory storage - but only session entries
 	// Session entries have ExpiresAt set, "once" entries have ExpiresAt as nil
 	for key, entry := range c.storage {
@@ -128,8 +166,8 @@ func (c *Cache) ClearSessionCache(ctx context.Context) error {
 }
 
 // NewSQLCache creates a new cache instance with a SQL database connection
-func NewSQLCache(db *sql.DB, projectID string) (*Cache, error) {
-	return &Cache{
+func NewSQLCache(db *sql.DB, projectID string) (*SQLCache, error) {
+	return &SQLCache{
 		db:        db,
 		projectID: projectID,
 		storage:   make(map[string]*CacheEntry),
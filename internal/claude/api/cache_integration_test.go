@@ -190,7 +190,7 @@ func TestCacheClearSessionCache(t *testing.T) {
 	verifyClearSessionCacheResults(t, cache)
 }
 
-func setupClearSessionCacheTest(t *testing.T, cache *Cache) {
+func setupClearSessionCacheTest(t *testing.T, cache Cache) {
 	t.Helper()
 	ctx := context.Background()
 
@@ -234,7 +234,7 @@ func setupClearSessionCacheTest(t *testing.T, cache *Cache) {
 	}
 }
 
-func verifyClearSessionCacheResults(t *testing.T, cache *Cache) {
+func verifyClearSessionCacheResults(t *testing.T, cache Cache) {
 	t.Helper()
 	ctx := context.Background()
 
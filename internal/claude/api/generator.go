@@ -17,38 +17,49 @@ type MessageGenerator interface {
 
 // Generator handles AI message generation with caching
 type Generator struct {
-	cache    *Cache
+	cache    Cache
 	launcher MessageGenerator
 }
 
 // NewGenerator creates a new AI message generator with project context
 func NewGenerator(ctx context.Context, dbPath, projectID string) (*Generator, error) {
-	cache, err := NewCacheWithProject(ctx, dbPath, projectID)
+	cache, err := OpenCache(ctx, dbPath, projectID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create cache: %w", err)
 	}
 
 	return &Generator{
 		cache:    cache,
-		launcher: claude.NewLauncher(nil),
+		launcher: claude.WrapFromEnv(claude.NewLauncher(nil)),
 	}, nil
 }
 
-// NewGeneratorWithLauncher creates a new AI message generator with custom launcher (for testing)
+// NewGeneratorWithLauncher creates a new AI message generator with custom launcher (for testing).
+// Like NewGenerator, the launcher is wrapped for recording or replay when BUMPERS_CLAUDE_RECORD
+// or BUMPERS_CLAUDE_REPLAY is set.
 func NewGeneratorWithLauncher(ctx context.Context, dbPath, projectID string,
 	launcher MessageGenerator,
 ) (*Generator, error) {
-	cache, err := NewCacheWithProject(ctx, dbPath, projectID)
+	cache, err := OpenCache(ctx, dbPath, projectID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create cache: %w", err)
 	}
 
 	return &Generator{
 		cache:    cache,
-		launcher: launcher,
+		launcher: claude.WrapFromEnv(launcher),
 	}, nil
 }
 
+// NewGeneratorWithCache creates a new AI message generator using cache, with launcher wrapped
+// like NewGeneratorWithLauncher
+func NewGeneratorWithCache(cache Cache, launcher MessageGenerator) *Generator {
+	return &Generator{
+		cache:    cache,
+		launcher: claude.WrapFromEnv(launcher),
+	}
+}
+
 // Close closes the generator
 func (g *Generator) Close() error {
 	return g.cache.Close()
@@ -70,6 +81,7 @@ func (g *Generator) GenerateMessage(ctx context.Context, req *GenerateRequest) (
 			if !cached.IsExpired() {
 				logging.Get(ctx).Debug().
 					Str("mode", req.GenerateMode).
+					Str("cache", "hit").
 					Str("original", req.OriginalMessage).
 					Msg("AI generation from cache")
 				return cached.GeneratedMessage, nil
@@ -82,6 +94,19 @@ func (g *Generator) GenerateMessage(ctx context.Context, req *GenerateRequest) (
 	if req.CustomPrompt != "" {
 		prompt = req.CustomPrompt + "\n\nMessage: " + req.OriginalMessage
 	}
+	if req.Locale != "" {
+		prompt += "\n\n" + BuildLocaleInstruction(req.Locale)
+	}
+
+	// Don't wait on a launch that's known to fail, bumpers status --refresh probes again
+	if probe := CachedProbe(ctx, g.cache); probe != nil && !probe.Available() {
+		logging.Get(ctx).Warn().
+			Str("reason", "claude_cli_unavailable").
+			Str("status", string(probe.Status)).
+			Str("detail", probe.Detail).
+			Msg("skipping AI generation, the claude CLI probe failed")
+		return req.OriginalMessage, fmt.Errorf("%w: %s", ErrCLIUnavailable, probe.Status)
+	}
 
 	result, err := g.launcher.GenerateMessage(ctx, prompt)
 	if err != nil {
@@ -89,8 +114,14 @@ func (g *Generator) GenerateMessage(ctx context.Context, req *GenerateRequest) (
 		return req.OriginalMessage, fmt.Errorf("claude generation failed: %w", err)
 	}
 
+	// "always" never reads or writes the cache, the other modes missed it
+	cacheStatus := "bypass"
+	if req.ShouldCache() {
+		cacheStatus = "miss"
+	}
 	logging.Get(ctx).Debug().
 		Str("mode", req.GenerateMode).
+		Str("cache", cacheStatus).
 		Str("original", req.OriginalMessage).
 		Msg("AI generation from fresh Claude call")
 
@@ -116,6 +147,15 @@ func (*Generator) generateCacheKey(req *GenerateRequest) string {
 	_, _ = hash.Write([]byte(req.OriginalMessage))
 	_, _ = hash.Write([]byte(req.CustomPrompt))
 	_, _ = hash.Write([]byte(req.Pattern))
+	// Only mixed in when set so existing cache entries keep their keys
+	if req.CacheKeyExtra != "" {
+		_, _ = hash.Write([]byte{0})
+		_, _ = hash.Write([]byte(req.CacheKeyExtra))
+	}
+	if req.Locale != "" {
+		_, _ = hash.Write([]byte{0, 1})
+		_, _ = hash.Write([]byte(req.Locale))
+	}
 	return fmt.Sprintf("%x", hash.Sum(nil))
 }
 
@@ -2,6 +2,8 @@ package ai
 
 import (
 	"context"
+	"crypto/sha256"
+	"fmt"
 	"path/filepath"
 	"testing"
 
@@ -244,3 +246,129 @@ func TestGeneratorLogsCache(t *testing.T) {
 	// Should only call the launcher once due to caching
 	claude.AssertMockCalled(t, mock, 1)
 }
+
+func TestGeneratorCacheKeyExtra(t *testing.T) {
+	t.Parallel()
+
+	generator := &Generator{}
+	base := &GenerateRequest{
+		OriginalMessage: "Use 'just test' instead of 'go test'",
+		GenerateMode:    "once",
+		Pattern:         "^go test",
+	}
+	withExtra := *base
+	withExtra.CacheKeyExtra = "main"
+	otherExtra := *base
+	otherExtra.CacheKeyExtra = "feature"
+
+	baseKey := generator.generateCacheKey(base)
+	if baseKey == generator.generateCacheKey(&withExtra) {
+		t.Error("Expected cache_key_extra to change the cache key")
+	}
+	if generator.generateCacheKey(&withExtra) == generator.generateCacheKey(&otherExtra) {
+		t.Error("Expected different cache_key_extra values to produce different keys")
+	}
+
+	// Keys without an extra must stay stable so existing cache entries remain valid
+	hash := sha256.New()
+	_, _ = hash.Write([]byte(base.OriginalMessage + base.CustomPrompt + base.Pattern))
+	if want := fmt.Sprintf("%x", hash.Sum(nil)); baseKey != want {
+		t.Errorf("Expected key without extra to be %s, got %s", want, baseKey)
+	}
+}
+
+//nolint:paralleltest // uses t.Setenv
+func TestGeneratorReplaysRecordedResponses(t *testing.T) {
+	ctx := setupTest(t)
+	replayDir := t.TempDir()
+	t.Setenv(claude.ReplayDirEnv, replayDir)
+	t.Setenv(claude.RecordDirEnv, "")
+
+	original := "Use 'just test' instead of 'go test'"
+	recorder := claude.NewRecordingLauncher(claude.NewMockLauncher(), replayDir)
+	if _, err := recorder.GenerateMessage(ctx, BuildDefaultPrompt(original)); err != nil {
+		t.Fatalf("Failed to record response: %v", err)
+	}
+
+	mock := claude.NewMockLauncher()
+	generator, err := NewGeneratorWithLauncher(ctx, filepath.Join(t.TempDir(), "test.db"), "test-project", mock)
+	if err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+	t.Cleanup(func() { _ = generator.Close() })
+
+	result, err := generator.GenerateMessage(ctx, &GenerateRequest{OriginalMessage: original, GenerateMode: "always"})
+	if err != nil {
+		t.Fatalf("GenerateMessage failed: %v", err)
+	}
+	if result != "Mock response" {
+		t.Errorf("Expected recorded response, got %q", result)
+	}
+	if mock.GetCallCount() != 0 {
+		t.Errorf("Expected replay to avoid the launcher, got %d calls", mock.GetCallCount())
+	}
+}
+
+func TestGeneratorMemoryCacheDeduplicates(t *testing.T) {
+	t.Parallel()
+	ctx := setupTest(t)
+
+	mock := claude.SetupMockLauncherWithDefaults()
+	mock.SetResponseForPattern(".*", "Mock AI response")
+	cache := NewMemoryCache()
+	generator := NewGeneratorWithCache(cache, mock)
+
+	req := &GenerateRequest{
+		OriginalMessage: "Use 'just test' instead of 'go test'",
+		GenerateMode:    "session",
+		Pattern:         "^go test",
+	}
+	for range 3 {
+		result, err := generator.GenerateMessage(ctx, req)
+		if err != nil {
+			t.Fatalf("GenerateMessage failed: %v", err)
+		}
+		if result != "Mock AI response" {
+			t.Errorf("Expected mocked response, got %q", result)
+		}
+	}
+	claude.AssertMockCalled(t, mock, 1)
+
+	// Session entries are dropped when a new session starts
+	if err := cache.ClearSessionCache(ctx); err != nil {
+		t.Fatalf("ClearSessionCache failed: %v", err)
+	}
+	if _, err := generator.GenerateMessage(ctx, req); err != nil {
+		t.Fatalf("GenerateMessage failed: %v", err)
+	}
+	claude.AssertMockCalled(t, mock, 2)
+}
+
+func TestOpenCacheSelectsBackend(t *testing.T) { //nolint:paralleltest // t.Setenv() usage
+	ctx := setupTest(t)
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+
+	t.Setenv(CacheEnv, CacheBackendMemory)
+	cache, err := OpenCache(ctx, dbPath, "test-project")
+	if err != nil {
+		t.Fatalf("OpenCache failed: %v", err)
+	}
+	if _, ok := cache.(*MemoryCache); !ok {
+		t.Errorf("Expected a memory cache, got %T", cache)
+	}
+
+	t.Setenv(CacheEnv, "")
+	cache, err = OpenCache(ctx, dbPath, "test-project")
+	if err != nil {
+		t.Fatalf("OpenCache failed: %v", err)
+	}
+	if _, ok := cache.(*SQLCache); !ok {
+		t.Errorf("Expected a SQLite cache, got %T", cache)
+	}
+	_ = cache.Close()
+
+	t.Setenv(CacheEnv, "bbolt")
+	if _, err := OpenCache(ctx, dbPath, "test-project"); err == nil {
+		t.Error("Expected an error for an unknown backend")
+	}
+}
@@ -0,0 +1,57 @@
+package ai
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryCache is a Cache kept in process memory, selected with BUMPERS_CACHE=memory. Entries
+// are lost when the process exits.
+type MemoryCache struct {
+	entries map[string]*CacheEntry
+	mu      sync.Mutex
+}
+
+// NewMemoryCache creates an empty in-memory cache
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{entries: make(map[string]*CacheEntry)}
+}
+
+// Get retrieves an entry from the cache
+func (c *MemoryCache) Get(_ context.Context, key string) (*CacheEntry, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, nil //nolint:nilnil // Cache miss returns nil value and nil error
+	}
+	stored := *entry
+	return &stored, nil
+}
+
+// Put stores an entry in the cache
+func (c *MemoryCache) Put(_ context.Context, key string, entry *CacheEntry) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stored := *entry
+	c.entries[key] = &stored
+	return nil
+}
+
+// ClearSessionCache clears all cached entries with "session" generate mode, which are the
+// ones with an expiry
+func (c *MemoryCache) ClearSessionCache(_ context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, entry := range c.entries {
+		if entry.ExpiresAt != nil {
+			delete(c.entries, key)
+		}
+	}
+	return nil
+}
+
+// Close does nothing, the entries are released with the cache
+func (*MemoryCache) Close() error {
+	return nil
+}
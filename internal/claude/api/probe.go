@@ -0,0 +1,53 @@
+package ai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/wizzomafizzo/bumpers/internal/claude"
+)
+
+// ErrCLIUnavailable is returned by GenerateMessage when the cached probe says the claude CLI
+// can't be used, so it wasn't launched
+var ErrCLIUnavailable = errors.New("claude CLI is unavailable")
+
+// probeCacheKey is the cache key the last claude CLI probe result is stored under
+const probeCacheKey = "probe:claude-cli"
+
+// ProbeCLI returns the cached claude CLI probe result, probing with prober and caching the
+// result for claude.ProbeTTL when there is none, it expired or refresh is set
+func ProbeCLI(ctx context.Context, cache Cache, prober claude.Prober, refresh bool) (*claude.ProbeResult, error) {
+	if !refresh {
+		if result := CachedProbe(ctx, cache); result != nil {
+			return result, nil
+		}
+	}
+
+	result := prober.Probe(ctx)
+	data, err := json.Marshal(result)
+	if err != nil {
+		return result, fmt.Errorf("failed to encode probe result: %w", err)
+	}
+	expiresAt := result.CheckedAt.Add(claude.ProbeTTL)
+	entry := &CacheEntry{GeneratedMessage: string(data), Timestamp: result.CheckedAt, ExpiresAt: &expiresAt}
+	if err := cache.Put(ctx, probeCacheKey, entry); err != nil {
+		return result, fmt.Errorf("failed to cache probe result: %w", err)
+	}
+	return result, nil
+}
+
+// CachedProbe returns the cached claude CLI probe result, or nil when there is none or it
+// expired. It never probes.
+func CachedProbe(ctx context.Context, cache Cache) *claude.ProbeResult {
+	entry, err := cache.Get(ctx, probeCacheKey)
+	if err != nil || entry == nil || entry.IsExpired() {
+		return nil
+	}
+	var result claude.ProbeResult
+	if err := json.Unmarshal([]byte(entry.GeneratedMessage), &result); err != nil {
+		return nil
+	}
+	return &result
+}
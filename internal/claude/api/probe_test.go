@@ -0,0 +1,135 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/wizzomafizzo/bumpers/internal/claude"
+)
+
+// fakeProber returns result from Probe, counting the calls
+type fakeProber struct {
+	result *claude.ProbeResult
+	calls  int
+}
+
+func (p *fakeProber) Probe(_ context.Context) *claude.ProbeResult {
+	p.calls++
+	result := *p.result
+	return &result
+}
+
+func TestProbeCLI(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		status    claude.CLIStatus
+		available bool
+	}{
+		{name: "available", status: claude.CLIAvailable, available: true},
+		{name: "unavailable", status: claude.CLINotFound},
+		{name: "auth expired", status: claude.CLIAuthExpired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := setupTest(t)
+			cache := NewMemoryCache()
+			prober := &fakeProber{result: &claude.ProbeResult{Status: tt.status, CheckedAt: time.Now()}}
+
+			result, err := ProbeCLI(ctx, cache, prober, false)
+			if err != nil {
+				t.Fatalf("ProbeCLI failed: %v", err)
+			}
+			if result.Status != tt.status || result.Available() != tt.available {
+				t.Errorf("Expected status %s (available %v), got %s", tt.status, tt.available, result.Status)
+			}
+
+			if _, err := ProbeCLI(ctx, cache, prober, false); err != nil {
+				t.Fatalf("ProbeCLI failed: %v", err)
+			}
+			if prober.calls != 1 {
+				t.Errorf("Expected the cached result to be reused, probed %d times", prober.calls)
+			}
+			if cached := CachedProbe(ctx, cache); cached == nil || cached.Status != tt.status {
+				t.Errorf("Expected cached status %s, got %+v", tt.status, cached)
+			}
+
+			if _, err := ProbeCLI(ctx, cache, prober, true); err != nil {
+				t.Fatalf("ProbeCLI failed: %v", err)
+			}
+			if prober.calls != 2 {
+				t.Errorf("Expected refresh to probe again, probed %d times", prober.calls)
+			}
+		})
+	}
+}
+
+func TestProbeCLIExpires(t *testing.T) {
+	t.Parallel()
+	ctx := setupTest(t)
+	cache := NewMemoryCache()
+	prober := &fakeProber{result: &claude.ProbeResult{
+		Status:    claude.CLIAvailable,
+		CheckedAt: time.Now().Add(-claude.ProbeTTL - time.Minute),
+	}}
+
+	if _, err := ProbeCLI(ctx, cache, prober, false); err != nil {
+		t.Fatalf("ProbeCLI failed: %v", err)
+	}
+	if cached := CachedProbe(ctx, cache); cached != nil {
+		t.Errorf("Expected no cached result past the TTL, got %+v", cached)
+	}
+	if _, err := ProbeCLI(ctx, cache, prober, false); err != nil {
+		t.Fatalf("ProbeCLI failed: %v", err)
+	}
+	if prober.calls != 2 {
+		t.Errorf("Expected an expired result to be probed again, probed %d times", prober.calls)
+	}
+}
+
+func TestGeneratorSkipsUnavailableCLI(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		status    claude.CLIStatus
+		launched  bool
+		wantError error
+	}{
+		{name: "available", status: claude.CLIAvailable, launched: true},
+		{name: "unavailable", status: claude.CLINotFound, wantError: ErrCLIUnavailable},
+		{name: "auth expired", status: claude.CLIAuthExpired, wantError: ErrCLIUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			ctx := setupTest(t)
+			cache := NewMemoryCache()
+			prober := &fakeProber{result: &claude.ProbeResult{Status: tt.status, CheckedAt: time.Now()}}
+			if _, err := ProbeCLI(ctx, cache, prober, false); err != nil {
+				t.Fatalf("ProbeCLI failed: %v", err)
+			}
+
+			mock := claude.NewMockLauncher()
+			generator := &Generator{cache: cache, launcher: mock}
+			req := &GenerateRequest{OriginalMessage: "Use just test", GenerateMode: "always"}
+			result, err := generator.GenerateMessage(ctx, req)
+
+			if !errors.Is(err, tt.wantError) && (tt.wantError != nil || err != nil) {
+				t.Errorf("Expected error %v, got %v", tt.wantError, err)
+			}
+			if launched := mock.GetCallCount() > 0; launched != tt.launched {
+				t.Errorf("Expected launched %v, got %v", tt.launched, launched)
+			}
+			if !tt.launched && result != req.OriginalMessage {
+				t.Errorf("Expected the original message when skipped, got %q", result)
+			}
+		})
+	}
+}
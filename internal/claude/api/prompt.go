@@ -22,6 +22,13 @@ ORIGINAL MESSAGE:
 ` + message
 }
 
+// BuildLocaleInstruction asks for the generated message in the language of locale, such as
+// ja or pt-BR
+func BuildLocaleInstruction(locale string) string {
+	return "Write your response in the language of the locale \"" + locale + "\", whatever " +
+		"language the original message is in."
+}
+
 // BuildRegexGenerationPrompt creates a prompt for generating regex patterns from commands or descriptions
 func BuildRegexGenerationPrompt(input string) string {
 	return `You are a regex pattern generator for command matching in a security hook system.
@@ -16,6 +16,8 @@ type GenerateRequest struct {
 	CustomPrompt    string
 	GenerateMode    string
 	Pattern         string
+	CacheKeyExtra   string // Mixed into the cache key to namespace cached responses
+	Locale          string // settings.locale, the language the message is generated in
 }
 
 // IsExpired checks if a cache entry has expired based on its mode
@@ -0,0 +1,144 @@
+package claude
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ProbeTTL is how long a probe result is trusted before the CLI is checked again
+const ProbeTTL = time.Hour
+
+// probeTimeout bounds running claude --version
+const probeTimeout = 5 * time.Second
+
+// CLIStatus is what a probe found out about the claude CLI
+type CLIStatus string
+
+const (
+	// CLIAvailable means the binary runs and no sign of missing auth was found
+	CLIAvailable CLIStatus = "available"
+	// CLINotFound means no claude binary was found
+	CLINotFound CLIStatus = "not_found"
+	// CLIBroken means the binary was found but claude --version failed
+	CLIBroken CLIStatus = "broken"
+	// CLIAuthExpired means the stored login expired and can't be refreshed
+	CLIAuthExpired CLIStatus = "auth_expired"
+)
+
+// ProbeResult describes the claude CLI as found by a Prober
+type ProbeResult struct {
+	CheckedAt time.Time `json:"checked_at"`
+	Status    CLIStatus `json:"status"`
+	Path      string    `json:"path,omitempty"`
+	Version   string    `json:"version,omitempty"`
+	// Detail explains a status other than available, or how auth was checked
+	Detail string `json:"detail,omitempty"`
+}
+
+// Available reports whether the CLI can be launched for AI generation
+func (r *ProbeResult) Available() bool {
+	return r.Status == CLIAvailable
+}
+
+// Prober checks whether the claude CLI is usable
+type Prober interface {
+	Probe(ctx context.Context) *ProbeResult
+}
+
+// ProberFunc adapts a function to a Prober
+type ProberFunc func(ctx context.Context) *ProbeResult
+
+// Probe calls f
+func (f ProberFunc) Probe(ctx context.Context) *ProbeResult {
+	return f(ctx)
+}
+
+// CLIProber probes the claude binary found by a Launcher: that it exists, its version and,
+// without making a request, whether its stored login has expired
+type CLIProber struct {
+	launcher        *Launcher
+	credentialsPath string
+	now             func() time.Time
+}
+
+// NewCLIProber creates a prober for the binary launcher finds, checking the login stored in
+// ~/.claude/.credentials.json
+func NewCLIProber(launcher *Launcher) *CLIProber {
+	prober := &CLIProber{launcher: launcher, now: time.Now}
+	if homeDir, err := os.UserHomeDir(); err == nil {
+		prober.credentialsPath = filepath.Join(homeDir, ".claude", ".credentials.json")
+	}
+	return prober
+}
+
+// Probe checks the CLI, returning the first problem found
+func (p *CLIProber) Probe(ctx context.Context) *ProbeResult {
+	result := &ProbeResult{CheckedAt: p.now()}
+
+	path, err := p.launcher.GetClaudePath()
+	if err != nil {
+		result.Status = CLINotFound
+		result.Detail = "claude binary not found in ~/.claude/local, PATH or common locations"
+		return result
+	}
+	result.Path = path
+
+	versionCtx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+	// #nosec G204 -- path is the claude binary found by the launcher
+	output, err := exec.CommandContext(versionCtx, path, "--version").Output()
+	if err != nil {
+		result.Status = CLIBroken
+		result.Detail = fmt.Sprintf("claude --version failed: %v", err)
+		return result
+	}
+	result.Version = strings.TrimSpace(string(output))
+
+	result.Status, result.Detail = checkAuth(p.credentialsPath, p.now())
+	return result
+}
+
+// storedCredentials is the part of the claude credentials file the auth check reads
+type storedCredentials struct {
+	ClaudeAiOauth *struct {
+		RefreshToken string `json:"refreshToken"`
+		ExpiresAt    int64  `json:"expiresAt"`
+	} `json:"claudeAiOauth"`
+}
+
+// checkAuth looks for signs the CLI can't authenticate, without contacting the API. An API
+// key or a login that can still be refreshed counts as authenticated. When no credentials
+// file exists, such as on macOS where the login is kept in the keychain, auth is assumed.
+func checkAuth(credentialsPath string, now time.Time) (status CLIStatus, detail string) {
+	if os.Getenv("ANTHROPIC_API_KEY") != "" {
+		return CLIAvailable, "authenticated with ANTHROPIC_API_KEY"
+	}
+	if credentialsPath == "" {
+		return CLIAvailable, "auth not checked"
+	}
+
+	data, err := os.ReadFile(credentialsPath) // #nosec G304 -- path is the claude credentials file
+	if errors.Is(err, os.ErrNotExist) {
+		return CLIAvailable, "auth not checked, no credentials file"
+	}
+	if err != nil {
+		return CLIAvailable, fmt.Sprintf("auth not checked: %v", err)
+	}
+
+	var credentials storedCredentials
+	if err := json.Unmarshal(data, &credentials); err != nil || credentials.ClaudeAiOauth == nil {
+		return CLIAvailable, "auth not checked, unrecognized credentials file"
+	}
+	oauth := credentials.ClaudeAiOauth
+	if oauth.ExpiresAt > 0 && oauth.RefreshToken == "" && now.After(time.UnixMilli(oauth.ExpiresAt)) {
+		return CLIAuthExpired, "login expired, run claude and log in again"
+	}
+	return CLIAvailable, "logged in"
+}
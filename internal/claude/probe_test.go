@@ -0,0 +1,68 @@
+package claude
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	testutil "github.com/wizzomafizzo/bumpers/internal/testing"
+)
+
+func TestCheckAuth(t *testing.T) {
+	_, _ = testutil.NewTestContext(t)
+	t.Setenv("ANTHROPIC_API_KEY", "")
+
+	now := time.Now()
+	past := now.Add(-time.Hour).UnixMilli()
+	future := now.Add(time.Hour).UnixMilli()
+	tests := []struct {
+		name        string
+		credentials string
+		want        CLIStatus
+	}{
+		{name: "no credentials file", want: CLIAvailable},
+		{name: "valid login", credentials: `{"claudeAiOauth":{"expiresAt":` + itoa(future) + `}}`, want: CLIAvailable},
+		{
+			name:        "expired but refreshable",
+			credentials: `{"claudeAiOauth":{"refreshToken":"r","expiresAt":` + itoa(past) + `}}`,
+			want:        CLIAvailable,
+		},
+		{name: "expired", credentials: `{"claudeAiOauth":{"expiresAt":` + itoa(past) + `}}`, want: CLIAuthExpired},
+		{name: "unrecognized file", credentials: `not json`, want: CLIAvailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), ".credentials.json")
+			if tt.credentials != "" {
+				if err := os.WriteFile(path, []byte(tt.credentials), 0o600); err != nil {
+					t.Fatalf("Failed to write credentials: %v", err)
+				}
+			}
+
+			status, detail := checkAuth(path, now)
+			if status != tt.want {
+				t.Errorf("Expected %s, got %s (%s)", tt.want, status, detail)
+			}
+		})
+	}
+}
+
+func TestCheckAuthAPIKey(t *testing.T) {
+	_, _ = testutil.NewTestContext(t)
+	t.Setenv("ANTHROPIC_API_KEY", "key")
+
+	path := filepath.Join(t.TempDir(), ".credentials.json")
+	if err := os.WriteFile(path, []byte(`{"claudeAiOauth":{"expiresAt":1}}`), 0o600); err != nil {
+		t.Fatalf("Failed to write credentials: %v", err)
+	}
+	if status, _ := checkAuth(path, time.Now()); status != CLIAvailable {
+		t.Errorf("Expected an API key to count as authenticated, got %s", status)
+	}
+}
+
+func itoa(n int64) string {
+	return strconv.FormatInt(n, 10)
+}
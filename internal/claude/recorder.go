@@ -0,0 +1,195 @@
+package claude
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Environment variables controlling recording and replay of Claude responses
+const (
+	// RecordDirEnv saves every generated response to this directory
+	RecordDirEnv = "BUMPERS_CLAUDE_RECORD"
+	// ReplayDirEnv serves responses from recordings in this directory instead of calling Claude
+	ReplayDirEnv = "BUMPERS_CLAUDE_REPLAY"
+	// ReplayFallbackEnv set to "1" or "true" calls the real launcher when a recording is missing
+	ReplayFallbackEnv = "BUMPERS_CLAUDE_REPLAY_FALLBACK"
+)
+
+// recordingExt is the file extension for saved recordings
+const recordingExt = ".json"
+
+// ErrRecordingNotFound is returned in replay mode when no recording exists for a prompt
+var ErrRecordingNotFound = errors.New("no recorded claude response for prompt")
+
+// Generator generates a message for a prompt, implemented by Launcher and MockLauncher
+type Generator interface {
+	GenerateMessage(ctx context.Context, prompt string) (string, error)
+}
+
+// Recording is a saved prompt and the response Claude gave for it
+type Recording struct {
+	RecordedAt time.Time `json:"recorded_at"`
+	Prompt     string    `json:"prompt"`
+	Response   string    `json:"response"`
+}
+
+// RecordingPath returns the file a recording for prompt is stored in
+func RecordingPath(dir, prompt string) string {
+	hash := sha256.Sum256([]byte(prompt))
+	return filepath.Join(dir, fmt.Sprintf("%x", hash)+recordingExt)
+}
+
+// RecordingLauncher saves every response from the wrapped generator to a directory
+type RecordingLauncher struct {
+	next Generator
+	dir  string
+}
+
+// NewRecordingLauncher creates a launcher that records responses from next into dir
+func NewRecordingLauncher(next Generator, dir string) *RecordingLauncher {
+	return &RecordingLauncher{next: next, dir: dir}
+}
+
+// GenerateMessage generates a response with the wrapped generator and saves it
+func (r *RecordingLauncher) GenerateMessage(ctx context.Context, prompt string) (string, error) {
+	response, err := r.next.GenerateMessage(ctx, prompt)
+	if err != nil {
+		return "", err
+	}
+
+	if err := saveRecording(r.dir, &Recording{
+		RecordedAt: time.Now(),
+		Prompt:     prompt,
+		Response:   response,
+	}); err != nil {
+		return "", err
+	}
+
+	return response, nil
+}
+
+// ReplayLauncher serves saved responses from a directory
+type ReplayLauncher struct {
+	fallback Generator
+	dir      string
+}
+
+// NewReplayLauncher creates a launcher that replays recordings from dir. On a miss it calls
+// fallback when one is given, otherwise it fails with ErrRecordingNotFound.
+func NewReplayLauncher(dir string, fallback Generator) *ReplayLauncher {
+	return &ReplayLauncher{dir: dir, fallback: fallback}
+}
+
+// GenerateMessage returns the recorded response for prompt
+func (r *ReplayLauncher) GenerateMessage(ctx context.Context, prompt string) (string, error) {
+	path := RecordingPath(r.dir, prompt)
+	data, err := os.ReadFile(path) //nolint:gosec // path is derived from a hash inside the replay dir
+	if errors.Is(err, os.ErrNotExist) {
+		if r.fallback != nil {
+			return r.fallback.GenerateMessage(ctx, prompt)
+		}
+		return "", fmt.Errorf("%w: %s", ErrRecordingNotFound, path)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read recording %s: %w", path, err)
+	}
+
+	var recording Recording
+	if err := json.Unmarshal(data, &recording); err != nil {
+		return "", fmt.Errorf("failed to parse recording %s: %w", path, err)
+	}
+	return recording.Response, nil
+}
+
+// WrapFromEnv wraps next with recording and/or replay according to the BUMPERS_CLAUDE_*
+// environment variables, returning next unchanged when none are set
+func WrapFromEnv(next Generator) Generator {
+	generator := next
+	if dir := os.Getenv(RecordDirEnv); dir != "" {
+		generator = NewRecordingLauncher(generator, dir)
+	}
+	if dir := os.Getenv(ReplayDirEnv); dir != "" {
+		var fallback Generator
+		if fallbackEnv := strings.ToLower(os.Getenv(ReplayFallbackEnv)); fallbackEnv == "1" || fallbackEnv == "true" {
+			fallback = generator
+		}
+		generator = NewReplayLauncher(dir, fallback)
+	}
+	return generator
+}
+
+// PruneRecordings deletes recordings in dir recorded more than maxAge before now,
+// returning how many were removed
+func PruneRecordings(dir string, maxAge time.Duration, now time.Time) (int, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read recordings directory %s: %w", dir, err)
+	}
+
+	cutoff := now.Add(-maxAge)
+	removed := 0
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != recordingExt {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		recordedAt, err := recordingTime(path)
+		if err != nil {
+			return removed, err
+		}
+		if !recordedAt.Before(cutoff) {
+			continue
+		}
+
+		if err := os.Remove(path); err != nil {
+			return removed, fmt.Errorf("failed to remove recording %s: %w", path, err)
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// recordingTime returns when a recording was made, falling back to the file's mtime
+func recordingTime(path string) (time.Time, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // path comes from listing the recordings dir
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to read recording %s: %w", path, err)
+	}
+
+	var recording Recording
+	if json.Unmarshal(data, &recording) == nil && !recording.RecordedAt.IsZero() {
+		return recording.RecordedAt, nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to stat recording %s: %w", path, err)
+	}
+	return info.ModTime(), nil
+}
+
+// saveRecording writes a recording to dir, creating the directory if needed
+func saveRecording(dir string, recording *Recording) error {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return fmt.Errorf("failed to create recordings directory %s: %w", dir, err)
+	}
+
+	data, err := json.MarshalIndent(recording, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal recording: %w", err)
+	}
+
+	path := RecordingPath(dir, recording.Prompt)
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write recording %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package claude
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRecordThenReplay(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	mock := NewMockLauncher()
+	mock.SetResponseForPattern(".*", testResponse)
+
+	recorder := NewRecordingLauncher(mock, dir)
+	got, err := recorder.GenerateMessage(ctx, testPrompt)
+	if err != nil {
+		t.Fatalf("record failed: %v", err)
+	}
+	if got != testResponse {
+		t.Errorf("Expected %q, got %q", testResponse, got)
+	}
+	if _, err := os.Stat(RecordingPath(dir, testPrompt)); err != nil {
+		t.Fatalf("Expected recording file: %v", err)
+	}
+
+	replayer := NewReplayLauncher(dir, nil)
+	got, err = replayer.GenerateMessage(ctx, testPrompt)
+	if err != nil {
+		t.Fatalf("replay failed: %v", err)
+	}
+	if got != testResponse {
+		t.Errorf("Expected replayed %q, got %q", testResponse, got)
+	}
+	if mock.GetCallCount() != 1 {
+		t.Errorf("Expected replay not to call the launcher, got %d calls", mock.GetCallCount())
+	}
+}
+
+func TestReplayMissFailsLoudly(t *testing.T) {
+	t.Parallel()
+
+	replayer := NewReplayLauncher(t.TempDir(), nil)
+	_, err := replayer.GenerateMessage(context.Background(), testPrompt)
+	if !errors.Is(err, ErrRecordingNotFound) {
+		t.Fatalf("Expected ErrRecordingNotFound, got %v", err)
+	}
+}
+
+func TestReplayMissFallsBack(t *testing.T) {
+	t.Parallel()
+
+	mock := NewMockLauncher()
+	replayer := NewReplayLauncher(t.TempDir(), mock)
+	got, err := replayer.GenerateMessage(context.Background(), testPrompt)
+	if err != nil {
+		t.Fatalf("Expected fallback, got %v", err)
+	}
+	if got != defaultMockResponse || mock.GetCallCount() != 1 {
+		t.Errorf("Expected fallback response from launcher, got %q with %d calls", got, mock.GetCallCount())
+	}
+}
+
+//nolint:paralleltest // uses t.Setenv
+func TestWrapFromEnv(t *testing.T) {
+	mock := NewMockLauncher()
+
+	t.Setenv(RecordDirEnv, "")
+	t.Setenv(ReplayDirEnv, "")
+	if WrapFromEnv(mock) != Generator(mock) {
+		t.Error("Expected launcher unchanged without env")
+	}
+
+	recordDir := t.TempDir()
+	t.Setenv(RecordDirEnv, recordDir)
+	if _, ok := WrapFromEnv(mock).(*RecordingLauncher); !ok {
+		t.Error("Expected recording launcher")
+	}
+
+	// Replay with fallback records misses from the real launcher
+	t.Setenv(ReplayDirEnv, t.TempDir())
+	t.Setenv(ReplayFallbackEnv, "true")
+	if _, err := WrapFromEnv(mock).GenerateMessage(context.Background(), testPrompt); err != nil {
+		t.Fatalf("Expected fallback to launcher, got %v", err)
+	}
+	if _, err := os.Stat(RecordingPath(recordDir, testPrompt)); err != nil {
+		t.Errorf("Expected fallback response to be recorded: %v", err)
+	}
+
+	t.Setenv(ReplayFallbackEnv, "")
+	if _, err := WrapFromEnv(mock).GenerateMessage(context.Background(), testPrompt); !errors.Is(err, ErrRecordingNotFound) {
+		t.Errorf("Expected ErrRecordingNotFound without fallback, got %v", err)
+	}
+}
+
+func TestPruneRecordings(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	now := time.Now()
+
+	write := func(prompt string, recordedAt time.Time) {
+		t.Helper()
+		data, err := json.Marshal(Recording{Prompt: prompt, Response: "r", RecordedAt: recordedAt})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(RecordingPath(dir, prompt), data, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("old", now.Add(-40*24*time.Hour))
+	write("new", now.Add(-time.Hour))
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("keep"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	removed, err := PruneRecordings(dir, 30*24*time.Hour, now)
+	if err != nil {
+		t.Fatalf("prune failed: %v", err)
+	}
+	if removed != 1 {
+		t.Errorf("Expected 1 recording removed, got %d", removed)
+	}
+	if _, err := os.Stat(RecordingPath(dir, "old")); !os.IsNotExist(err) {
+		t.Error("Expected old recording to be removed")
+	}
+	for _, keep := range []string{RecordingPath(dir, "new"), filepath.Join(dir, "notes.txt")} {
+		if _, err := os.Stat(keep); err != nil {
+			t.Errorf("Expected %s to be kept: %v", keep, err)
+		}
+	}
+}
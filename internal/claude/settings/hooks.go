@@ -253,3 +253,63 @@ func (s *Settings) AddOrAppendHook(event HookEvent, matcher string, command Hook
 
 	return nil
 }
+
+// ReplaceOrAppendHook adds command to the event like AddOrAppendHook, but first removes every
+// command that stale reports as an older copy of it, such as one with an outdated path. A stale
+// command under the same matcher is replaced in place instead, and matchers left without
+// commands are removed.
+func (s *Settings) ReplaceOrAppendHook(
+	event HookEvent, matcher string, command HookCommand, stale func(HookCommand) bool,
+) error {
+	if s.Hooks == nil {
+		s.Hooks = &Hooks{}
+	}
+
+	var hookMatchers *[]HookMatcher
+	switch event {
+	case PreToolUseEvent:
+		hookMatchers = &s.Hooks.PreToolUse
+	case PostToolUseEvent:
+		hookMatchers = &s.Hooks.PostToolUse
+	case UserPromptSubmitEvent:
+		hookMatchers = &s.Hooks.UserPromptSubmit
+	case SessionStartEvent:
+		hookMatchers = &s.Hooks.SessionStart
+	case StopEvent:
+		hookMatchers = &s.Hooks.Stop
+	case SubagentStopEvent:
+		hookMatchers = &s.Hooks.SubagentStop
+	case PreCompactEvent:
+		hookMatchers = &s.Hooks.PreCompact
+	case NotificationEvent:
+		hookMatchers = &s.Hooks.Notification
+	default:
+		return fmt.Errorf("unsupported event: %s", event)
+	}
+
+	replaced := false
+	kept := make([]HookMatcher, 0, len(*hookMatchers))
+	for _, hookMatcher := range *hookMatchers {
+		commands := make([]HookCommand, 0, len(hookMatcher.Hooks))
+		for _, existing := range hookMatcher.Hooks {
+			if !stale(existing) {
+				commands = append(commands, existing)
+				continue
+			}
+			if !replaced && hookMatcher.Matcher == matcher {
+				commands = append(commands, command)
+				replaced = true
+			}
+		}
+		if len(commands) > 0 {
+			hookMatcher.Hooks = commands
+			kept = append(kept, hookMatcher)
+		}
+	}
+	*hookMatchers = kept
+
+	if replaced {
+		return nil
+	}
+	return s.AddOrAppendHook(event, matcher, command)
+}
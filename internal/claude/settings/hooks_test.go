@@ -1,6 +1,8 @@
 package settings
 
 import (
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -650,3 +652,38 @@ func TestSettings_AddOrAppendHook_UserPromptSubmit(t *testing.T) {
 		t.Errorf("Expected 2 commands, got %d", len(settings.Hooks.UserPromptSubmit[0].Hooks))
 	}
 }
+
+func TestSettings_ReplaceOrAppendHook(t *testing.T) {
+	t.Parallel()
+
+	settings := &Settings{Hooks: &Hooks{
+		PreToolUse: []HookMatcher{
+			{Matcher: "", Hooks: []HookCommand{
+				{Type: "command", Command: "other-tool"},
+				{Type: "command", Command: "/old/bin/bumpers hook"},
+			}},
+			{Matcher: "Bash", Hooks: []HookCommand{{Type: "command", Command: "/older/bin/bumpers hook"}}},
+		},
+	}}
+	stale := func(command HookCommand) bool {
+		return strings.HasSuffix(command.Command, "bumpers hook")
+	}
+	command := HookCommand{Type: "command", Command: "/new/bin/bumpers hook"}
+
+	if err := settings.ReplaceOrAppendHook(PreToolUseEvent, "", command, stale); err != nil {
+		t.Fatalf("ReplaceOrAppendHook failed: %v", err)
+	}
+
+	want := []HookMatcher{{Matcher: "", Hooks: []HookCommand{{Type: "command", Command: "other-tool"}, command}}}
+	if !reflect.DeepEqual(settings.Hooks.PreToolUse, want) {
+		t.Errorf("Expected stale hooks replaced in place, got %+v", settings.Hooks.PreToolUse)
+	}
+
+	// Without a stale command under the matcher it's appended
+	if err := settings.ReplaceOrAppendHook(PostToolUseEvent, "", command, stale); err != nil {
+		t.Fatalf("ReplaceOrAppendHook failed: %v", err)
+	}
+	if len(settings.Hooks.PostToolUse) != 1 || settings.Hooks.PostToolUse[0].Hooks[0] != command {
+		t.Errorf("Expected hook appended, got %+v", settings.Hooks.PostToolUse)
+	}
+}
@@ -0,0 +1,99 @@
+package transcript
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/wizzomafizzo/bumpers/internal/logging"
+)
+
+// FormatEnv selects the transcript parser by name, defaulting to FormatClaude
+const FormatEnv = "BUMPERS_TRANSCRIPT_FORMAT"
+
+// Transcript format names accepted by ParserByName
+const (
+	FormatClaude = "claude"
+	FormatText   = "text"
+)
+
+// TranscriptParser turns one line of an agent's transcript into an entry. Intent extraction
+// only relies on the entry's type, UUIDs and content items, so supporting another agent's
+// transcript means mapping its lines onto those fields.
+type TranscriptParser interface {
+	ParseEntry(line string) (TranscriptEntry, bool)
+}
+
+// ClaudeTranscriptParser parses Claude Code's JSONL transcript format
+type ClaudeTranscriptParser struct{}
+
+// ParseEntry decodes a JSONL line, reporting false for lines that aren't valid JSON
+func (ClaudeTranscriptParser) ParseEntry(line string) (TranscriptEntry, bool) {
+	var entry TranscriptEntry
+	if err := json.Unmarshal([]byte(strings.TrimSpace(line)), &entry); err != nil {
+		return entry, false
+	}
+	return entry, true
+}
+
+// TextTranscriptParser treats each non-blank line of a plain text log as an assistant
+// message. It has no tool use IDs, so intent comes from the most recent lines.
+type TextTranscriptParser struct{}
+
+// ParseEntry returns the trimmed line as an assistant text entry
+func (TextTranscriptParser) ParseEntry(line string) (TranscriptEntry, bool) {
+	text := strings.TrimSpace(line)
+	if text == "" {
+		return TranscriptEntry{}, false
+	}
+	return TranscriptEntry{
+		Type:    "assistant",
+		Message: MessageContent{Role: "assistant", Content: []ContentItem{{Type: "text", Text: text}}},
+	}, true
+}
+
+// parsers maps format names to their parsers
+var parsers = map[string]TranscriptParser{
+	FormatClaude: ClaudeTranscriptParser{},
+	FormatText:   TextTranscriptParser{},
+}
+
+// ParserByName returns the parser for a transcript format name
+func ParserByName(name string) (TranscriptParser, error) {
+	parser, ok := parsers[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		names := make([]string, 0, len(parsers))
+		for format := range parsers {
+			names = append(names, format)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("unknown transcript format '%s': must be one of: %s", name, strings.Join(names, ", "))
+	}
+	return parser, nil
+}
+
+type parserContextKey struct{}
+
+// WithParser returns a context whose transcript reads use parser instead of the default
+func WithParser(ctx context.Context, parser TranscriptParser) context.Context {
+	return context.WithValue(ctx, parserContextKey{}, parser)
+}
+
+// parserFromContext returns the parser set with WithParser, else the one named by
+// BUMPERS_TRANSCRIPT_FORMAT, else the Claude Code parser
+func parserFromContext(ctx context.Context) TranscriptParser {
+	if parser, ok := ctx.Value(parserContextKey{}).(TranscriptParser); ok {
+		return parser
+	}
+	if name := os.Getenv(FormatEnv); name != "" {
+		parser, err := ParserByName(name)
+		if err == nil {
+			return parser
+		}
+		logging.Get(ctx).Warn().Err(err).Msg("ignoring " + FormatEnv + ", using the Claude Code format")
+	}
+	return ClaudeTranscriptParser{}
+}
@@ -0,0 +1,96 @@
+package transcript
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTranscript(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "transcript.log")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write transcript: %v", err)
+	}
+	return path
+}
+
+func TestParserByName(t *testing.T) {
+	t.Parallel()
+
+	for _, name := range []string{"claude", "TEXT", " text "} {
+		if _, err := ParserByName(name); err != nil {
+			t.Errorf("ParserByName(%q) returned error: %v", name, err)
+		}
+	}
+
+	_, err := ParserByName("cursor")
+	if err == nil || !strings.Contains(err.Error(), "must be one of: claude, text") {
+		t.Errorf("expected unknown format error, got %v", err)
+	}
+}
+
+func TestTextTranscriptParser(t *testing.T) {
+	t.Parallel()
+
+	entry, ok := TextTranscriptParser{}.ParseEntry("  Running the tests now  \n")
+	if !ok {
+		t.Fatal("expected line to parse")
+	}
+	if entry.Type != "assistant" || len(entry.Message.Content) != 1 ||
+		entry.Message.Content[0].Text != "Running the tests now" {
+		t.Errorf("unexpected entry: %+v", entry)
+	}
+
+	if _, ok := (TextTranscriptParser{}).ParseEntry("   "); ok {
+		t.Error("expected blank line to be skipped")
+	}
+}
+
+func TestWithParserSwapsTranscriptFormat(t *testing.T) {
+	t.Parallel()
+
+	path := writeTranscript(t, "Checking the build first\nI'll run the unit tests with go test\n")
+
+	// The Claude Code parser finds no JSON entries in a plain text log
+	if _, err := FindRecentToolUseAndExtractIntent(context.Background(), path, 0); err == nil {
+		t.Error("expected the Claude Code parser to find no intent in a text log")
+	}
+
+	ctx := WithParser(context.Background(), TextTranscriptParser{})
+	intent, err := FindRecentToolUseAndExtractIntent(ctx, path, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if intent != "Checking the build first I'll run the unit tests with go test" {
+		t.Errorf("unexpected intent: %q", intent)
+	}
+
+	content, err := ExtractIntentContent(ctx, path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if content != intent {
+		t.Errorf("expected ExtractIntentContent to match, got %q", content)
+	}
+}
+
+func TestParserFromEnv(t *testing.T) { //nolint:paralleltest // t.Setenv() usage
+	path := writeTranscript(t, "Formatting the code before committing\n")
+
+	t.Setenv(FormatEnv, FormatText)
+	intent, err := FindRecentToolUseAndExtractIntentWithLimit(context.Background(), path, 1, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if intent != "Formatting the code before committing" {
+		t.Errorf("unexpected intent: %q", intent)
+	}
+
+	t.Setenv(FormatEnv, "unknown")
+	if _, ok := parserFromContext(context.Background()).(ClaudeTranscriptParser); !ok {
+		t.Error("expected unknown formats to fall back to the Claude Code parser")
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"strings"
 
 	"github.com/wizzomafizzo/bumpers/internal/logging"
+	"github.com/wizzomafizzo/bumpers/internal/metrics"
 )
 
 func ExtractReasoningContent(transcriptPath string) (string, error) {
@@ -78,6 +79,7 @@ type TranscriptEntry struct {
 	Type       string         `json:"type"`
 	UUID       string         `json:"uuid"`
 	ParentUUID string         `json:"parentUuid"` //nolint:tagliatelle // Claude transcript format
+	Timestamp  string         `json:"timestamp,omitempty"`
 	Message    MessageContent `json:"message"`
 }
 
@@ -89,10 +91,49 @@ type MessageContent struct {
 
 // ContentItem represents individual content items in a message
 type ContentItem struct {
-	Type     string `json:"type"`
-	Text     string `json:"text,omitempty"`
-	Thinking string `json:"thinking,omitempty"` // For thinking content
-	ID       string `json:"id,omitempty"`       // For tool_use content
+	Type     string         `json:"type"`
+	Text     string         `json:"text,omitempty"`
+	Thinking string         `json:"thinking,omitempty"` // For thinking content
+	ID       string         `json:"id,omitempty"`       // For tool_use content
+	Name     string         `json:"name,omitempty"`     // Tool name for tool_use content
+	Input    map[string]any `json:"input,omitempty"`    // Tool input for tool_use content
+	// ToolUseID and Content are set on tool_result content, Content holding either a string
+	// or a list of text items
+	ToolUseID string          `json:"tool_use_id,omitempty"` //nolint:tagliatelle // Claude transcript format
+	Content   json.RawMessage `json:"content,omitempty"`
+}
+
+// ResultText returns the text of a tool_result's content, joining the text items of a list
+func (c *ContentItem) ResultText() string {
+	if len(c.Content) == 0 {
+		return ""
+	}
+	var text string
+	if err := json.Unmarshal(c.Content, &text); err == nil {
+		return text
+	}
+	var items []ContentItem
+	if err := json.Unmarshal(c.Content, &items); err != nil {
+		return ""
+	}
+	parts := make([]string, 0, len(items))
+	for i := range items {
+		if items[i].Type == "text" && items[i].Text != "" {
+			parts = append(parts, items[i].Text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// cancelCheckLines is how many lines are read between checks for a canceled context
+const cancelCheckLines = 256
+
+// checkCanceled returns ctx's error, noting how far the read got, once ctx is done
+func checkCanceled(ctx context.Context, bytesRead int64) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("transcript read stopped after %d bytes: %w", bytesRead, err)
+	}
+	return nil
 }
 
 func ExtractIntentContent(ctx context.Context, transcriptPath string) (string, error) {
@@ -108,7 +149,7 @@ func ExtractIntentContent(ctx context.Context, transcriptPath string) (string, e
 		}
 	}()
 
-	intentParts, err := readIntentFromFile(file, transcriptPath)
+	intentParts, err := readIntentFromFile(ctx, file, parserFromContext(ctx), transcriptPath)
 	if err != nil {
 		return "", err
 	}
@@ -120,12 +161,22 @@ func ExtractIntentContent(ctx context.Context, transcriptPath string) (string, e
 }
 
 // readIntentFromFile reads and processes all lines from the file
-func readIntentFromFile(file *os.File, transcriptPath string) ([]string, error) {
+func readIntentFromFile(
+	ctx context.Context, file *os.File, parser TranscriptParser, transcriptPath string,
+) ([]string, error) {
 	reader := bufio.NewReader(file)
 	var intentParts []string
+	var bytesRead int64
+
+	for lineCount := 0; ; lineCount++ {
+		if lineCount%cancelCheckLines == 0 {
+			if err := checkCanceled(ctx, bytesRead); err != nil {
+				return nil, err
+			}
+		}
 
-	for {
 		line, err := reader.ReadString('\n')
+		bytesRead += int64(len(line))
 		if err != nil && err != io.EOF {
 			return nil, fmt.Errorf("failed to read transcript file %s: %w", transcriptPath, err)
 		}
@@ -134,7 +185,7 @@ func readIntentFromFile(file *os.File, transcriptPath string) ([]string, error)
 			break
 		}
 
-		if processedLine := processIntentLine(line); len(processedLine) > 0 {
+		if processedLine := processIntentLine(parser, line); len(processedLine) > 0 {
 			intentParts = append(intentParts, processedLine...)
 		}
 
@@ -147,12 +198,12 @@ func readIntentFromFile(file *os.File, transcriptPath string) ([]string, error)
 }
 
 // processIntentLine processes a single line for intent content
-func processIntentLine(line string) []string {
+func processIntentLine(parser TranscriptParser, line string) []string {
 	line = strings.TrimSuffix(line, "\n")
 	if strings.TrimSpace(line) == "" {
 		return nil
 	}
-	return extractIntentFromLine(line)
+	return extractIntentFromLine(parser, line)
 }
 
 // logExtractedIntent logs the extraction results for debugging
@@ -160,15 +211,15 @@ func logExtractedIntent(ctx context.Context, transcriptPath string, intentParts
 	logging.Get(ctx).Debug().
 		Str("transcript_path", transcriptPath).
 		Int("intent_parts_count", len(intentParts)).
-		Str("extracted_intent", result).
+		Int("intent_length", len(result)).
 		Msg("ExtractIntentContent extracted content from transcript")
 }
 
 // extractIntentFromLine extracts intent content from a single transcript line
-func extractIntentFromLine(line string) []string {
-	var entry TranscriptEntry
-	if err := json.Unmarshal([]byte(line), &entry); err != nil {
-		// Skip lines that aren't valid JSON (like user messages or errors)
+func extractIntentFromLine(parser TranscriptParser, line string) []string {
+	entry, valid := parser.ParseEntry(line)
+	if !valid {
+		// Skip lines the parser can't read (like user messages or errors)
 		return nil
 	}
 
@@ -217,16 +268,17 @@ func ExtractIntentContentOptimized(ctx context.Context, transcriptPath string, m
 		}
 	}()
 
-	lines, err := readRecentLines(file, maxLines)
+	lines, err := readRecentLines(ctx, file, maxLines)
 	if err != nil {
 		return "", err
 	}
 
-	return extractIntentFromLines(lines), nil
+	return extractIntentFromLines(parserFromContext(ctx), lines), nil
 }
 
-// readRecentLines reads the most recent lines from a file efficiently
-func readRecentLines(file *os.File, maxLines int) ([]string, error) {
+// readRecentLines reads the most recent lines from a file efficiently, checking for
+// cancellation before each chunk
+func readRecentLines(ctx context.Context, file *os.File, maxLines int) ([]string, error) {
 	fileInfo, err := file.Stat()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get file stats: %w", err)
@@ -238,6 +290,9 @@ func readRecentLines(file *os.File, maxLines int) ([]string, error) {
 
 	offset := fileInfo.Size()
 	for len(lines) < maxLines && offset > 0 {
+		if err := checkCanceled(ctx, fileInfo.Size()-offset); err != nil {
+			return nil, err
+		}
 		chunk, newOffset, err := readChunk(file, offset, chunkSize)
 		if err != nil {
 			return nil, err
@@ -248,7 +303,7 @@ func readRecentLines(file *os.File, maxLines int) ([]string, error) {
 		lines, buf = extractLinesFromBuffer(buf, lines, maxLines)
 	}
 
-	if offset == 0 && len(buf) > 0 {
+	if offset == 0 && len(buf) > 0 && len(lines) < maxLines {
 		lines = addRemainingBuffer(buf, lines)
 	}
 
@@ -326,22 +381,25 @@ func reverseLines(lines []string) []string {
 }
 
 // extractIntentFromLines processes lines to extract intent content
-func extractIntentFromLines(lines []string) string {
+func extractIntentFromLines(parser TranscriptParser, lines []string) string {
 	var intentParts []string
 	for _, line := range lines {
-		parts := extractIntentFromLine(line)
+		parts := extractIntentFromLine(parser, line)
 		intentParts = append(intentParts, parts...)
 	}
 	return strings.Join(intentParts, " ")
 }
 
 // ExtractIntentByToolUseID extracts the intent for a specific tool use ID
-// by finding the assistant message that precedes the tool_use message
-func ExtractIntentByToolUseID(ctx context.Context, transcriptPath, toolUseID string) (string, error) {
-	return ExtractIntentByToolUseIDWithContext(ctx, transcriptPath, toolUseID)
+// by finding the assistant message that precedes the tool_use message.
+// Only the last maxDepth lines of the transcript are searched; 0 searches all of it.
+func ExtractIntentByToolUseID(ctx context.Context, transcriptPath, toolUseID string, maxDepth int) (string, error) {
+	return ExtractIntentByToolUseIDWithContext(ctx, transcriptPath, toolUseID, maxDepth)
 }
 
-func ExtractIntentByToolUseIDWithContext(ctx context.Context, transcriptPath, toolUseID string) (string, error) {
+func ExtractIntentByToolUseIDWithContext(
+	ctx context.Context, transcriptPath, toolUseID string, maxDepth int,
+) (string, error) {
 	file, err := os.Open(transcriptPath) // #nosec G304 - path is validated by caller
 	if err != nil {
 		return "", fmt.Errorf("failed to open transcript file %s: %w", transcriptPath, err)
@@ -354,24 +412,49 @@ func ExtractIntentByToolUseIDWithContext(ctx context.Context, transcriptPath, to
 		}
 	}()
 
-	result, err := findIntentByToolUseID(file, toolUseID)
+	var result string
+	if maxDepth > 0 {
+		var lines []string
+		lines, err = readRecentLines(ctx, file, maxDepth)
+		if err != nil {
+			return "", err
+		}
+		metrics.FromContext(ctx).Add(metrics.TranscriptBytesRead, linesSize(lines))
+		result, err = findIntentByToolUseID(ctx, strings.NewReader(strings.Join(lines, "\n")),
+			parserFromContext(ctx), toolUseID)
+	} else {
+		result, err = findIntentByToolUseID(ctx, file, parserFromContext(ctx), toolUseID)
+		if offset, seekErr := file.Seek(0, io.SeekCurrent); seekErr == nil {
+			metrics.FromContext(ctx).Add(metrics.TranscriptBytesRead, offset)
+		}
+	}
 	if err == nil {
 		logging.Get(ctx).Debug().
 			Str("transcript_path", transcriptPath).
 			Str("tool_use_id", toolUseID).
-			Str("extracted_intent", result).
+			Int("intent_length", len(result)).
 			Msg("ExtractIntentByToolUseID extracted content from transcript")
 	}
 	return result, err
 }
 
 // findIntentByToolUseID searches for intent message associated with tool use ID
-func findIntentByToolUseID(file *os.File, toolUseID string) (string, error) {
-	reader := bufio.NewReader(file)
+func findIntentByToolUseID(
+	ctx context.Context, source io.Reader, parser TranscriptParser, toolUseID string,
+) (string, error) {
+	reader := bufio.NewReader(source)
 	processedEntries := make([]TranscriptEntry, 0, 100)
+	var bytesRead int64
+
+	for lineCount := 0; ; lineCount++ {
+		if lineCount%cancelCheckLines == 0 {
+			if err := checkCanceled(ctx, bytesRead); err != nil {
+				return "", err
+			}
+		}
 
-	for {
 		line, err := reader.ReadString('\n')
+		bytesRead += int64(len(line))
 		if err != nil && err != io.EOF {
 			return "", fmt.Errorf("failed to read transcript: %w", err)
 		}
@@ -379,14 +462,18 @@ func findIntentByToolUseID(file *os.File, toolUseID string) (string, error) {
 			break
 		}
 
-		entry, valid := parseTranscriptEntry(line)
+		entry, valid := parser.ParseEntry(line)
 		if !valid {
 			continue
 		}
 
 		processedEntries = append(processedEntries, entry)
 
-		if result := checkForToolUseMatch(&entry, toolUseID, processedEntries); result != "" {
+		if result, depth := checkForToolUseMatch(&entry, toolUseID, processedEntries); result != "" {
+			logging.Get(ctx).Debug().
+				Str("tool_use_id", toolUseID).
+				Int("parent_depth", depth).
+				Msg("found intent in tool use's parent chain")
 			return result, nil
 		}
 
@@ -398,38 +485,55 @@ func findIntentByToolUseID(file *os.File, toolUseID string) (string, error) {
 	return "", nil // No intent found for this tool use ID
 }
 
-// parseTranscriptEntry parses a transcript line into an entry
-func parseTranscriptEntry(line string) (TranscriptEntry, bool) {
-	var entry TranscriptEntry
-	if err := json.Unmarshal([]byte(strings.TrimSpace(line)), &entry); err != nil {
-		return entry, false
-	}
-	return entry, true
-}
+// maxParentChainDepth bounds how many ancestors are checked for a tool use's intent
+const maxParentChainDepth = 5
 
-// checkForToolUseMatch checks if entry contains matching tool use and returns intent
-func checkForToolUseMatch(entry *TranscriptEntry, toolUseID string, processedEntries []TranscriptEntry) string {
+// checkForToolUseMatch checks if entry contains matching tool use and returns intent along
+// with how many parentUuid links were followed to find it
+func checkForToolUseMatch(
+	entry *TranscriptEntry, toolUseID string, processedEntries []TranscriptEntry,
+) (intent string, depth int) {
 	const assistantType = "assistant"
 	if entry.Type != assistantType {
-		return ""
+		return "", 0
 	}
 
 	if content := findToolUseWithID(entry, toolUseID); content != nil {
 		return findParentIntent(entry, processedEntries)
 	}
 
-	return ""
+	return "", 0
 }
 
-// findParentIntent finds the parent intent message for the given entry
-func findParentIntent(entry *TranscriptEntry, processedEntries []TranscriptEntry) string {
+// findParentIntent finds the parent intent message for the given entry. Chained tool calls
+// can have a parent with only another tool_use or no content, so assistant ancestors are
+// followed up to maxParentChainDepth links to the nearest one with text or thinking.
+func findParentIntent(entry *TranscriptEntry, processedEntries []TranscriptEntry) (intent string, depth int) {
 	const assistantType = "assistant"
-	for _, processed := range processedEntries {
-		if processed.Type == assistantType && processed.UUID == entry.ParentUUID {
-			return extractTextFromEntry(&processed)
+	visited := make(map[string]bool, maxParentChainDepth)
+	parentUUID := entry.ParentUUID
+	for depth = 1; depth <= maxParentChainDepth && parentUUID != "" && !visited[parentUUID]; depth++ {
+		visited[parentUUID] = true
+		parent := findEntryByUUID(processedEntries, parentUUID)
+		if parent == nil || parent.Type != assistantType {
+			break
+		}
+		if text := extractTextFromEntry(parent); text != "" {
+			return text, depth
 		}
+		parentUUID = parent.ParentUUID
 	}
-	return ""
+	return "", 0
+}
+
+// findEntryByUUID returns the processed entry with the given UUID, or nil
+func findEntryByUUID(processedEntries []TranscriptEntry, uuid string) *TranscriptEntry {
+	for i := range processedEntries {
+		if processedEntries[i].UUID == uuid {
+			return &processedEntries[i]
+		}
+	}
+	return nil
 }
 
 // findToolUseWithID searches for tool_use content with matching ID
@@ -464,88 +568,63 @@ func extractTextPartsFromEntry(entry *TranscriptEntry) []string {
 }
 
 // findMostRecentToolUseParentUUID scans backwards to find the most recent tool_use and returns its parentUuid
-func findMostRecentToolUseParentUUID(lines []string) string {
+func findMostRecentToolUseParentUUID(parser TranscriptParser, lines []string) string {
 	for i := len(lines) - 1; i >= 0; i-- {
-		if parentUUID := extractParentUUIDFromLine(lines[i]); parentUUID != "" {
+		if parentUUID := extractParentUUIDFromLine(parser, lines[i]); parentUUID != "" {
 			return parentUUID
 		}
 	}
 	return ""
 }
 
-// extractParentUUIDFromLine extracts parent UUID from a single line if it contains tool use
-func extractParentUUIDFromLine(line string) string {
-	var entry map[string]any
-	if err := json.Unmarshal([]byte(line), &entry); err != nil {
-		return ""
-	}
-
-	if !isAssistantEntry(entry) {
-		return ""
-	}
-
-	return findToolUseParentUUID(entry)
-}
-
-// isAssistantEntry checks if the entry is an assistant type
-func isAssistantEntry(entry map[string]any) bool {
+// extractParentUUIDFromLine returns the parent UUID of an assistant entry containing a tool use
+func extractParentUUIDFromLine(parser TranscriptParser, line string) string {
 	const assistantType = "assistant"
-	entryType, ok := entry["type"].(string)
-	return ok && entryType == assistantType
-}
-
-// findToolUseParentUUID finds the parent UUID for a tool use entry
-func findToolUseParentUUID(entry map[string]any) string {
-	message, ok := entry["message"].(map[string]any)
-	if !ok {
-		return ""
-	}
-
-	content, ok := message["content"].([]any)
-	if !ok {
+	entry, valid := parser.ParseEntry(line)
+	if !valid || entry.Type != assistantType || !hasToolUseContent(&entry) {
 		return ""
 	}
-
-	if hasToolUseContent(content) {
-		if parentUUID, ok := entry["parentUuid"].(string); ok && parentUUID != "" {
-			return parentUUID
-		}
-	}
-	return ""
+	return entry.ParentUUID
 }
 
-// hasToolUseContent checks if content array contains a tool_use item
-func hasToolUseContent(content []any) bool {
-	for _, contentItem := range content {
-		if item, ok := contentItem.(map[string]any); ok {
-			if itemType, ok := item["type"].(string); ok && itemType == "tool_use" {
-				return true
-			}
+// hasToolUseContent checks if the entry's content contains a tool_use item
+func hasToolUseContent(entry *TranscriptEntry) bool {
+	for _, content := range entry.Message.Content {
+		if content.Type == "tool_use" {
+			return true
 		}
 	}
 	return false
 }
 
 // FindRecentToolUseAndExtractIntent scans backwards through transcript to find recent tool uses
-// and extracts the associated intent content within a 1-minute time window
-func FindRecentToolUseAndExtractIntent(ctx context.Context, transcriptPath string) (string, error) {
-	lines, err := readTranscriptLines(ctx, transcriptPath)
+// and extracts the associated intent content within a 1-minute time window.
+// Only the last maxDepth lines of the transcript are searched; 0 searches all of it.
+func FindRecentToolUseAndExtractIntent(ctx context.Context, transcriptPath string, maxDepth int) (string, error) {
+	var lines []string
+	var err error
+	if maxDepth > 0 {
+		lines, err = readTranscriptTail(ctx, transcriptPath, maxDepth)
+	} else {
+		lines, err = readTranscriptLines(ctx, transcriptPath)
+	}
 	if err != nil {
 		return "", err
 	}
+	parser := parserFromContext(ctx)
 
 	// First pass: find the most recent tool_use message and get its parent UUID
-	mostRecentToolUseParentUUID := findMostRecentToolUseParentUUID(lines)
+	mostRecentToolUseParentUUID := findMostRecentToolUseParentUUID(parser, lines)
 
 	// Second pass: collect text content, prioritizing the tool_use parent message
-	allContentParts := extractPrioritizedContent(lines, mostRecentToolUseParentUUID)
+	allContentParts := extractPrioritizedContent(parser, lines, mostRecentToolUseParentUUID)
 
 	if len(allContentParts) > 0 {
 		result := strings.Join(allContentParts, " ")
 		logging.Get(ctx).Debug().
 			Str("transcript_path", transcriptPath).
 			Int("content_parts_count", len(allContentParts)).
-			Str("extracted_intent", result).
+			Int("intent_length", len(result)).
 			Msg("FindRecentToolUseAndExtractIntent extracted content from transcript")
 		return result, nil
 	}
@@ -553,6 +632,24 @@ func FindRecentToolUseAndExtractIntent(ctx context.Context, transcriptPath strin
 	return "", errors.New("no recent tool use intent found")
 }
 
+// readTranscriptTail reads the last maxLines lines of the transcript file
+func readTranscriptTail(ctx context.Context, transcriptPath string, maxLines int) ([]string, error) {
+	file, err := os.Open(transcriptPath) // #nosec G304 - path is validated by caller
+	if err != nil {
+		return nil, fmt.Errorf("failed to open transcript file %s: %w", transcriptPath, err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	lines, err := readRecentLines(ctx, file, maxLines)
+	if err != nil {
+		return nil, err
+	}
+	metrics.FromContext(ctx).Add(metrics.TranscriptBytesRead, linesSize(lines))
+	return lines, nil
+}
+
 // readTranscriptLines reads all lines from the transcript file
 func readTranscriptLines(ctx context.Context, transcriptPath string) ([]string, error) {
 	file, err := os.Open(transcriptPath) // #nosec G304 - path is validated by caller
@@ -568,10 +665,21 @@ func readTranscriptLines(ctx context.Context, transcriptPath string) ([]string,
 	}()
 
 	var lines []string
+	var bytesRead int64
 	reader := bufio.NewReader(file)
+	defer func() {
+		metrics.FromContext(ctx).Add(metrics.TranscriptBytesRead, bytesRead)
+	}()
+
+	for lineCount := 0; ; lineCount++ {
+		if lineCount%cancelCheckLines == 0 {
+			if err := checkCanceled(ctx, bytesRead); err != nil {
+				return nil, err
+			}
+		}
 
-	for {
 		line, err := reader.ReadString('\n')
+		bytesRead += int64(len(line))
 		if err != nil && err != io.EOF {
 			return nil, fmt.Errorf("failed to read line: %w", err)
 		}
@@ -589,18 +697,18 @@ func readTranscriptLines(ctx context.Context, transcriptPath string) ([]string,
 }
 
 // extractPrioritizedContent extracts content from lines, prioritizing tool use parent message
-func extractPrioritizedContent(lines []string, mostRecentToolUseParentUUID string) []string {
+func extractPrioritizedContent(parser TranscriptParser, lines []string, mostRecentToolUseParentUUID string) []string {
 	var allContentParts []string
 	assistantCount := 0
 	maxRecentAssistants := 2
 
 	for i := len(lines) - 1; i >= 0 && assistantCount < maxRecentAssistants; i-- {
-		contentParts := extractContentFromLine(lines[i])
+		entry, contentParts := extractContentFromLine(parser, lines[i])
 		if len(contentParts) == 0 {
 			continue
 		}
 
-		if shouldUseContent(lines[i], mostRecentToolUseParentUUID, &allContentParts, contentParts, &assistantCount) {
+		if shouldUseContent(&entry, mostRecentToolUseParentUUID, &allContentParts, contentParts, &assistantCount) {
 			break
 		}
 	}
@@ -608,23 +716,24 @@ func extractPrioritizedContent(lines []string, mostRecentToolUseParentUUID strin
 	return allContentParts
 }
 
-// extractContentFromLine extracts text content from a single line
-func extractContentFromLine(line string) []string {
+// extractContentFromLine parses a line and extracts its text content if it is an assistant entry
+func extractContentFromLine(parser TranscriptParser, line string) (TranscriptEntry, []string) {
 	const assistantType = "assistant"
-	entry, valid := parseTranscriptEntry(line)
+	entry, valid := parser.ParseEntry(line)
 	if !valid || entry.Type != assistantType {
-		return nil
+		return entry, nil
 	}
 
-	return extractTextPartsFromEntry(&entry)
+	return entry, extractTextPartsFromEntry(&entry)
 }
 
 // shouldUseContent determines if content should be used and updates collections
-func shouldUseContent(line, parentUUID string, allParts *[]string, contentParts []string, assistantCount *int) bool {
+func shouldUseContent(
+	entry *TranscriptEntry, parentUUID string, allParts *[]string, contentParts []string, assistantCount *int,
+) bool {
 	if parentUUID != "" {
-		// Check if this line has the specific parent UUID we want
-		entry, valid := parseTranscriptEntry(line)
-		if !valid || entry.UUID != parentUUID {
+		// Check if this entry is the specific parent UUID we want
+		if entry.UUID != parentUUID {
 			return false
 		}
 
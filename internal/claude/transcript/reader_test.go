@@ -2,6 +2,7 @@ package transcript
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -404,7 +405,7 @@ func TestExtractIntentByToolUseID_Success(t *testing.T) {
 	}
 
 	// Test extracting intent by tool use ID
-	intent, err := ExtractIntentByToolUseID(context.Background(), transcriptPath, "toolu_01KTePc3uLq34eriLmSLbgnx")
+	intent, err := ExtractIntentByToolUseID(context.Background(), transcriptPath, "toolu_01KTePc3uLq34eriLmSLbgnx", 0)
 	if err != nil {
 		t.Fatalf("ExtractIntentByToolUseID failed: %v", err)
 	}
@@ -436,7 +437,7 @@ func TestExtractIntentByToolUseID_LogsExtractedContent(t *testing.T) {
 	}
 
 	// Extract intent using context-aware function - this should log the result
-	intent, err := ExtractIntentByToolUseIDWithContext(ctx, transcriptPath, "test-tool-id")
+	intent, err := ExtractIntentByToolUseIDWithContext(ctx, transcriptPath, "test-tool-id", 0)
 	if err != nil {
 		t.Fatalf("ExtractIntentByToolUseID failed: %v", err)
 	}
@@ -445,13 +446,81 @@ func TestExtractIntentByToolUseID_LogsExtractedContent(t *testing.T) {
 		t.Errorf("Expected 'Testing logging behavior', got: %q", intent)
 	}
 
-	// Check logs contain the extracted content
+	// Check logs record the extraction without the content, which may hold secrets
 	logOutput := getLogs()
 	if !strings.Contains(logOutput, "ExtractIntentByToolUseID extracted content from transcript") {
 		t.Errorf("Expected log message not found in logs: %s", logOutput)
 	}
-	if !strings.Contains(logOutput, "Testing logging behavior") {
-		t.Errorf("Expected extracted content not found in logs: %s", logOutput)
+	if !strings.Contains(logOutput, `"intent_length":24`) {
+		t.Errorf("Expected intent length not found in logs: %s", logOutput)
+	}
+	if strings.Contains(logOutput, "Testing logging behavior") {
+		t.Errorf("Extracted content should not be logged: %s", logOutput)
+	}
+}
+
+func TestExtractIntentByToolUseID_ParentChain(t *testing.T) {
+	ctx, getLogs := testutil.NewTestContext(t)
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		transcript string
+		expected   string
+		depth      string
+	}{
+		{
+			name: "two-level chain",
+			transcript: `{"type":"user","uuid":"user1","message":{"role":"user","content":"Tidy up"}}
+{"type":"assistant","uuid":"intent","parentUuid":"user1","message":{"role":"assistant",` +
+				`"content":[{"type":"text","text":"Now let me remove the build directory."}]}}
+{"type":"assistant","uuid":"empty","parentUuid":"intent","message":{"role":"assistant","content":[]}}
+{"type":"assistant","uuid":"first-call","parentUuid":"empty","message":{"role":"assistant",` +
+				`"content":[{"type":"tool_use","id":"first-id","name":"Bash"}]}}
+{"type":"assistant","uuid":"second-call","parentUuid":"first-call","message":{"role":"assistant",` +
+				`"content":[{"type":"tool_use","id":"second-id","name":"Bash"}]}}`,
+			expected: "Now let me remove the build directory.",
+			depth:    `"parent_depth":3`,
+		},
+		{
+			name: "cycle",
+			transcript: `{"type":"assistant","uuid":"a","parentUuid":"b","message":{"role":"assistant",` +
+				`"content":[{"type":"tool_use","id":"other-id","name":"Bash"}]}}
+{"type":"assistant","uuid":"b","parentUuid":"a","message":{"role":"assistant","content":[]}}
+{"type":"assistant","uuid":"call","parentUuid":"a","message":{"role":"assistant",` +
+				`"content":[{"type":"tool_use","id":"second-id","name":"Bash"}]}}`,
+		},
+		{
+			name: "stops at user message",
+			transcript: `{"type":"assistant","uuid":"old","message":{"role":"assistant",` +
+				`"content":[{"type":"text","text":"Earlier turn"}]}}
+{"type":"user","uuid":"user1","parentUuid":"old","message":{"role":"user","content":"Next"}}
+{"type":"assistant","uuid":"empty","parentUuid":"user1","message":{"role":"assistant","content":[]}}
+{"type":"assistant","uuid":"call","parentUuid":"empty","message":{"role":"assistant",` +
+				`"content":[{"type":"tool_use","id":"second-id","name":"Bash"}]}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			transcriptPath := filepath.Join(t.TempDir(), "transcript.jsonl")
+			if err := os.WriteFile(transcriptPath, []byte(tt.transcript), 0o600); err != nil {
+				t.Fatalf("Failed to create test transcript: %v", err)
+			}
+
+			intent, err := ExtractIntentByToolUseIDWithContext(ctx, transcriptPath, "second-id", 0)
+			if err != nil {
+				t.Fatalf("ExtractIntentByToolUseID failed: %v", err)
+			}
+			if intent != tt.expected {
+				t.Errorf("Expected intent: %q, got: %q", tt.expected, intent)
+			}
+			if tt.depth != "" && !strings.Contains(getLogs(), tt.depth) {
+				t.Errorf("Expected %s in logs: %s", tt.depth, getLogs())
+			}
+		})
 	}
 }
 
@@ -474,7 +543,7 @@ func TestExtractIntentByToolUseID_NotFound(t *testing.T) {
 	}
 
 	// Should return empty string when tool_use_id not found
-	intent, err := ExtractIntentByToolUseID(context.Background(), transcriptPath, "toolu_01KTePc3uLq34eriLmSLbgnx")
+	intent, err := ExtractIntentByToolUseID(context.Background(), transcriptPath, "toolu_01KTePc3uLq34eriLmSLbgnx", 0)
 	if err != nil {
 		t.Fatalf("ExtractIntentByToolUseID failed: %v", err)
 	}
@@ -506,7 +575,7 @@ not json at all`
 	}
 
 	// Should handle malformed JSON gracefully and still find the valid intent
-	intent, err := ExtractIntentByToolUseID(context.Background(), transcriptPath, "toolu_01KTePc3uLq34eriLmSLbgnx")
+	intent, err := ExtractIntentByToolUseID(context.Background(), transcriptPath, "toolu_01KTePc3uLq34eriLmSLbgnx", 0)
 	if err != nil {
 		t.Fatalf("ExtractIntentByToolUseID failed: %v", err)
 	}
@@ -593,6 +662,37 @@ func TestExtractIntentContent_HandlesLongLines(t *testing.T) {
 }
 
 // TestFindRecentToolUseAndExtractIntent tests the new reliable intent extraction method
+func TestExtractIntentMaxDepth(t *testing.T) {
+	t.Parallel()
+
+	transcriptPath := filepath.Join(t.TempDir(), "transcript.jsonl")
+	content := `{"type":"assistant","uuid":"intent1","message":{"role":"assistant",` +
+		`"content":[{"type":"text","text":"Clean up the build directory."}]}}
+{"type":"assistant","uuid":"tool1","parentUuid":"intent1","message":{"role":"assistant",` +
+		`"content":[{"type":"tool_use","id":"toolu_1","name":"Bash"}]}}
+` + strings.Repeat(`{"type":"user","message":{"role":"user","content":"ok"}}`+"\n", 5)
+	if err := os.WriteFile(transcriptPath, []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to create test transcript: %v", err)
+	}
+
+	intent, err := ExtractIntentByToolUseID(context.Background(), transcriptPath, "toolu_1", 7)
+	if err != nil || intent != "Clean up the build directory." {
+		t.Errorf("Expected intent within depth 7, got %q (err: %v)", intent, err)
+	}
+	intent, err = ExtractIntentByToolUseID(context.Background(), transcriptPath, "toolu_1", 6)
+	if err != nil || intent != "" {
+		t.Errorf("Expected no intent beyond depth 6, got %q (err: %v)", intent, err)
+	}
+
+	intent, err = FindRecentToolUseAndExtractIntent(context.Background(), transcriptPath, 7)
+	if err != nil || !strings.Contains(intent, "Clean up the build directory.") {
+		t.Errorf("Expected recent intent within depth 7, got %q (err: %v)", intent, err)
+	}
+	if _, err = FindRecentToolUseAndExtractIntent(context.Background(), transcriptPath, 5); err == nil {
+		t.Error("Expected no recent intent beyond depth 5")
+	}
+}
+
 func TestFindRecentToolUseAndExtractIntent(t *testing.T) {
 	_, _ = testutil.NewTestContext(t) // Context-aware logging available
 	t.Parallel()
@@ -621,7 +721,7 @@ func TestFindRecentToolUseAndExtractIntent(t *testing.T) {
 	}
 
 	// Test the new function
-	intent, err := FindRecentToolUseAndExtractIntent(context.Background(), transcriptPath)
+	intent, err := FindRecentToolUseAndExtractIntent(context.Background(), transcriptPath, 0)
 	if err != nil {
 		t.Fatalf("FindRecentToolUseAndExtractIntent failed: %v", err)
 	}
@@ -665,7 +765,7 @@ func TestFindRecentToolUseAndExtractIntent_ShouldPrioritizeToolUseParentIntent(t
 	}
 
 	// Test the function - it should extract the CURRENT intent, not the previous one
-	intent, err := FindRecentToolUseAndExtractIntent(context.Background(), transcriptPath)
+	intent, err := FindRecentToolUseAndExtractIntent(context.Background(), transcriptPath, 0)
 	if err != nil {
 		t.Fatalf("FindRecentToolUseAndExtractIntent failed: %v", err)
 	}
@@ -704,7 +804,7 @@ func TestFindRecentToolUseAndExtractIntent_WithDifferentContent(t *testing.T) {
 	}
 
 	// Test the new function
-	intent, err := FindRecentToolUseAndExtractIntent(context.Background(), transcriptPath)
+	intent, err := FindRecentToolUseAndExtractIntent(context.Background(), transcriptPath, 0)
 	if err != nil {
 		t.Fatalf("FindRecentToolUseAndExtractIntent failed: %v", err)
 	}
@@ -731,7 +831,7 @@ func TestFindRecentToolUseAndExtractIntent_WithTextContent(t *testing.T) {
 		t.Fatalf("Failed to create test transcript: %v", err)
 	}
 
-	intent, err := FindRecentToolUseAndExtractIntent(context.Background(), transcriptPath)
+	intent, err := FindRecentToolUseAndExtractIntent(context.Background(), transcriptPath, 0)
 	if err != nil {
 		t.Fatalf("FindRecentToolUseAndExtractIntent failed: %v", err)
 	}
@@ -778,3 +878,73 @@ func TestExtractIntentContent_WithThinkingBlocks(t *testing.T) {
 		t.Errorf("Expected intent to contain text content 'investigate the intent extraction', got: %s", intent)
 	}
 }
+
+// cancelAfterContext reports itself canceled once Err has been called checks times, so a
+// read is canceled partway through
+type cancelAfterContext struct {
+	context.Context
+	checks int
+}
+
+func (c *cancelAfterContext) Err() error {
+	c.checks--
+	if c.checks < 0 {
+		return context.Canceled
+	}
+	return nil
+}
+
+func TestTranscriptReadsStopWhenContextCanceled(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "large.jsonl")
+	var content strings.Builder
+	for i := 0; i < 20000; i++ {
+		_, _ = fmt.Fprintf(&content, `{"type":"assistant","uuid":"u%d","message":{"content":[`+
+			`{"type":"text","text":"Message %d"}]}}`+"\n", i, i)
+	}
+	if err := os.WriteFile(path, []byte(content.String()), 0o600); err != nil {
+		t.Fatalf("Failed to write transcript: %v", err)
+	}
+
+	reads := map[string]func(ctx context.Context) error{
+		"ExtractIntentContent": func(ctx context.Context) error {
+			_, err := ExtractIntentContent(ctx, path)
+			return err
+		},
+		"ExtractIntentContentOptimized": func(ctx context.Context) error {
+			_, err := ExtractIntentContentOptimized(ctx, path, 10000)
+			return err
+		},
+		"FindRecentToolUseAndExtractIntent": func(ctx context.Context) error {
+			_, err := FindRecentToolUseAndExtractIntent(ctx, path, 0)
+			return err
+		},
+		"ExtractIntentByToolUseID": func(ctx context.Context) error {
+			_, err := ExtractIntentByToolUseIDWithContext(ctx, path, "missing", 0)
+			return err
+		},
+	}
+
+	for name, read := range reads {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			ctx := &cancelAfterContext{Context: context.Background(), checks: 3}
+
+			start := time.Now()
+			err := read(ctx)
+			if !errors.Is(err, context.Canceled) {
+				t.Fatalf("Expected context.Canceled, got %v", err)
+			}
+			if !strings.Contains(err.Error(), "transcript read stopped after") {
+				t.Errorf("Expected read progress in error, got %v", err)
+			}
+			if strings.Contains(err.Error(), "after 0 bytes") {
+				t.Errorf("Expected the read to be canceled partway through, got %v", err)
+			}
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Errorf("Expected prompt return after cancellation, took %v", elapsed)
+			}
+		})
+	}
+}
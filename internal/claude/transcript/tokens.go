@@ -0,0 +1,105 @@
+package transcript
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/wizzomafizzo/bumpers/internal/logging"
+)
+
+const (
+	// charsPerToken is the rough number of characters per model token
+	charsPerToken = 4
+
+	// recentIntentLines is how many trailing lines are scanned once a transcript exceeds the token limit
+	recentIntentLines = 200
+)
+
+// projectDirChars matches characters Claude Code replaces when naming per-project transcript directories
+var projectDirChars = regexp.MustCompile(`[^a-zA-Z0-9]`)
+
+// CountTokens estimates the number of tokens in a transcript as its character count divided by 4.
+// The file size is used as the character count, which is exact for ASCII-heavy JSONL.
+func CountTokens(path string) (int, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to stat transcript file %s: %w", path, err)
+	}
+	return int((info.Size() + charsPerToken - 1) / charsPerToken), nil
+}
+
+// FindRecentToolUseAndExtractIntentWithLimit behaves like FindRecentToolUseAndExtractIntent, but
+// transcripts estimated above maxTokens are read backwards from the end instead of in full.
+// A maxTokens of zero or less disables the limit.
+func FindRecentToolUseAndExtractIntentWithLimit(
+	ctx context.Context, transcriptPath string, maxTokens, maxDepth int,
+) (string, error) {
+	if maxTokens <= 0 {
+		return FindRecentToolUseAndExtractIntent(ctx, transcriptPath, maxDepth)
+	}
+
+	tokens, err := CountTokens(transcriptPath)
+	if err != nil {
+		return "", err
+	}
+	if tokens <= maxTokens {
+		return FindRecentToolUseAndExtractIntent(ctx, transcriptPath, maxDepth)
+	}
+
+	logging.Get(ctx).Debug().
+		Str("transcript_path", transcriptPath).
+		Int("tokens", tokens).
+		Int("max_intent_tokens", maxTokens).
+		Msg("transcript exceeds max_intent_tokens, reading recent lines only")
+
+	if maxDepth <= 0 || maxDepth > recentIntentLines {
+		maxDepth = recentIntentLines
+	}
+	return FindRecentToolUseAndExtractIntent(ctx, transcriptPath, maxDepth)
+}
+
+// linesSize returns the number of bytes lines took up in the file, including newlines
+func linesSize(lines []string) int64 {
+	var size int64
+	for _, line := range lines {
+		size += int64(len(line)) + 1
+	}
+	return size
+}
+
+// ProjectTranscriptDir returns the directory Claude Code stores a project's transcripts in
+func ProjectTranscriptDir(claudeHome, projectRoot string) string {
+	return filepath.Join(claudeHome, "projects", projectDirChars.ReplaceAllString(projectRoot, "-"))
+}
+
+// LatestTranscript returns the most recently modified .jsonl transcript in dir
+func LatestTranscript(dir string) (string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to read transcript directory %s: %w", dir, err)
+	}
+
+	var latest string
+	var latestInfo os.FileInfo
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".jsonl" {
+			continue
+		}
+		info, infoErr := entry.Info()
+		if infoErr != nil {
+			continue
+		}
+		if latestInfo == nil || info.ModTime().After(latestInfo.ModTime()) {
+			latest = filepath.Join(dir, entry.Name())
+			latestInfo = info
+		}
+	}
+
+	if latest == "" {
+		return "", fmt.Errorf("no transcripts found in %s", dir)
+	}
+	return latest, nil
+}
@@ -0,0 +1,109 @@
+package transcript
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCountTokens(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "transcript.jsonl")
+	if err := os.WriteFile(path, []byte(strings.Repeat("a", 401)), 0o600); err != nil {
+		t.Fatalf("Failed to write transcript: %v", err)
+	}
+
+	tokens, err := CountTokens(path)
+	if err != nil {
+		t.Fatalf("CountTokens failed: %v", err)
+	}
+	if tokens != 101 {
+		t.Errorf("Expected ~101 tokens, got %d", tokens)
+	}
+
+	if _, err := CountTokens(filepath.Join(t.TempDir(), "missing.jsonl")); err == nil {
+		t.Error("Expected error for missing transcript")
+	}
+}
+
+func TestFindRecentToolUseAndExtractIntentWithLimit(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "transcript.jsonl")
+
+	var content strings.Builder
+	for i := 0; i < 500; i++ {
+		_, _ = fmt.Fprintf(&content, `{"type":"assistant","uuid":"old%d","message":{"content":[`+
+			`{"type":"text","text":"Old message %d"}]}}`+"\n", i, i)
+	}
+	_, _ = content.WriteString(`{"type":"assistant","uuid":"recent","message":{"content":[` +
+		`{"type":"text","text":"Run the recent tests"}]}}` + "\n")
+	_, _ = content.WriteString(`{"type":"assistant","parentUuid":"recent","message":{"content":[` +
+		`{"type":"tool_use","id":"tool1","name":"Bash","input":{"command":"go test"}}]}}` + "\n")
+	if err := os.WriteFile(path, []byte(content.String()), 0o600); err != nil {
+		t.Fatalf("Failed to write transcript: %v", err)
+	}
+
+	full, err := FindRecentToolUseAndExtractIntent(context.Background(), path, 0)
+	if err != nil {
+		t.Fatalf("Full extraction failed: %v", err)
+	}
+
+	limited, err := FindRecentToolUseAndExtractIntentWithLimit(context.Background(), path, 100, 0)
+	if err != nil {
+		t.Fatalf("Limited extraction failed: %v", err)
+	}
+	if !strings.Contains(limited, "Run the recent tests") {
+		t.Errorf("Expected recent intent from limited extraction, got %q", limited)
+	}
+	if limited != full {
+		t.Errorf("Expected limited extraction to match full extraction, got %q vs %q", limited, full)
+	}
+
+	unlimited, err := FindRecentToolUseAndExtractIntentWithLimit(context.Background(), path, 0, 0)
+	if err != nil || unlimited != full {
+		t.Errorf("Expected zero limit to use full extraction, got %q (err %v)", unlimited, err)
+	}
+}
+
+func TestLatestTranscript(t *testing.T) {
+	t.Parallel()
+
+	claudeHome := t.TempDir()
+	dir := ProjectTranscriptDir(claudeHome, "/home/user/my.project")
+	if filepath.Base(dir) != "-home-user-my-project" {
+		t.Errorf("Unexpected project transcript dir %q", dir)
+	}
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		t.Fatalf("Failed to create dir: %v", err)
+	}
+
+	older := filepath.Join(dir, "older.jsonl")
+	newer := filepath.Join(dir, "newer.jsonl")
+	for _, path := range []string{older, newer, filepath.Join(dir, "notes.txt")} {
+		if err := os.WriteFile(path, []byte("{}\n"), 0o600); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+	}
+	past := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(older, past, past); err != nil {
+		t.Fatalf("Failed to set mtime: %v", err)
+	}
+
+	latest, err := LatestTranscript(dir)
+	if err != nil {
+		t.Fatalf("LatestTranscript failed: %v", err)
+	}
+	if latest != newer {
+		t.Errorf("Expected %s, got %s", newer, latest)
+	}
+
+	if _, err := LatestTranscript(t.TempDir()); err == nil {
+		t.Error("Expected error for directory without transcripts")
+	}
+}
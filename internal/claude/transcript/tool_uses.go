@@ -0,0 +1,79 @@
+package transcript
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ToolUseEntry is one tool call recorded in a transcript
+type ToolUseEntry struct {
+	Timestamp  time.Time
+	Input      map[string]any
+	UUID       string
+	ParentUUID string
+	ToolUseID  string
+}
+
+// FindToolUsesByToolName returns every use of toolName in the transcript, oldest first.
+// Timestamp is zero when the entry's timestamp is missing or can't be parsed.
+func FindToolUsesByToolName(ctx context.Context, transcriptPath, toolName string) ([]ToolUseEntry, error) {
+	lines, err := readTranscriptLines(ctx, transcriptPath)
+	if err != nil {
+		return nil, err
+	}
+
+	parser := parserFromContext(ctx)
+	var uses []ToolUseEntry
+	for _, line := range lines {
+		entry, valid := parser.ParseEntry(line)
+		if !valid || entry.Type != "assistant" {
+			continue
+		}
+
+		timestamp, _ := time.Parse(time.RFC3339Nano, entry.Timestamp)
+		for i := range entry.Message.Content {
+			content := &entry.Message.Content[i]
+			if content.Type != "tool_use" || content.Name != toolName {
+				continue
+			}
+			uses = append(uses, ToolUseEntry{
+				UUID:       entry.UUID,
+				ParentUUID: entry.ParentUUID,
+				Timestamp:  timestamp,
+				ToolUseID:  content.ID,
+				Input:      content.Input,
+			})
+		}
+	}
+	return uses, nil
+}
+
+// FindPreviousToolOutput returns the text of the most recent tool_result in the last maxLines
+// lines of the transcript; 0 searches all of it
+func FindPreviousToolOutput(ctx context.Context, transcriptPath string, maxLines int) (string, error) {
+	var lines []string
+	var err error
+	if maxLines > 0 {
+		lines, err = readTranscriptTail(ctx, transcriptPath, maxLines)
+	} else {
+		lines, err = readTranscriptLines(ctx, transcriptPath)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	parser := parserFromContext(ctx)
+	for i := len(lines) - 1; i >= 0; i-- {
+		entry, valid := parser.ParseEntry(lines[i])
+		if !valid {
+			continue
+		}
+		for j := len(entry.Message.Content) - 1; j >= 0; j-- {
+			if content := &entry.Message.Content[j]; content.Type == "tool_result" {
+				return content.ResultText(), nil
+			}
+		}
+	}
+	return "", errors.New("no previous tool output found")
+}
@@ -0,0 +1,95 @@
+package transcript
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFindToolUsesByToolName(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "transcript.jsonl")
+	content := `{"type":"assistant","uuid":"a1","timestamp":"2025-01-02T03:04:05.5Z","message":{"content":[` +
+		`{"type":"text","text":"Writing the file"}]}}
+{"type":"assistant","uuid":"a2","parentUuid":"a1","timestamp":"2025-01-02T03:04:06Z","message":{"content":[` +
+		`{"type":"tool_use","id":"tool1","name":"Write","input":{"file_path":"/repo/a.go"}},` +
+		`{"type":"tool_use","id":"tool2","name":"Bash","input":{"command":"go test"}}]}}
+not json
+{"type":"user","uuid":"u1","message":{"content":[{"type":"tool_result"}]}}
+{"type":"assistant","uuid":"a3","parentUuid":"a2","message":{"content":[` +
+		`{"type":"tool_use","id":"tool3","name":"Write","input":{"file_path":"/repo/b.go"}}]}}
+`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write transcript: %v", err)
+	}
+
+	uses, err := FindToolUsesByToolName(context.Background(), path, "Write")
+	if err != nil {
+		t.Fatalf("FindToolUsesByToolName failed: %v", err)
+	}
+	if len(uses) != 2 {
+		t.Fatalf("Expected 2 Write uses, got %d", len(uses))
+	}
+
+	first := uses[0]
+	if first.UUID != "a2" || first.ParentUUID != "a1" || first.ToolUseID != "tool1" {
+		t.Errorf("Unexpected first use: %+v", first)
+	}
+	if want := time.Date(2025, 1, 2, 3, 4, 6, 0, time.UTC); !first.Timestamp.Equal(want) {
+		t.Errorf("Expected timestamp %v, got %v", want, first.Timestamp)
+	}
+	if first.Input["file_path"] != "/repo/a.go" {
+		t.Errorf("Expected file_path input, got %v", first.Input)
+	}
+
+	if uses[1].ToolUseID != "tool3" || !uses[1].Timestamp.IsZero() {
+		t.Errorf("Unexpected second use: %+v", uses[1])
+	}
+
+	bash, err := FindToolUsesByToolName(context.Background(), path, "Bash")
+	if err != nil || len(bash) != 1 {
+		t.Errorf("Expected 1 Bash use, got %d (%v)", len(bash), err)
+	}
+
+	missing := filepath.Join(t.TempDir(), "missing.jsonl")
+	if _, err := FindToolUsesByToolName(context.Background(), missing, "Write"); err == nil {
+		t.Error("Expected error for missing transcript")
+	}
+}
+
+func TestFindPreviousToolOutput(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "transcript.jsonl")
+	content := `{"type":"user","uuid":"u1","message":{"content":[` +
+		`{"type":"tool_result","tool_use_id":"tool1","content":"ok\tgithub.com/x/y"}]}}
+{"type":"assistant","uuid":"a1","message":{"content":[` +
+		`{"type":"tool_use","id":"tool2","name":"Bash","input":{"command":"go test"}}]}}
+{"type":"user","uuid":"u2","message":{"content":[{"type":"tool_result","tool_use_id":"tool2",` +
+		`"content":[{"type":"text","text":"--- FAIL: TestParse"},{"type":"text","text":"FAIL"}]}]}}
+{"type":"assistant","uuid":"a2","message":{"content":[{"type":"text","text":"Committing now"}]}}
+`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write transcript: %v", err)
+	}
+
+	output, err := FindPreviousToolOutput(context.Background(), path, 0)
+	if err != nil {
+		t.Fatalf("FindPreviousToolOutput failed: %v", err)
+	}
+	if output != "--- FAIL: TestParse\nFAIL" {
+		t.Errorf("Expected the most recent tool_result, got %q", output)
+	}
+
+	if _, err := FindPreviousToolOutput(context.Background(), path, 1); err == nil {
+		t.Error("Expected error when no tool_result is within maxLines")
+	}
+
+	output, err = FindPreviousToolOutput(context.Background(), path, 4)
+	if err != nil || output != "--- FAIL: TestParse\nFAIL" {
+		t.Errorf("Expected the tool_result within 4 lines, got %q (%v)", output, err)
+	}
+}
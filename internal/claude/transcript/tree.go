@@ -0,0 +1,57 @@
+package transcript
+
+import "context"
+
+// TranscriptNode is a transcript entry linked to the entries around it by uuid and parentUuid
+type TranscriptNode struct {
+	// Parent is nil for roots, entries without a parentUuid, and for entries whose parent
+	// isn't in the transcript, such as the first entry after a compaction
+	Parent *TranscriptNode
+	// Children are the entries naming this one as their parent, in transcript order, e.g.
+	// the parallel tool calls made for one intent
+	Children []*TranscriptNode
+	Entry    TranscriptEntry
+}
+
+// IsRoot reports whether the node's entry has no parentUuid
+func (n *TranscriptNode) IsRoot() bool {
+	return n.Entry.ParentUUID == ""
+}
+
+// ExtractToolCallTree reads a Claude Code transcript into a tree of its entries, linking
+// intent messages to their tool calls and tool calls to their results. The nodes are keyed
+// by uuid; entries without one, and lines that aren't valid JSON, are left out. When a
+// uuid repeats, the last entry with it is kept.
+func ExtractToolCallTree(path string) (map[string]*TranscriptNode, error) {
+	lines, err := readTranscriptLines(context.Background(), path)
+	if err != nil {
+		return nil, err
+	}
+
+	var parser ClaudeTranscriptParser
+	nodes := make(map[string]*TranscriptNode, len(lines))
+	order := make([]*TranscriptNode, 0, len(lines))
+	for _, line := range lines {
+		entry, valid := parser.ParseEntry(line)
+		if !valid || entry.UUID == "" {
+			continue
+		}
+		node := &TranscriptNode{Entry: entry}
+		nodes[entry.UUID] = node
+		order = append(order, node)
+	}
+
+	// Children are linked once every entry is known, so they don't depend on line order
+	for _, node := range order {
+		if nodes[node.Entry.UUID] != node {
+			continue
+		}
+		parent := nodes[node.Entry.ParentUUID]
+		if node.IsRoot() || parent == nil || parent == node {
+			continue
+		}
+		node.Parent = parent
+		parent.Children = append(parent.Children, node)
+	}
+	return nodes, nil
+}
@@ -0,0 +1,64 @@
+package transcript
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExtractToolCallTree(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "transcript.jsonl")
+	content := `{"type":"user","uuid":"u1","message":{"content":[{"type":"text","text":"Run the tests"}]}}
+{"type":"assistant","uuid":"a1","parentUuid":"u1","message":{"content":[` +
+		`{"type":"text","text":"Running unit and lint checks"}]}}
+{"type":"assistant","uuid":"a2","parentUuid":"a1","message":{"content":[` +
+		`{"type":"tool_use","id":"tool1","name":"Bash","input":{"command":"just test"}}]}}
+{"type":"assistant","uuid":"a3","parentUuid":"a1","message":{"content":[` +
+		`{"type":"tool_use","id":"tool2","name":"Bash","input":{"command":"just lint"}}]}}
+not json
+{"type":"summary","summary":"no uuid"}
+{"type":"user","uuid":"r1","parentUuid":"a2","message":{"content":[{"type":"tool_result","tool_use_id":"tool1"}]}}
+{"type":"user","uuid":"r2","parentUuid":"gone","message":{"content":[{"type":"text","text":"orphan"}]}}
+`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write transcript: %v", err)
+	}
+
+	tree, err := ExtractToolCallTree(path)
+	if err != nil {
+		t.Fatalf("ExtractToolCallTree failed: %v", err)
+	}
+	if len(tree) != 6 {
+		t.Fatalf("Expected 6 nodes, got %d", len(tree))
+	}
+
+	root := tree["u1"]
+	if !root.IsRoot() || root.Parent != nil || len(root.Children) != 1 {
+		t.Fatalf("Unexpected root: %+v", root)
+	}
+
+	intent := tree["a1"]
+	if intent.Parent != root || root.Children[0] != intent {
+		t.Error("Expected the intent to be the root's child")
+	}
+	if len(intent.Children) != 2 || intent.Children[0] != tree["a2"] || intent.Children[1] != tree["a3"] {
+		t.Errorf("Expected both parallel tool calls under the intent, got %d children", len(intent.Children))
+	}
+
+	result := tree["r1"]
+	if result.Parent != tree["a2"] || result.Parent.Parent != intent {
+		t.Error("Expected the tool result to chain back to its intent")
+	}
+
+	orphan := tree["r2"]
+	if orphan.IsRoot() || orphan.Parent != nil {
+		t.Errorf("Expected an entry with a missing parent to have no Parent, got %+v", orphan)
+	}
+
+	missing := filepath.Join(t.TempDir(), "missing.jsonl")
+	if _, err := ExtractToolCallTree(missing); err == nil {
+		t.Error("Expected error for missing transcript")
+	}
+}
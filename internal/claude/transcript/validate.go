@@ -0,0 +1,94 @@
+package transcript
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// messageEntryTypes are the transcript entry types that make up the conversation and must
+// carry a uuid and timestamp. Other types, such as summary, are metadata.
+var messageEntryTypes = map[string]bool{"user": true, "assistant": true, "system": true}
+
+// formatEntry is the subset of a transcript line ValidateTranscriptFormat checks
+type formatEntry struct {
+	Message   *json.RawMessage `json:"message"`
+	Type      string           `json:"type"`
+	UUID      string           `json:"uuid"`
+	Timestamp string           `json:"timestamp"`
+}
+
+// ValidateTranscriptFormat checks that a Claude transcript is well formed: every line is a
+// JSON object with a type, and conversation entries have a non-empty uuid, a parseable
+// timestamp and a message. Each problem is returned as a warning naming its line, rather
+// than stopping at the first, so the reason intent extraction comes back empty can be found.
+// The error is only set when the transcript can't be read.
+func ValidateTranscriptFormat(path string) ([]string, error) {
+	file, err := os.Open(path) // #nosec G304 -- path is given by the user to check
+	if err != nil {
+		return nil, fmt.Errorf("failed to open transcript file %s: %w", path, err)
+	}
+	defer func() { _ = file.Close() }()
+
+	var warnings []string
+	entries := 0
+	reader := bufio.NewReader(file)
+	for lineNumber := 1; ; lineNumber++ {
+		line, readErr := reader.ReadString('\n')
+		if readErr != nil && !errors.Is(readErr, io.EOF) {
+			return warnings, fmt.Errorf("failed to read transcript line %d: %w", lineNumber, readErr)
+		}
+
+		if line = strings.TrimSpace(line); line != "" {
+			entries++
+			for _, issue := range validateTranscriptLine(line) {
+				warnings = append(warnings, fmt.Sprintf("line %d: %s", lineNumber, issue))
+			}
+		}
+		if errors.Is(readErr, io.EOF) {
+			break
+		}
+	}
+
+	if entries == 0 {
+		warnings = append(warnings, "transcript has no entries")
+	}
+	return warnings, nil
+}
+
+// validateTranscriptLine returns the problems with one non-empty transcript line
+func validateTranscriptLine(line string) []string {
+	var entry formatEntry
+	if err := json.Unmarshal([]byte(line), &entry); err != nil {
+		return []string{fmt.Sprintf("invalid JSON: %v", err)}
+	}
+
+	if entry.Type == "" {
+		return []string{"missing type"}
+	}
+	if !messageEntryTypes[entry.Type] {
+		return nil
+	}
+
+	var issues []string
+	if entry.UUID == "" {
+		issues = append(issues, fmt.Sprintf("%s entry has an empty uuid", entry.Type))
+	}
+	switch {
+	case entry.Timestamp == "":
+		issues = append(issues, fmt.Sprintf("%s entry is missing a timestamp", entry.Type))
+	default:
+		if _, err := time.Parse(time.RFC3339Nano, entry.Timestamp); err != nil {
+			issues = append(issues, fmt.Sprintf("%s entry has an unparseable timestamp %q", entry.Type, entry.Timestamp))
+		}
+	}
+	if entry.Type != "system" && (entry.Message == nil || string(*entry.Message) == "null") {
+		issues = append(issues, fmt.Sprintf("%s entry is missing a message", entry.Type))
+	}
+	return issues
+}
@@ -0,0 +1,65 @@
+package transcript
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	testutil "github.com/wizzomafizzo/bumpers/internal/testing"
+)
+
+func TestValidateTranscriptFormat(t *testing.T) {
+	_, _ = testutil.NewTestContext(t)
+	t.Parallel()
+
+	content := `{"type":"summary","summary":"Earlier work","leafUuid":"a1"}
+{"type":"user","message":{"role":"user","content":"hi"},"uuid":"u1","timestamp":"2024-01-01T10:00:00Z"}
+not json
+
+{"type":"assistant","message":{"role":"assistant","content":"ok"},"uuid":"","timestamp":"yesterday"}
+{"message":{"role":"user","content":"hi"}}
+{"type":"user","uuid":"u2","timestamp":"2024-01-01T10:00:00.123Z"}
+`
+	path := filepath.Join(t.TempDir(), "transcript.jsonl")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write transcript: %v", err)
+	}
+
+	warnings, err := ValidateTranscriptFormat(path)
+	if err != nil {
+		t.Fatalf("ValidateTranscriptFormat failed: %v", err)
+	}
+
+	want := []string{
+		"line 3: invalid JSON: invalid character 'o' in literal null (expecting 'u')",
+		"line 5: assistant entry has an empty uuid",
+		`line 5: assistant entry has an unparseable timestamp "yesterday"`,
+		"line 6: missing type",
+		"line 7: user entry is missing a message",
+	}
+	if !slices.Equal(warnings, want) {
+		t.Errorf("Expected warnings:\n%q\ngot:\n%q", want, warnings)
+	}
+}
+
+func TestValidateTranscriptFormatEmptyAndMissing(t *testing.T) {
+	_, _ = testutil.NewTestContext(t)
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "empty.jsonl")
+	if err := os.WriteFile(path, []byte("\n\n"), 0o600); err != nil {
+		t.Fatalf("Failed to write transcript: %v", err)
+	}
+	warnings, err := ValidateTranscriptFormat(path)
+	if err != nil {
+		t.Fatalf("ValidateTranscriptFormat failed: %v", err)
+	}
+	if !slices.Equal(warnings, []string{"transcript has no entries"}) {
+		t.Errorf("Expected an empty transcript warning, got: %q", warnings)
+	}
+
+	if _, err := ValidateTranscriptFormat(filepath.Join(t.TempDir(), "missing.jsonl")); err == nil {
+		t.Error("Expected an error for a missing transcript")
+	}
+}
@@ -0,0 +1,98 @@
+package transcript
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// DefaultWatchInterval is how often WatchTranscript checks the transcript for new lines
+const DefaultWatchInterval = 500 * time.Millisecond
+
+// WatchOptions controls WatchTranscript
+type WatchOptions struct {
+	// Interval between checks for new lines, DefaultWatchInterval when zero
+	Interval time.Duration
+	// FromStart also passes the entries already in the transcript, instead of only new ones
+	FromStart bool
+}
+
+// WatchTranscript follows a transcript like tail -f, calling handle with each entry as its
+// line is completed, until ctx is done. A line is only parsed once its newline is written,
+// and reading starts over if the file is truncated. Lines the context's parser rejects are
+// skipped. It returns nil when ctx is canceled.
+func WatchTranscript(
+	ctx context.Context, path string, opts WatchOptions, handle func(TranscriptEntry),
+) error {
+	file, err := os.Open(path) // #nosec G304 -- path is the transcript given by the user
+	if err != nil {
+		return fmt.Errorf("failed to open transcript file %s: %w", path, err)
+	}
+	defer func() { _ = file.Close() }()
+
+	var offset int64
+	if !opts.FromStart {
+		if offset, err = file.Seek(0, io.SeekEnd); err != nil {
+			return fmt.Errorf("failed to seek transcript %s: %w", path, err)
+		}
+	}
+	interval := opts.Interval
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+
+	parser := parserFromContext(ctx)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if offset, err = readNewEntries(file, offset, parser, handle); err != nil {
+			return fmt.Errorf("failed to read transcript %s: %w", path, err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// readNewEntries passes every complete line after offset to handle, returning the offset
+// just past the last one
+func readNewEntries(
+	file *os.File, offset int64, parser TranscriptParser, handle func(TranscriptEntry),
+) (int64, error) {
+	info, err := file.Stat()
+	if err != nil {
+		return offset, fmt.Errorf("failed to stat transcript: %w", err)
+	}
+	if info.Size() < offset {
+		offset = 0
+	}
+	if info.Size() == offset {
+		return offset, nil
+	}
+
+	reader := bufio.NewReader(io.NewSectionReader(file, offset, info.Size()-offset))
+	for {
+		line, readErr := reader.ReadString('\n')
+		if errors.Is(readErr, io.EOF) {
+			// Leave a partly written line for the next check
+			return offset, nil
+		}
+		if readErr != nil {
+			return offset, fmt.Errorf("failed to read transcript line: %w", readErr)
+		}
+		offset += int64(len(line))
+		if line = strings.TrimSpace(line); line == "" {
+			continue
+		}
+		if entry, valid := parser.ParseEntry(line); valid {
+			handle(entry)
+		}
+	}
+}
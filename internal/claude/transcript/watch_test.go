@@ -0,0 +1,73 @@
+package transcript
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	testutil "github.com/wizzomafizzo/bumpers/internal/testing"
+)
+
+func TestWatchTranscript(t *testing.T) {
+	ctx, _ := testutil.NewTestContext(t)
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "transcript.jsonl")
+	entry := func(uuid string) string {
+		return `{"type":"assistant","uuid":"` + uuid + `","message":{"role":"assistant",` +
+			`"content":[{"type":"text","text":"hi"}]}}`
+	}
+	if err := os.WriteFile(path, []byte(entry("old")+"\n"), 0o600); err != nil {
+		t.Fatalf("Failed to write transcript: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	entries := make(chan TranscriptEntry, 10)
+	done := make(chan error, 1)
+	go func() {
+		done <- WatchTranscript(ctx, path, WatchOptions{Interval: 5 * time.Millisecond}, func(e TranscriptEntry) {
+			entries <- e
+		})
+	}()
+
+	appendLine := func(text string) {
+		file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o600) // #nosec G304 -- test temp file
+		if err != nil {
+			t.Fatalf("Failed to open transcript: %v", err)
+		}
+		defer func() { _ = file.Close() }()
+		if _, err := file.WriteString(text); err != nil {
+			t.Fatalf("Failed to append to transcript: %v", err)
+		}
+	}
+	next := func() string {
+		select {
+		case e := <-entries:
+			return e.UUID
+		case <-time.After(2 * time.Second):
+			t.Fatal("Timed out waiting for a transcript entry")
+			return ""
+		}
+	}
+
+	// Give the watcher time to seek to the end before appending
+	time.Sleep(20 * time.Millisecond)
+	appendLine(entry("new1") + "\n" + entry("new2")[:20])
+	if got := next(); got != "new1" {
+		t.Errorf("Expected new1 after the existing entries, got %q", got)
+	}
+	appendLine(entry("new2")[20:] + "\n")
+	if got := next(); got != "new2" {
+		t.Errorf("Expected new2 once its line was completed, got %q", got)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Expected nil after cancel, got %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("Expected no other entries, got %d", len(entries))
+	}
+}
@@ -0,0 +1,87 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+)
+
+// Values accepted by a rule's action and actions fields
+const (
+	// ActionBlock sends the rule's message, blocking the tool call before it runs
+	ActionBlock = "block"
+	// ActionAudit appends the match to the audit log as a JSON line
+	ActionAudit = "audit"
+	// ActionWebhook posts the match as JSON to settings.webhook_url
+	ActionWebhook = "webhook"
+)
+
+// GetActions returns the actions run, in order, when the rule matches: actions, the single
+// action shorthand, or just block by default
+func (r *Rule) GetActions() []string {
+	if len(r.Actions) > 0 {
+		return r.Actions
+	}
+	if r.Action != "" {
+		return []string{r.Action}
+	}
+	return []string{ActionBlock}
+}
+
+// HasAction reports whether action is one of the rule's actions
+func (r *Rule) HasAction(action string) bool {
+	return slices.Contains(r.GetActions(), action)
+}
+
+// validateActions checks the action and actions fields
+func (r *Rule) validateActions() error {
+	if r.Action != "" && len(r.Actions) > 0 {
+		return errors.New("action and actions can't both be set, use actions")
+	}
+	seen := make(map[string]bool)
+	for _, action := range r.GetActions() {
+		switch action {
+		case ActionBlock, ActionAudit, ActionWebhook:
+		default:
+			return fmt.Errorf("invalid action '%s': must be '%s', '%s' or '%s'",
+				action, ActionBlock, ActionAudit, ActionWebhook)
+		}
+		if seen[action] {
+			return fmt.Errorf("duplicate action '%s'", action)
+		}
+		seen[action] = true
+	}
+	if r.GetMatch().Event == EventSession && !slices.Equal(r.GetActions(), []string{ActionBlock}) {
+		return errors.New("actions are only supported on 'pre' and 'post' event rules")
+	}
+	return nil
+}
+
+// validateWebhookURL checks that webhook_url, when set, is an http or https URL
+func (s *Settings) validateWebhookURL() error {
+	if s.WebhookURL == "" {
+		return nil
+	}
+	parsed, err := url.Parse(s.WebhookURL)
+	if err != nil {
+		return fmt.Errorf("invalid webhook_url '%s': %w", s.WebhookURL, err)
+	}
+	if (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("invalid webhook_url '%s': must be an http or https URL", s.WebhookURL)
+	}
+	return nil
+}
+
+// validateWebhookActions checks that rules using the webhook action have a URL to post to
+func (c *Config) validateWebhookActions() error {
+	if c.Settings.WebhookURL != "" {
+		return nil
+	}
+	for i := range c.Rules {
+		if c.Rules[i].HasAction(ActionWebhook) {
+			return &RuleError{Index: i, Reason: errors.New("webhook action requires settings.webhook_url")}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ApprovalSession is the approval mode where a blocked value can be approved once, with
+// bumpers approve or $approve and the token in the block message, and is then allowed for
+// the rest of the session
+const ApprovalSession = "session"
+
+// validateApproval checks the approval field
+func (r *Rule) validateApproval() error {
+	switch r.Approval {
+	case "":
+		return nil
+	case ApprovalSession:
+	default:
+		return fmt.Errorf("invalid approval '%s': must be '%s'", r.Approval, ApprovalSession)
+	}
+	if r.GetMatch().Event != "pre" {
+		return errors.New("approval is only supported on 'pre' event rules")
+	}
+	if !r.HasAction(ActionBlock) {
+		return errors.New("approval requires the block action")
+	}
+	if r.GetMatch().Shadow {
+		return errors.New("approval can't be used on shadow rules, which never block")
+	}
+	return nil
+}
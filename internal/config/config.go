@@ -1,24 +1,144 @@
 package config
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
-	"os"
+	"io"
 	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/wizzomafizzo/bumpers/internal/constants"
+	"github.com/wizzomafizzo/bumpers/internal/version"
 	"gopkg.in/yaml.v3"
 )
 
 type Config struct {
-	Rules    []Rule    `yaml:"rules,omitempty" mapstructure:"rules"`
-	Commands []Command `yaml:"commands,omitempty" mapstructure:"commands"`
-	Session  []Session `yaml:"session,omitempty" mapstructure:"session"`
+	Rules         []Rule         `yaml:"rules,omitempty" mapstructure:"rules"`
+	Commands      []Command      `yaml:"commands,omitempty" mapstructure:"commands"`
+	Session       []Session      `yaml:"session,omitempty" mapstructure:"session"`
+	Notifications []Notification `yaml:"notifications,omitempty" mapstructure:"notifications"`
+	Settings      Settings       `yaml:"settings,omitempty" mapstructure:"settings"`
+	// Allow lists exact commands (Bash) or paths (file tools) that skip all rule matching
+	Allow  []string `yaml:"allow,omitempty" mapstructure:"allow"`
+	Output Output   `yaml:"output,omitempty" mapstructure:"output"`
+	// Profiles add rules when selected with BUMPERS_PROFILE or --profile
+	Profiles map[string]Profile `yaml:"profiles,omitempty" mapstructure:"profiles"`
+	// Tests are sample hook payloads run by bumpers selftest
+	Tests []Test `yaml:"tests,omitempty" mapstructure:"tests"`
 }
 
+// Test is a sample hook payload for bumpers selftest, with whether a rule should fire for it
+type Test struct {
+	// Input is the hook payload, e.g. {tool_name: Bash, tool_input: {command: "go test"}}
+	Input map[string]any `yaml:"input" mapstructure:"input"`
+	// ExpectMatch is whether the hook should block or respond; when unset the test only
+	// checks that the payload is processed without error
+	ExpectMatch *bool  `yaml:"expect_match,omitempty" mapstructure:"expect_match"`
+	Name        string `yaml:"name,omitempty" mapstructure:"name"`
+}
+
+// Output controls how matched rules are turned into a response
+type Output struct {
+	// Select picks which rule wins when several match: "first" (default) or "specific"
+	Select string `yaml:"select,omitempty" mapstructure:"select"`
+}
+
+// Values accepted by output.select
+const (
+	SelectFirst    = "first"
+	SelectSpecific = "specific"
+)
+
+// Validate checks output.select is a known mode
+func (o *Output) Validate() error {
+	switch o.Select {
+	case "", SelectFirst, SelectSpecific:
+		return nil
+	default:
+		return fmt.Errorf("invalid select '%s': must be 'first' or 'specific'", o.Select)
+	}
+}
+
+// Settings contains global options that change how bumpers behaves
+type Settings struct {
+	// OnEmptyMessage controls what happens when a matched rule produces no usable guidance
+	OnEmptyMessage string `yaml:"on_empty_message,omitempty" mapstructure:"on_empty_message"`
+	// MaxIntentTokens switches intent extraction to recent lines only for larger transcripts
+	MaxIntentTokens int `yaml:"max_intent_tokens,omitempty" mapstructure:"max_intent_tokens"`
+	// IntentScanLines bounds how many of the transcript's last lines any intent extraction
+	// scans, including rules with a larger or unlimited max_intent_depth; 0 leaves it unbounded
+	IntentScanLines int `yaml:"intent_scan_lines,omitempty" mapstructure:"intent_scan_lines"`
+	// MaxMatchBytes caps candidate values before matching; longer values are head+tail sampled
+	MaxMatchBytes int `yaml:"max_match_bytes,omitempty" mapstructure:"max_match_bytes"`
+	// MaxDisplayBytes caps the matched value exposed to templates such as {{.Command}}
+	MaxDisplayBytes int `yaml:"max_display_bytes,omitempty" mapstructure:"max_display_bytes"`
+	// NotificationHook enables installation of the Claude Code Notification hook
+	NotificationHook bool `yaml:"notification_hook,omitempty" mapstructure:"notification_hook"`
+	// Strict turns config warnings such as duplicate command names into load errors
+	Strict bool `yaml:"strict,omitempty" mapstructure:"strict"`
+	// LogRedactPatterns are regexes whose matches are masked in every logged tool value
+	LogRedactPatterns []string `yaml:"log_redact_patterns,omitempty" mapstructure:"log_redact_patterns"`
+	// Redact are keys, such as "token", whose following values are masked in every logged value
+	Redact []string `yaml:"redact,omitempty" mapstructure:"redact"`
+	// HookTimeout bounds how long one hook may run, as a Go duration such as "30s"
+	HookTimeout string `yaml:"hook_timeout,omitempty" mapstructure:"hook_timeout"`
+	// DecisionCacheTTL is how long a PreToolUse block is reused for exact repeats of the
+	// same tool call, as a Go duration such as "30s"; unset or "0s" leaves the cache off
+	DecisionCacheTTL string `yaml:"decision_cache_ttl,omitempty" mapstructure:"decision_cache_ttl"`
+	// ToolPolicy is "allow" (default) or "deny"; with deny, tools not in AllowedTools are
+	// blocked before any rule is checked
+	ToolPolicy string `yaml:"tool_policy,omitempty" mapstructure:"tool_policy"`
+	// AllowedTools are tool name regexes, or groups such as @readonly, allowed under tool_policy: deny
+	AllowedTools []string `yaml:"allowed_tools,omitempty" mapstructure:"allowed_tools"`
+	// ToolDeniedMessage is the template sent for tools blocked by tool_policy
+	ToolDeniedMessage string `yaml:"tool_denied_message,omitempty" mapstructure:"tool_denied_message"`
+	// RequiredVersion is a semver range, such as ">=1.2.0 <2.0.0", the bumpers binary should meet
+	RequiredVersion string `yaml:"required_version,omitempty" mapstructure:"required_version"`
+	// AuditLog is the file rules with the audit action append to, by default audit.jsonl in
+	// the bumpers state directory
+	AuditLog string `yaml:"audit_log,omitempty" mapstructure:"audit_log"`
+	// WebhookURL is where rules with the webhook action post their matches
+	WebhookURL string `yaml:"webhook_url,omitempty" mapstructure:"webhook_url"`
+	// ShowRuleSource adds a footer naming the rule and config file to blocking messages
+	ShowRuleSource bool `yaml:"show_rule_source,omitempty" mapstructure:"show_rule_source"`
+	// SessionSources are the SessionStart sources session notes are added on, by default
+	// startup and clear
+	SessionSources []string `yaml:"session_sources,omitempty" mapstructure:"session_sources"`
+	// Locale picks the message from sends and session notes written as a map of locale to
+	// message, falling back to en, and is the language AI generation is asked to reply in
+	Locale string `yaml:"locale,omitempty" mapstructure:"locale"`
+	// LoopProtection set to false lets post rules match tool output containing a message
+	// bumpers sent earlier in the session, which it otherwise skips
+	LoopProtection *bool `yaml:"loop_protection,omitempty" mapstructure:"loop_protection"`
+}
+
+// Defaults used when the corresponding settings are not set
+const (
+	DefaultMaxIntentTokens = 2000
+	DefaultMaxMatchBytes   = 1 << 20
+	DefaultMaxDisplayBytes = 16 << 10
+	DefaultHookTimeout     = 30 * time.Second
+	DefaultMaxIntentDepth  = 20
+)
+
+// Values accepted by settings.on_empty_message
+const (
+	OnEmptyMessageBlock = "block"
+	OnEmptyMessageAllow = "allow"
+)
+
 // PartialConfig represents a configuration where some rules may be invalid
 type PartialConfig struct {
 	Config
 	ValidationWarnings []ValidationWarning
+	CommandWarnings    []string
+	// RuleWarnings lists duplicate and shadowed rules, see Config.ShadowedRules
+	RuleWarnings []string
 }
 
 // ValidationWarning represents a validation error for a specific rule
@@ -26,11 +146,68 @@ type ValidationWarning struct {
 	Error     error
 	Rule      Rule
 	RuleIndex int
+	// Line and Column locate the rule in the YAML passed to LoadPartial, or are 0 when unknown
+	Line   int
+	Column int
 }
 
 type Generate struct {
-	Mode   string `yaml:"mode" mapstructure:"mode"`
-	Prompt string `yaml:"prompt" mapstructure:"prompt"`
+	Mode          string `yaml:"mode" mapstructure:"mode"`
+	Prompt        string `yaml:"prompt" mapstructure:"prompt"`
+	CacheKeyExtra string `yaml:"cache_key_extra,omitempty" mapstructure:"cache_key_extra"`
+	// FallbackMessage replaces the send message when AI generation fails
+	FallbackMessage string `yaml:"fallback_message,omitempty" mapstructure:"fallback_message"`
+	// OnError chooses what to show when AI generation fails: "fallback" (default),
+	// "message" or "append"
+	OnError string `yaml:"on_error,omitempty" mapstructure:"on_error"`
+	// ErrorMessage overrides the notice used by on_error "message" and "append"
+	ErrorMessage string `yaml:"error_message,omitempty" mapstructure:"error_message"`
+}
+
+// Values accepted by generate.on_error
+const (
+	OnErrorFallback = "fallback"
+	OnErrorMessage  = "message"
+	OnErrorAppend   = "append"
+)
+
+// Default notices shown by generate.on_error when error_message is not set
+const (
+	DefaultAIErrorMessage = "AI guidance is unavailable right now."
+	DefaultAIErrorSuffix  = "(AI unavailable)"
+)
+
+// OnErrorMessage returns the message to show when AI generation failed, given the
+// fallback that would otherwise be shown
+func (g *Generate) OnErrorMessage(fallback string) string {
+	switch g.OnError {
+	case OnErrorMessage:
+		if g.ErrorMessage != "" {
+			return g.ErrorMessage
+		}
+		return DefaultAIErrorMessage
+	case OnErrorAppend:
+		suffix := g.ErrorMessage
+		if suffix == "" {
+			suffix = DefaultAIErrorSuffix
+		}
+		if fallback == "" {
+			return suffix
+		}
+		return fallback + " " + suffix
+	default:
+		return fallback
+	}
+}
+
+// validateOnError checks generate.on_error is a known mode
+func (g *Generate) validateOnError() error {
+	switch g.OnError {
+	case "", OnErrorFallback, OnErrorMessage, OnErrorAppend:
+		return nil
+	default:
+		return fmt.Errorf("invalid on_error '%s': must be one of: fallback, message, append", g.OnError)
+	}
 }
 
 // Match represents the match configuration for a rule
@@ -38,38 +215,350 @@ type Match struct {
 	Pattern string   `yaml:"pattern" mapstructure:"pattern"`
 	Event   string   `yaml:"event,omitempty" mapstructure:"event"`
 	Sources []string `yaml:"sources,omitempty" mapstructure:"sources"`
+	// SourceFieldRegex also checks every tool_input field whose key matches this regex
+	SourceFieldRegex string `yaml:"source_field_regex,omitempty" mapstructure:"source_field_regex"`
+	// Fields must all equal the tool_input field of the same name, compared as strings so
+	// booleans and numbers can be matched, e.g. force: "true"
+	Fields map[string]string `yaml:"fields,omitempty" mapstructure:"fields"`
+	// StripEnv drops leading VAR=value assignments from a Bash command before matching
+	StripEnv bool `yaml:"strip_env,omitempty" mapstructure:"strip_env"`
+	// ResolvePaths makes file_path, path and notebook_path values absolute, cleaned and with
+	// symlinks followed before matching
+	ResolvePaths bool `yaml:"resolve_paths,omitempty" mapstructure:"resolve_paths"`
+	// Shadow records the rule's matches in the log and audit log without blocking, so a new
+	// rule can be trialled before it's enforced
+	Shadow bool `yaml:"shadow,omitempty" mapstructure:"shadow"`
+	// MinArgs and MaxArgs bound the number of whitespace-separated words after the match
+	MinArgs *int `yaml:"min_args,omitempty" mapstructure:"min_args"`
+	MaxArgs *int `yaml:"max_args,omitempty" mapstructure:"max_args"`
+	// MinWordCount and MaxWordCount bound the number of whitespace-separated words in the
+	// whole value, checked before the pattern
+	MinWordCount *int `yaml:"min_word_count,omitempty" mapstructure:"min_word_count"`
+	MaxWordCount *int `yaml:"max_word_count,omitempty" mapstructure:"max_word_count"`
+	// Anchor ties the pattern to the start of the value ("start") or the whole value ("full")
+	// instead of matching anywhere in it
+	Anchor string `yaml:"anchor,omitempty" mapstructure:"anchor"`
+	// MaxIntentDepth is how many of the transcript's most recent messages are searched for
+	// the #intent source; 0 searches the whole transcript
+	MaxIntentDepth *int `yaml:"max_intent_depth,omitempty" mapstructure:"max_intent_depth"`
+}
+
+// Values accepted by match.anchor
+const (
+	AnchorStart = "start"
+	AnchorFull  = "full"
+)
+
+// AnchoredPattern returns the pattern wrapped as match.anchor asks, e.g. "rm|del" with
+// anchor full becomes "^(?:rm|del)$"
+func (m *Match) AnchoredPattern() string {
+	switch m.Anchor {
+	case AnchorStart:
+		return "^(?:" + m.Pattern + ")"
+	case AnchorFull:
+		return "^(?:" + m.Pattern + ")$"
+	default:
+		return m.Pattern
+	}
+}
+
+// validateAnchor checks match.anchor is a known value
+func (m *Match) validateAnchor() error {
+	switch m.Anchor {
+	case "", AnchorStart, AnchorFull:
+		return nil
+	default:
+		return fmt.Errorf("invalid anchor '%s': must be 'start' or 'full'", m.Anchor)
+	}
+}
+
+// GetMaxIntentDepth returns max_intent_depth, or DefaultMaxIntentDepth when unset
+func (m *Match) GetMaxIntentDepth() int {
+	if m.MaxIntentDepth == nil {
+		return DefaultMaxIntentDepth
+	}
+	return *m.MaxIntentDepth
+}
+
+// ArgsWithinLimits reports whether count satisfies min_args and max_args
+func (m *Match) ArgsWithinLimits(count int) bool {
+	if m.MinArgs != nil && count < *m.MinArgs {
+		return false
+	}
+	return m.MaxArgs == nil || count <= *m.MaxArgs
+}
+
+// HasArgLimits reports whether min_args or max_args is set
+func (m *Match) HasArgLimits() bool {
+	return m.MinArgs != nil || m.MaxArgs != nil
+}
+
+// validateArgLimits checks min_args and max_args are non-negative and ordered
+func (m *Match) validateArgLimits() error {
+	if m.MinArgs != nil && *m.MinArgs < 0 {
+		return fmt.Errorf("invalid min_args %d: must not be negative", *m.MinArgs)
+	}
+	if m.MaxArgs != nil && *m.MaxArgs < 0 {
+		return fmt.Errorf("invalid max_args %d: must not be negative", *m.MaxArgs)
+	}
+	if m.MinArgs != nil && m.MaxArgs != nil && *m.MinArgs > *m.MaxArgs {
+		return fmt.Errorf("invalid min_args %d: must not be greater than max_args %d", *m.MinArgs, *m.MaxArgs)
+	}
+	return nil
+}
+
+// WordCountWithinLimits reports whether count satisfies min_word_count and max_word_count
+func (m *Match) WordCountWithinLimits(count int) bool {
+	if m.MinWordCount != nil && count < *m.MinWordCount {
+		return false
+	}
+	return m.MaxWordCount == nil || count <= *m.MaxWordCount
+}
+
+// HasWordCountLimits reports whether min_word_count or max_word_count is set
+func (m *Match) HasWordCountLimits() bool {
+	return m.MinWordCount != nil || m.MaxWordCount != nil
+}
+
+// validateWordCountLimits checks min_word_count and max_word_count are non-negative and ordered
+func (m *Match) validateWordCountLimits() error {
+	if m.MinWordCount != nil && *m.MinWordCount < 0 {
+		return fmt.Errorf("invalid min_word_count %d: must not be negative", *m.MinWordCount)
+	}
+	if m.MaxWordCount != nil && *m.MaxWordCount < 0 {
+		return fmt.Errorf("invalid max_word_count %d: must not be negative", *m.MaxWordCount)
+	}
+	if m.MinWordCount != nil && m.MaxWordCount != nil && *m.MinWordCount > *m.MaxWordCount {
+		return fmt.Errorf("invalid min_word_count %d: must not be greater than max_word_count %d",
+			*m.MinWordCount, *m.MaxWordCount)
+	}
+	return nil
 }
 
 type Rule struct {
-	Generate any    `yaml:"generate,omitempty" mapstructure:"generate"`
-	Match    any    `yaml:"match" mapstructure:"match"`
-	Tool     string `yaml:"tool,omitempty" mapstructure:"tool"`
-	Send     string `yaml:"send" mapstructure:"send"`
+	ID       string   `yaml:"id,omitempty" mapstructure:"id"` // stable reference for rules remove/edit
+	Generate any      `yaml:"generate,omitempty" mapstructure:"generate"`
+	Match    any      `yaml:"match" mapstructure:"match"`
+	Tool     string   `yaml:"tool,omitempty" mapstructure:"tool"`
+	Send     string   `yaml:"send" mapstructure:"send"`
+	Replace  string   `yaml:"replace,omitempty" mapstructure:"replace"` // exact command to run instead
+	Exec     string   `yaml:"exec,omitempty" mapstructure:"exec"`       // shell command run after a match
+	Except   []string `yaml:"except,omitempty" mapstructure:"except"`
+	Log      string   `yaml:"log,omitempty" mapstructure:"log"` // full, redact or off for logged matched values
+	// Enabled set to false keeps the rule in the config without it ever matching
+	Enabled *bool `yaml:"enabled,omitempty" mapstructure:"enabled"`
+	// Action is shorthand for an actions list with one entry
+	Action string `yaml:"action,omitempty" mapstructure:"action"`
+	// Actions run in order when the rule matches: block, audit and webhook. Defaults to block.
+	Actions []string `yaml:"actions,omitempty" mapstructure:"actions"`
+	// Priority orders rules, highest first, for bumpers rules sort --by=priority; rules are
+	// still matched in config order
+	Priority int `yaml:"priority,omitempty" mapstructure:"priority"`
+	// Approval set to session blocks a matched value until it's approved, then allows that
+	// exact value for the rest of the session
+	Approval string `yaml:"approval,omitempty" mapstructure:"approval"`
+	// When holds conditions on the project's files, checked before the pattern
+	When *When `yaml:"when,omitempty" mapstructure:"when"`
+	// Origin is where the rule was defined, set by the loader and kept when configs are
+	// merged. It can't be set in a config file.
+	Origin *RuleOrigin `yaml:"-" mapstructure:"-"`
+	// SendLocales holds send written as a map of locale to message; Send is set to the one
+	// for settings.locale when the config is loaded
+	SendLocales map[string]string `yaml:"-" mapstructure:"-"`
+}
+
+// IsEnabled reports whether the rule takes part in matching, which is the default
+func (r *Rule) IsEnabled() bool {
+	return r.Enabled == nil || *r.Enabled
+}
+
+// SetAllRulesEnabled sets every rule's enabled flag, returning how many rules changed
+func (c *Config) SetAllRulesEnabled(enabled bool) int {
+	changed := 0
+	for i := range c.Rules {
+		rule := &c.Rules[i]
+		if rule.IsEnabled() != enabled {
+			changed++
+		}
+		// Enabled is the default, so the field is dropped rather than written out
+		rule.Enabled = nil
+		if !enabled {
+			rule.Enabled = new(bool)
+		}
+	}
+	return changed
+}
+
+// Values accepted by a rule's log field
+const (
+	LogFull   = "full"
+	LogRedact = "redact"
+	LogOff    = "off"
+)
+
+// GetLog returns how values matched by the rule are logged, defaulting to LogFull
+func (r *Rule) GetLog() string {
+	if r.Log == "" {
+		return LogFull
+	}
+	return r.Log
 }
 
 type Command struct {
-	Generate any    `yaml:"generate,omitempty" mapstructure:"generate"`
-	Name     string `yaml:"name" mapstructure:"name"`
-	Send     string `yaml:"send" mapstructure:"send"`
+	Generate any      `yaml:"generate,omitempty" mapstructure:"generate"`
+	Name     string   `yaml:"name" mapstructure:"name"`
+	Send     string   `yaml:"send" mapstructure:"send"`
+	Aliases  []string `yaml:"aliases,omitempty" mapstructure:"aliases"`
+	// SendLocales holds send written as a map of locale to message, see Rule.SendLocales
+	SendLocales map[string]string `yaml:"-" mapstructure:"-"`
 }
 
 type Session struct {
 	Generate any    `yaml:"generate,omitempty" mapstructure:"generate"`
-	Add      string `yaml:"add" mapstructure:"add"`
+	Add      string `yaml:"add,omitempty" mapstructure:"add"`
+	AddFile  string `yaml:"add_file,omitempty" mapstructure:"add_file"` // read at hook time, relative to the project root
+	// Sources are the SessionStart sources the note is added on, overriding settings.session_sources
+	Sources []string `yaml:"sources,omitempty" mapstructure:"sources"`
+	// OncePerSession shows the note at most once for each session ID
+	OncePerSession bool `yaml:"once_per_session,omitempty" mapstructure:"once_per_session"`
+	// AddLocales holds add written as a map of locale to message, see Rule.SendLocales
+	AddLocales map[string]string `yaml:"-" mapstructure:"-"`
+}
+
+// Notification annotates Claude Code notifications whose message matches a pattern
+type Notification struct {
+	Match string `yaml:"match" mapstructure:"match"`
+	Send  string `yaml:"send" mapstructure:"send"`
+}
+
+// UnmarshalYAML accepts send as a string, a list of lines or a map of locale to message
+func (r *Rule) UnmarshalYAML(value *yaml.Node) error {
+	if err := joinSendLines(value); err != nil {
+		return err
+	}
+	locales, err := splitLocalized(value, "send")
+	if err != nil {
+		return err
+	}
+	type plain Rule
+	if err := value.Decode((*plain)(r)); err != nil {
+		return err
+	}
+	r.SendLocales = locales
+	return nil
+}
+
+// MarshalYAML writes a localized send back as its map of locale to message
+func (r Rule) MarshalYAML() (any, error) {
+	type plain Rule
+	return withLocalized(plain(r), "send", r.SendLocales)
+}
+
+// UnmarshalYAML accepts send as a string, a list of lines or a map of locale to message
+func (c *Command) UnmarshalYAML(value *yaml.Node) error {
+	if err := joinSendLines(value); err != nil {
+		return err
+	}
+	locales, err := splitLocalized(value, "send")
+	if err != nil {
+		return err
+	}
+	type plain Command
+	if err := value.Decode((*plain)(c)); err != nil {
+		return err
+	}
+	c.SendLocales = locales
+	return nil
+}
+
+// MarshalYAML writes a localized send back as its map of locale to message
+func (c Command) MarshalYAML() (any, error) {
+	type plain Command
+	return withLocalized(plain(c), "send", c.SendLocales)
+}
+
+// UnmarshalYAML accepts add as a string or a map of locale to message
+func (s *Session) UnmarshalYAML(value *yaml.Node) error {
+	locales, err := splitLocalized(value, "add")
+	if err != nil {
+		return err
+	}
+	type plain Session
+	if err := value.Decode((*plain)(s)); err != nil {
+		return err
+	}
+	s.AddLocales = locales
+	return nil
+}
+
+// MarshalYAML writes a localized add back as its map of locale to message
+func (s Session) MarshalYAML() (any, error) {
+	type plain Session
+	return withLocalized(plain(s), "add", s.AddLocales)
+}
+
+// UnmarshalYAML accepts send as a string or a list of lines
+func (n *Notification) UnmarshalYAML(value *yaml.Node) error {
+	if err := joinSendLines(value); err != nil {
+		return err
+	}
+	type plain Notification
+	return value.Decode((*plain)(n))
+}
+
+// joinSendLines replaces a list-form send in a mapping node with its lines joined by newlines
+func joinSendLines(value *yaml.Node) error {
+	if value.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(value.Content); i += 2 {
+		key, send := value.Content[i], value.Content[i+1]
+		if key.Value != "send" || send.Kind != yaml.SequenceNode {
+			continue
+		}
+
+		lines := make([]string, 0, len(send.Content))
+		for _, line := range send.Content {
+			if line.Kind != yaml.ScalarNode {
+				return fmt.Errorf("line %d, column %d: send list entries must be strings", line.Line, line.Column)
+			}
+			lines = append(lines, line.Value)
+		}
+		value.Content[i+1] = &yaml.Node{
+			Kind:   yaml.ScalarNode,
+			Tag:    "!!str",
+			Value:  strings.Join(lines, "\n"),
+			Line:   send.Line,
+			Column: send.Column,
+		}
+	}
+	return nil
 }
 
 func Load(path string) (*Config, error) {
-	data, err := os.ReadFile(path)
+	return load(path, false)
+}
+
+// LoadForEdit loads the config at path like Load, but also accepts a config with nothing in
+// it yet, so adding its first rule or command keeps the rest of the file
+func LoadForEdit(path string) (*Config, error) {
+	return load(path, true)
+}
+
+func load(path string, allowEmpty bool) (*Config, error) {
+	source, err := ReadSource(path)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read config: %w", err)
+		return nil, err
 	}
 
 	var config Config
-	if err := yaml.Unmarshal(data, &config); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
+	if err := yaml.Unmarshal(source.Data, &config); err != nil {
+		return nil, newParseError("", err)
 	}
+	config.restoreRuleOrigins(source.origins)
+	config.applyLocale()
 
-	if err := config.Validate(); err != nil {
+	if err := config.Validate(); err != nil && (!allowEmpty || !errors.Is(err, ErrEmptyConfig)) {
 		return nil, fmt.Errorf("config validation failed: %w", err)
 	}
 
@@ -80,8 +569,9 @@ func Load(path string) (*Config, error) {
 func LoadFromYAML(data []byte) (*Config, error) {
 	var config Config
 	if err := yaml.Unmarshal(data, &config); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
+		return nil, newParseError("", err)
 	}
+	config.applyLocale()
 
 	if err := config.Validate(); err != nil {
 		return nil, fmt.Errorf("config validation failed: %w", err)
@@ -92,19 +582,316 @@ func LoadFromYAML(data []byte) (*Config, error) {
 
 // Validate performs comprehensive config validation
 func (c *Config) Validate() error {
-	if len(c.Rules) == 0 && len(c.Commands) == 0 && len(c.Session) == 0 {
-		return errors.New("config must contain at least one rule, command, or session")
+	if len(c.Rules) == 0 && len(c.Commands) == 0 && len(c.Session) == 0 && len(c.Notifications) == 0 {
+		return ErrEmptyConfig
 	}
 
 	for i := range c.Rules {
 		if err := c.Rules[i].Validate(); err != nil {
-			return fmt.Errorf("rule %d validation failed: %w", i+1, err)
+			return &RuleError{Index: i, Reason: err}
+		}
+	}
+
+	if err := c.validateRuleIDs(); err != nil {
+		return err
+	}
+
+	if err := c.validateWebhookActions(); err != nil {
+		return err
+	}
+
+	if err := c.validateProfiles(); err != nil {
+		return err
+	}
+
+	if err := c.validateCommandAliases(); err != nil {
+		return err
+	}
+	for i := range c.Commands {
+		if err := validateLocales("send", c.Commands[i].SendLocales); err != nil {
+			return fmt.Errorf("command %d validation failed: %w", i+1, err)
+		}
+	}
+
+	if duplicates := c.DuplicateCommandNames(); c.Settings.Strict && len(duplicates) > 0 {
+		return fmt.Errorf("strict mode: %s", duplicates[0])
+	}
+
+	for i := range c.Session {
+		if err := c.Session[i].Validate(); err != nil {
+			return fmt.Errorf("session %d validation failed: %w", i+1, err)
+		}
+	}
+
+	for i := range c.Notifications {
+		if err := c.Notifications[i].Validate(); err != nil {
+			return fmt.Errorf("notification %d validation failed: %w", i+1, err)
+		}
+	}
+
+	for i := range c.Tests {
+		if len(c.Tests[i].Input) == 0 {
+			return fmt.Errorf("test %d validation failed: input is required", i+1)
+		}
+	}
+
+	if err := c.Settings.Validate(); err != nil {
+		return fmt.Errorf("settings validation failed: %w", err)
+	}
+
+	if err := c.Output.Validate(); err != nil {
+		return fmt.Errorf("output validation failed: %w", err)
+	}
+
+	return nil
+}
+
+// validateCommandAliases ensures every alias is unique across all command names and aliases
+func (c *Config) validateCommandAliases() error {
+	names := make(map[string]bool, len(c.Commands))
+	for i := range c.Commands {
+		names[c.Commands[i].Name] = true
+	}
+
+	seen := make(map[string]bool)
+	for i := range c.Commands {
+		for _, alias := range c.Commands[i].Aliases {
+			if alias == "" {
+				return fmt.Errorf("command %d has an empty alias", i+1)
+			}
+			if names[alias] || seen[alias] {
+				return fmt.Errorf("command %d alias '%s' conflicts with another command name or alias", i+1, alias)
+			}
+			seen[alias] = true
 		}
 	}
 
 	return nil
 }
 
+// DuplicateCommandNames describes each command whose name was already used by an earlier
+// command. Only the first command with a given name is reachable.
+func (c *Config) DuplicateCommandNames() []string {
+	var duplicates []string
+	first := make(map[string]int, len(c.Commands))
+	for i := range c.Commands {
+		name := c.Commands[i].Name
+		if name == "" {
+			continue
+		}
+		if j, ok := first[name]; ok {
+			duplicates = append(duplicates, fmt.Sprintf(
+				"command %d name '%s' duplicates command %d and will be ignored", i+1, name, j+1))
+			continue
+		}
+		first[name] = i
+	}
+	return duplicates
+}
+
+// Validate performs settings-level validation
+func (s *Settings) Validate() error {
+	if s.MaxIntentTokens < 0 {
+		return fmt.Errorf("invalid max_intent_tokens %d: must not be negative", s.MaxIntentTokens)
+	}
+	if s.Locale != "" {
+		if err := validateLocale("locale", s.Locale); err != nil {
+			return err
+		}
+	}
+	if s.IntentScanLines < 0 {
+		return fmt.Errorf("invalid intent_scan_lines %d: must not be negative", s.IntentScanLines)
+	}
+	if s.MaxMatchBytes < 0 {
+		return fmt.Errorf("invalid max_match_bytes %d: must not be negative", s.MaxMatchBytes)
+	}
+	if s.MaxDisplayBytes < 0 {
+		return fmt.Errorf("invalid max_display_bytes %d: must not be negative", s.MaxDisplayBytes)
+	}
+	for _, pattern := range s.LogRedactPatterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("invalid log_redact_patterns entry '%s': %w", pattern, err)
+		}
+	}
+	if s.HookTimeout != "" {
+		timeout, err := time.ParseDuration(s.HookTimeout)
+		if err != nil {
+			return fmt.Errorf("invalid hook_timeout '%s': %w", s.HookTimeout, err)
+		}
+		if timeout < 0 {
+			return fmt.Errorf("invalid hook_timeout '%s': must not be negative", s.HookTimeout)
+		}
+	}
+	if s.DecisionCacheTTL != "" {
+		ttl, err := time.ParseDuration(s.DecisionCacheTTL)
+		if err != nil {
+			return fmt.Errorf("invalid decision_cache_ttl '%s': %w", s.DecisionCacheTTL, err)
+		}
+		if ttl < 0 {
+			return fmt.Errorf("invalid decision_cache_ttl '%s': must not be negative", s.DecisionCacheTTL)
+		}
+	}
+	if err := s.validateToolPolicy(); err != nil {
+		return err
+	}
+	if err := s.validateWebhookURL(); err != nil {
+		return err
+	}
+	if err := validateSessionSources("session_sources", s.SessionSources); err != nil {
+		return err
+	}
+	if s.RequiredVersion != "" {
+		if err := version.ValidateConstraint(s.RequiredVersion); err != nil {
+			return fmt.Errorf("invalid required_version: %w", err)
+		}
+	}
+
+	switch s.OnEmptyMessage {
+	case "", OnEmptyMessageBlock, OnEmptyMessageAllow:
+		return nil
+	default:
+		return fmt.Errorf("invalid on_empty_message '%s': must be 'block' or 'allow'", s.OnEmptyMessage)
+	}
+}
+
+// GetMaxIntentTokens returns the configured intent token limit or the default
+func (s *Settings) GetMaxIntentTokens() int {
+	if s.MaxIntentTokens > 0 {
+		return s.MaxIntentTokens
+	}
+	return DefaultMaxIntentTokens
+}
+
+// LimitIntentDepth bounds an intent search depth by intent_scan_lines, where a depth of 0
+// searches the whole transcript
+func (s *Settings) LimitIntentDepth(depth int) int {
+	if s.IntentScanLines > 0 && (depth <= 0 || depth > s.IntentScanLines) {
+		return s.IntentScanLines
+	}
+	return depth
+}
+
+// sessionSources are the SessionStart sources Claude Code sends
+var sessionSources = []string{
+	constants.SessionSourceStartup, constants.SessionSourceResume,
+	constants.SessionSourceClear, constants.SessionSourceCompact,
+}
+
+// validateSessionSources checks every entry of the named sources list is a SessionStart source
+func validateSessionSources(field string, sources []string) error {
+	for _, source := range sources {
+		if !slices.Contains(sessionSources, source) {
+			return fmt.Errorf("invalid %s entry '%s': must be one of %s",
+				field, source, strings.Join(sessionSources, ", "))
+		}
+	}
+	return nil
+}
+
+// GetSessionSources returns the SessionStart sources session notes are added on
+func (s *Settings) GetSessionSources() []string {
+	if len(s.SessionSources) > 0 {
+		return s.SessionSources
+	}
+	return []string{constants.SessionSourceStartup, constants.SessionSourceClear}
+}
+
+// GetMaxMatchBytes returns the configured match size cap or the default
+func (s *Settings) GetMaxMatchBytes() int {
+	if s.MaxMatchBytes > 0 {
+		return s.MaxMatchBytes
+	}
+	return DefaultMaxMatchBytes
+}
+
+// GetMaxDisplayBytes returns the configured display size cap or the default
+func (s *Settings) GetMaxDisplayBytes() int {
+	if s.MaxDisplayBytes > 0 {
+		return s.MaxDisplayBytes
+	}
+	return DefaultMaxDisplayBytes
+}
+
+// GetHookTimeout returns the configured hook timeout or the default
+func (s *Settings) GetHookTimeout() time.Duration {
+	if timeout, err := time.ParseDuration(s.HookTimeout); err == nil && timeout > 0 {
+		return timeout
+	}
+	return DefaultHookTimeout
+}
+
+// LoopProtectionEnabled reports whether post rules skip tool output containing bumpers'
+// own messages, which is the default
+func (s *Settings) LoopProtectionEnabled() bool {
+	return s.LoopProtection == nil || *s.LoopProtection
+}
+
+// GetDecisionCacheTTL returns the configured decision cache TTL, 0 when it's unset, which
+// means decisions aren't cached
+func (s *Settings) GetDecisionCacheTTL() time.Duration {
+	if ttl, err := time.ParseDuration(s.DecisionCacheTTL); err == nil && ttl >= 0 {
+		return ttl
+	}
+	return 0
+}
+
+// AllowOnEmptyMessage reports whether rules with no usable guidance should allow the command
+func (s *Settings) AllowOnEmptyMessage() bool {
+	return s.OnEmptyMessage == OnEmptyMessageAllow
+}
+
+// IsNoOpMessage reports whether a rendered message gives no guidance beyond the matched value:
+// it is blank, or identical to the value once both are normalized by normalizeMessage. Close
+// but different messages aren't caught, since a good message is often a near copy of the
+// value, e.g. "pnpm install" for "npm install".
+func IsNoOpMessage(message, matchedValue string) bool {
+	normalizedMessage := normalizeMessage(message)
+	if normalizedMessage == "" {
+		return true
+	}
+	return normalizedMessage == normalizeMessage(matchedValue)
+}
+
+// normalizeMessage lowercases, collapses whitespace and strips surrounding quotes and punctuation
+func normalizeMessage(message string) string {
+	normalized := strings.ToLower(strings.Join(strings.Fields(message), " "))
+	return strings.Trim(normalized, "\"'`.!:;")
+}
+
+// Validate performs session note validation
+func (s *Session) Validate() error {
+	if s.Add != "" && s.AddFile != "" {
+		return errors.New("add and add_file cannot both be set")
+	}
+	if err := validateLocales("add", s.AddLocales); err != nil {
+		return err
+	}
+	return validateSessionSources("sources", s.Sources)
+}
+
+// ShowsOn reports whether the note is added on a SessionStart from source: its own sources
+// when set, otherwise settings.session_sources
+func (s *Session) ShowsOn(source string, settings *Settings) bool {
+	if len(s.Sources) > 0 {
+		return slices.Contains(s.Sources, source)
+	}
+	return slices.Contains(settings.GetSessionSources(), source)
+}
+
+// Validate performs notification-level validation
+func (n *Notification) Validate() error {
+	if n.Match == "" {
+		return errors.New("match field is required and cannot be empty")
+	}
+	if _, err := regexp.Compile(n.Match); err != nil {
+		return fmt.Errorf("invalid regex pattern '%s': %w", n.Match, err)
+	}
+	if n.Send == "" {
+		return errors.New("send field is required and cannot be empty")
+	}
+	return nil
+}
+
 // Validate performs rule-level validation
 func (r *Rule) Validate() error {
 	if err := r.validateRequiredFields(); err != nil {
@@ -122,18 +909,51 @@ func (r *Rule) Validate() error {
 	if err := r.validateEventValue(); err != nil {
 		return fmt.Errorf("event validation failed: %w", err)
 	}
-	return nil
+	if err := r.validateActions(); err != nil {
+		return err
+	}
+	if err := r.validateApproval(); err != nil {
+		return err
+	}
+	if err := r.validateWhen(); err != nil {
+		return err
+	}
+	if err := r.validateID(); err != nil {
+		return err
+	}
+	if err := validateLocales("send", r.SendLocales); err != nil {
+		return err
+	}
+	switch r.Log {
+	case "", LogFull, LogRedact, LogOff:
+	default:
+		return fmt.Errorf("invalid log '%s': must be 'full', 'redact' or 'off'", r.Log)
+	}
+	match := r.GetMatch()
+	if match.MaxIntentDepth != nil && *match.MaxIntentDepth < 0 {
+		return fmt.Errorf("invalid max_intent_depth %d: must not be negative", *match.MaxIntentDepth)
+	}
+	if err := match.validateArgLimits(); err != nil {
+		return err
+	}
+	if err := match.validateAnchor(); err != nil {
+		return err
+	}
+	return match.validateWordCountLimits()
 }
 
+// validateRequiredFields checks the rule has a match string or a mapping with a pattern.
+// An empty pattern is allowed and soft-disables the rule, see EffectiveMatch.
 func (r *Rule) validateRequiredFields() error {
-	if r.Match == nil {
-		return errors.New("match field is required and cannot be empty")
-	}
-	match := r.GetMatch()
-	if match.Pattern == "" {
-		return errors.New("match field is required and cannot be empty")
+	switch match := r.Match.(type) {
+	case string:
+		return nil
+	case map[string]any:
+		if _, ok := match["pattern"].(string); ok {
+			return nil
+		}
 	}
-	return nil
+	return errors.New("match field is required and cannot be empty")
 }
 
 func (r *Rule) validateRegexPatterns() error {
@@ -141,6 +961,11 @@ func (r *Rule) validateRegexPatterns() error {
 	if _, err := regexp.Compile(match.Pattern); err != nil {
 		return fmt.Errorf("invalid regex pattern '%s': %w", match.Pattern, err)
 	}
+	if match.SourceFieldRegex != "" {
+		if _, err := regexp.Compile(match.SourceFieldRegex); err != nil {
+			return fmt.Errorf("invalid source_field_regex '%s': %w", match.SourceFieldRegex, err)
+		}
+	}
 	if r.Tool != "" {
 		if _, err := regexp.Compile(r.Tool); err != nil {
 			return fmt.Errorf("invalid tools regex pattern '%s': %w", r.Tool, err)
@@ -151,7 +976,7 @@ func (r *Rule) validateRegexPatterns() error {
 
 func (r *Rule) validateResponseMechanism() error {
 	generate := r.GetGenerate()
-	if r.Send == "" && generate.Mode == "off" {
+	if r.Send == "" && generate.Mode == "off" && r.HasAction(ActionBlock) {
 		return errors.New("rule must provide either a message or generate configuration")
 	}
 	return nil
@@ -159,6 +984,9 @@ func (r *Rule) validateResponseMechanism() error {
 
 func (r *Rule) validateGenerateMode() error {
 	generate := r.GetGenerate()
+	if err := generate.validateOnError(); err != nil {
+		return err
+	}
 	if generate.Mode == "" {
 		return nil
 	}
@@ -171,13 +999,41 @@ func (r *Rule) validateGenerateMode() error {
 	return fmt.Errorf("invalid generate mode '%s': must be one of: off, once, session, always", generate.Mode)
 }
 
+// EventSession is the match event for rules evaluated on SessionStart, with the pattern
+// checked against the session source ("startup", "clear" or "resume")
+const EventSession = "session"
+
 // validateEventValue validates the event field in the match configuration
 func (r *Rule) validateEventValue() error {
 	match := r.GetMatch()
 
-	// Validate event value (should be pre or post)
-	if match.Event != "pre" && match.Event != "post" {
-		return fmt.Errorf("invalid event '%s': must be 'pre' or 'post'", match.Event)
+	// Validate event value (should be pre, post or session)
+	if match.Event != "pre" && match.Event != "post" && match.Event != EventSession {
+		return fmt.Errorf("invalid event '%s': must be 'pre', 'post' or 'session'", match.Event)
+	}
+
+	if r.Replace != "" && match.Event != "pre" {
+		return errors.New("replace is only supported on 'pre' event rules")
+	}
+
+	if r.Exec != "" && match.Event == EventSession {
+		return errors.New("exec is only supported on 'pre' and 'post' event rules")
+	}
+
+	if match.SourceFieldRegex != "" && match.Event != "pre" {
+		return errors.New("source_field_regex is only supported on 'pre' event rules")
+	}
+
+	if len(match.Fields) > 0 && match.Event != "pre" {
+		return errors.New("fields is only supported on 'pre' event rules")
+	}
+
+	if match.ResolvePaths && match.Event != "pre" {
+		return errors.New("resolve_paths is only supported on 'pre' event rules")
+	}
+
+	if match.Shadow && match.Event == EventSession {
+		return errors.New("shadow is only supported on 'pre' and 'post' event rules")
 	}
 
 	// No source validation - any source name is valid
@@ -193,7 +1049,17 @@ func (r *Rule) GetGenerate() Generate {
 	return parseGenerateField(r.Generate, "session")
 }
 
-// GetMatch converts the interface{} Match field to a Match struct
+// EffectiveMatch returns the rule's match and whether the rule can fire. ok is false when
+// the match field is missing or malformed, or its pattern is empty or only whitespace: an
+// empty regex would match every value, so clearing a rule's pattern soft-disables it. Such
+// rules stay in the config and are shown by rules list, but are skipped when matching.
+func (r *Rule) EffectiveMatch() (match Match, ok bool) {
+	match = r.GetMatch()
+	return match, strings.TrimSpace(match.Pattern) != ""
+}
+
+// GetMatch converts the interface{} Match field to a Match struct. A missing or malformed
+// field gives a match with an empty pattern; use EffectiveMatch to tell whether it can fire.
 func (r *Rule) GetMatch() Match {
 	if r.Match == nil {
 		return Match{Pattern: "", Event: "pre", Sources: []string{}}
@@ -241,48 +1107,185 @@ func parseMatchFromMap(matchMap map[string]any) Match {
 		match.Sources = convertedSources
 	}
 
+	if fieldRegex, ok := matchMap["source_field_regex"].(string); ok {
+		match.SourceFieldRegex = fieldRegex
+	}
+
+	if fields, ok := matchMap["fields"].(map[string]any); ok {
+		match.Fields = make(map[string]string, len(fields))
+		for name, value := range fields {
+			match.Fields[name] = FieldString(value)
+		}
+	}
+
+	if stripEnv, ok := matchMap["strip_env"].(bool); ok {
+		match.StripEnv = stripEnv
+	}
+
+	if resolvePaths, ok := matchMap["resolve_paths"].(bool); ok {
+		match.ResolvePaths = resolvePaths
+	}
+
+	if shadow, ok := matchMap["shadow"].(bool); ok {
+		match.Shadow = shadow
+	}
+
+	if anchor, ok := matchMap["anchor"].(string); ok {
+		match.Anchor = anchor
+	}
+
+	if minArgs, ok := matchMap["min_args"].(int); ok {
+		match.MinArgs = &minArgs
+	}
+	if maxArgs, ok := matchMap["max_args"].(int); ok {
+		match.MaxArgs = &maxArgs
+	}
+	if minWordCount, ok := matchMap["min_word_count"].(int); ok {
+		match.MinWordCount = &minWordCount
+	}
+	if maxWordCount, ok := matchMap["max_word_count"].(int); ok {
+		match.MaxWordCount = &maxWordCount
+	}
+	if maxIntentDepth, ok := matchMap["max_intent_depth"].(int); ok {
+		match.MaxIntentDepth = &maxIntentDepth
+	}
+
 	return match
 }
 
-// LoadPartial loads config from YAML bytes with partial parsing support
+// FieldString returns a tool_input or match.fields value as the string it's compared as:
+// strings as is, booleans as "true" or "false", numbers in their shortest decimal form and
+// anything else as JSON
+func FieldString(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case uint64:
+		return strconv.FormatUint(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case json.Number:
+		return v.String()
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+// LoadPartial loads config from YAML bytes with partial parsing support, applying the
+// profile selected by BUMPERS_PROFILE
 func LoadPartial(data []byte) (*PartialConfig, error) {
+	return LoadSource(&Source{Data: data})
+}
+
+// LoadSource loads a config read with ReadSource like LoadPartial, with the rules of a
+// merged config keeping the file and position they came from
+func LoadSource(source *Source) (*PartialConfig, error) {
+	data := source.Data
 	var config Config
-	if err := yaml.Unmarshal(data, &config); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
+	var root yaml.Node
+	if err := yaml.NewDecoder(bytes.NewReader(data)).Decode(&root); err != nil && !errors.Is(err, io.EOF) {
+		return nil, newParseError("", err)
+	}
+	if root.Kind != 0 {
+		if err := root.Decode(&config); err != nil {
+			return nil, newParseError("", err)
+		}
+	}
+	config.restoreRuleOrigins(source.origins)
+	config.applyLocale()
+	// Rules read from one file get their positions here, merged files already have origins
+	config.SetRuleOrigins("")
+	if err := config.ApplyProfile(selectedProfile()); err != nil {
+		return nil, err
 	}
 
 	// Use partial validation to collect errors instead of failing
 	validConfig, warnings := config.ValidatePartial()
+	nodes := ruleNodes(&root)
+	for i := range warnings {
+		if index := warnings[i].RuleIndex; index < len(nodes) {
+			warnings[i].Line = nodes[index].Line
+			warnings[i].Column = nodes[index].Column
+		}
+	}
+
+	commandWarnings := config.DuplicateCommandNames()
+	if config.Settings.Strict && len(commandWarnings) > 0 {
+		return nil, fmt.Errorf("strict mode: %s", commandWarnings[0])
+	}
 
 	return &PartialConfig{
 		Config:             validConfig,
 		ValidationWarnings: warnings,
+		CommandWarnings:    commandWarnings,
+		RuleWarnings:       config.ShadowedRules(),
 	}, nil
 }
 
+// ruleNodes returns the node of each entry in the rules list of a parsed config document
+func ruleNodes(root *yaml.Node) []*yaml.Node {
+	if root.Kind != yaml.DocumentNode || len(root.Content) == 0 {
+		return nil
+	}
+	mapping := root.Content[0]
+	if mapping.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == "rules" && mapping.Content[i+1].Kind == yaml.SequenceNode {
+			return mapping.Content[i+1].Content
+		}
+	}
+	return nil
+}
+
 // ValidatePartial performs validation and returns valid config with warnings for invalid rules
 func (c *Config) ValidatePartial() (Config, []ValidationWarning) {
 	var validRules []Rule
 	var warnings []ValidationWarning
 
-	// Validate each rule separately
+	// Validate each rule separately; a rule reusing an earlier rule's id is invalid
+	ids := make(map[string]int)
 	for i := range c.Rules {
 		rule := &c.Rules[i]
-		if err := rule.Validate(); err != nil {
+		err := rule.Validate()
+		if err == nil && rule.ID != "" {
+			if j, ok := ids[rule.ID]; ok {
+				err = fmt.Errorf("duplicate id '%s': already used by rule %d", rule.ID, j+1)
+			} else {
+				ids[rule.ID] = i
+			}
+		}
+		if err != nil {
 			warnings = append(warnings, ValidationWarning{
 				RuleIndex: i,
 				Rule:      *rule,
 				Error:     err,
 			})
-		} else {
+		} else if _, active := rule.EffectiveMatch(); active && rule.IsEnabled() {
 			validRules = append(validRules, *rule)
 		}
 	}
 
 	validConfig := Config{
-		Rules:    validRules,
-		Commands: c.Commands,
-		Session:  c.Session,
+		Rules:         validRules,
+		Commands:      c.Commands,
+		Session:       c.Session,
+		Notifications: c.Notifications,
+		Settings:      c.Settings,
+		Allow:         c.Allow,
+		Profiles:      c.Profiles,
+		Tests:         c.Tests,
+		Output:        c.Output,
 	}
 
 	return validConfig, warnings
@@ -306,6 +1309,18 @@ func parseGenerateField(generateField any, defaultMode string) Generate {
 		if prompt, ok := generateMap["prompt"].(string); ok {
 			gen.Prompt = prompt
 		}
+		if extra, ok := generateMap["cache_key_extra"].(string); ok {
+			gen.CacheKeyExtra = extra
+		}
+		if fallback, ok := generateMap["fallback_message"].(string); ok {
+			gen.FallbackMessage = fallback
+		}
+		if onError, ok := generateMap["on_error"].(string); ok {
+			gen.OnError = onError
+		}
+		if errorMessage, ok := generateMap["error_message"].(string); ok {
+			gen.ErrorMessage = errorMessage
+		}
 		if gen.Mode == "" {
 			gen.Mode = defaultMode
 		}
@@ -320,6 +1335,19 @@ func (c *Command) GetGenerate() Generate {
 	return parseGenerateField(c.Generate, "off")
 }
 
+// Matches reports whether name is the command's name or one of its aliases
+func (c *Command) Matches(name string) bool {
+	if c.Name == name {
+		return true
+	}
+	for _, alias := range c.Aliases {
+		if alias == name {
+			return true
+		}
+	}
+	return false
+}
+
 // GetGenerate converts the interface{} Generate field to a Generate struct for Session
 func (s *Session) GetGenerate() Generate {
 	return parseGenerateField(s.Generate, "off")
@@ -338,20 +1366,6 @@ func convertSourcesSlice(sources []any) ([]string, error) {
 	return result, nil
 }
 
-// Save writes config to file with proper YAML formatting
-func (c *Config) Save(path string) error {
-	data, err := yaml.Marshal(c)
-	if err != nil {
-		return fmt.Errorf("failed to marshal config: %w", err)
-	}
-
-	if err := os.WriteFile(path, data, 0o600); err != nil {
-		return fmt.Errorf("failed to write config: %w", err)
-	}
-
-	return nil
-}
-
 // AddRule appends a rule to the config
 func (c *Config) AddRule(rule Rule) {
 	c.Rules = append(c.Rules, rule)
@@ -368,12 +1382,16 @@ func (c *Config) DeleteRule(index int) error {
 	return nil
 }
 
-// UpdateRule replaces a rule at the specified index
+// UpdateRule replaces a rule at the specified index, keeping its id unless rule sets one
 func (c *Config) UpdateRule(index int, rule Rule) error {
 	if index < 0 || index >= len(c.Rules) {
 		return fmt.Errorf("invalid index %d: must be between 1 and %d", index+1, len(c.Rules))
 	}
 
+	// Keep the rule's id so references to it survive the edit
+	if rule.ID == "" {
+		rule.ID = c.Rules[index].ID
+	}
 	c.Rules[index] = rule
 	return nil
 }
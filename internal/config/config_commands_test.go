@@ -1,6 +1,7 @@
 package config
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -133,6 +134,28 @@ func TestCommandGenerateFullForm(t *testing.T) {
 	}
 }
 
+// Test Generate cache_key_extra is parsed from the full form
+func TestCommandGenerateCacheKeyExtra(t *testing.T) {
+	t.Parallel()
+
+	yamlContent := `commands:
+  - name: "help"
+    send: "Help message"
+    generate:
+      mode: "once"
+      cache_key_extra: "{{.GitBranch}}"`
+
+	config, err := LoadFromYAML([]byte(yamlContent))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	generate := config.Commands[0].GetGenerate()
+	if generate.CacheKeyExtra != "{{.GitBranch}}" {
+		t.Errorf("Expected Generate.CacheKeyExtra to be '{{.GitBranch}}', got %s", generate.CacheKeyExtra)
+	}
+}
+
 // Test Session Generate field defaults to session
 func TestSessionGenerateFieldDefaultToOff(t *testing.T) {
 	t.Parallel()
@@ -197,6 +220,63 @@ func TestSessionGenerateFullForm(t *testing.T) {
 	}
 }
 
+func TestSessionSources(t *testing.T) {
+	t.Parallel()
+
+	config, err := LoadFromYAML([]byte(`settings:
+  session_sources: ["startup"]
+session:
+  - add: "Welcome back"
+    sources: ["resume", "compact"]
+  - add: "Fresh start"`))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	tests := []struct {
+		source string
+		note   int
+		want   bool
+	}{
+		{note: 0, source: "resume", want: true},
+		{note: 0, source: "compact", want: true},
+		{note: 0, source: "startup", want: false},
+		{note: 1, source: "startup", want: true},
+		{note: 1, source: "clear", want: false},
+	}
+	for _, tt := range tests {
+		if got := config.Session[tt.note].ShowsOn(tt.source, &config.Settings); got != tt.want {
+			t.Errorf("Expected note %d ShowsOn(%q) = %v, got %v", tt.note, tt.source, tt.want, got)
+		}
+	}
+
+	_, err = LoadFromYAML([]byte(`session:
+  - add: "Session note"
+    sources: ["restart"]`))
+	if err == nil || !strings.Contains(err.Error(), "invalid sources entry 'restart'") {
+		t.Errorf("Expected invalid sources error, got %v", err)
+	}
+}
+
+func TestSessionAddFile(t *testing.T) {
+	t.Parallel()
+
+	config, err := LoadFromYAML([]byte(`session:
+  - add_file: "CLAUDE.md"`))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if config.Session[0].AddFile != "CLAUDE.md" {
+		t.Errorf("Expected AddFile to be 'CLAUDE.md', got %s", config.Session[0].AddFile)
+	}
+
+	_, err = LoadFromYAML([]byte(`session:
+  - add: "Session note"
+    add_file: "CLAUDE.md"`))
+	if err == nil || !strings.Contains(err.Error(), "add and add_file cannot both be set") {
+		t.Errorf("Expected add/add_file conflict error, got %v", err)
+	}
+}
+
 func TestConfigWithNotes(t *testing.T) {
 	t.Parallel()
 
@@ -296,3 +376,167 @@ func TestDefaultConfigCommandsHaveNames(t *testing.T) {
 		}
 	}
 }
+
+func TestCommandAliases(t *testing.T) {
+	t.Parallel()
+
+	config := loadConfigFromYAML(t, `commands:
+  - name: "help"
+    aliases: ["h", "?"]
+    send: "Help text"`)
+
+	cmd := &config.Commands[0]
+	require.Equal(t, []string{"h", "?"}, cmd.Aliases)
+	for _, name := range []string{"help", "h", "?"} {
+		require.True(t, cmd.Matches(name), "Expected %q to match", name)
+	}
+	require.False(t, cmd.Matches("status"))
+}
+
+func TestCommandAliasesMustBeUnique(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		yaml string
+	}{
+		{
+			name: "alias duplicates another command name",
+			yaml: `commands:
+  - name: "help"
+    send: "Help"
+  - name: "status"
+    aliases: ["help"]
+    send: "Status"`,
+		},
+		{
+			name: "alias duplicates another alias",
+			yaml: `commands:
+  - name: "help"
+    aliases: ["h"]
+    send: "Help"
+  - name: "history"
+    aliases: ["h"]
+    send: "History"`,
+		},
+		{
+			name: "alias duplicates own name",
+			yaml: `commands:
+  - name: "help"
+    aliases: ["help"]
+    send: "Help"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := LoadFromYAML([]byte(tt.yaml))
+			require.Error(t, err)
+			require.Contains(t, err.Error(), "conflicts with another command name or alias")
+		})
+	}
+}
+
+func TestDuplicateCommandNamesWarn(t *testing.T) {
+	t.Parallel()
+
+	yamlContent := `commands:
+  - name: "help"
+    send: "Help"
+  - name: "status"
+    send: "Status"
+  - name: "help"
+    send: "Other help"`
+
+	partial, err := LoadPartial([]byte(yamlContent))
+	require.NoError(t, err)
+	require.Len(t, partial.CommandWarnings, 1)
+	require.Contains(t, partial.CommandWarnings[0], "command 3 name 'help' duplicates command 1")
+
+	// Non-strict configs still load
+	_, err = LoadFromYAML([]byte(yamlContent))
+	require.NoError(t, err)
+}
+
+func TestDuplicateCommandNamesStrict(t *testing.T) {
+	t.Parallel()
+
+	yamlContent := `settings:
+  strict: true
+commands:
+  - name: "help"
+    send: "Help"
+  - name: "help"
+    send: "Other help"`
+
+	_, err := LoadPartial([]byte(yamlContent))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "duplicates command 1")
+
+	_, err = LoadFromYAML([]byte(yamlContent))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "strict mode")
+}
+
+func TestGenerateFallbackMessage(t *testing.T) {
+	t.Parallel()
+
+	yamlContent := `commands:
+  - name: "help"
+    send: "Help message"
+    generate:
+      mode: "always"
+      fallback_message: "AI unavailable"`
+
+	config, err := LoadFromYAML([]byte(yamlContent))
+	require.NoError(t, err)
+	require.Equal(t, "AI unavailable", config.Commands[0].GetGenerate().FallbackMessage)
+}
+
+func TestGenerateOnError(t *testing.T) {
+	t.Parallel()
+
+	config, err := LoadFromYAML([]byte(`rules:
+  - match: "^go test"
+    send: "Use just test"
+    generate:
+      mode: "always"
+      on_error: "message"
+      error_message: "Claude is offline"`))
+	require.NoError(t, err)
+
+	generate := config.Rules[0].GetGenerate()
+	require.Equal(t, OnErrorMessage, generate.OnError)
+	require.Equal(t, "Claude is offline", generate.ErrorMessage)
+
+	_, err = LoadFromYAML([]byte(`rules:
+  - match: "^go test"
+    send: "Use just test"
+    generate:
+      mode: "always"
+      on_error: "ignore"`))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid on_error 'ignore'")
+}
+
+func TestGenerateOnErrorMessage(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		generate Generate
+		fallback string
+		want     string
+	}{
+		{generate: Generate{}, fallback: "Use just", want: "Use just"},
+		{generate: Generate{OnError: OnErrorFallback}, fallback: "Use just", want: "Use just"},
+		{generate: Generate{OnError: OnErrorMessage}, fallback: "Use just", want: DefaultAIErrorMessage},
+		{generate: Generate{OnError: OnErrorAppend}, fallback: "Use just", want: "Use just " + DefaultAIErrorSuffix},
+		{generate: Generate{OnError: OnErrorAppend}, fallback: "", want: DefaultAIErrorSuffix},
+		{generate: Generate{OnError: OnErrorAppend, ErrorMessage: "[offline]"}, fallback: "Use just", want: "Use just [offline]"},
+	}
+
+	for _, tt := range tests {
+		require.Equal(t, tt.want, tt.generate.OnErrorMessage(tt.fallback), tt.generate.OnError)
+	}
+}
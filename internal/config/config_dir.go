@@ -0,0 +1,294 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// configDirExtensions are the file extensions loaded from a config directory
+var configDirExtensions = map[string]bool{".yml": true, ".yaml": true, ".json": true}
+
+// DirFiles returns the config files in dir, sorted by name
+func DirFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config directory %s: %w", dir, err)
+	}
+
+	var files []string
+	for _, entry := range entries {
+		if entry.IsDir() || !configDirExtensions[strings.ToLower(filepath.Ext(entry.Name()))] {
+			continue
+		}
+		files = append(files, filepath.Join(dir, entry.Name()))
+	}
+	sort.Strings(files)
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no config files found in %s", dir)
+	}
+	return files, nil
+}
+
+// LoadDirRaw reads and merges every config file in dir without validating the result.
+// Lists are concatenated in file name order and later files override settings.
+func LoadDirRaw(dir string) (*Config, error) {
+	files, err := DirFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := &Config{}
+	for _, file := range files {
+		data, err := os.ReadFile(file) // #nosec G304 -- file is listed from the config directory
+		if err != nil {
+			return nil, fmt.Errorf("failed to read config %s: %w", file, readError(err))
+		}
+
+		var cfg Config
+		if err := yaml.Unmarshal(data, &cfg); err != nil {
+			return nil, newParseError(file, err)
+		}
+		cfg.SetRuleOrigins(file)
+		merged.merge(&cfg)
+	}
+	return merged, nil
+}
+
+// JoinPaths combines config files into a single config path, listed from highest to
+// lowest precedence. ReadData and SourceStat treat the result as one merged config.
+func JoinPaths(files []string) string {
+	return strings.Join(files, string(filepath.ListSeparator))
+}
+
+// splitPaths returns the files in a path built by JoinPaths, or nil for a single path
+func splitPaths(path string) []string {
+	if !strings.ContainsRune(path, filepath.ListSeparator) {
+		return nil
+	}
+	return filepath.SplitList(path)
+}
+
+// LoadFilesRaw reads and merges config files listed from highest to lowest precedence,
+// without validating the result. Lists are concatenated so higher precedence entries come
+// first, and settings from higher precedence files override lower ones.
+func LoadFilesRaw(files []string) (*Config, error) {
+	configs := make([]*Config, 0, len(files))
+	for _, file := range files {
+		data, err := os.ReadFile(file) // #nosec G304 -- file is one of the user's config files
+		if err != nil {
+			return nil, fmt.Errorf("failed to read config %s: %w", file, readError(err))
+		}
+
+		var cfg Config
+		if err := yaml.Unmarshal(data, &cfg); err != nil {
+			return nil, newParseError(file, err)
+		}
+		cfg.SetRuleOrigins(file)
+		configs = append(configs, &cfg)
+	}
+
+	merged := &Config{}
+	for _, cfg := range configs {
+		merged.appendLists(cfg)
+	}
+	for i := len(configs) - 1; i >= 0; i-- {
+		merged.mergeSettings(configs[i])
+	}
+	return merged, nil
+}
+
+// merge appends other's lists and applies its non-zero settings
+func (c *Config) merge(other *Config) {
+	c.appendLists(other)
+	c.mergeSettings(other)
+}
+
+// appendLists appends other's rules, commands, session notes, notifications, allow list,
+// tests and profile rules
+func (c *Config) appendLists(other *Config) {
+	c.Rules = append(c.Rules, other.Rules...)
+	c.Commands = append(c.Commands, other.Commands...)
+	c.Session = append(c.Session, other.Session...)
+	c.Notifications = append(c.Notifications, other.Notifications...)
+	c.Allow = append(c.Allow, other.Allow...)
+	c.Tests = append(c.Tests, other.Tests...)
+	c.appendProfiles(other)
+}
+
+// mergeSettings applies other's non-zero settings and output options
+func (c *Config) mergeSettings(other *Config) {
+	if other.Settings.OnEmptyMessage != "" {
+		c.Settings.OnEmptyMessage = other.Settings.OnEmptyMessage
+	}
+	if other.Settings.MaxIntentTokens != 0 {
+		c.Settings.MaxIntentTokens = other.Settings.MaxIntentTokens
+	}
+	if other.Settings.Locale != "" {
+		c.Settings.Locale = other.Settings.Locale
+	}
+	if other.Settings.IntentScanLines != 0 {
+		c.Settings.IntentScanLines = other.Settings.IntentScanLines
+	}
+	if other.Settings.MaxMatchBytes != 0 {
+		c.Settings.MaxMatchBytes = other.Settings.MaxMatchBytes
+	}
+	if other.Settings.MaxDisplayBytes != 0 {
+		c.Settings.MaxDisplayBytes = other.Settings.MaxDisplayBytes
+	}
+	if other.Settings.HookTimeout != "" {
+		c.Settings.HookTimeout = other.Settings.HookTimeout
+	}
+	if other.Settings.ToolPolicy != "" {
+		c.Settings.ToolPolicy = other.Settings.ToolPolicy
+	}
+	if other.Settings.ToolDeniedMessage != "" {
+		c.Settings.ToolDeniedMessage = other.Settings.ToolDeniedMessage
+	}
+	if other.Settings.RequiredVersion != "" {
+		c.Settings.RequiredVersion = other.Settings.RequiredVersion
+	}
+	if other.Settings.AuditLog != "" {
+		c.Settings.AuditLog = other.Settings.AuditLog
+	}
+	if other.Settings.WebhookURL != "" {
+		c.Settings.WebhookURL = other.Settings.WebhookURL
+	}
+	if other.Settings.ShowRuleSource {
+		c.Settings.ShowRuleSource = true
+	}
+	if len(other.Settings.SessionSources) > 0 {
+		c.Settings.SessionSources = other.Settings.SessionSources
+	}
+	c.Settings.AllowedTools = append(c.Settings.AllowedTools, other.Settings.AllowedTools...)
+	if other.Settings.NotificationHook {
+		c.Settings.NotificationHook = true
+	}
+	if other.Settings.Strict {
+		c.Settings.Strict = true
+	}
+	c.Settings.LogRedactPatterns = append(c.Settings.LogRedactPatterns, other.Settings.LogRedactPatterns...)
+	c.Settings.Redact = append(c.Settings.Redact, other.Settings.Redact...)
+	if other.Output.Select != "" {
+		c.Output.Select = other.Output.Select
+	}
+}
+
+// ReadData returns the raw config bytes for path. When path is a directory, or a list of
+// files from JoinPaths, the files are merged and returned as a single YAML document.
+// EnvConfigPath returns the config built from environment variables.
+func ReadData(path string) ([]byte, error) {
+	source, err := ReadSource(path)
+	if err != nil {
+		return nil, err
+	}
+	return source.Data, nil
+}
+
+// Source is a config read by ReadSource
+type Source struct {
+	// Data is the config as ReadData returns it
+	Data []byte
+	// origins are the origins of a merged config's rules, which Data leaves out
+	origins *ruleOrigins
+}
+
+// ReadSource reads the config at path like ReadData, keeping the origins of the rules of a
+// merged config for LoadSource
+func ReadSource(path string) (*Source, error) {
+	cfg, combined, err := loadCombinedRaw(path)
+	if err != nil {
+		return nil, err
+	}
+	if !combined {
+		data, readErr := os.ReadFile(path) // #nosec G304 -- path is the user's config file
+		if readErr != nil {
+			return nil, fmt.Errorf("failed to read config: %w", readError(readErr))
+		}
+		return &Source{Data: data}, nil
+	}
+
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal merged config: %w", err)
+	}
+	return &Source{Data: data, origins: cfg.ruleOrigins()}, nil
+}
+
+// IsMerged reports whether path names a config assembled from several sources, whose
+// ReadData bytes don't line up with any one file
+func IsMerged(path string) bool {
+	if path == EnvConfigPath || splitPaths(path) != nil {
+		return true
+	}
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// loadCombinedRaw loads a config assembled from the environment, a JoinPaths list or a
+// directory, reporting false when path is a single config file
+func loadCombinedRaw(path string) (cfg *Config, combined bool, err error) {
+	switch {
+	case path == EnvConfigPath:
+		cfg, err = loadRawFromEnv()
+	case splitPaths(path) != nil:
+		cfg, err = LoadFilesRaw(splitPaths(path))
+	default:
+		info, statErr := os.Stat(path)
+		if statErr != nil || !info.IsDir() {
+			return nil, false, nil
+		}
+		cfg, err = LoadDirRaw(path)
+	}
+	return cfg, true, err
+}
+
+// SourceStat returns the latest modification time and total size of the config at path,
+// covering every config file when path is a directory or a list of files
+func SourceStat(path string) (modTime time.Time, size int64, err error) {
+	// The environment can't change under a running process
+	if path == EnvConfigPath {
+		return time.Time{}, 0, nil
+	}
+	if files := splitPaths(path); files != nil {
+		return filesStat(files, time.Time{})
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}, 0, fmt.Errorf("failed to stat config %s: %w", path, err)
+	}
+	if !info.IsDir() {
+		return info.ModTime(), info.Size(), nil
+	}
+
+	files, err := DirFiles(path)
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+
+	// Include the directory itself so added or removed files are noticed
+	return filesStat(files, info.ModTime())
+}
+
+// filesStat returns the latest modification time of files, or since if later, and their total size
+func filesStat(files []string, since time.Time) (modTime time.Time, size int64, err error) {
+	modTime = since
+	for _, file := range files {
+		fileInfo, statErr := os.Stat(file)
+		if statErr != nil {
+			return time.Time{}, 0, fmt.Errorf("failed to stat config %s: %w", file, statErr)
+		}
+		if fileInfo.ModTime().After(modTime) {
+			modTime = fileInfo.ModTime()
+		}
+		size += fileInfo.Size()
+	}
+	return modTime, size, nil
+}
@@ -0,0 +1,111 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfigDir(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+	}
+	return dir
+}
+
+func TestLoadConfigDirMergesFilesInNameOrder(t *testing.T) {
+	t.Parallel()
+
+	dir := writeConfigDir(t, map[string]string{
+		"20-go.yaml": `rules:
+  - match: "^go test"
+    send: "Use just test"
+settings:
+  on_empty_message: allow`,
+		"10-git.yml": `rules:
+  - match: "git commit --no-verify"
+    send: "Do not skip hooks"
+commands:
+  - name: "help"
+    send: "Help"`,
+		"30-rm.json": `{"rules": [{"match": "rm -rf", "send": "Use safer deletion"}]}`,
+		"notes.txt":  "not a config file",
+	})
+
+	cfg, err := Load(dir)
+	require.NoError(t, err)
+
+	require.Len(t, cfg.Rules, 3)
+	assert.Equal(t, "git commit --no-verify", cfg.Rules[0].GetMatch().Pattern)
+	assert.Equal(t, "^go test", cfg.Rules[1].GetMatch().Pattern)
+	assert.Equal(t, "rm -rf", cfg.Rules[2].GetMatch().Pattern)
+	require.Len(t, cfg.Commands, 1)
+	assert.Equal(t, OnEmptyMessageAllow, cfg.Settings.OnEmptyMessage)
+}
+
+func TestLoadConfigDirErrors(t *testing.T) {
+	t.Parallel()
+
+	_, err := Load(t.TempDir())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no config files found")
+
+	dir := writeConfigDir(t, map[string]string{"bad.yml": "rules: [\n"})
+	_, err = Load(dir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "bad.yml")
+}
+
+func TestSourceStatTracksFilesInDir(t *testing.T) {
+	t.Parallel()
+
+	dir := writeConfigDir(t, map[string]string{
+		"a.yml": "rules:\n  - match: a\n    send: a\n",
+		"b.yml": "rules:\n  - match: b\n    send: b\n",
+	})
+
+	_, size, err := SourceStat(dir)
+	require.NoError(t, err)
+	assert.Positive(t, size)
+
+	future := time.Now().Add(time.Hour)
+	require.NoError(t, os.Chtimes(filepath.Join(dir, "b.yml"), future, future))
+
+	modTime, _, err := SourceStat(dir)
+	require.NoError(t, err)
+	assert.True(t, modTime.Equal(future), "expected latest file mtime, got %v", modTime)
+}
+
+func TestLoadJoinedPathsMergesByPrecedence(t *testing.T) {
+	t.Parallel()
+
+	dir := writeConfigDir(t, map[string]string{
+		"bumpers.yml": `rules:
+  - match: "^go test"
+    send: "Use just test"
+settings:
+  max_intent_tokens: 500`,
+		"bumpers.json": `{"rules": [{"match": "rm -rf", "send": "Use safer deletion"}],
+  "settings": {"max_intent_tokens": 100, "on_empty_message": "allow"}}`,
+	})
+	path := JoinPaths([]string{filepath.Join(dir, "bumpers.yml"), filepath.Join(dir, "bumpers.json")})
+
+	cfg, err := Load(path)
+	require.NoError(t, err)
+
+	require.Len(t, cfg.Rules, 2)
+	assert.Equal(t, "^go test", cfg.Rules[0].GetMatch().Pattern, "higher precedence rules come first")
+	assert.Equal(t, "rm -rf", cfg.Rules[1].GetMatch().Pattern)
+	assert.Equal(t, 500, cfg.Settings.MaxIntentTokens, "higher precedence settings win")
+	assert.Equal(t, OnEmptyMessageAllow, cfg.Settings.OnEmptyMessage, "unset settings fall through")
+
+	_, size, err := SourceStat(path)
+	require.NoError(t, err)
+	assert.Positive(t, size)
+}
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -183,6 +184,26 @@ func TestLoadConfigFromFile(t *testing.T) {
 	}
 }
 
+func TestLoadForEditAcceptsEmptyConfig(t *testing.T) {
+	t.Parallel()
+
+	configPath := filepath.Join(t.TempDir(), "bumpers.yml")
+	if err := os.WriteFile(configPath, []byte("settings:\n  locale: en\nrules: []\n"), 0o600); err != nil {
+		t.Fatalf("Failed to create temp config: %v", err)
+	}
+
+	if _, err := Load(configPath); !errors.Is(err, ErrEmptyConfig) {
+		t.Errorf("Expected Load to fail with ErrEmptyConfig, got %v", err)
+	}
+	config, err := LoadForEdit(configPath)
+	if err != nil {
+		t.Fatalf("Expected LoadForEdit to accept an empty config, got %v", err)
+	}
+	if config.Settings.Locale != "en" {
+		t.Errorf("Expected settings to be loaded, got locale %q", config.Settings.Locale)
+	}
+}
+
 func TestGenerateFieldAsString(t *testing.T) {
 	t.Parallel()
 
@@ -312,6 +333,14 @@ func testValidConfigs(t *testing.T) {
     send: "Use just test instead"`,
 			expectError: false,
 		},
+		{
+			name: "empty pattern soft-disables the rule",
+			yamlContent: `rules:
+  - match:
+      pattern: ""
+    send: "Empty pattern"`,
+			expectError: false,
+		},
 		{
 			name: "valid generate with prompt",
 			yamlContent: `rules:
@@ -330,6 +359,15 @@ func testValidConfigs(t *testing.T) {
     send: "Use just test instead"`,
 			expectError: false,
 		},
+		{
+			name: "notifications only",
+			yamlContent: `settings:
+  notification_hook: true
+notifications:
+  - match: "permission"
+    send: "Remember: use just, not make"`,
+			expectError: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -356,11 +394,11 @@ func testInvalidConfigs(t *testing.T) {
 			errorContains: "must contain at least one rule",
 		},
 		{
-			name: "empty pattern",
+			name: "missing pattern",
 			yamlContent: `rules:
   - match:
-      pattern: ""
-    send: "Empty pattern"`,
+      event: pre
+    send: "Missing pattern"`,
 			expectError:   true,
 			errorContains: "match field is required",
 		},
@@ -394,6 +432,14 @@ func testInvalidConfigs(t *testing.T) {
 			expectError:   true,
 			errorContains: "rule 2 validation failed",
 		},
+		{
+			name: "notification with invalid regex",
+			yamlContent: `notifications:
+  - match: "[invalid"
+    send: "Invalid notification"`,
+			expectError:   true,
+			errorContains: "notification 1 validation failed",
+		},
 	}
 
 	for _, tt := range tests {
@@ -480,14 +526,15 @@ func TestPartialConfigLoading(t *testing.T) {
 		t.Fatalf("Expected LoadPartial to succeed even with invalid rules, got %v", err)
 	}
 
-	// Should have 2 valid rules (go test and rm -rf)
+	// Should have 2 active rules (go test and rm -rf); the empty pattern rule is
+	// soft-disabled rather than invalid
 	if len(partialConfig.Rules) != 2 {
 		t.Errorf("Expected 2 valid rules, got %d", len(partialConfig.Rules))
 	}
 
-	// Should have 2 warnings (invalid regex and empty pattern)
-	if len(partialConfig.ValidationWarnings) != 2 {
-		t.Errorf("Expected 2 validation warnings, got %d", len(partialConfig.ValidationWarnings))
+	// Should have 1 warning (invalid regex)
+	if len(partialConfig.ValidationWarnings) != 1 {
+		t.Errorf("Expected 1 validation warning, got %d", len(partialConfig.ValidationWarnings))
 	}
 
 	// Verify the valid rules are the correct ones
@@ -498,6 +545,15 @@ func TestPartialConfigLoading(t *testing.T) {
 		}
 	}
 
+	// Warnings point at each invalid rule's entry in the rules list
+	expectedLines := []int{5}
+	for i, warning := range partialConfig.ValidationWarnings {
+		if warning.Line != expectedLines[i] || warning.Column != 5 {
+			t.Errorf("Expected warning %d at line %d, column 5, got line %d, column %d",
+				i, expectedLines[i], warning.Line, warning.Column)
+		}
+	}
+
 	// Debug: print warnings
 	for i, warning := range partialConfig.ValidationWarnings {
 		t.Logf("Warning %d: Rule index %d, pattern '%s', error: %v",
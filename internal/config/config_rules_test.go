@@ -594,3 +594,546 @@ func TestUpdateRule(t *testing.T) {
 	require.Error(t, err, "Should error on index too large")
 	require.Contains(t, err.Error(), "must be between 1 and", "Error should show 1-indexed ranges")
 }
+
+func TestMatchStripEnv(t *testing.T) {
+	t.Parallel()
+
+	config, err := LoadFromYAML([]byte(`rules:
+  - match:
+      pattern: "^make deploy"
+      strip_env: true
+    send: "Use just deploy"
+  - match: "^make"
+    send: "Use just"`))
+	require.NoError(t, err)
+	require.Len(t, config.Rules, 2)
+
+	assert.True(t, config.Rules[0].GetMatch().StripEnv)
+	assert.False(t, config.Rules[1].GetMatch().StripEnv)
+}
+
+func TestRuleResolvePaths(t *testing.T) {
+	t.Parallel()
+
+	config, err := LoadFromYAML([]byte(`rules:
+  - match:
+      pattern: "/secrets/"
+      resolve_paths: true
+    tool: "^(Read|Edit)$"
+    send: "Secrets are off limits"`))
+	require.NoError(t, err)
+	assert.True(t, config.Rules[0].GetMatch().ResolvePaths)
+
+	_, err = LoadFromYAML([]byte(`rules:
+  - match:
+      pattern: "/secrets/"
+      event: "post"
+      resolve_paths: true
+    send: "Secrets are off limits"`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "resolve_paths is only supported on 'pre' event rules")
+}
+
+func TestRuleShadow(t *testing.T) {
+	t.Parallel()
+
+	config, err := LoadFromYAML([]byte(`rules:
+  - match:
+      pattern: "^git push"
+      shadow: true
+    send: "Open a PR instead"`))
+	require.NoError(t, err)
+	assert.True(t, config.Rules[0].GetMatch().Shadow)
+
+	_, err = LoadFromYAML([]byte(`rules:
+  - match:
+      pattern: "startup"
+      event: "session"
+      shadow: true
+    send: "Hello"`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "shadow is only supported on 'pre' and 'post' event rules")
+
+	_, err = LoadFromYAML([]byte(`rules:
+  - match:
+      pattern: "^git push"
+      shadow: true
+    send: "Open a PR instead"
+    approval: session`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "approval can't be used on shadow rules")
+}
+
+func TestRuleReplace(t *testing.T) {
+	t.Parallel()
+
+	config, err := LoadFromYAML([]byte(`rules:
+  - match: "^go test"
+    send: "Use just test"
+    replace: "just test"`))
+	require.NoError(t, err)
+	assert.Equal(t, "just test", config.Rules[0].Replace)
+
+	_, err = LoadFromYAML([]byte(`rules:
+  - match:
+      pattern: "FAIL"
+      event: "post"
+    send: "Tests failed"
+    replace: "just test"`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "replace is only supported on 'pre' event rules")
+}
+
+func TestSessionEventRule(t *testing.T) {
+	t.Parallel()
+
+	config, err := LoadFromYAML([]byte(`rules:
+  - match:
+      pattern: "^resume$"
+      event: "session"
+    send: "Welcome back"`))
+	require.NoError(t, err)
+	assert.Equal(t, EventSession, config.Rules[0].GetMatch().Event)
+
+	_, err = LoadFromYAML([]byte(`rules:
+  - match:
+      pattern: "x"
+      event: "stop"
+    send: "Nope"`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "must be 'pre', 'post' or 'session'")
+}
+
+func TestSendListForm(t *testing.T) {
+	t.Parallel()
+
+	config, err := LoadFromYAML([]byte(`rules:
+  - match: "^go test"
+    send:
+      - "Use just test"
+      - "It sets up the test database"
+commands:
+  - name: "help"
+    send: ["Line one", "Line two"]
+notifications:
+  - match: "permission"
+    send:
+      - "Check the allow list"`))
+	require.NoError(t, err)
+	assert.Equal(t, "Use just test\nIt sets up the test database", config.Rules[0].Send)
+	assert.Equal(t, "Line one\nLine two", config.Commands[0].Send)
+	assert.Equal(t, "Check the allow list", config.Notifications[0].Send)
+
+	_, err = LoadFromYAML([]byte(`rules:
+  - match: "x"
+    send:
+      - nested: "map"`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "send list entries must be strings")
+}
+
+func TestRuleExec(t *testing.T) {
+	t.Parallel()
+
+	config, err := LoadFromYAML([]byte(`rules:
+  - match: "\\.env$"
+    tool: "^Edit$"
+    send: "Don't edit .env"
+    exec: "./scripts/backup-env.sh"`))
+	require.NoError(t, err)
+	assert.Equal(t, "./scripts/backup-env.sh", config.Rules[0].Exec)
+
+	_, err = LoadFromYAML([]byte(`rules:
+  - match:
+      pattern: "startup"
+      event: "session"
+    send: "Hello"
+    exec: "./scripts/on-start.sh"`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "exec is only supported on 'pre' and 'post' event rules")
+}
+
+func TestMatchArgLimits(t *testing.T) {
+	t.Parallel()
+
+	config, err := LoadFromYAML([]byte(`rules:
+  - match:
+      pattern: "^git push"
+      max_args: 0
+    send: "Name the remote and branch"`))
+	require.NoError(t, err)
+	match := config.Rules[0].GetMatch()
+	require.NotNil(t, match.MaxArgs)
+	assert.Equal(t, 0, *match.MaxArgs)
+	assert.Nil(t, match.MinArgs)
+	assert.True(t, match.ArgsWithinLimits(0))
+	assert.False(t, match.ArgsWithinLimits(1))
+
+	_, err = LoadFromYAML([]byte(`rules:
+  - match:
+      pattern: "^git push"
+      min_args: 3
+      max_args: 1
+    send: "Nope"`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "must not be greater than max_args")
+}
+
+func TestMatchWordCountLimits(t *testing.T) {
+	t.Parallel()
+
+	config, err := LoadFromYAML([]byte(`rules:
+  - match:
+      pattern: "\\brm\\b"
+      min_word_count: 2
+    send: "Name what to remove"`))
+	require.NoError(t, err)
+	match := config.Rules[0].GetMatch()
+	require.NotNil(t, match.MinWordCount)
+	assert.Nil(t, match.MaxWordCount)
+	assert.True(t, match.HasWordCountLimits())
+	assert.False(t, match.WordCountWithinLimits(1))
+	assert.True(t, match.WordCountWithinLimits(5))
+
+	_, err = LoadFromYAML([]byte(`rules:
+  - match:
+      pattern: "rm"
+      min_word_count: 3
+      max_word_count: 2
+    send: "Nope"`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "must not be greater than max_word_count")
+
+	_, err = LoadFromYAML([]byte(`rules:
+  - match:
+      pattern: "rm"
+      max_word_count: -1
+    send: "Nope"`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid max_word_count -1")
+}
+
+func TestMatchAnchor(t *testing.T) {
+	t.Parallel()
+
+	config, err := LoadFromYAML([]byte(`rules:
+  - match:
+      pattern: "rm|del"
+      anchor: full
+    send: "Name what to remove"
+  - match: "rm"
+    send: "Careful"`))
+	require.NoError(t, err)
+	full, plain := config.Rules[0].GetMatch(), config.Rules[1].GetMatch()
+	assert.Equal(t, AnchorFull, full.Anchor)
+	assert.Equal(t, "^(?:rm|del)$", full.AnchoredPattern())
+	assert.Equal(t, "rm", plain.AnchoredPattern())
+
+	_, err = LoadFromYAML([]byte(`rules:
+  - match:
+      pattern: "rm"
+      anchor: end
+    send: "Nope"`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid anchor 'end'")
+}
+
+func TestMatchMaxIntentDepth(t *testing.T) {
+	t.Parallel()
+
+	config, err := LoadFromYAML([]byte(`rules:
+  - match:
+      pattern: "delete everything"
+      sources: ["#intent"]
+      max_intent_depth: 0
+    send: "Ask first"
+  - match: "^rm -rf"
+    send: "Use git clean"`))
+	require.NoError(t, err)
+	deep, shallow := config.Rules[0].GetMatch(), config.Rules[1].GetMatch()
+	assert.Equal(t, 0, deep.GetMaxIntentDepth())
+	assert.Equal(t, DefaultMaxIntentDepth, shallow.GetMaxIntentDepth())
+
+	_, err = LoadFromYAML([]byte(`rules:
+  - match:
+      pattern: "delete"
+      max_intent_depth: -1
+    send: "Nope"`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid max_intent_depth -1")
+}
+
+func TestRuleIDs(t *testing.T) {
+	t.Parallel()
+
+	config, err := LoadFromYAML([]byte(`rules:
+  - id: no-go-test
+    match: "^go test"
+    send: "Use just test"
+  - match: "^rm -rf"
+    send: "Use git clean"`))
+	require.NoError(t, err)
+
+	index, err := config.FindRule("no-go-test")
+	require.NoError(t, err)
+	assert.Equal(t, 0, index)
+
+	index, err = config.FindRule("2")
+	require.NoError(t, err)
+	assert.Equal(t, 1, index)
+
+	_, err = config.FindRule("3")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "must be between 1 and 2")
+
+	_, err = config.FindRule("missing")
+	require.ErrorIs(t, err, ErrRuleNotFound)
+
+	rule := config.Rules[1]
+	id := config.NewRuleID(&rule)
+	assert.Len(t, id, ruleIDLength)
+	assert.Equal(t, id, config.NewRuleID(&rule), "generated ids are stable")
+	_, err = config.FindRule(id)
+	require.ErrorIs(t, err, ErrRuleNotFound, "generated ids are unique")
+
+	config.Rules[1].ID = "no-go-test"
+	err = config.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "rule 2 has the same id 'no-go-test' as rule 1")
+
+	config.Rules[1].ID = "42"
+	err = config.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "must not be a number")
+}
+
+func TestSourceFieldRegex(t *testing.T) {
+	t.Parallel()
+
+	config, err := LoadFromYAML([]byte(`rules:
+  - match:
+      pattern: "\\.env$"
+      source_field_regex: ".*_path$"
+    send: "Don't touch .env files"`))
+	require.NoError(t, err)
+	assert.Equal(t, ".*_path$", config.Rules[0].GetMatch().SourceFieldRegex)
+
+	_, err = LoadFromYAML([]byte(`rules:
+  - match:
+      pattern: "\\.env$"
+      source_field_regex: "(_path"
+    send: "Nope"`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid source_field_regex")
+
+	_, err = LoadFromYAML([]byte(`rules:
+  - match:
+      pattern: "\\.env$"
+      event: post
+      source_field_regex: ".*_path$"
+    send: "Nope"`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "source_field_regex is only supported on 'pre' event rules")
+}
+
+func TestMatchFields(t *testing.T) {
+	t.Parallel()
+
+	config, err := LoadFromYAML([]byte(`rules:
+  - match:
+      pattern: "^prod$"
+      fields: {force: true, replicas: 3, ratio: 1.50, mode: fast}
+    tool: "^Deploy$"
+    send: "Don't force deploys"`))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"force": "true", "replicas": "3", "ratio": "1.5", "mode": "fast"},
+		config.Rules[0].GetMatch().Fields)
+
+	// JSON decodes numbers as float64, which must stringify the same as YAML's ints
+	assert.Equal(t, "3", FieldString(float64(3)))
+	assert.Equal(t, "1.5", FieldString(1.50))
+	assert.Equal(t, "false", FieldString(false))
+	assert.Equal(t, `["a"]`, FieldString([]any{"a"}))
+	assert.Equal(t, "null", FieldString(nil))
+
+	_, err = LoadFromYAML([]byte(`rules:
+  - match:
+      pattern: "^prod$"
+      event: post
+      fields: {force: true}
+    send: "Nope"`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "fields is only supported on 'pre' event rules")
+}
+
+func TestRuleLogMode(t *testing.T) {
+	t.Parallel()
+
+	config, err := LoadFromYAML([]byte(`settings:
+  log_redact_patterns: ["token=\\S+"]
+rules:
+  - match: "^deploy"
+    send: "Use just deploy"
+    log: redact
+  - match: "^rm"
+    send: "No"`))
+	require.NoError(t, err)
+	assert.Equal(t, LogRedact, config.Rules[0].GetLog())
+	assert.Equal(t, LogFull, config.Rules[1].GetLog())
+
+	_, err = LoadFromYAML([]byte(`rules:
+  - match: "^deploy"
+    send: "Use just deploy"
+    log: hidden`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid log 'hidden'")
+
+	_, err = LoadFromYAML([]byte(`settings:
+  log_redact_patterns: ["(token"]
+rules:
+  - match: "^deploy"
+    send: "Use just deploy"`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid log_redact_patterns entry")
+}
+
+func TestRuleActions(t *testing.T) {
+	t.Parallel()
+
+	config, err := LoadFromYAML([]byte(`settings:
+  webhook_url: "https://hooks.example.com/bumpers"
+rules:
+  - match: "^deploy"
+    send: "Use just deploy"
+  - match: "^curl"
+    action: audit
+  - match: "^rm"
+    send: "No"
+    actions: [audit, block, webhook]`))
+	require.NoError(t, err)
+	assert.Equal(t, []string{ActionBlock}, config.Rules[0].GetActions())
+	assert.Equal(t, []string{ActionAudit}, config.Rules[1].GetActions(), "rules without block need no message")
+	assert.Equal(t, []string{ActionAudit, ActionBlock, ActionWebhook}, config.Rules[2].GetActions())
+
+	tests := []struct {
+		yaml    string
+		wantErr string
+	}{
+		{yaml: "rules:\n  - match: x\n    send: y\n    action: notify", wantErr: "invalid action 'notify'"},
+		{yaml: "rules:\n  - match: x\n    send: y\n    action: block\n    actions: [audit]", wantErr: "can't both be set"},
+		{yaml: "rules:\n  - match: x\n    send: y\n    actions: [audit, audit]", wantErr: "duplicate action 'audit'"},
+		{yaml: "rules:\n  - match: x\n    send: y\n    action: webhook", wantErr: "requires settings.webhook_url"},
+		{yaml: "settings:\n  webhook_url: ftp://example.com\nrules:\n  - match: x\n    send: y", wantErr: "invalid webhook_url"},
+	}
+	for _, tt := range tests {
+		_, err := LoadFromYAML([]byte(tt.yaml))
+		require.Error(t, err, tt.yaml)
+		assert.Contains(t, err.Error(), tt.wantErr)
+	}
+}
+
+func TestRuleApproval(t *testing.T) {
+	t.Parallel()
+
+	config, err := LoadFromYAML([]byte("rules:\n  - match: ^terraform apply\n    send: Check the plan\n    approval: session"))
+	require.NoError(t, err)
+	assert.Equal(t, ApprovalSession, config.Rules[0].Approval)
+
+	tests := []struct {
+		yaml    string
+		wantErr string
+	}{
+		{yaml: "rules:\n  - match: x\n    send: y\n    approval: always", wantErr: "invalid approval 'always'"},
+		{yaml: "rules:\n  - match:\n      pattern: x\n      event: post\n    send: y\n    approval: session", wantErr: "only supported on 'pre'"},
+		{yaml: "rules:\n  - match: x\n    action: audit\n    approval: session", wantErr: "requires the block action"},
+	}
+	for _, tt := range tests {
+		_, err := LoadFromYAML([]byte(tt.yaml))
+		require.Error(t, err, tt.yaml)
+		assert.Contains(t, err.Error(), tt.wantErr)
+	}
+}
+
+func TestRuleEffectiveMatch(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		match   any
+		name    string
+		pattern string
+		active  bool
+	}{
+		{name: "string", match: "^rm", pattern: "^rm", active: true},
+		{name: "mapping", match: map[string]any{"pattern": "^rm", "event": "post"}, pattern: "^rm", active: true},
+		{name: "leading space kept", match: " rm", pattern: " rm", active: true},
+		{name: "empty string", match: "", active: false},
+		{name: "whitespace only", match: map[string]any{"pattern": " \t"}, pattern: " \t", active: false},
+		{name: "missing", match: nil, active: false},
+		{name: "malformed", match: 42, active: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			rule := Rule{Match: tt.match}
+			match, active := rule.EffectiveMatch()
+			assert.Equal(t, tt.active, active)
+			assert.Equal(t, tt.pattern, match.Pattern)
+		})
+	}
+}
+
+func TestRuleWhenFiles(t *testing.T) {
+	t.Parallel()
+
+	config, err := LoadFromYAML([]byte(`rules:
+  - match: "^npm publish"
+    send: "Releases need approval"
+    when:
+      files:
+        - path: .release-approved
+          exists: false
+        - glob: "migrations/*.sql"
+          count_gt: 2`))
+	require.NoError(t, err)
+	rule := config.Rules[0]
+	require.True(t, rule.HasFileConditions())
+	require.Len(t, rule.When.Files, 2)
+	assert.Equal(t, ".release-approved", rule.When.Files[0].Path)
+	assert.False(t, rule.When.Files[0].ShouldExist())
+	assert.Equal(t, "migrations/*.sql", rule.When.Files[1].Glob)
+	assert.Equal(t, 2, rule.When.Files[1].MinCount())
+
+	tests := []struct {
+		yaml    string
+		wantErr string
+	}{
+		{yaml: "rules:\n  - match: x\n    send: y\n    when:\n      files:\n        - path: /etc/passwd", wantErr: "must be relative"},
+		{yaml: "rules:\n  - match: x\n    send: y\n    when:\n      files:\n        - glob: /tmp/*", wantErr: "must be relative"},
+		{yaml: "rules:\n  - match: x\n    send: y\n    when:\n      files:\n        - exists: true", wantErr: "path or glob is required"},
+		{yaml: "rules:\n  - match: x\n    send: y\n    when:\n      files:\n        - glob: \"[\"", wantErr: "invalid glob"},
+		{yaml: "rules:\n  - match: x\n    send: y\n    when:\n      files:\n        - path: a\n          count_gt: 1", wantErr: "only supported with glob"},
+		{
+			yaml:    "rules:\n  - match:\n      pattern: startup\n      event: session\n    send: y\n    when:\n      files:\n        - path: a",
+			wantErr: "only supported on 'pre' and 'post'",
+		},
+	}
+	for _, tt := range tests {
+		_, err := LoadFromYAML([]byte(tt.yaml))
+		require.Error(t, err, tt.yaml)
+		assert.Contains(t, err.Error(), tt.wantErr)
+	}
+}
+
+func TestRuleTrackingKey(t *testing.T) {
+	t.Parallel()
+
+	withID := Rule{ID: "no-rm", Match: "^rm -rf"}
+	assert.Equal(t, "no-rm", withID.TrackingKey())
+
+	first := Rule{Match: "^npm", Send: "Use pnpm"}
+	edited := Rule{Match: "^npm", Send: "Use pnpm instead"}
+	other := Rule{Match: "^npx"}
+	assert.True(t, strings.HasPrefix(first.TrackingKey(), "pattern:"))
+	assert.Equal(t, first.TrackingKey(), edited.TrackingKey(), "the key only depends on the pattern")
+	assert.NotEqual(t, first.TrackingKey(), other.TrackingKey())
+}
@@ -1,6 +1,11 @@
 package config
 
-import "testing"
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
 
 // This file contains placeholder for any remaining tests that don't fit into the focused categories
 // All major tests have been moved to:
@@ -43,3 +48,148 @@ func TestConvertSourcesSliceWithInvalidTypesReturnsError(t *testing.T) {
 		t.Errorf("Expected nil result when error occurs, got %v", result)
 	}
 }
+
+func TestIsNoOpMessage(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		message      string
+		matchedValue string
+		expected     bool
+	}{
+		{"empty", "", "go test", true},
+		{"whitespace only", "  \n\t ", "go test", true},
+		{"identical", "go test ./...", "go test ./...", true},
+		{"case and punctuation", "Go  Test ./...!", "go test ./...", true},
+		{"quoted", "`go test`", "go test", true},
+		{"real guidance", "Use just test instead", "go test", false},
+		{"added word", "go test ./... again", "go test ./...", false},
+		{"near copy replacement", "pnpm install", "npm install", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := IsNoOpMessage(tt.message, tt.matchedValue); got != tt.expected {
+				t.Errorf("IsNoOpMessage(%q, %q) = %v, want %v", tt.message, tt.matchedValue, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSettingsValidateOnEmptyMessage(t *testing.T) {
+	t.Parallel()
+
+	for _, value := range []string{"", OnEmptyMessageBlock, OnEmptyMessageAllow} {
+		settings := Settings{OnEmptyMessage: value}
+		if err := settings.Validate(); err != nil {
+			t.Errorf("Expected %q to be valid, got: %v", value, err)
+		}
+	}
+
+	settings := Settings{OnEmptyMessage: "ignore"}
+	if err := settings.Validate(); err == nil {
+		t.Error("Expected error for invalid on_empty_message value")
+	}
+
+	if (&Settings{}).AllowOnEmptyMessage() {
+		t.Error("Expected default on_empty_message to block")
+	}
+	if !(&Settings{OnEmptyMessage: OnEmptyMessageAllow}).AllowOnEmptyMessage() {
+		t.Error("Expected allow when on_empty_message is 'allow'")
+	}
+}
+
+func TestSettingsHookTimeout(t *testing.T) {
+	t.Parallel()
+
+	if got := (&Settings{}).GetHookTimeout(); got != DefaultHookTimeout {
+		t.Errorf("Expected default hook timeout %v, got %v", DefaultHookTimeout, got)
+	}
+	if got := (&Settings{HookTimeout: "5s"}).GetHookTimeout(); got != 5*time.Second {
+		t.Errorf("Expected hook timeout 5s, got %v", got)
+	}
+
+	for _, value := range []string{"soon", "-1s"} {
+		settings := Settings{HookTimeout: value}
+		if err := settings.Validate(); err == nil {
+			t.Errorf("Expected error for hook_timeout %q", value)
+		}
+	}
+
+	cfg, err := LoadFromYAML([]byte("settings:\n  hook_timeout: 2m\nrules:\n  - match: rm\n    send: no\n"))
+	if err != nil {
+		t.Fatalf("Expected valid config, got: %v", err)
+	}
+	if got := cfg.Settings.GetHookTimeout(); got != 2*time.Minute {
+		t.Errorf("Expected hook timeout 2m, got %v", got)
+	}
+}
+
+func TestSettingsSessionSources(t *testing.T) {
+	t.Parallel()
+
+	if got := (&Settings{}).GetSessionSources(); !slices.Equal(got, []string{"startup", "clear"}) {
+		t.Errorf("Expected default session sources [startup clear], got %v", got)
+	}
+	settings := Settings{SessionSources: []string{"startup"}}
+	if got := settings.GetSessionSources(); !slices.Equal(got, []string{"startup"}) {
+		t.Errorf("Expected session sources [startup], got %v", got)
+	}
+
+	settings = Settings{SessionSources: []string{"startup", "reboot"}}
+	if err := settings.Validate(); err == nil || !strings.Contains(err.Error(), "invalid session_sources entry 'reboot'") {
+		t.Errorf("Expected error for unknown session source, got: %v", err)
+	}
+}
+
+func TestSettingsToolPolicy(t *testing.T) {
+	t.Parallel()
+
+	if !(&Settings{}).ToolAllowed("mcp__anything__tool") {
+		t.Error("Expected the default tool_policy to allow every tool")
+	}
+
+	settings := Settings{ToolPolicy: ToolPolicyDeny, AllowedTools: []string{"@readonly", "mcp__github__.*"}}
+	if err := settings.Validate(); err != nil {
+		t.Fatalf("Expected valid tool policy, got: %v", err)
+	}
+	for tool, want := range map[string]bool{
+		"Read":                      true,
+		"LS":                        true,
+		"mcp__github__create_issue": true,
+		"Bash":                      false,
+		"ReadX":                     false,
+		"mcp__gitlab__merge":        false,
+	} {
+		if got := settings.ToolAllowed(tool); got != want {
+			t.Errorf("ToolAllowed(%q) = %v, want %v", tool, got, want)
+		}
+	}
+
+	for _, invalid := range []Settings{
+		{ToolPolicy: "block"},
+		{ToolPolicy: ToolPolicyDeny, AllowedTools: []string{"@writers"}},
+		{ToolPolicy: ToolPolicyDeny, AllowedTools: []string{"[unclosed"}},
+	} {
+		if err := invalid.Validate(); err == nil {
+			t.Errorf("Expected error for tool_policy %q with allowed_tools %v", invalid.ToolPolicy, invalid.AllowedTools)
+		}
+	}
+}
+
+func TestOutputValidateSelect(t *testing.T) {
+	t.Parallel()
+
+	for _, value := range []string{"", SelectFirst, SelectSpecific} {
+		output := Output{Select: value}
+		if err := output.Validate(); err != nil {
+			t.Errorf("Expected %q to be valid, got: %v", value, err)
+		}
+	}
+
+	if _, err := LoadFromYAML([]byte("output:\n  select: best\nrules:\n  - match: rm\n    send: no\n")); err == nil {
+		t.Error("Expected error for invalid output.select value")
+	}
+}
@@ -0,0 +1,197 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+// redactedConfigText replaces matches of settings.log_redact_patterns in encoded configs
+const redactedConfigText = "[REDACTED]"
+
+// ErrNoPrimaryFile is returned by LoadPrimaryRaw for configs read from the environment,
+// which have no file of their own
+var ErrNoPrimaryFile = errors.New("config has no project file")
+
+// LoadEffective loads the config at path as hooks see it: sources merged, the selected
+// profile applied, send locales resolved and invalid rules left out, with each rule tagged
+// with the file and position it came from
+func LoadEffective(path string) (*Config, error) {
+	source, err := ReadSource(path)
+	if err != nil {
+		return nil, err
+	}
+	partialCfg, err := LoadSource(source)
+	if err != nil {
+		return nil, err
+	}
+	if !IsMerged(path) {
+		partialCfg.SetRuleOrigins(path)
+	}
+	return &partialCfg.Config, nil
+}
+
+// LoadPrimaryRaw loads the project's own config file as written, before merging, profiles
+// and locales: path itself, the highest precedence file of a JoinPaths list, or the first
+// file of a config directory
+func LoadPrimaryRaw(path string) (*Config, error) {
+	file := path
+	if path == EnvConfigPath {
+		return nil, ErrNoPrimaryFile
+	}
+	if files := splitPaths(path); files != nil {
+		file = files[0]
+	} else if IsMerged(path) {
+		files, err := DirFiles(path)
+		if err != nil {
+			return nil, err
+		}
+		file = files[0]
+	}
+	return LoadFilesRaw([]string{file})
+}
+
+// EncodeCanonical encodes the config as YAML, or JSON with asJSON, in canonical form: every
+// match and generate is written as a mapping with its defaults filled in. Strings matching
+// settings.log_redact_patterns are masked. With effective, sends are written in the locale
+// they were resolved to and rules keep their origin tags.
+func (c *Config) EncodeCanonical(asJSON, effective bool) ([]byte, error) {
+	canonical := c.canonical(effective)
+
+	var root yaml.Node
+	if err := root.Encode(canonical); err != nil {
+		return nil, fmt.Errorf("failed to encode config: %w", err)
+	}
+	if effective {
+		if err := encodeOrigins(&root, canonical); err != nil {
+			return nil, err
+		}
+	}
+	redactNode(&root, redactPatterns(c.Settings.LogRedactPatterns))
+
+	if !asJSON {
+		var buf bytes.Buffer
+		encoder := yaml.NewEncoder(&buf)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(&root); err != nil {
+			return nil, fmt.Errorf("failed to marshal config: %w", err)
+		}
+		if err := encoder.Close(); err != nil {
+			return nil, fmt.Errorf("failed to marshal config: %w", err)
+		}
+		return buf.Bytes(), nil
+	}
+
+	var value any
+	if err := root.Decode(&value); err != nil {
+		return nil, fmt.Errorf("failed to decode config: %w", err)
+	}
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal config: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// canonical returns a copy of the config with matches and generate settings as structs.
+// Without effective, origin tags are dropped; with it, send locales are.
+func (c *Config) canonical(effective bool) *Config {
+	canonical := *c
+	canonical.Rules = slices.Clone(c.Rules)
+	for i := range canonical.Rules {
+		rule := &canonical.Rules[i]
+		rule.Match = rule.GetMatch()
+		rule.Generate = rule.GetGenerate()
+		if effective {
+			rule.SendLocales = nil
+		} else {
+			rule.Origin = nil
+		}
+	}
+	canonical.Commands = slices.Clone(c.Commands)
+	for i := range canonical.Commands {
+		command := &canonical.Commands[i]
+		command.Generate = command.GetGenerate()
+		if effective {
+			command.SendLocales = nil
+		}
+	}
+	canonical.Session = slices.Clone(c.Session)
+	for i := range canonical.Session {
+		canonical.Session[i].Generate = canonical.Session[i].GetGenerate()
+	}
+	return &canonical
+}
+
+// encodeOrigins adds the origin of each rule and profile rule to the config encoded in
+// root, since origins aren't part of the config format
+func encodeOrigins(root *yaml.Node, cfg *Config) error {
+	if err := encodeRuleOrigins(mappingValue(root, "rules"), cfg.Rules); err != nil {
+		return err
+	}
+	profiles := mappingValue(root, "profiles")
+	for name, profile := range cfg.Profiles {
+		if err := encodeRuleOrigins(mappingValue(mappingValue(profiles, name), "rules"), profile.Rules); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// encodeRuleOrigins appends an origin key to each encoded rule in node that has one
+func encodeRuleOrigins(node *yaml.Node, rules []Rule) error {
+	for i, ruleNode := range sequenceItems(node) {
+		if i >= len(rules) || rules[i].Origin == nil {
+			continue
+		}
+		var origin yaml.Node
+		if err := origin.Encode(rules[i].Origin); err != nil {
+			return fmt.Errorf("failed to encode rule origin: %w", err)
+		}
+		key := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "origin"}
+		ruleNode.Content = append(ruleNode.Content, key, &origin)
+	}
+	return nil
+}
+
+// redactPatterns compiles the log redaction patterns, skipping invalid ones
+func redactPatterns(patterns []string) []*regexp.Regexp {
+	var compiled []*regexp.Regexp
+	for _, pattern := range patterns {
+		if re, err := regexp.Compile(pattern); err == nil {
+			compiled = append(compiled, re)
+		}
+	}
+	return compiled
+}
+
+// redactNode masks matches of patterns in node's string values. The log_redact_patterns
+// entries themselves are kept so the output shows what is redacted.
+func redactNode(node *yaml.Node, patterns []*regexp.Regexp) {
+	if len(patterns) == 0 {
+		return
+	}
+	if node.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == "log_redact_patterns" {
+				continue
+			}
+			redactNode(node.Content[i+1], patterns)
+		}
+		return
+	}
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!str" {
+		for _, re := range patterns {
+			node.Value = re.ReplaceAllString(node.Value, redactedConfigText)
+		}
+		return
+	}
+	for _, child := range node.Content {
+		redactNode(child, patterns)
+	}
+}
@@ -0,0 +1,111 @@
+package config
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadEffective(t *testing.T) {
+	t.Parallel()
+
+	dir := writeConfigDir(t, map[string]string{
+		"10-base.yml": `rules:
+  - match: "^npm"
+    send: "Use pnpm"`,
+		"20-rm.yml": `rules:
+  - id: no-rm
+    match: "^rm -rf"
+    send: "Use git clean"
+  - match: "(unclosed"
+    send: "Invalid rules are left out"`,
+	})
+
+	cfg, err := LoadEffective(dir)
+	require.NoError(t, err)
+	require.Len(t, cfg.Rules, 2)
+	assert.Equal(t, &RuleOrigin{File: filepath.Join(dir, "20-rm.yml"), Index: 1}, cfg.Rules[1].Origin)
+
+	// Origins are passed on beside the merged data rather than written into it
+	data, err := ReadData(dir)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "origin")
+	validDir := writeConfigDir(t, map[string]string{
+		"10-base.yml": "rules:\n  - match: \"^npm\"\n    send: \"Use pnpm\"",
+		"20-rm.yml":   "rules:\n  - match: \"^rm -rf\"\n    send: \"Use git clean\"",
+	})
+	loaded, err := Load(validDir)
+	require.NoError(t, err)
+	assert.Equal(t, &RuleOrigin{File: filepath.Join(validDir, "20-rm.yml"), Index: 1}, loaded.Rules[1].Origin)
+
+	raw, err := LoadPrimaryRaw(dir)
+	require.NoError(t, err)
+	require.Len(t, raw.Rules, 1, "the primary file is the directory's first")
+	assert.Equal(t, "^npm", raw.Rules[0].GetMatch().Pattern)
+
+	_, err = LoadPrimaryRaw(EnvConfigPath)
+	require.ErrorIs(t, err, ErrNoPrimaryFile)
+}
+
+func TestRuleOriginIsNotConfigurable(t *testing.T) {
+	t.Parallel()
+
+	partialCfg, err := LoadPartial([]byte(`rules:
+  - match: "^rm -rf"
+    send: "Use git clean"
+    origin:
+      file: /etc/passwd
+      index: 42`))
+	require.NoError(t, err)
+	require.Len(t, partialCfg.Rules, 1)
+	assert.Equal(t, &RuleOrigin{Index: 1}, partialCfg.Rules[0].Origin, "origin is set by the loader only")
+}
+
+func TestEncodeCanonical(t *testing.T) {
+	t.Parallel()
+
+	cfg, err := LoadFromYAML([]byte(`settings:
+  log_redact_patterns: ["sk-[a-z0-9]+"]
+rules:
+  - match: "^curl .*sk-[a-z0-9]+"
+    send:
+      en: "Don't pass sk-abc123 on the command line"
+      fr: "Ne passez pas la clé"
+    generate: "off"`))
+	require.NoError(t, err)
+	cfg.SetRuleOrigins("bumpers.yml")
+
+	data, err := cfg.EncodeCanonical(false, true)
+	require.NoError(t, err)
+	assert.Equal(t, `rules:
+  - generate:
+      mode: "off"
+      prompt: ""
+    match:
+      pattern: ^curl .*sk-[a-z0-9]+
+      event: pre
+    send: Don't pass [REDACTED] on the command line
+    origin:
+      file: bumpers.yml
+      index: 1
+settings:
+  log_redact_patterns:
+    - sk-[a-z0-9]+
+`, string(data))
+
+	data, err = cfg.EncodeCanonical(true, false)
+	require.NoError(t, err)
+	var decoded struct {
+		Rules []map[string]any `json:"rules"`
+	}
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	require.Len(t, decoded.Rules, 1)
+	assert.NotContains(t, decoded.Rules[0], "origin", "origins are only kept in effective output")
+	assert.Equal(t, map[string]any{
+		"en": "Don't pass [REDACTED] on the command line",
+		"fr": "Ne passez pas la clé",
+	}, decoded.Rules[0]["send"])
+}
@@ -0,0 +1,137 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// ConfigJSONEnv holds a complete config as inline JSON
+	ConfigJSONEnv = "BUMPERS_CONFIG_JSON"
+
+	// EnvConfigPath is the config path that makes Load and ReadData read the config from
+	// environment variables rather than a file
+	EnvConfigPath = "<environment>"
+)
+
+// ErrNoEnvConfig is returned by LoadFromEnv when no config environment variables are set
+var ErrNoEnvConfig = errors.New("no config environment variables set")
+
+// ruleEnvVar matches BUMPERS_RULE_<n>_<FIELD> variables, capturing the index and field
+var ruleEnvVar = regexp.MustCompile(`^BUMPERS_RULE_(\d+)_(MATCH|SEND|TOOL)$`)
+
+// HasEnvConfig reports whether any config environment variables are set
+func HasEnvConfig() bool {
+	if os.Getenv(ConfigJSONEnv) != "" {
+		return true
+	}
+	for _, env := range os.Environ() {
+		name, _, _ := strings.Cut(env, "=")
+		if ruleEnvVar.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// LoadFromEnv builds a config from BUMPERS_CONFIG_JSON and BUMPERS_RULE_<n>_MATCH, _SEND and
+// _TOOL variables, with numbered rules appended after any JSON rules in index order. AI
+// generation is turned off everywhere since its cache is tied to a config file.
+func LoadFromEnv() (*Config, error) {
+	cfg, err := loadRawFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("config validation failed: %w", err)
+	}
+	return cfg, nil
+}
+
+// loadRawFromEnv builds the environment config without validating it
+func loadRawFromEnv() (*Config, error) {
+	cfg := &Config{}
+	found := false
+
+	if data := os.Getenv(ConfigJSONEnv); data != "" {
+		if err := yaml.Unmarshal([]byte(data), cfg); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal %s: %w", ConfigJSONEnv, err)
+		}
+		found = true
+	}
+
+	if rules := rulesFromEnv(); len(rules) > 0 {
+		cfg.Rules = append(cfg.Rules, rules...)
+		found = true
+	}
+
+	if !found {
+		return nil, ErrNoEnvConfig
+	}
+
+	cfg.disableGeneration()
+	return cfg, nil
+}
+
+// rulesFromEnv collects the rules defined by BUMPERS_RULE_<n>_* variables, ordered by n
+func rulesFromEnv() []Rule {
+	byIndex := make(map[int]*Rule)
+	for _, env := range os.Environ() {
+		name, value, _ := strings.Cut(env, "=")
+		parts := ruleEnvVar.FindStringSubmatch(name)
+		if parts == nil {
+			continue
+		}
+		index, err := strconv.Atoi(parts[1])
+		if err != nil {
+			continue
+		}
+
+		rule, ok := byIndex[index]
+		if !ok {
+			rule = &Rule{}
+			byIndex[index] = rule
+		}
+		switch parts[2] {
+		case "MATCH":
+			rule.Match = value
+		case "SEND":
+			rule.Send = value
+		case "TOOL":
+			rule.Tool = value
+		}
+	}
+
+	indices := make([]int, 0, len(byIndex))
+	for index := range byIndex {
+		indices = append(indices, index)
+	}
+	sort.Ints(indices)
+
+	rules := make([]Rule, 0, len(indices))
+	for _, index := range indices {
+		rules = append(rules, *byIndex[index])
+	}
+	return rules
+}
+
+// disableGeneration sets every rule, command and session note to generate: off
+func (c *Config) disableGeneration() {
+	for i := range c.Rules {
+		c.Rules[i].Generate = "off"
+	}
+	for i := range c.Commands {
+		c.Commands[i].Generate = "off"
+	}
+	for i := range c.Session {
+		c.Session[i].Generate = "off"
+	}
+}
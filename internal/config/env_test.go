@@ -0,0 +1,68 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadFromEnvRules(t *testing.T) { //nolint:paralleltest // t.Setenv() usage
+	t.Setenv("BUMPERS_RULE_10_MATCH", "^rm -rf")
+	t.Setenv("BUMPERS_RULE_10_SEND", "Use safer deletion")
+	t.Setenv("BUMPERS_RULE_2_MATCH", "\\.env$")
+	t.Setenv("BUMPERS_RULE_2_SEND", "Don't edit env files")
+	t.Setenv("BUMPERS_RULE_2_TOOL", "^(Write|Edit)$")
+
+	require.True(t, HasEnvConfig())
+	cfg, err := LoadFromEnv()
+	require.NoError(t, err)
+
+	require.Len(t, cfg.Rules, 2)
+	assert.Equal(t, "\\.env$", cfg.Rules[0].GetMatch().Pattern, "rules are ordered by index")
+	assert.Equal(t, "^(Write|Edit)$", cfg.Rules[0].Tool)
+	assert.Equal(t, "^rm -rf", cfg.Rules[1].GetMatch().Pattern)
+	assert.Equal(t, "Use safer deletion", cfg.Rules[1].Send)
+	assert.Equal(t, "off", cfg.Rules[1].GetGenerate().Mode, "env configs can't use AI generation")
+}
+
+func TestLoadFromEnvJSON(t *testing.T) { //nolint:paralleltest // t.Setenv() usage
+	t.Setenv(ConfigJSONEnv, `{"rules": [{"match": "^go test", "send": "Use just test", "generate": "always"}],
+		"commands": [{"name": "help", "send": "Help", "generate": "once"}]}`)
+	t.Setenv("BUMPERS_RULE_1_MATCH", "^make")
+	t.Setenv("BUMPERS_RULE_1_SEND", "Use just")
+
+	cfg, err := LoadFromEnv()
+	require.NoError(t, err)
+
+	require.Len(t, cfg.Rules, 2)
+	assert.Equal(t, "^go test", cfg.Rules[0].GetMatch().Pattern, "JSON rules come first")
+	assert.Equal(t, "^make", cfg.Rules[1].GetMatch().Pattern)
+	assert.Equal(t, "off", cfg.Rules[0].GetGenerate().Mode)
+	assert.Equal(t, "off", cfg.Commands[0].GetGenerate().Mode)
+
+	// Load reads the same config through EnvConfigPath
+	loaded, err := Load(EnvConfigPath)
+	require.NoError(t, err)
+	assert.Len(t, loaded.Rules, 2)
+}
+
+func TestLoadFromEnvErrors(t *testing.T) { //nolint:paralleltest // t.Setenv() usage
+	t.Setenv(ConfigJSONEnv, "")
+	if HasEnvConfig() {
+		t.Skip("BUMPERS_RULE_* variables are set in the test environment")
+	}
+	_, err := LoadFromEnv()
+	assert.ErrorIs(t, err, ErrNoEnvConfig)
+
+	t.Setenv(ConfigJSONEnv, `{"rules": [`)
+	_, err = LoadFromEnv()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), ConfigJSONEnv)
+
+	t.Setenv(ConfigJSONEnv, "")
+	t.Setenv("BUMPERS_RULE_1_SEND", "Missing a pattern")
+	_, err = LoadFromEnv()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "match field is required")
+}
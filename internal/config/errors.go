@@ -0,0 +1,98 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Sentinels for classifying config failures with errors.Is
+var (
+	// ErrConfigNotFound means the config file, or one of the files it's merged from, doesn't exist
+	ErrConfigNotFound = errors.New("config not found")
+	// ErrConfigParse is matched by every *ParseError
+	ErrConfigParse = errors.New("config parse failed")
+	// ErrRuleInvalid is matched by every *RuleError
+	ErrRuleInvalid = errors.New("invalid rule")
+	// ErrEmptyConfig means the config has no rules, commands, session messages or notifications
+	ErrEmptyConfig = errors.New("config must contain at least one rule, command, session, or notification")
+)
+
+// notFoundError marks a missing config file as ErrConfigNotFound without changing its message
+type notFoundError struct {
+	err error
+}
+
+func (e *notFoundError) Error() string { return e.err.Error() }
+
+func (e *notFoundError) Unwrap() error { return e.err }
+
+func (*notFoundError) Is(target error) bool { return target == ErrConfigNotFound }
+
+// readError returns err, marked as ErrConfigNotFound when the file doesn't exist
+func readError(err error) error {
+	if errors.Is(err, fs.ErrNotExist) {
+		return &notFoundError{err: err}
+	}
+	return err
+}
+
+// ParseError is a config that isn't valid YAML or doesn't fit the config schema
+type ParseError struct {
+	Err error
+	// File is the config file that failed, when it's one of several merged files
+	File string
+	// Line and Column locate the problem when YAML reports it; Column is 0 when only the
+	// line is known
+	Line   int
+	Column int
+}
+
+func (e *ParseError) Error() string {
+	if e.File != "" {
+		return fmt.Sprintf("failed to unmarshal config %s: %v", e.File, e.Err)
+	}
+	return fmt.Sprintf("failed to unmarshal config: %v", e.Err)
+}
+
+func (e *ParseError) Unwrap() error { return e.Err }
+
+func (*ParseError) Is(target error) bool { return target == ErrConfigParse }
+
+// yamlPosition finds "line N" and "column N" in YAML error messages
+var yamlPosition = regexp.MustCompile(`line (\d+)(?:, column (\d+))?`)
+
+// newParseError wraps a YAML unmarshal error from file, locating it from the error message
+func newParseError(file string, err error) *ParseError {
+	parseErr := &ParseError{Err: err, File: file}
+
+	message := err.Error()
+	var typeErr *yaml.TypeError
+	if errors.As(err, &typeErr) && len(typeErr.Errors) > 0 {
+		message = typeErr.Errors[0]
+	}
+	if match := yamlPosition.FindStringSubmatch(message); match != nil {
+		parseErr.Line, _ = strconv.Atoi(match[1])
+		parseErr.Column, _ = strconv.Atoi(match[2])
+	}
+	return parseErr
+}
+
+// RuleError is a rule that failed validation
+type RuleError struct {
+	Reason error
+	// Index is the rule's zero-based position in the config
+	Index int
+}
+
+func (e *RuleError) Error() string {
+	return fmt.Sprintf("rule %d validation failed: %v", e.Index+1, e.Reason)
+}
+
+func (e *RuleError) Unwrap() error { return e.Reason }
+
+func (*RuleError) Is(target error) bool { return target == ErrRuleInvalid }
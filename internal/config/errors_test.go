@@ -0,0 +1,92 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrConfigNotFound(t *testing.T) {
+	t.Parallel()
+
+	missing := filepath.Join(t.TempDir(), "bumpers.yml")
+	_, err := Load(missing)
+	require.ErrorIs(t, err, ErrConfigNotFound)
+	require.ErrorIs(t, err, fs.ErrNotExist)
+	assert.Contains(t, err.Error(), "failed to read config")
+
+	_, err = ReadData(JoinPaths([]string{missing, missing + ".local"}))
+	require.ErrorIs(t, fmt.Errorf("wrapped: %w", err), ErrConfigNotFound)
+
+	// Other read failures aren't reported as missing
+	_, err = Load(t.TempDir() + string(filepath.Separator) + ".")
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, ErrConfigNotFound)
+}
+
+func TestParseError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		yaml   string
+		line   int
+		column int
+	}{
+		{name: "syntax", yaml: "rules:\n  - match: rm\n\tsend: no\n", line: 2},
+		{name: "schema", yaml: "rules:\n  - match: rm\n    send: no\nsettings:\n  strict: [1]\n", line: 5},
+		{name: "send list", yaml: "rules:\n  - match: rm\n    send:\n      - ok\n      - {a: b}\n", line: 5, column: 9},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := LoadPartial([]byte(tt.yaml))
+			wrapped := fmt.Errorf("failed to load config from bumpers.yml: %w", err)
+
+			var parseErr *ParseError
+			require.ErrorAs(t, wrapped, &parseErr)
+			require.ErrorIs(t, wrapped, ErrConfigParse)
+			assert.Equal(t, tt.line, parseErr.Line)
+			assert.Equal(t, tt.column, parseErr.Column)
+			assert.Contains(t, err.Error(), "failed to unmarshal config: ")
+		})
+	}
+}
+
+func TestParseErrorNamesMergedFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	good := filepath.Join(dir, "bumpers.yml")
+	bad := filepath.Join(dir, "bumpers.local.yml")
+	require.NoError(t, os.WriteFile(good, []byte("rules:\n  - match: rm\n    send: no\n"), 0o600))
+	require.NoError(t, os.WriteFile(bad, []byte("rules: [unclosed"), 0o600))
+
+	_, err := ReadData(JoinPaths([]string{bad, good}))
+	var parseErr *ParseError
+	require.ErrorAs(t, err, &parseErr)
+	assert.Equal(t, bad, parseErr.File)
+	assert.Contains(t, err.Error(), "failed to unmarshal config "+bad)
+}
+
+func TestRuleError(t *testing.T) {
+	t.Parallel()
+
+	_, err := LoadFromYAML([]byte("rules:\n  - match: rm\n    send: no\n  - match: \"[unclosed\"\n    send: no\n"))
+	require.ErrorIs(t, err, ErrRuleInvalid)
+	assert.Contains(t, err.Error(), "config validation failed: rule 2 validation failed: invalid regex pattern")
+
+	var ruleErr *RuleError
+	require.ErrorAs(t, err, &ruleErr)
+	assert.Equal(t, 1, ruleErr.Index)
+	assert.Contains(t, ruleErr.Reason.Error(), "invalid regex pattern")
+	assert.False(t, errors.Is(err, ErrConfigParse))
+}
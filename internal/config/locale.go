@@ -0,0 +1,140 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultLocale is the fallback for localized messages without the configured locale
+const DefaultLocale = "en"
+
+// localePattern matches locale tags such as en, ja or pt-BR
+var localePattern = regexp.MustCompile(`^[A-Za-z]{2,3}([-_][A-Za-z0-9]{2,8})*$`)
+
+// ResolveLocale picks the message for locale from a map of locale to message: locale
+// itself, then DefaultLocale, then the first locale in sorted order
+func ResolveLocale(messages map[string]string, locale string) string {
+	if message, ok := messages[locale]; ok && locale != "" {
+		return message
+	}
+	if message, ok := messages[DefaultLocale]; ok {
+		return message
+	}
+	if locales := slices.Sorted(maps.Keys(messages)); len(locales) > 0 {
+		return messages[locales[0]]
+	}
+	return ""
+}
+
+// validateLocale checks a settings.locale or send map key is a locale tag
+func validateLocale(field, locale string) error {
+	if !localePattern.MatchString(locale) {
+		return fmt.Errorf("invalid %s %q: must be a locale such as en, ja or pt-BR", field, locale)
+	}
+	return nil
+}
+
+// validateLocales checks every key of a localized message is a locale tag
+func validateLocales(field string, messages map[string]string) error {
+	for _, locale := range slices.Sorted(maps.Keys(messages)) {
+		if err := validateLocale(field+" locale", locale); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyLocale resolves the localized send and add messages for settings.locale, so they're
+// read like plain strings from then on
+func (c *Config) applyLocale() {
+	locale := c.Settings.Locale
+	for i := range c.Rules {
+		c.Rules[i].applyLocale(locale)
+	}
+	for name, profile := range c.Profiles {
+		for i := range profile.Rules {
+			profile.Rules[i].applyLocale(locale)
+		}
+		c.Profiles[name] = profile
+	}
+	for i := range c.Commands {
+		if len(c.Commands[i].SendLocales) > 0 {
+			c.Commands[i].Send = ResolveLocale(c.Commands[i].SendLocales, locale)
+		}
+	}
+	for i := range c.Session {
+		if len(c.Session[i].AddLocales) > 0 {
+			c.Session[i].Add = ResolveLocale(c.Session[i].AddLocales, locale)
+		}
+	}
+}
+
+func (r *Rule) applyLocale(locale string) {
+	if len(r.SendLocales) > 0 {
+		r.Send = ResolveLocale(r.SendLocales, locale)
+	}
+}
+
+// splitLocalized takes a map-form key out of a mapping node, such as send: {en: ..., ja: ...},
+// returning its messages. The node is left with the DefaultLocale message in its place, until
+// applyLocale picks the configured one.
+func splitLocalized(value *yaml.Node, key string) (map[string]string, error) {
+	if value.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+	for i := 0; i+1 < len(value.Content); i += 2 {
+		field := value.Content[i+1]
+		if value.Content[i].Value != key || field.Kind != yaml.MappingNode {
+			continue
+		}
+
+		messages := make(map[string]string, len(field.Content)/2)
+		for j := 0; j+1 < len(field.Content); j += 2 {
+			message := field.Content[j+1]
+			if message.Kind != yaml.ScalarNode {
+				return nil, fmt.Errorf("line %d, column %d: %s locale %q must be a string",
+					message.Line, message.Column, key, field.Content[j].Value)
+			}
+			messages[field.Content[j].Value] = message.Value
+		}
+		if len(messages) == 0 {
+			return nil, fmt.Errorf("line %d, column %d: %s needs at least one locale", field.Line, field.Column, key)
+		}
+		value.Content[i+1] = &yaml.Node{
+			Kind:   yaml.ScalarNode,
+			Tag:    "!!str",
+			Value:  ResolveLocale(messages, ""),
+			Line:   field.Line,
+			Column: field.Column,
+		}
+		return messages, nil
+	}
+	return nil, nil
+}
+
+// withLocalized returns value encoded with key written as its map of localized messages, or
+// value itself when there are none
+func withLocalized(value any, key string, messages map[string]string) (any, error) {
+	if len(messages) == 0 {
+		return value, nil
+	}
+	var node, localized yaml.Node
+	if err := node.Encode(value); err != nil {
+		return nil, fmt.Errorf("failed to encode %s: %w", key, err)
+	}
+	if err := localized.Encode(messages); err != nil {
+		return nil, fmt.Errorf("failed to encode %s locales: %w", key, err)
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			node.Content[i+1] = &localized
+			return &node, nil
+		}
+	}
+	node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, &localized)
+	return &node, nil
+}
@@ -0,0 +1,134 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestResolveLocale(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		messages map[string]string
+		name     string
+		locale   string
+		want     string
+	}{
+		{name: "configured locale", messages: map[string]string{"en": "Hi", "ja": "こんにちは"}, locale: "ja", want: "こんにちは"},
+		{name: "falls back to en", messages: map[string]string{"en": "Hi", "fr": "Salut"}, locale: "ja", want: "Hi"},
+		{name: "no locale set", messages: map[string]string{"en": "Hi", "ja": "こんにちは"}, want: "Hi"},
+		{name: "falls back to any", messages: map[string]string{"ja": "こんにちは", "fr": "Salut"}, locale: "de", want: "Salut"},
+		{name: "empty", messages: map[string]string{}, locale: "ja", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, ResolveLocale(tt.messages, tt.locale))
+		})
+	}
+}
+
+func TestLoadLocalizedMessages(t *testing.T) {
+	t.Parallel()
+
+	cfg, err := LoadFromYAML([]byte(`settings:
+  locale: ja
+rules:
+  - match: "^go test"
+    send:
+      en: "Use just test for {{.Command}}"
+      ja: "{{.Command}} ではなく just test を使ってください"
+  - match: "^rm"
+    send:
+      en: "Careful"
+      fr: "Attention"
+  - match: "^npm"
+    send: "Use pnpm"
+commands:
+  - name: help
+    send: {en: "Help", ja: "ヘルプ"}
+session:
+  - add: {fr: "Bonjour", de: "Hallo"}
+`))
+	require.NoError(t, err)
+
+	assert.Equal(t, "{{.Command}} ではなく just test を使ってください", cfg.Rules[0].Send)
+	assert.Equal(t, "Careful", cfg.Rules[1].Send)
+	assert.Equal(t, "Use pnpm", cfg.Rules[2].Send)
+	assert.Nil(t, cfg.Rules[2].SendLocales)
+	assert.Equal(t, "ヘルプ", cfg.Commands[0].Send)
+	assert.Equal(t, "Hallo", cfg.Session[0].Add)
+}
+
+func TestLoadLocalizedMessagesInvalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		yaml    string
+		wantErr string
+	}{
+		{
+			name:    "bad settings locale",
+			yaml:    "settings:\n  locale: japanese!\nrules:\n  - match: go\n    send: x\n",
+			wantErr: `invalid locale "japanese!"`,
+		},
+		{
+			name:    "bad send locale",
+			yaml:    "rules:\n  - match: go\n    send: {en: x, \"not a locale\": y}\n",
+			wantErr: `invalid send locale "not a locale"`,
+		},
+		{
+			name:    "non-string message",
+			yaml:    "rules:\n  - match: go\n    send: {en: [a, b]}\n",
+			wantErr: `send locale "en" must be a string`,
+		},
+		{
+			name:    "empty map",
+			yaml:    "commands:\n  - name: x\n    send: {}\n",
+			wantErr: "send needs at least one locale",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := LoadFromYAML([]byte(tt.yaml))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestSaveKeepsLocalizedMessages(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "bumpers.yml")
+	cfg, err := LoadFromYAML([]byte(`settings:
+  locale: ja
+rules:
+  - match: "^go test"
+    send: {en: "Use just test", ja: "just test を使ってください"}
+session:
+  - add: {en: "Hello", ja: "こんにちは"}
+`))
+	require.NoError(t, err)
+	cfg.AddRule(Rule{Match: "^rm", Send: "Careful"})
+	require.NoError(t, cfg.Save(path))
+
+	saved, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"en": "Use just test", "ja": "just test を使ってください"}, saved.Rules[0].SendLocales)
+	assert.Equal(t, "just test を使ってください", saved.Rules[0].Send)
+	assert.Equal(t, "Careful", saved.Rules[1].Send)
+	assert.Equal(t, "こんにちは", saved.Session[0].Add)
+
+	data, err := yaml.Marshal(saved.Rules[0])
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "ja: just test を使ってください")
+}
@@ -0,0 +1,224 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/wizzomafizzo/bumpers/internal/constants"
+	"gopkg.in/yaml.v3"
+)
+
+// legacyCommandPrefix is the prefix commands were invoked with before it became
+// constants.CommandPrefix
+const legacyCommandPrefix = "%"
+
+// legacyMatchKeys are rule keys that now live in the match map
+var legacyMatchKeys = []string{"event", "sources"}
+
+// Migration is a config rewritten by Migrate
+type Migration struct {
+	// Data is the migrated config, the original data when nothing changed
+	Data []byte
+	// Changes describes each rewrite, empty when the config is already current
+	Changes []string
+	// Warnings are deprecated forms Migrate can't safely rewrite, to fix by hand
+	Warnings []string
+}
+
+// Migrate rewrites deprecated config forms to the current schema:
+//   - a rule's pattern key becomes match
+//   - a rule's event and sources keys move into the match map, turning a string match
+//     into the map form
+//   - command names and aliases lose the old % prefix
+//
+// Messages still telling users to send %command are flagged as warnings. Comments are kept,
+// but the YAML is re-indented.
+func Migrate(data []byte) (*Migration, error) {
+	var root yaml.Node
+	if err := yaml.NewDecoder(bytes.NewReader(data)).Decode(&root); err != nil && !errors.Is(err, io.EOF) {
+		return nil, newParseError("", err)
+	}
+	migration := &Migration{Data: data}
+	if root.Kind != yaml.DocumentNode || len(root.Content) == 0 || root.Content[0].Kind != yaml.MappingNode {
+		return migration, nil
+	}
+	top := root.Content[0]
+
+	for i, rule := range sequenceItems(mappingValue(top, "rules")) {
+		migration.migrateRule(rule, fmt.Sprintf("rule %d", i+1))
+	}
+	if profiles := mappingValue(top, "profiles"); profiles != nil && profiles.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(profiles.Content); i += 2 {
+			name := profiles.Content[i].Value
+			for j, rule := range sequenceItems(mappingValue(profiles.Content[i+1], "rules")) {
+				migration.migrateRule(rule, fmt.Sprintf("profile %q rule %d", name, j+1))
+			}
+		}
+	}
+	commands := sequenceItems(mappingValue(top, "commands"))
+	for _, command := range commands {
+		migration.migrateCommand(command)
+	}
+	migration.flagLegacyPrefix(top, commands)
+
+	if len(migration.Changes) == 0 {
+		return migration, nil
+	}
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&root); err != nil {
+		return nil, fmt.Errorf("failed to encode migrated config: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode migrated config: %w", err)
+	}
+	migration.Data = buf.Bytes()
+	return migration, nil
+}
+
+// migrateRule moves a rule's legacy pattern, event and sources keys into match
+func (m *Migration) migrateRule(rule *yaml.Node, label string) {
+	if rule.Kind != yaml.MappingNode {
+		return
+	}
+	line := fmt.Sprintf("%s (line %d)", label, rule.Line)
+
+	if pattern := mappingValue(rule, "pattern"); pattern != nil {
+		if mappingValue(rule, "match") != nil {
+			m.Warnings = append(m.Warnings, line+": has both pattern and match, remove pattern")
+		} else {
+			renameKey(rule, "pattern", "match")
+			m.Changes = append(m.Changes, line+": renamed pattern to match")
+		}
+	}
+
+	for _, key := range legacyMatchKeys {
+		value := mappingValue(rule, key)
+		if value == nil {
+			continue
+		}
+		match := mappingValue(rule, "match")
+		if match == nil {
+			m.Warnings = append(m.Warnings, fmt.Sprintf("%s: has %s but no match", line, key))
+			continue
+		}
+		if match.Kind == yaml.ScalarNode {
+			pattern := *match
+			*match = yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Content: []*yaml.Node{
+				{Kind: yaml.ScalarNode, Tag: "!!str", Value: "pattern"}, &pattern,
+			}}
+			m.Changes = append(m.Changes, line+": converted match to the map form")
+		}
+		if mappingValue(match, key) != nil {
+			m.Warnings = append(m.Warnings, fmt.Sprintf("%s: has %s in both the rule and match, remove the rule's", line, key))
+			continue
+		}
+		removeKey(rule, key)
+		match.Content = append(match.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+		m.Changes = append(m.Changes, fmt.Sprintf("%s: moved %s into match", line, key))
+	}
+}
+
+// migrateCommand strips the legacy prefix from a command's name and aliases
+func (m *Migration) migrateCommand(command *yaml.Node) {
+	if command.Kind != yaml.MappingNode {
+		return
+	}
+	names := []*yaml.Node{mappingValue(command, "name")}
+	names = append(names, sequenceItems(mappingValue(command, "aliases"))...)
+	for _, name := range names {
+		if name == nil || name.Kind != yaml.ScalarNode || !strings.HasPrefix(name.Value, legacyCommandPrefix) {
+			continue
+		}
+		stripped := strings.TrimLeft(name.Value, legacyCommandPrefix)
+		m.Changes = append(m.Changes, fmt.Sprintf("command %q (line %d): renamed to %q, invoked as %s%s",
+			name.Value, name.Line, stripped, constants.CommandPrefix, stripped))
+		name.Value = stripped
+	}
+}
+
+// flagLegacyPrefix warns about messages that still mention a command with the legacy
+// prefix, which may be prose rather than an instruction so they aren't rewritten
+func (m *Migration) flagLegacyPrefix(top *yaml.Node, commands []*yaml.Node) {
+	var names []string
+	for _, command := range commands {
+		names = append(names, scalarValues(mappingValue(command, "name"))...)
+		for _, alias := range sequenceItems(mappingValue(command, "aliases")) {
+			names = append(names, scalarValues(alias)...)
+		}
+	}
+	if len(names) == 0 {
+		return
+	}
+	for i := range names {
+		names[i] = regexp.QuoteMeta(names[i])
+	}
+	legacy := regexp.MustCompile(regexp.QuoteMeta(legacyCommandPrefix) + `(` + strings.Join(names, "|") + `)\b`)
+
+	var walk func(node *yaml.Node)
+	walk = func(node *yaml.Node) {
+		if node.Kind == yaml.ScalarNode {
+			if found := legacy.FindString(node.Value); found != "" {
+				m.Warnings = append(m.Warnings, fmt.Sprintf("line %d: mentions %s, commands are now invoked as %s%s",
+					node.Line, found, constants.CommandPrefix, strings.TrimPrefix(found, legacyCommandPrefix)))
+			}
+			return
+		}
+		for _, child := range node.Content {
+			walk(child)
+		}
+	}
+	walk(top)
+}
+
+// mappingValue returns the value of key in a mapping node, or nil
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// sequenceItems returns the items of a sequence node, or nil for any other node
+func sequenceItems(node *yaml.Node) []*yaml.Node {
+	if node == nil || node.Kind != yaml.SequenceNode {
+		return nil
+	}
+	return node.Content
+}
+
+// scalarValues returns a scalar node's value as a one-item list, or nil
+func scalarValues(node *yaml.Node) []string {
+	if node == nil || node.Kind != yaml.ScalarNode || node.Value == "" {
+		return nil
+	}
+	return []string{node.Value}
+}
+
+func renameKey(node *yaml.Node, from, to string) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == from {
+			node.Content[i].Value = to
+			return
+		}
+	}
+}
+
+func removeKey(node *yaml.Node, key string) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			node.Content = append(node.Content[:i], node.Content[i+2:]...)
+			return
+		}
+	}
+}
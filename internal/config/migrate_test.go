@@ -0,0 +1,92 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrateDeprecatedForms(t *testing.T) {
+	t.Parallel()
+
+	migration, err := Migrate([]byte(`# team rules
+rules:
+  - pattern: "^go test"
+    send: "Use just test"
+  - match: "panic"
+    event: post
+    sources: ["tool_output"]
+    send: "Investigate the panic"
+profiles:
+  ci:
+    rules:
+      - pattern: "^git push"
+        send: "No pushing from CI"
+commands:
+  - name: "%test"
+    aliases: ["%t"]
+    send: "Run just test"
+  - name: help
+    send: "Send %test to run the tests"
+`))
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"rule 1 (line 3): renamed pattern to match",
+		"rule 2 (line 5): converted match to the map form",
+		"rule 2 (line 5): moved event into match",
+		"rule 2 (line 5): moved sources into match",
+		`profile "ci" rule 1 (line 12): renamed pattern to match`,
+		`command "%test" (line 15): renamed to "test", invoked as $test`,
+		`command "%t" (line 16): renamed to "t", invoked as $t`,
+	}, migration.Changes)
+	assert.Equal(t, []string{"line 19: mentions %test, commands are now invoked as $test"}, migration.Warnings)
+	assert.Contains(t, string(migration.Data), "# team rules")
+
+	partial, err := LoadPartial(migration.Data)
+	require.NoError(t, err)
+	assert.Empty(t, partial.ValidationWarnings)
+	require.Len(t, partial.Rules, 2)
+	assert.Equal(t, "^go test", partial.Rules[0].GetMatch().Pattern)
+	match := partial.Rules[1].GetMatch()
+	assert.Equal(t, "panic", match.Pattern)
+	assert.Equal(t, "post", match.Event)
+	assert.Equal(t, []string{"tool_output"}, match.Sources)
+	assert.Equal(t, "^git push", partial.Profiles["ci"].Rules[0].GetMatch().Pattern)
+	assert.Equal(t, "test", partial.Commands[0].Name)
+	assert.Equal(t, []string{"t"}, partial.Commands[0].Aliases)
+
+	again, err := Migrate(migration.Data)
+	require.NoError(t, err)
+	assert.Empty(t, again.Changes, "a migrated config is current")
+}
+
+func TestMigrateCurrentConfigUnchanged(t *testing.T) {
+	t.Parallel()
+
+	data := []byte("rules:\n  - match: \"^rm\"\n    send: \"No\"\n")
+	migration, err := Migrate(data)
+	require.NoError(t, err)
+	assert.Empty(t, migration.Changes)
+	assert.Empty(t, migration.Warnings)
+	assert.Equal(t, data, migration.Data)
+}
+
+func TestMigrateConflictsAreWarnings(t *testing.T) {
+	t.Parallel()
+
+	migration, err := Migrate([]byte(`rules:
+  - pattern: "^a"
+    match: "^b"
+    send: "x"
+  - match: {pattern: "^c", event: post}
+    event: pre
+    send: "y"
+`))
+	require.NoError(t, err)
+	assert.Empty(t, migration.Changes)
+	assert.Equal(t, []string{
+		"rule 1 (line 2): has both pattern and match, remove pattern",
+		"rule 2 (line 5): has event in both the rule and match, remove the rule's",
+	}, migration.Warnings)
+}
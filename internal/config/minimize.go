@@ -0,0 +1,181 @@
+package config
+
+import (
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RuleMerge is a set of rules that differ only in their pattern, which bumpers rules
+// minimize combines into one rule at the position of the first
+type RuleMerge struct {
+	// Pattern is the combined pattern, an alternation of the rules' patterns
+	Pattern string
+	// Indices are the zero-based positions of the merged rules, in config order
+	Indices []int
+}
+
+// MinimizeRules finds rules with the same send, tool and other options that can be combined
+// into one rule. A rule only joins an earlier one when no rule between them could match the
+// same input first, since merging moves it up to the earlier rule's position.
+func (c *Config) MinimizeRules() []RuleMerge {
+	var merges []RuleMerge
+	merged := make(map[int]bool)
+
+	for i := range c.Rules {
+		key, ok := mergeKey(&c.Rules[i])
+		if !ok || merged[i] {
+			continue
+		}
+
+		group := []int{i}
+		for j := i + 1; j < len(c.Rules); j++ {
+			if merged[j] {
+				continue
+			}
+			if otherKey, otherOK := mergeKey(&c.Rules[j]); otherOK && otherKey == key &&
+				!c.interveningMatch(group, j) {
+				group = append(group, j)
+			}
+		}
+		if len(group) < 2 {
+			continue
+		}
+
+		patterns := make([]string, 0, len(group))
+		for _, index := range group {
+			merged[index] = true
+			pattern := c.Rules[index].GetMatch().Pattern
+			if !slices.Contains(patterns, pattern) {
+				patterns = append(patterns, pattern)
+			}
+		}
+		merges = append(merges, RuleMerge{Indices: group, Pattern: combinePatterns(patterns)})
+	}
+	return merges
+}
+
+// ApplyMerges replaces each merge's rules with one rule using the combined pattern, keeping
+// the first rule's position and id
+func (c *Config) ApplyMerges(merges []RuleMerge) {
+	removed := make(map[int]bool)
+	for _, merge := range merges {
+		first := &c.Rules[merge.Indices[0]]
+		first.Match = withPattern(first.Match, merge.Pattern)
+		for _, index := range merge.Indices[1:] {
+			removed[index] = true
+		}
+	}
+
+	rules := make([]Rule, 0, len(c.Rules)-len(removed))
+	for i := range c.Rules {
+		if !removed[i] {
+			rules = append(rules, c.Rules[i])
+		}
+	}
+	c.Rules = rules
+}
+
+// interveningMatch reports whether a rule between the group's first rule and rule j, and
+// not in the group, could fire for the same input as rule j
+func (c *Config) interveningMatch(group []int, j int) bool {
+	for k := group[0] + 1; k < j; k++ {
+		if slices.Contains(group, k) || !sameRuleScope(&c.Rules[k], &c.Rules[j]) {
+			continue
+		}
+		if !patternsDisjoint(c.Rules[k].GetMatch().Pattern, c.Rules[j].GetMatch().Pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// patternsDisjoint reports whether no value can match both patterns, judged by both being
+// anchored at the start with literal prefixes that differ, like "^go test" and "^npm ci"
+func patternsDisjoint(a, b string) bool {
+	if !strings.HasPrefix(a, "^") || !strings.HasPrefix(b, "^") {
+		return false
+	}
+	reA, errA := regexp.Compile(a[1:])
+	reB, errB := regexp.Compile(b[1:])
+	if errA != nil || errB != nil {
+		return false
+	}
+	prefixA, _ := reA.LiteralPrefix()
+	prefixB, _ := reB.LiteralPrefix()
+	n := min(len(prefixA), len(prefixB))
+	return prefixA[:n] != prefixB[:n]
+}
+
+// mergeKey returns the rule with its id and pattern removed, so rules that differ only in
+// those share a key. Rules whose pattern doesn't compile or that use except are skipped.
+func mergeKey(rule *Rule) (string, bool) {
+	match := rule.GetMatch()
+	if _, err := regexp.Compile(match.Pattern); err != nil || len(rule.Except) > 0 {
+		return "", false
+	}
+
+	keyRule := *rule
+	keyRule.ID = ""
+	match.Pattern = ""
+	if match.Event == "" {
+		match.Event = "pre"
+	}
+	keyRule.Match = match
+	data, err := yaml.Marshal(&keyRule)
+	if err != nil {
+		return "", false
+	}
+	return string(data), true
+}
+
+// withPattern returns a rule's match field with its pattern replaced, keeping its form
+func withPattern(match any, pattern string) any {
+	if fields, ok := match.(map[string]any); ok {
+		updated := maps.Clone(fields)
+		updated["pattern"] = pattern
+		return updated
+	}
+	return pattern
+}
+
+// literalPrefixChars are the characters a shared prefix may contain and still be factored
+// out of an alternation without changing what it matches
+var literalPrefixChars = regexp.MustCompile(`^\^?[A-Za-z0-9 _/-]*`)
+
+// combinePatterns joins patterns into one alternation, factoring out a literal prefix they
+// all share, e.g. "^npm install" and "^npm ci" become "^npm (?:install|ci)"
+func combinePatterns(patterns []string) string {
+	prefix := ""
+	if !slices.ContainsFunc(patterns, func(pattern string) bool { return strings.Contains(pattern, "|") }) {
+		prefix = literalPrefixChars.FindString(patterns[0])
+		for _, pattern := range patterns[1:] {
+			prefix = commonPrefix(prefix, pattern)
+		}
+		// A quantifier after the prefix applies to its last character, so keep that in the
+		// alternation
+		for len(prefix) > 0 && slices.ContainsFunc(patterns, func(pattern string) bool {
+			return strings.ContainsAny(pattern[len(prefix):min(len(prefix)+1, len(pattern))], "*+?{")
+		}) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+
+	alternatives := make([]string, len(patterns))
+	for i, pattern := range patterns {
+		alternatives[i] = pattern[len(prefix):]
+	}
+	return prefix + "(?:" + strings.Join(alternatives, "|") + ")"
+}
+
+// commonPrefix returns the longest prefix a and b share
+func commonPrefix(a, b string) string {
+	n := 0
+	for n < len(a) && n < len(b) && a[n] == b[n] {
+		n++
+	}
+	return a[:n]
+}
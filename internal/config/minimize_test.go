@@ -0,0 +1,85 @@
+package config
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMinimizeRules(t *testing.T) {
+	t.Parallel()
+
+	config, err := LoadFromYAML([]byte(`rules:
+  - id: npm-install
+    match: "^npm install"
+    send: "Use pnpm"
+  - match: "^go test"
+    send: "Use just test"
+  - match:
+      pattern: "^npm ci"
+    send: "Use pnpm"
+  - match: "^npm test"
+    send: "Use pnpm"
+    tool: "^Bash$"
+  - match: "^npm run"
+    send: "Use pnpm"
+    generate: "once"
+  - match: "^npm i"
+    send: "Use pnpm"`))
+	require.NoError(t, err)
+
+	merges := config.MinimizeRules()
+	require.Len(t, merges, 1)
+	assert.Equal(t, []int{0, 2, 5}, merges[0].Indices, "rules with other tool or generate options stay separate")
+	assert.Equal(t, "^npm (?:install|ci|i)", merges[0].Pattern)
+
+	config.ApplyMerges(merges)
+	require.Len(t, config.Rules, 4)
+	assert.Equal(t, "npm-install", config.Rules[0].ID)
+	assert.Equal(t, "^npm (?:install|ci|i)", config.Rules[0].GetMatch().Pattern)
+	assert.Equal(t, "^go test", config.Rules[1].GetMatch().Pattern)
+	require.NoError(t, config.Validate())
+}
+
+func TestMinimizeRulesKeepsPrecedence(t *testing.T) {
+	t.Parallel()
+
+	// Moving "^npm publish" above "^npm" would change which rule answers npm publish
+	config, err := LoadFromYAML([]byte(`rules:
+  - match: "^npm install"
+    send: "Use pnpm"
+  - match: "^npm"
+    send: "Prefer pnpm"
+  - match: "^npm publish"
+    send: "Use pnpm"`))
+	require.NoError(t, err)
+	assert.Empty(t, config.MinimizeRules())
+}
+
+func TestCombinePatterns(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		want     string
+		patterns []string
+	}{
+		{name: "shared prefix", patterns: []string{"^npm install", "^npm ci"}, want: "^npm (?:install|ci)"},
+		{name: "no prefix", patterns: []string{"rm -rf", "^sudo"}, want: "(?:rm -rf|^sudo)"},
+		{name: "quantifier after prefix", patterns: []string{"^gos+", "^go test"}, want: "^go(?:s+| test)"},
+		{name: "alternation inside pattern", patterns: []string{"^git a|b", "^git c"}, want: "(?:^git a|b|^git c)"},
+		{name: "escape ends prefix", patterns: []string{`^rm\s+-rf`, `^rm\s+-r`}, want: `^rm(?:\s+-rf|\s+-r)`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := combinePatterns(tt.patterns)
+			assert.Equal(t, tt.want, got)
+			_, err := regexp.Compile(got)
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// RuleOrigin records where a rule was defined, so a decision can name the file to edit
+// when the config is merged from several files
+type RuleOrigin struct {
+	// File is the config file the rule is in, empty when it isn't known, e.g. for rules
+	// from environment variables
+	File string `yaml:"file,omitempty" json:"file,omitempty"`
+	// Profile is the profile whose rules list the rule is in, empty for top-level rules
+	Profile string `yaml:"profile,omitempty" json:"profile,omitempty"`
+	// Index is the rule's 1-based position in its file's rules, or its profile's
+	Index int `yaml:"index" json:"index"`
+}
+
+// SetRuleOrigins records file and each rule's position as the origin of the rules and
+// profile rules that don't have one yet, and fills in file for origins without a file
+func (c *Config) SetRuleOrigins(file string) {
+	setOrigins(c.Rules, file, "")
+	for name, profile := range c.Profiles {
+		setOrigins(profile.Rules, file, name)
+	}
+}
+
+// setOrigins records the origin of each rule in rules
+func setOrigins(rules []Rule, file, profile string) {
+	for i := range rules {
+		rule := &rules[i]
+		if rule.Origin == nil {
+			rule.Origin = &RuleOrigin{File: file, Profile: profile, Index: i + 1}
+		} else if rule.Origin.File == "" {
+			rule.Origin.File = file
+		}
+	}
+}
+
+// ruleOrigins holds the origins of a config's rules and profile rules by position. Origins
+// aren't part of the config format, so a merged config passes them on alongside its data.
+type ruleOrigins struct {
+	rules    []*RuleOrigin
+	profiles map[string][]*RuleOrigin
+}
+
+// ruleOrigins returns the origins of the config's rules and profile rules
+func (c *Config) ruleOrigins() *ruleOrigins {
+	origins := &ruleOrigins{rules: originsOf(c.Rules), profiles: make(map[string][]*RuleOrigin)}
+	for name, profile := range c.Profiles {
+		origins.profiles[name] = originsOf(profile.Rules)
+	}
+	return origins
+}
+
+// restoreRuleOrigins sets the origins returned by ruleOrigins on the rules and profile
+// rules at the same positions
+func (c *Config) restoreRuleOrigins(origins *ruleOrigins) {
+	if origins == nil {
+		return
+	}
+	restoreOrigins(c.Rules, origins.rules)
+	for name, profile := range c.Profiles {
+		restoreOrigins(profile.Rules, origins.profiles[name])
+	}
+}
+
+// originsOf returns the origin of each rule in rules
+func originsOf(rules []Rule) []*RuleOrigin {
+	origins := make([]*RuleOrigin, len(rules))
+	for i := range rules {
+		origins[i] = rules[i].Origin
+	}
+	return origins
+}
+
+// restoreOrigins sets origins on the rules at the same positions
+func restoreOrigins(rules []Rule, origins []*RuleOrigin) {
+	for i := range rules {
+		if i < len(origins) {
+			rules[i].Origin = origins[i]
+		}
+	}
+}
+
+// DescribeOrigin names the rule by its position, id and file for messages and logs,
+// e.g. `rule 2 "no-deploy" in /project/.bumpers/b.yml`. It's empty without an origin.
+func (r *Rule) DescribeOrigin() string {
+	if r.Origin == nil {
+		return ""
+	}
+	description := "rule " + strconv.Itoa(r.Origin.Index)
+	if r.ID != "" {
+		description += fmt.Sprintf(" %q", r.ID)
+	}
+	if r.Origin.Profile != "" {
+		description += " of profile " + r.Origin.Profile
+	}
+	if r.Origin.File != "" {
+		description += " in " + r.Origin.File
+	}
+	return description
+}
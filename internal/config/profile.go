@@ -0,0 +1,78 @@
+package config
+
+import (
+	"fmt"
+	"os"
+)
+
+// ProfileEnv names the profile LoadPartial applies, e.g. BUMPERS_PROFILE=ci
+const ProfileEnv = "BUMPERS_PROFILE"
+
+// Profile holds rules applied on top of the base config when the profile is selected
+type Profile struct {
+	// Rules replace the base rule with the same id, or are appended after the base rules
+	Rules []Rule `yaml:"rules,omitempty" mapstructure:"rules"`
+}
+
+// ApplyProfile merges the named profile's rules into the config. An empty name applies
+// nothing; a name with no matching profile is an error.
+func (c *Config) ApplyProfile(name string) error {
+	if name == "" {
+		return nil
+	}
+	profile, ok := c.Profiles[name]
+	if !ok {
+		return fmt.Errorf("unknown profile '%s'", name)
+	}
+
+	for _, rule := range profile.Rules {
+		if index := c.ruleIndexByID(rule.ID); index >= 0 {
+			c.Rules[index] = rule
+			continue
+		}
+		c.Rules = append(c.Rules, rule)
+	}
+	return nil
+}
+
+// selectedProfile returns the profile named by BUMPERS_PROFILE
+func selectedProfile() string {
+	return os.Getenv(ProfileEnv)
+}
+
+// ruleIndexByID returns the index of the rule with id, or -1 when id is empty or unused
+func (c *Config) ruleIndexByID(id string) int {
+	if id == "" {
+		return -1
+	}
+	for i := range c.Rules {
+		if c.Rules[i].ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// validateProfiles checks every profile's rules, whether or not the profile is selected
+func (c *Config) validateProfiles() error {
+	for name, profile := range c.Profiles {
+		for i := range profile.Rules {
+			if err := profile.Rules[i].Validate(); err != nil {
+				return fmt.Errorf("profile '%s' rule %d validation failed: %w", name, i+1, err)
+			}
+		}
+	}
+	return nil
+}
+
+// appendProfiles adds other's profiles, appending the rules of profiles both configs define
+func (c *Config) appendProfiles(other *Config) {
+	for name, profile := range other.Profiles {
+		if c.Profiles == nil {
+			c.Profiles = make(map[string]Profile)
+		}
+		merged := c.Profiles[name]
+		merged.Rules = append(merged.Rules, profile.Rules...)
+		c.Profiles[name] = merged
+	}
+}
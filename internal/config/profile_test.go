@@ -0,0 +1,77 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const profileConfig = `rules:
+  - id: no-go-test
+    match: "^go test"
+    send: "Use just test"
+profiles:
+  ci:
+    rules:
+      - match: "^git push --force"
+        send: "No force pushes from CI"
+      - id: no-go-test
+        match: "^go test"
+        send: "CI must run just ci"
+`
+
+func TestLoadPartialDefaultProfile(t *testing.T) {
+	t.Setenv(ProfileEnv, "")
+
+	cfg, err := LoadPartial([]byte(profileConfig))
+	require.NoError(t, err)
+	require.Len(t, cfg.Rules, 1)
+	assert.Equal(t, "Use just test", cfg.Rules[0].Send)
+}
+
+func TestLoadPartialCIProfile(t *testing.T) {
+	t.Setenv(ProfileEnv, "ci")
+
+	cfg, err := LoadPartial([]byte(profileConfig))
+	require.NoError(t, err)
+	require.Len(t, cfg.Rules, 2)
+	assert.Equal(t, "CI must run just ci", cfg.Rules[0].Send, "rules with a base rule's id replace it")
+	assert.Equal(t, "^git push --force", cfg.Rules[1].GetMatch().Pattern)
+}
+
+func TestLoadPartialUnknownProfile(t *testing.T) {
+	t.Setenv(ProfileEnv, "staging")
+
+	_, err := LoadPartial([]byte(profileConfig))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown profile 'staging'")
+}
+
+func TestValidateProfileRules(t *testing.T) {
+	t.Parallel()
+
+	_, err := LoadFromYAML([]byte(`rules:
+  - match: "^go test"
+    send: "Use just test"
+profiles:
+  ci:
+    rules:
+      - match: "[unclosed"
+        send: "Never matches"
+`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "profile 'ci' rule 1 validation failed")
+}
+
+func TestMergedProfiles(t *testing.T) {
+	t.Parallel()
+
+	base := &Config{Profiles: map[string]Profile{"ci": {Rules: []Rule{{Match: "^a", Send: "a"}}}}}
+	base.appendLists(&Config{Profiles: map[string]Profile{
+		"ci":    {Rules: []Rule{{Match: "^b", Send: "b"}}},
+		"local": {Rules: []Rule{{Match: "^c", Send: "c"}}},
+	}})
+	assert.Len(t, base.Profiles["ci"].Rules, 2)
+	assert.Len(t, base.Profiles["local"].Rules, 1)
+}
@@ -0,0 +1,95 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// ruleIDLength is the number of hex characters in a generated rule ID
+const ruleIDLength = 6
+
+// ruleIDPattern is the form rule IDs take; all-digit IDs are rejected separately so an
+// argument like "3" always means an index
+var ruleIDPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+
+// ErrRuleNotFound is returned when a rule reference matches no rule
+var ErrRuleNotFound = errors.New("rule not found")
+
+// validateID checks the rule's id, if set, can be told apart from an index
+func (r *Rule) validateID() error {
+	if r.ID == "" {
+		return nil
+	}
+	if !ruleIDPattern.MatchString(r.ID) {
+		return fmt.Errorf("invalid id '%s': must contain only letters, digits, '-' and '_'", r.ID)
+	}
+	if _, err := strconv.Atoi(r.ID); err == nil {
+		return fmt.Errorf("invalid id '%s': must not be a number", r.ID)
+	}
+	return nil
+}
+
+// validateRuleIDs reports the first id shared by two rules
+func (c *Config) validateRuleIDs() error {
+	first := make(map[string]int)
+	for i := range c.Rules {
+		id := c.Rules[i].ID
+		if id == "" {
+			continue
+		}
+		if j, ok := first[id]; ok {
+			return fmt.Errorf("rule %d has the same id '%s' as rule %d", i+1, id, j+1)
+		}
+		first[id] = i
+	}
+	return nil
+}
+
+// NewRuleID returns a short ID for rule derived from its match, tool and message, unique
+// among the config's rules and never all digits
+func (c *Config) NewRuleID(rule *Rule) string {
+	match := rule.GetMatch()
+	for salt := 0; ; salt++ {
+		sum := sha256.Sum256([]byte(fmt.Sprintf("%s\x00%s\x00%s\x00%s\x00%d",
+			match.Pattern, match.Event, rule.Tool, rule.Send, salt)))
+		id := hex.EncodeToString(sum[:])[:ruleIDLength]
+		if _, err := strconv.Atoi(id); err == nil {
+			continue
+		}
+		if _, err := c.FindRule(id); errors.Is(err, ErrRuleNotFound) {
+			return id
+		}
+	}
+}
+
+// TrackingKey identifies the rule in project state, such as when it last matched: its id, or
+// for a rule without one the hash of its match pattern. Hashes can't clash with ids, which
+// can't contain ':'.
+func (r *Rule) TrackingKey() string {
+	if r.ID != "" {
+		return r.ID
+	}
+	sum := sha256.Sum256([]byte(r.GetMatch().Pattern))
+	return "pattern:" + hex.EncodeToString(sum[:])
+}
+
+// FindRule resolves ref, a 1-based index or a rule id, to a 0-based rule index
+func (c *Config) FindRule(ref string) (int, error) {
+	if index, err := strconv.Atoi(ref); err == nil {
+		if index < 1 || index > len(c.Rules) {
+			return 0, fmt.Errorf("invalid index %d: must be between 1 and %d", index, len(c.Rules))
+		}
+		return index - 1, nil
+	}
+
+	for i := range c.Rules {
+		if c.Rules[i].ID == ref {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("no rule with id '%s': %w", ref, ErrRuleNotFound)
+}
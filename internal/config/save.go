@@ -0,0 +1,419 @@
+package config
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// editableLists are the top-level lists Save edits entry by entry in an existing file
+var editableLists = []struct {
+	items func(c *Config) []any
+	key   string
+}{
+	{key: "rules", items: func(c *Config) []any { return listItems(c.Rules) }},
+	{key: "commands", items: func(c *Config) []any { return listItems(c.Commands) }},
+	{key: "session", items: func(c *Config) []any { return listItems(c.Session) }},
+	{key: "notifications", items: func(c *Config) []any { return listItems(c.Notifications) }},
+}
+
+func listItems[T any](items []T) []any {
+	result := make([]any, len(items))
+	for i := range items {
+		result[i] = items[i]
+	}
+	return result
+}
+
+// lineEdit replaces lines [start, end) of a config file
+type lineEdit struct {
+	lines []string
+	start int
+	end   int
+	order int
+}
+
+// listChunk is one entry of an edited list: its lines and the blank lines or comments
+// that followed it
+type listChunk struct {
+	text []string
+	tail []string
+}
+
+// Save writes config to path. An existing YAML file is edited in place: only the rules,
+// commands, session notes and notifications that changed are rewritten, so comments, blank
+// lines and key order elsewhere are kept. Other changes, and JSON files, rewrite the file.
+func (c *Config) Save(path string) error {
+	_, err := c.SaveEdits(path)
+	return err
+}
+
+// SaveEdits saves like Save, also reporting whether an existing file was rewritten in full,
+// losing its comments and formatting
+func (c *Config) SaveEdits(path string) (rewritten bool, err error) {
+	original, readErr := os.ReadFile(path) // #nosec G304 -- path is the user's config file
+	if readErr == nil {
+		if data, ok := c.editYAML(original); ok {
+			return false, writeConfig(path, data)
+		}
+	}
+
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal config: %w", err)
+	}
+	return readErr == nil, writeConfig(path, data)
+}
+
+func writeConfig(path string, data []byte) error {
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write config: %w", err)
+	}
+	return nil
+}
+
+// editYAML returns original with the list entries that differ from c replaced, or false
+// when c can't be reached by editing lists, e.g. settings changed or a list is in flow style
+func (c *Config) editYAML(original []byte) ([]byte, bool) {
+	var saved Config
+	if err := yaml.Unmarshal(original, &saved); err != nil {
+		return nil, false
+	}
+	if !sameOutsideLists(&saved, c) {
+		return nil, false
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(original, &doc); err != nil ||
+		len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, false
+	}
+	text := string(original)
+	if !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+	lines := strings.SplitAfter(text, "\n")
+	lines = lines[:len(lines)-1]
+
+	var edits []lineEdit
+	for order, list := range editableLists {
+		oldKeys, oldErr := itemKeys(list.items(&saved))
+		newItems := list.items(c)
+		newKeys, newErr := itemKeys(newItems)
+		if oldErr != nil || newErr != nil {
+			return nil, false
+		}
+		if slices.Equal(oldKeys, newKeys) {
+			continue
+		}
+		edit, ok := editList(doc.Content[0], lines, list.key, oldKeys, newKeys, newItems)
+		if !ok {
+			return nil, false
+		}
+		edit.order = order
+		edits = append(edits, edit)
+	}
+	if len(edits) == 0 {
+		return original, true
+	}
+
+	// Apply from the bottom up so earlier line numbers stay valid, and lists appended at
+	// the end of the file land in editableLists order
+	slices.SortFunc(edits, func(a, b lineEdit) int {
+		return cmp.Or(cmp.Compare(b.start, a.start), cmp.Compare(b.order, a.order))
+	})
+	for _, edit := range edits {
+		lines = slices.Replace(lines, edit.start, edit.end, edit.lines...)
+	}
+	data := []byte(strings.Join(lines, ""))
+
+	// The edited file must load back as c, which fails if e.g. a removed entry held an anchor
+	var check Config
+	if err := yaml.Unmarshal(data, &check); err != nil {
+		return nil, false
+	}
+	got, gotErr := yaml.Marshal(&check)
+	want, wantErr := yaml.Marshal(c)
+	if gotErr != nil || wantErr != nil || !bytes.Equal(got, want) {
+		return nil, false
+	}
+	return data, true
+}
+
+// sameOutsideLists reports whether a and b are the same apart from their editable lists
+func sameOutsideLists(a, b *Config) bool {
+	strip := func(c *Config) ([]byte, error) {
+		rest := *c
+		rest.Rules, rest.Commands, rest.Session, rest.Notifications = nil, nil, nil, nil
+		return yaml.Marshal(&rest)
+	}
+	aRest, aErr := strip(a)
+	bRest, bErr := strip(b)
+	return aErr == nil && bErr == nil && bytes.Equal(aRest, bRest)
+}
+
+// itemKeys marshals each item, so unchanged entries can be recognized
+func itemKeys(items []any) ([]string, error) {
+	keys := make([]string, len(items))
+	for i, item := range items {
+		data, err := yaml.Marshal(item)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal list entry: %w", err)
+		}
+		keys[i] = string(data)
+	}
+	return keys, nil
+}
+
+// editList returns the edit turning the block list under key from oldKeys into newItems,
+// keeping the lines, head comments and spacing of entries that didn't change
+func editList(
+	root *yaml.Node, lines []string, key string, oldKeys, newKeys []string, newItems []any,
+) (lineEdit, bool) {
+	keyIndex := -1
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value == key {
+			keyIndex = i
+			break
+		}
+	}
+	if keyIndex < 0 {
+		return appendList(lines, key, newItems)
+	}
+
+	seq := root.Content[keyIndex+1]
+	if seq.Kind != yaml.SequenceNode || seq.Style&yaml.FlowStyle != 0 ||
+		len(seq.Content) == 0 || len(seq.Content) != len(oldKeys) {
+		return lineEdit{}, false
+	}
+	keyLine := root.Content[keyIndex].Line
+	limit := len(lines)
+	if keyIndex+2 < len(root.Content) {
+		limit = root.Content[keyIndex+2].Line - 1
+	}
+
+	// Find each entry's first line (its head comment), dash line and last content line,
+	// all 1-based
+	count := len(seq.Content)
+	starts, dashes := make([]int, count), make([]int, count)
+	for i, item := range seq.Content {
+		dash := item.Line
+		for dash > keyLine+1 && !strings.HasPrefix(strings.TrimSpace(lines[dash-1]), "-") {
+			dash--
+		}
+		start := dash
+		for start > keyLine+1 && isCommentLine(lines[start-2]) {
+			start--
+		}
+		starts[i], dashes[i] = start, dash
+	}
+	old := make([]listChunk, count)
+	var end int
+	for i := range count {
+		last := i == count-1
+		next := limit
+		if !last {
+			next = starts[i+1] - 1
+		}
+		end = next
+		for end > dashes[i] && (isBlankLine(lines[end-1]) || last && isCommentLine(lines[end-1])) {
+			end--
+		}
+		old[i] = listChunk{text: lines[starts[i]-1 : end], tail: lines[end:next]}
+		if last {
+			old[i].tail = nil
+		}
+	}
+
+	indent := leadingSpace(lines[dashes[0]-1])
+	chunks, ok := alignChunks(old, dashes, starts, oldKeys, newKeys, newItems, indent)
+	if !ok {
+		return lineEdit{}, false
+	}
+	spaced := count > 1 && len(old[0].tail) > 0 && isBlankLine(old[0].tail[len(old[0].tail)-1])
+	return lineEdit{start: starts[0] - 1, end: end, lines: joinChunks(chunks, spaced)}, true
+}
+
+// alignChunks matches old entries to new ones by their longest common subsequence. Matched
+// entries keep their lines, and so do entries that moved, such as after sorting, which are
+// found by their key among the unmatched ones. Replaced entries keep their head comment and
+// spacing, and added entries are rendered with indent.
+func alignChunks(
+	old []listChunk, dashes, starts []int, oldKeys, newKeys []string, newItems []any, indent string,
+) ([]listChunk, bool) {
+	common := make([][]int, len(oldKeys)+1)
+	for i := range common {
+		common[i] = make([]int, len(newKeys)+1)
+	}
+	for i := len(oldKeys) - 1; i >= 0; i-- {
+		for j := len(newKeys) - 1; j >= 0; j-- {
+			if oldKeys[i] == newKeys[j] {
+				common[i][j] = common[i+1][j+1] + 1
+			} else {
+				common[i][j] = max(common[i+1][j], common[i][j+1])
+			}
+		}
+	}
+
+	// Walk the subsequence, noting which old entry each new one keeps (-1 when added) and
+	// which old entries were dropped from it
+	kept := make([]int, len(newKeys))
+	var dropped []int
+	for i, j := 0, 0; i < len(oldKeys) || j < len(newKeys); {
+		switch {
+		case i < len(oldKeys) && j < len(newKeys) && oldKeys[i] == newKeys[j]:
+			kept[j] = i
+			i++
+			j++
+		case j < len(newKeys) && (i == len(oldKeys) || common[i][j+1] >= common[i+1][j]):
+			kept[j] = -1
+			j++
+		default:
+			dropped = append(dropped, i)
+			i++
+		}
+	}
+
+	// A dropped entry whose key was added elsewhere moved there
+	moved := make(map[int]bool)
+	for j := range newKeys {
+		if kept[j] >= 0 {
+			continue
+		}
+		for _, i := range dropped {
+			if !moved[i] && oldKeys[i] == newKeys[j] {
+				kept[j] = i
+				moved[i] = true
+				break
+			}
+		}
+	}
+
+	// Entries dropped for good are replaced, in order, by the entries added between the same
+	// two unchanged ones
+	var chunks []listChunk
+	var removed []int
+	flush := func(added []int) bool {
+		n := 0
+		for _, j := range added {
+			if kept[j] >= 0 {
+				chunks = append(chunks, old[kept[j]])
+				continue
+			}
+			text, err := renderListItem(newItems[j], indent)
+			if err != nil {
+				return false
+			}
+			chunk := listChunk{text: text}
+			if n < len(removed) {
+				// A replaced entry keeps its head comment and the spacing after it
+				i := removed[n]
+				chunk.text = append(slices.Clone(old[i].text[:dashes[i]-starts[i]]), text...)
+				chunk.tail = old[i].tail
+			}
+			n++
+			chunks = append(chunks, chunk)
+		}
+		removed = nil
+		return true
+	}
+
+	i, j := 0, 0
+	var added []int
+	for i < len(oldKeys) || j < len(newKeys) {
+		switch {
+		case i < len(oldKeys) && j < len(newKeys) && kept[j] == i && !moved[i]:
+			if !flush(added) {
+				return nil, false
+			}
+			added = nil
+			chunks = append(chunks, old[i])
+			i++
+			j++
+		case j < len(newKeys) && (kept[j] < 0 || moved[kept[j]]):
+			added = append(added, j)
+			j++
+		default:
+			if !moved[i] {
+				removed = append(removed, i)
+			}
+			i++
+		}
+	}
+	return chunks, flush(added)
+}
+
+// joinChunks lays out list entries, separating entries that had no spacing of their own
+// with a blank line when the list was spaced
+func joinChunks(chunks []listChunk, spaced bool) []string {
+	var lines []string
+	for n, chunk := range chunks {
+		lines = append(lines, chunk.text...)
+		tail := chunk.tail
+		if n == len(chunks)-1 {
+			for len(tail) > 0 && isBlankLine(tail[len(tail)-1]) {
+				tail = tail[:len(tail)-1]
+			}
+		} else if len(tail) == 0 && spaced {
+			tail = []string{"\n"}
+		}
+		lines = append(lines, tail...)
+	}
+	return lines
+}
+
+// appendList returns the edit adding a new list under key at the end of the file
+func appendList(lines []string, key string, newItems []any) (lineEdit, bool) {
+	var added []string
+	if len(lines) > 0 && !isBlankLine(lines[len(lines)-1]) {
+		added = append(added, "\n")
+	}
+	added = append(added, key+":\n")
+	for _, item := range newItems {
+		text, err := renderListItem(item, "  ")
+		if err != nil {
+			return lineEdit{}, false
+		}
+		added = append(added, text...)
+	}
+	return lineEdit{start: len(lines), end: len(lines), lines: added}, true
+}
+
+// renderListItem marshals item as a block list entry indented by indent
+func renderListItem(item any, indent string) ([]string, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode([]any{item}); err != nil {
+		return nil, fmt.Errorf("failed to marshal list entry: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("failed to marshal list entry: %w", err)
+	}
+
+	lines := strings.SplitAfter(buf.String(), "\n")
+	lines = lines[:len(lines)-1]
+	for i, line := range lines {
+		if line != "\n" {
+			lines[i] = indent + line
+		}
+	}
+	return lines, nil
+}
+
+func isBlankLine(line string) bool {
+	return strings.TrimSpace(line) == ""
+}
+
+func isCommentLine(line string) bool {
+	return strings.HasPrefix(strings.TrimSpace(line), "#")
+}
+
+func leadingSpace(line string) string {
+	return line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+}
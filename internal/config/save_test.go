@@ -0,0 +1,300 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const commentedConfig = `# Team bumpers config
+settings:
+  show_rule_source: true   # keep this
+
+rules:
+  # Use just for tests
+  - match: "^go test"
+    send: "Use just test"   # inline note
+
+  # Never force push
+  - id: no-force
+    match: "git push.*--force"
+    send: "No force pushing"
+
+  - match: "^make"
+    send: "Use just"
+
+# Commands are below
+commands:
+  - name: hello
+    send: "Hi"
+`
+
+func TestSaveKeepsFormatting(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		edit func(t *testing.T, cfg *Config)
+		name string
+		want string
+	}{
+		{
+			name: "unchanged",
+			edit: func(*testing.T, *Config) {},
+			want: commentedConfig,
+		},
+		{
+			name: "add rule",
+			edit: func(_ *testing.T, cfg *Config) {
+				cfg.AddRule(Rule{Match: "^npm", Send: "Use pnpm"})
+			},
+			want: `# Team bumpers config
+settings:
+  show_rule_source: true   # keep this
+
+rules:
+  # Use just for tests
+  - match: "^go test"
+    send: "Use just test"   # inline note
+
+  # Never force push
+  - id: no-force
+    match: "git push.*--force"
+    send: "No force pushing"
+
+  - match: "^make"
+    send: "Use just"
+
+  - match: ^npm
+    send: Use pnpm
+
+# Commands are below
+commands:
+  - name: hello
+    send: "Hi"
+`,
+		},
+		{
+			name: "sort rules",
+			edit: func(t *testing.T, cfg *Config) {
+				t.Helper()
+				_, err := cfg.SortRules(SortByPattern)
+				require.NoError(t, err)
+			},
+			want: `# Team bumpers config
+settings:
+  show_rule_source: true   # keep this
+
+rules:
+  # Use just for tests
+  - match: "^go test"
+    send: "Use just test"   # inline note
+
+  - match: "^make"
+    send: "Use just"
+
+  # Never force push
+  - id: no-force
+    match: "git push.*--force"
+    send: "No force pushing"
+
+# Commands are below
+commands:
+  - name: hello
+    send: "Hi"
+`,
+		},
+		{
+			name: "sort rules by message",
+			edit: func(t *testing.T, cfg *Config) {
+				t.Helper()
+				_, err := cfg.SortRules(SortByMessage)
+				require.NoError(t, err)
+			},
+			want: `# Team bumpers config
+settings:
+  show_rule_source: true   # keep this
+
+rules:
+  # Never force push
+  - id: no-force
+    match: "git push.*--force"
+    send: "No force pushing"
+
+  - match: "^make"
+    send: "Use just"
+
+  # Use just for tests
+  - match: "^go test"
+    send: "Use just test"   # inline note
+
+# Commands are below
+commands:
+  - name: hello
+    send: "Hi"
+`,
+		},
+		{
+			name: "remove middle rule",
+			edit: func(t *testing.T, cfg *Config) {
+				require.NoError(t, cfg.DeleteRule(1))
+			},
+			want: `# Team bumpers config
+settings:
+  show_rule_source: true   # keep this
+
+rules:
+  # Use just for tests
+  - match: "^go test"
+    send: "Use just test"   # inline note
+
+  - match: "^make"
+    send: "Use just"
+
+# Commands are below
+commands:
+  - name: hello
+    send: "Hi"
+`,
+		},
+		{
+			name: "remove last rule",
+			edit: func(t *testing.T, cfg *Config) {
+				require.NoError(t, cfg.DeleteRule(2))
+			},
+			want: `# Team bumpers config
+settings:
+  show_rule_source: true   # keep this
+
+rules:
+  # Use just for tests
+  - match: "^go test"
+    send: "Use just test"   # inline note
+
+  # Never force push
+  - id: no-force
+    match: "git push.*--force"
+    send: "No force pushing"
+
+# Commands are below
+commands:
+  - name: hello
+    send: "Hi"
+`,
+		},
+		{
+			name: "update rule keeps its comment",
+			edit: func(t *testing.T, cfg *Config) {
+				require.NoError(t, cfg.UpdateRule(1, Rule{Match: "git push.*(-f|--force)", Send: "No force pushing"}))
+			},
+			want: `# Team bumpers config
+settings:
+  show_rule_source: true   # keep this
+
+rules:
+  # Use just for tests
+  - match: "^go test"
+    send: "Use just test"   # inline note
+
+  # Never force push
+  - id: no-force
+    match: git push.*(-f|--force)
+    send: No force pushing
+
+  - match: "^make"
+    send: "Use just"
+
+# Commands are below
+commands:
+  - name: hello
+    send: "Hi"
+`,
+		},
+		{
+			name: "add command and session note",
+			edit: func(_ *testing.T, cfg *Config) {
+				cfg.Commands = append(cfg.Commands, Command{Name: "bye", Send: "Bye"})
+				cfg.Session = append(cfg.Session, Session{Add: "Read the README"})
+			},
+			want: commentedConfig + `  - name: bye
+    send: Bye
+
+session:
+  - add: Read the README
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), "bumpers.yml")
+			require.NoError(t, os.WriteFile(path, []byte(commentedConfig), 0o600))
+			cfg, err := Load(path)
+			require.NoError(t, err)
+
+			tt.edit(t, cfg)
+			rewritten, err := cfg.SaveEdits(path)
+			require.NoError(t, err)
+			assert.False(t, rewritten)
+
+			data, err := os.ReadFile(path) // #nosec G304 -- path is a test file
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, string(data))
+		})
+	}
+}
+
+func TestSaveRewritesWholeFile(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		edit     func(cfg *Config)
+		name     string
+		file     string
+		contents string
+	}{
+		{
+			name:     "settings changed",
+			file:     "bumpers.yml",
+			contents: commentedConfig,
+			edit:     func(cfg *Config) { cfg.Settings.Strict = true },
+		},
+		{
+			name:     "flow style rules",
+			file:     "bumpers.yml",
+			contents: "rules: [{match: rm, send: no}] # flow\n",
+			edit:     func(cfg *Config) { cfg.AddRule(Rule{Match: "^npm", Send: "Use pnpm"}) },
+		},
+		{
+			name:     "json config",
+			file:     "bumpers.json",
+			contents: `{"rules": [{"match": "rm", "send": "no"}]}`,
+			edit:     func(cfg *Config) { cfg.AddRule(Rule{Match: "^npm", Send: "Use pnpm"}) },
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), tt.file)
+			require.NoError(t, os.WriteFile(path, []byte(tt.contents), 0o600))
+			cfg, err := Load(path)
+			require.NoError(t, err)
+
+			tt.edit(cfg)
+			rewritten, err := cfg.SaveEdits(path)
+			require.NoError(t, err)
+			assert.True(t, rewritten)
+
+			saved, err := Load(path)
+			require.NoError(t, err)
+			assert.Equal(t, cfg.Rules, saved.Rules)
+			assert.Equal(t, cfg.Settings, saved.Settings)
+		})
+	}
+}
@@ -0,0 +1,217 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+)
+
+// defaultToolPattern is the tool filter applied to rules without one
+const defaultToolPattern = "^Bash$"
+
+// ShadowedRules describes rules that can never match, or that an earlier rule will usually
+// match first: exact duplicate patterns, and later patterns that extend an earlier one
+// (e.g. "^go " before "^go test"). Only rules with the same event and overlapping tool
+// and source scopes are compared. This is a heuristic; matching itself is unaffected.
+func (c *Config) ShadowedRules() []string {
+	var warnings []string
+	for j := range c.Rules {
+		later := &c.Rules[j]
+		for i := range j {
+			earlier := &c.Rules[i]
+			earlierMatch := earlier.GetMatch()
+			if len(earlier.Except) > 0 || earlierMatch.HasArgLimits() || earlierMatch.HasWordCountLimits() || !sameRuleScope(earlier, later) {
+				continue
+			}
+
+			// Anchors change what a pattern matches, so only equally anchored patterns are compared
+			laterMatch := later.GetMatch()
+			if earlierMatch.Anchor != laterMatch.Anchor {
+				continue
+			}
+
+			earlierPattern, laterPattern := earlierMatch.Pattern, laterMatch.Pattern
+			if earlierPattern == laterPattern {
+				warnings = append(warnings, fmt.Sprintf(
+					"rule %d duplicates the pattern of rule %d ('%s') and will never match: "+
+						"remove it or merge it into rule %d", j+1, i+1, laterPattern, i+1))
+				break
+			}
+
+			// With output.select: specific the longer pattern is preferred, so order doesn't matter
+			// A fully anchored pattern never matches the longer values a pattern extending it does
+			if c.Output.Select != SelectSpecific && earlierMatch.Anchor != AnchorFull &&
+				patternShadows(earlierPattern, laterPattern) {
+				warnings = append(warnings, fmt.Sprintf(
+					"rule %d (pattern '%s') is shadowed by earlier rule %d (pattern '%s'): "+
+						"move rule %d above rule %d", j+1, laterPattern, i+1, earlierPattern, j+1, i+1))
+				break
+			}
+		}
+	}
+	return warnings
+}
+
+// sameRuleScope reports whether two rules can fire for the same event, tool and source.
+// Rules in shadow mode are matched separately, so they neither hide nor are hidden.
+func sameRuleScope(a, b *Rule) bool {
+	matchA, activeA := a.EffectiveMatch()
+	matchB, activeB := b.EffectiveMatch()
+	if !a.IsEnabled() || !b.IsEnabled() || !activeA || !activeB {
+		return false
+	}
+	if matchA.Shadow || matchB.Shadow {
+		return false
+	}
+	if normalizeEvent(matchA.Event) != normalizeEvent(matchB.Event) {
+		return false
+	}
+	// Fields picked by source_field_regex depend on the tool input, so only identical
+	// selectors are compared
+	if matchA.SourceFieldRegex != matchB.SourceFieldRegex {
+		return false
+	}
+	// Fields narrow a rule by the tool input, so only rules requiring the same fields are compared
+	if !maps.Equal(matchA.Fields, matchB.Fields) {
+		return false
+	}
+	if !sourcesOverlap(matchA.Sources, matchB.Sources) {
+		return false
+	}
+	return toolsOverlap(a.Tool, b.Tool)
+}
+
+// normalizeEvent maps the empty event to its "pre" default
+func normalizeEvent(event string) string {
+	if event == "" {
+		return "pre"
+	}
+	return event
+}
+
+// sourcesOverlap reports whether two source lists can check the same field. An empty list
+// checks the tool's default fields, so it overlaps with anything.
+func sourcesOverlap(a, b []string) bool {
+	if len(a) == 0 || len(b) == 0 {
+		return true
+	}
+	for _, source := range a {
+		for _, other := range b {
+			if source == other {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// toolsOverlap reports whether two tool filters can match the same tool name. Filters
+// naming literal tools are compared by name; two open-ended filters are assumed to overlap.
+func toolsOverlap(a, b string) bool {
+	if a == "" {
+		a = defaultToolPattern
+	}
+	if b == "" {
+		b = defaultToolPattern
+	}
+	if strings.EqualFold(a, b) {
+		return true
+	}
+
+	namesA, literalA := toolNames(a)
+	namesB, literalB := toolNames(b)
+	switch {
+	case literalA && literalB:
+		for _, name := range namesA {
+			if containsFold(namesB, name) {
+				return true
+			}
+		}
+		return false
+	case literalA:
+		return anyToolMatches(b, namesA)
+	case literalB:
+		return anyToolMatches(a, namesB)
+	default:
+		return true
+	}
+}
+
+// toolNames returns the tool names an anchored filter like "^Bash$" or "^(Write|Edit)$"
+// accepts, reporting false when the filter isn't a fixed list of names
+func toolNames(pattern string) ([]string, bool) {
+	if !strings.HasPrefix(pattern, "^") || !strings.HasSuffix(pattern, "$") {
+		return nil, false
+	}
+	re, err := syntax.Parse(strings.TrimSuffix(strings.TrimPrefix(pattern, "^"), "$"), syntax.Perl)
+	if err != nil {
+		return nil, false
+	}
+	if re.Op == syntax.OpCapture {
+		re = re.Sub[0]
+	}
+
+	alternatives := []*syntax.Regexp{re}
+	if re.Op == syntax.OpAlternate {
+		alternatives = re.Sub
+	}
+
+	names := make([]string, 0, len(alternatives))
+	for _, alt := range alternatives {
+		if alt.Op != syntax.OpLiteral {
+			return nil, false
+		}
+		names = append(names, string(alt.Rune))
+	}
+	return names, true
+}
+
+// anyToolMatches reports whether the tool filter matches any of names
+func anyToolMatches(pattern string, names []string) bool {
+	re, err := regexp.Compile("(?i)" + pattern)
+	if err != nil {
+		return false
+	}
+	for _, name := range names {
+		if re.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsFold reports whether names contains name, ignoring case
+func containsFold(names []string, name string) bool {
+	for _, candidate := range names {
+		if strings.EqualFold(candidate, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// patternShadows reports whether every value matching later also matches earlier, judged
+// by earlier being a leading part of later ("^go " and "^go test") or an unanchored literal
+// that later's literal prefix always contains ("rm" and "^rm -rf")
+func patternShadows(earlier, later string) bool {
+	if _, err := regexp.Compile(earlier); err != nil {
+		return false
+	}
+	laterRe, err := regexp.Compile(later)
+	if err != nil {
+		return false
+	}
+
+	// An end anchor or trailing escape means earlier doesn't carry over into later's suffix
+	if strings.HasPrefix(later, earlier) && !strings.HasSuffix(earlier, "$") && !strings.HasSuffix(earlier, `\`) {
+		return true
+	}
+
+	if regexp.QuoteMeta(earlier) != earlier {
+		return false
+	}
+	prefix, _ := laterRe.LiteralPrefix()
+	return prefix != "" && strings.Contains(prefix, earlier)
+}
@@ -0,0 +1,179 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShadowedRules(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		yaml     string
+		warnings []string
+	}{
+		{
+			name: "exact duplicate",
+			yaml: `rules:
+  - match: "^rm -rf"
+    send: "No"
+  - match: "^rm -rf"
+    send: "Also no"`,
+			warnings: []string{
+				"rule 2 duplicates the pattern of rule 1 ('^rm -rf') and will never match: remove it or merge it into rule 1",
+			},
+		},
+		{
+			name: "prefix shadows longer pattern",
+			yaml: `rules:
+  - match: "^go "
+    send: "Use just"
+  - match: "^go test"
+    send: "Use just test"`,
+			warnings: []string{
+				"rule 2 (pattern '^go test') is shadowed by earlier rule 1 (pattern '^go '): move rule 2 above rule 1",
+			},
+		},
+		{
+			name: "unanchored literal shadows anchored pattern",
+			yaml: `rules:
+  - match: "rm"
+    send: "Careful"
+  - match: "^rm -rf"
+    send: "Never"`,
+			warnings: []string{
+				"rule 2 (pattern '^rm -rf') is shadowed by earlier rule 1 (pattern 'rm'): move rule 2 above rule 1",
+			},
+		},
+		{
+			name: "wildcard tool overlaps default Bash scope",
+			yaml: `rules:
+  - match: "secret"
+    tool: ".*"
+    send: "No secrets"
+  - match: "secret"
+    send: "No secrets in commands"`,
+			warnings: []string{
+				"rule 2 duplicates the pattern of rule 1 ('secret') and will never match: remove it or merge it into rule 1",
+			},
+		},
+		{
+			name: "specific rule first is fine",
+			yaml: `rules:
+  - match: "^go test"
+    send: "Use just test"
+  - match: "^go "
+    send: "Use just"`,
+		},
+		{
+			name: "non-overlapping tools",
+			yaml: `rules:
+  - match: "secret"
+    tool: "^Bash$"
+    send: "No"
+  - match: "secret"
+    tool: "^(Write|Edit)$"
+    send: "No"`,
+		},
+		{
+			name: "different events",
+			yaml: `rules:
+  - match: "error"
+    send: "Pre"
+  - match:
+      pattern: "error"
+      event: "post"
+    send: "Post"`,
+		},
+		{
+			name: "disjoint sources",
+			yaml: `rules:
+  - match:
+      pattern: "deploy"
+      sources: ["#intent"]
+    send: "Intent"
+  - match:
+      pattern: "deploy"
+      sources: ["command"]
+    send: "Command"`,
+		},
+		{
+			name: "earlier rule with exceptions",
+			yaml: `rules:
+  - match: "^go "
+    except: ["^go test"]
+    send: "Use just"
+  - match: "^go test"
+    send: "Use just test"`,
+		},
+		{
+			name: "end anchor does not shadow",
+			yaml: `rules:
+  - match: "^go$"
+    send: "Bare go"
+  - match: "^go$|^go build"
+    send: "Go build"`,
+		},
+		{
+			name: "full anchor does not shadow",
+			yaml: `rules:
+  - match:
+      pattern: "go"
+      anchor: full
+    send: "Bare go"
+  - match:
+      pattern: "go build"
+      anchor: full
+    send: "Go build"`,
+		},
+		{
+			name: "different anchors are not duplicates",
+			yaml: `rules:
+  - match:
+      pattern: "rm"
+      anchor: start
+    send: "Careful"
+  - match: "rm"
+    send: "Anywhere"`,
+		},
+		{
+			name: "specific select mode ignores ordering",
+			yaml: `output:
+  select: specific
+rules:
+  - match: "^go "
+    send: "Use just"
+  - match: "^go test"
+    send: "Use just test"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			partial, err := LoadPartial([]byte(tt.yaml))
+			require.NoError(t, err)
+			assert.Equal(t, tt.warnings, partial.RuleWarnings)
+		})
+	}
+}
+
+func TestShadowedRulesKeepsOriginalIndices(t *testing.T) {
+	t.Parallel()
+
+	partial, err := LoadPartial([]byte(`rules:
+  - match: "[invalid"
+    send: "Broken"
+  - match: "^make"
+    send: "Use just"
+  - match: "^make"
+    send: "Use just again"`))
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"rule 3 duplicates the pattern of rule 2 ('^make') and will never match: remove it or merge it into rule 2",
+	}, partial.RuleWarnings)
+}
@@ -0,0 +1,53 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Keys bumpers rules sort can order rules by
+const (
+	SortByPattern  = "pattern"
+	SortByMessage  = "message"
+	SortByPriority = "priority"
+)
+
+// SortRules reorders the rules by pattern or message, alphabetically and ignoring case, or
+// by priority, highest first. Disabled rules go to the bottom, and rules with equal keys
+// keep their relative order. It returns each rule's previous index in the new order.
+func (c *Config) SortRules(by string) ([]int, error) {
+	var less func(a, b *Rule) bool
+	switch by {
+	case SortByPattern:
+		less = func(a, b *Rule) bool {
+			return strings.ToLower(a.GetMatch().Pattern) < strings.ToLower(b.GetMatch().Pattern)
+		}
+	case SortByMessage:
+		less = func(a, b *Rule) bool { return strings.ToLower(a.Send) < strings.ToLower(b.Send) }
+	case SortByPriority:
+		less = func(a, b *Rule) bool { return a.Priority > b.Priority }
+	default:
+		return nil, fmt.Errorf("invalid sort key '%s': must be %s, %s or %s",
+			by, SortByPattern, SortByMessage, SortByPriority)
+	}
+
+	order := make([]int, len(c.Rules))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := &c.Rules[order[i]], &c.Rules[order[j]]
+		if a.IsEnabled() != b.IsEnabled() {
+			return a.IsEnabled()
+		}
+		return less(a, b)
+	})
+
+	sorted := make([]Rule, len(c.Rules))
+	for i, index := range order {
+		sorted[i] = c.Rules[index]
+	}
+	c.Rules = sorted
+	return order, nil
+}
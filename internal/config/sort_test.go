@@ -0,0 +1,60 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSortRules(t *testing.T) {
+	t.Parallel()
+
+	const yaml = `rules:
+  - match: "^npm"
+    send: "Use pnpm"
+    priority: 1
+  - match: "^Go test"
+    send: "Use just test"
+  - match: "^curl"
+    send: "Avoid network calls"
+    enabled: false
+    priority: 9
+  - match: "^apt"
+    send: "Use nix"
+    priority: 1
+  - match: "^go build"
+    send: "Use just build"
+    priority: 5`
+
+	tests := []struct {
+		by        string
+		wantOrder []int
+	}{
+		{by: SortByPattern, wantOrder: []int{3, 4, 1, 0, 2}},
+		{by: SortByMessage, wantOrder: []int{4, 1, 3, 0, 2}},
+		{by: SortByPriority, wantOrder: []int{4, 0, 3, 1, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.by, func(t *testing.T) {
+			t.Parallel()
+
+			config, err := LoadFromYAML([]byte(yaml))
+			require.NoError(t, err)
+			original := append([]Rule(nil), config.Rules...)
+
+			order, err := config.SortRules(tt.by)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantOrder, order, "disabled rules go last and ties keep their order")
+			for i, previous := range order {
+				assert.Equal(t, original[previous].GetMatch().Pattern, config.Rules[i].GetMatch().Pattern)
+			}
+		})
+	}
+
+	config, err := LoadFromYAML([]byte(yaml))
+	require.NoError(t, err)
+	_, err = config.SortRules("length")
+	require.ErrorContains(t, err, "invalid sort key 'length'")
+}
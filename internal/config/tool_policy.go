@@ -0,0 +1,79 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Values accepted by settings.tool_policy
+const (
+	ToolPolicyAllow = "allow"
+	ToolPolicyDeny  = "deny"
+)
+
+// DefaultToolDeniedMessage is sent for tools blocked by tool_policy: deny when
+// settings.tool_denied_message is not set
+const DefaultToolDeniedMessage = "Tool '{{.ToolName}}' is not allowed by this project's bumpers " +
+	"tool_policy. Ask the user to add it to settings.allowed_tools if it's needed."
+
+// toolGroups are the named groups allowed_tools entries can refer to with an @ prefix
+var toolGroups = map[string][]string{
+	"@readonly": {"Read", "Grep", "Glob", "LS"},
+}
+
+// DeniesUnknownTools reports whether tool_policy is deny
+func (s *Settings) DeniesUnknownTools() bool {
+	return s.ToolPolicy == ToolPolicyDeny
+}
+
+// ToolAllowed reports whether tool_policy lets toolName run: always under the allow
+// policy, otherwise only when it's in a group or fully matches a regex in allowed_tools.
+// Invalid regexes never match.
+func (s *Settings) ToolAllowed(toolName string) bool {
+	if !s.DeniesUnknownTools() {
+		return true
+	}
+	for _, entry := range s.AllowedTools {
+		if tools, ok := toolGroups[entry]; ok {
+			if slices.Contains(tools, toolName) {
+				return true
+			}
+			continue
+		}
+		if re, err := regexp.Compile("^(?:" + entry + ")$"); err == nil && re.MatchString(toolName) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetToolDeniedMessage returns tool_denied_message or DefaultToolDeniedMessage
+func (s *Settings) GetToolDeniedMessage() string {
+	if s.ToolDeniedMessage != "" {
+		return s.ToolDeniedMessage
+	}
+	return DefaultToolDeniedMessage
+}
+
+// validateToolPolicy checks tool_policy and the allowed_tools entries
+func (s *Settings) validateToolPolicy() error {
+	switch s.ToolPolicy {
+	case "", ToolPolicyAllow, ToolPolicyDeny:
+	default:
+		return fmt.Errorf("invalid tool_policy '%s': must be 'allow' or 'deny'", s.ToolPolicy)
+	}
+	for _, entry := range s.AllowedTools {
+		if strings.HasPrefix(entry, "@") {
+			if _, ok := toolGroups[entry]; !ok {
+				return fmt.Errorf("invalid allowed_tools entry '%s': unknown tool group", entry)
+			}
+			continue
+		}
+		if _, err := regexp.Compile(entry); err != nil {
+			return fmt.Errorf("invalid allowed_tools entry '%s': %w", entry, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+)
+
+// When holds conditions checked before a rule's pattern. The rule only matches while all
+// of them hold.
+type When struct {
+	Files []FileCondition `yaml:"files,omitempty" mapstructure:"files"`
+}
+
+// FileCondition checks the project's files, relative to the project root. A path condition
+// holds when the path's existence equals exists (true by default); a glob condition holds
+// when more than count_gt files match it (0 by default).
+type FileCondition struct {
+	Exists  *bool  `yaml:"exists,omitempty" mapstructure:"exists"`
+	CountGt *int   `yaml:"count_gt,omitempty" mapstructure:"count_gt"`
+	Path    string `yaml:"path,omitempty" mapstructure:"path"`
+	Glob    string `yaml:"glob,omitempty" mapstructure:"glob"`
+}
+
+// ShouldExist reports whether a path condition wants the path to exist
+func (f *FileCondition) ShouldExist() bool {
+	return f.Exists == nil || *f.Exists
+}
+
+// MinCount returns the number of matches a glob condition must exceed
+func (f *FileCondition) MinCount() int {
+	if f.CountGt == nil {
+		return 0
+	}
+	return *f.CountGt
+}
+
+// HasFileConditions reports whether the rule has when.files conditions
+func (r *Rule) HasFileConditions() bool {
+	return r.When != nil && len(r.When.Files) > 0
+}
+
+// validateWhen checks the rule's when conditions
+func (r *Rule) validateWhen() error {
+	if !r.HasFileConditions() {
+		return nil
+	}
+	if r.GetMatch().Event == EventSession {
+		return errors.New("when.files is only supported on 'pre' and 'post' event rules")
+	}
+	for i := range r.When.Files {
+		if err := r.When.Files[i].validate(); err != nil {
+			return fmt.Errorf("when.files entry %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+func (f *FileCondition) validate() error {
+	switch {
+	case f.Path == "" && f.Glob == "":
+		return errors.New("path or glob is required")
+	case f.Path != "" && f.Glob != "":
+		return errors.New("path and glob cannot both be set")
+	case f.Path != "":
+		if filepath.IsAbs(f.Path) {
+			return fmt.Errorf("path '%s' must be relative to the project root", f.Path)
+		}
+		if f.CountGt != nil {
+			return errors.New("count_gt is only supported with glob")
+		}
+	default:
+		if filepath.IsAbs(f.Glob) {
+			return fmt.Errorf("glob '%s' must be relative to the project root", f.Glob)
+		}
+		if _, err := filepath.Match(f.Glob, ""); err != nil {
+			return fmt.Errorf("invalid glob '%s': %w", f.Glob, err)
+		}
+		if f.Exists != nil {
+			return errors.New("exists is only supported with path")
+		}
+		if f.MinCount() < 0 {
+			return fmt.Errorf("invalid count_gt %d: must not be negative", f.MinCount())
+		}
+	}
+	return nil
+}
@@ -16,4 +16,9 @@ const (
 
 	// FieldSource is the JSON field name for event sources
 	FieldSource = "source"
+	// FieldMessage is the JSON field name for notification messages
+	FieldMessage = "message"
+
+	// FieldToolCalls is the JSON field name for the tool calls of a batched PreToolUse hook
+	FieldToolCalls = "tool_calls"
 )
@@ -14,4 +14,6 @@ func TestFieldConstants_ShouldExist(t *testing.T) {
 	assert.Equal(t, "hook_event_name", FieldHookEventName)
 	assert.Equal(t, "session_id", FieldSessionID)
 	assert.Equal(t, "source", FieldSource)
+	assert.Equal(t, "message", FieldMessage)
+	assert.Equal(t, "tool_calls", FieldToolCalls)
 }
@@ -13,6 +13,8 @@ const (
 
 	// PostToolUseEvent is the hook event name for post-tool-use events
 	PostToolUseEvent = "PostToolUse"
+	// NotificationEvent is the hook event name for notification events
+	NotificationEvent = "Notification"
 )
 
 // Session start sources
@@ -22,4 +24,10 @@ const (
 
 	// SessionSourceClear indicates a session started from a clear command
 	SessionSourceClear = "clear"
+
+	// SessionSourceResume indicates a session resumed with --resume or --continue
+	SessionSourceResume = "resume"
+
+	// SessionSourceCompact indicates a session restarted after compaction
+	SessionSourceCompact = "compact"
 )
@@ -14,6 +14,15 @@ const (
 	// DatabaseFilename is the default database file name for bumpers.
 	DatabaseFilename = "bumpers.db"
 
+	// StateDatabaseFilename is the database file name for project state, kept apart from the cache.
+	StateDatabaseFilename = "state.db"
+
+	// AuditLogFilename is the default file rules with the audit action append to.
+	AuditLogFilename = "audit.jsonl"
+
 	// SettingsFilename is the Claude settings file name that bumpers modifies.
 	SettingsFilename = "settings.local.json"
+
+	// IgnoreFilename is the project root file listing paths bumpers should not process.
+	IgnoreFilename = ".bumpersignore"
 )
@@ -0,0 +1,32 @@
+// Package display formats terminal output, leaving out ANSI escape codes when color is off
+package display
+
+import "github.com/fatih/color"
+
+// ANSI color codes accepted by Colorize
+const (
+	Red    = "31"
+	Green  = "32"
+	Yellow = "33"
+	Cyan   = "36"
+)
+
+// ColorEnabled reports whether output may contain ANSI colors. Color is off for --no-color,
+// when NO_COLOR is set, TERM is dumb or stdout isn't a terminal.
+func ColorEnabled() bool {
+	return !color.NoColor
+}
+
+// DisableColor turns off colored output for the rest of the process, as --no-color does
+func DisableColor() {
+	color.NoColor = true
+}
+
+// Colorize wraps text in the ANSI escape for colorCode, such as Green, or returns it
+// unchanged when color is off
+func Colorize(text, colorCode string) string {
+	if !ColorEnabled() || colorCode == "" {
+		return text
+	}
+	return "\x1b[" + colorCode + "m" + text + "\x1b[0m"
+}
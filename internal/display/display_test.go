@@ -0,0 +1,31 @@
+package display
+
+import (
+	"testing"
+
+	"github.com/fatih/color"
+)
+
+func TestColorize(t *testing.T) { //nolint:paralleltest // sets the global color.NoColor
+	previous := color.NoColor
+	t.Cleanup(func() { color.NoColor = previous })
+
+	color.NoColor = false
+	if !ColorEnabled() {
+		t.Fatal("Expected color to be enabled")
+	}
+	if got := Colorize("[✓]", Green); got != "\x1b[32m[✓]\x1b[0m" {
+		t.Errorf("Expected green escape codes, got %q", got)
+	}
+	if got := Colorize("plain", ""); got != "plain" {
+		t.Errorf("Expected no escape codes without a color code, got %q", got)
+	}
+
+	DisableColor()
+	if ColorEnabled() {
+		t.Fatal("Expected color to be disabled")
+	}
+	if got := Colorize("[✗]", Red); got != "[✗]" {
+		t.Errorf("Expected plain text with color off, got %q", got)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"os"
 
 	"github.com/wizzomafizzo/bumpers/internal/constants"
 )
@@ -16,6 +17,7 @@ const (
 	UserPromptSubmitHook
 	PostToolUseHook
 	SessionStartHook
+	NotificationHook
 )
 
 // String returns a human-readable string representation of the hook type
@@ -32,20 +34,34 @@ func (h HookType) String() string {
 		return "PostToolUse"
 	case SessionStartHook:
 		return constants.SessionStartEvent
+	case NotificationHook:
+		return constants.NotificationEvent
 	default:
 		return "Unknown"
 	}
 }
 
 type HookEvent struct {
-	ToolInput      map[string]any `json:"tool_input"`
-	ToolName       string         `json:"tool_name"`
-	TranscriptPath string         `json:"transcript_path"`
-	ToolUseID      string         `json:"tool_use_id"`
-	ToolResponse   any            `json:"tool_response"`
-	SessionID      string         `json:"session_id"`
-	HookEventName  string         `json:"hook_event_name"`
-	CWD            string         `json:"cwd"`
+	ToolInput        map[string]any `json:"tool_input"`
+	ToolName         string         `json:"tool_name"`
+	TranscriptPath   string         `json:"transcript_path"`
+	ToolUseID        string         `json:"tool_use_id"`
+	ToolResponse     any            `json:"tool_response"`
+	SessionID        string         `json:"session_id"`
+	HookEventName    string         `json:"hook_event_name"`
+	WorkingDirectory string         `json:"cwd"`
+}
+
+// GetWorkingDirectory returns the event's working directory, or the process's when the
+// hook input didn't include one
+func (e *HookEvent) GetWorkingDirectory() string {
+	if e.WorkingDirectory != "" {
+		return e.WorkingDirectory
+	}
+	if dir, err := os.Getwd(); err == nil {
+		return dir
+	}
+	return ""
 }
 
 func ParseInput(reader io.Reader) (*HookEvent, error) {
@@ -83,6 +99,8 @@ func DetectHookType(reader io.Reader) (HookType, json.RawMessage, error) {
 				return UserPromptSubmitHook, json.RawMessage(data), nil
 			case constants.SessionStartEvent:
 				return SessionStartHook, json.RawMessage(data), nil
+			case constants.NotificationEvent:
+				return NotificationHook, json.RawMessage(data), nil
 			}
 		}
 	}
@@ -94,6 +112,10 @@ func DetectHookType(reader io.Reader) (HookType, json.RawMessage, error) {
 		}
 		return PreToolUseHook, json.RawMessage(data), nil
 	}
+	// Batched PreToolUse payloads carry their tool calls in a tool_calls array
+	if _, ok := generic[constants.FieldToolCalls].([]any); ok {
+		return PreToolUseHook, json.RawMessage(data), nil
+	}
 	if _, ok := generic[constants.FieldPrompt]; ok {
 		return UserPromptSubmitHook, json.RawMessage(data), nil
 	}
@@ -13,6 +13,7 @@ func TestHookTypeString(t *testing.T) {
 		{"UserPromptSubmit", UserPromptSubmitHook},
 		{"PostToolUse", PostToolUseHook},
 		{"SessionStart", SessionStartHook},
+		{"Notification", NotificationHook},
 	}
 
 	for _, tt := range tests {
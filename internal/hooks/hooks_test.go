@@ -1,6 +1,7 @@
 package hooks
 
 import (
+	"os"
 	"strings"
 	"testing"
 
@@ -68,6 +69,14 @@ func TestDetectHookType(t *testing.T) {
 			}`,
 			expected: PostToolUseHook,
 		},
+		{
+			name: "Batched PreToolUse hook",
+			jsonData: `{
+				"session_id": "abc123",
+				"tool_calls": [{"tool_name": "Bash", "tool_input": {"command": "ls"}, "tool_use_id": "t1"}]
+			}`,
+			expected: PreToolUseHook,
+		},
 		{
 			name: "SessionStart hook with startup source",
 			jsonData: `{
@@ -86,6 +95,15 @@ func TestDetectHookType(t *testing.T) {
 			}`,
 			expected: SessionStartHook,
 		},
+		{
+			name: "Notification hook",
+			jsonData: `{
+				"session_id": "ghi789",
+				"hook_event_name": "Notification",
+				"message": "Claude needs your permission to use Bash"
+			}`,
+			expected: NotificationHook,
+		},
 		{
 			name:     "Unknown hook",
 			jsonData: `{"unknown_field": "value"}`,
@@ -210,8 +228,29 @@ func TestParseInputWithPostToolUseFields(t *testing.T) {
 	}
 
 	// Verify cwd field is populated
-	if event.CWD != "/home/user/project" {
-		t.Errorf("Expected cwd '/home/user/project', got '%s'", event.CWD)
+	if event.WorkingDirectory != "/home/user/project" {
+		t.Errorf("Expected cwd '/home/user/project', got '%s'", event.WorkingDirectory)
+	}
+	if dir := event.GetWorkingDirectory(); dir != "/home/user/project" {
+		t.Errorf("Expected working directory '/home/user/project', got '%s'", dir)
+	}
+}
+
+func TestGetWorkingDirectoryFallsBackToProcess(t *testing.T) {
+	_, _ = testutil.NewTestContext(t)
+	t.Parallel()
+
+	event, err := ParseInput(strings.NewReader(`{"tool_name": "Bash", "tool_input": {"command": "ls"}}`))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	want, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get working directory: %v", err)
+	}
+	if dir := event.GetWorkingDirectory(); dir != want {
+		t.Errorf("Expected working directory '%s' without cwd, got '%s'", want, dir)
 	}
 }
 
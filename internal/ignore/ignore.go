@@ -0,0 +1,143 @@
+// Package ignore implements .bumpersignore matching using a gitignore-like glob syntax.
+package ignore
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// pattern is a single compiled ignore line
+type pattern struct {
+	re     *regexp.Regexp
+	negate bool
+}
+
+// Matcher reports whether project-relative paths are ignored
+type Matcher struct {
+	patterns []pattern
+}
+
+// Load reads and parses an ignore file. A missing file yields an empty matcher.
+func Load(path string) (*Matcher, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- path is the project ignore file
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return &Matcher{}, nil
+		}
+		return nil, fmt.Errorf("failed to read ignore file %s: %w", path, err)
+	}
+	return Parse(data)
+}
+
+// Parse compiles ignore file contents. Blank lines and lines starting with # are skipped,
+// a leading ! re-includes a previously ignored path, a leading / anchors the pattern to the
+// project root and a trailing / matches everything under a directory.
+func Parse(data []byte) (*Matcher, error) {
+	m := &Matcher{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		negate := strings.HasPrefix(line, "!")
+		line = strings.TrimPrefix(line, "!")
+
+		re, err := regexp.Compile(globToRegex(line))
+		if err != nil {
+			return nil, fmt.Errorf("invalid ignore pattern on line %d: %w", lineNum, err)
+		}
+		m.patterns = append(m.patterns, pattern{re: re, negate: negate})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to scan ignore file: %w", err)
+	}
+	return m, nil
+}
+
+// Empty reports whether the matcher has no patterns
+func (m *Matcher) Empty() bool {
+	return m == nil || len(m.patterns) == 0
+}
+
+// Match reports whether a slash-separated, project-relative path is ignored.
+// The last matching pattern wins, as in gitignore.
+func (m *Matcher) Match(relPath string) bool {
+	if m.Empty() {
+		return false
+	}
+
+	relPath = strings.TrimPrefix(filepath.ToSlash(relPath), "./")
+	ignored := false
+	for _, p := range m.patterns {
+		if p.re.MatchString(relPath) {
+			ignored = !p.negate
+		}
+	}
+	return ignored
+}
+
+// MatchPath reports whether path is ignored, resolving absolute paths against root.
+// Paths outside root are never ignored.
+func (m *Matcher) MatchPath(root, path string) bool {
+	if m.Empty() || path == "" {
+		return false
+	}
+
+	if filepath.IsAbs(path) {
+		rel, err := filepath.Rel(root, path)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return false
+		}
+		path = rel
+	}
+	return m.Match(path)
+}
+
+// globToRegex converts a gitignore-style glob into an anchored regular expression
+func globToRegex(glob string) string {
+	anchored := strings.HasPrefix(glob, "/")
+	glob = strings.TrimPrefix(glob, "/")
+	glob = strings.TrimSuffix(glob, "/")
+	if strings.Contains(glob, "/") {
+		anchored = true
+	}
+
+	var sb strings.Builder
+	_, _ = sb.WriteString("^")
+	if !anchored {
+		_, _ = sb.WriteString("(?:.*/)?")
+	}
+
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch {
+		case c == '*' && strings.HasPrefix(glob[i:], "**/"):
+			_, _ = sb.WriteString("(?:.*/)?")
+			i += 2
+		case c == '*' && strings.HasPrefix(glob[i:], "**"):
+			_, _ = sb.WriteString(".*")
+			i++
+		case c == '*':
+			_, _ = sb.WriteString("[^/]*")
+		case c == '?':
+			_, _ = sb.WriteString("[^/]")
+		default:
+			_, _ = sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	// A pattern matches the path itself or anything beneath it
+	_, _ = sb.WriteString("(?:/.*)?$")
+	return sb.String()
+}
@@ -0,0 +1,97 @@
+package ignore
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMatch(t *testing.T) {
+	t.Parallel()
+
+	m, err := Parse([]byte(`# vendored code
+vendor/
+*.pb.go
+/build
+docs/**/generated
+!vendor/keep.go
+`))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	tests := []struct {
+		path     string
+		expected bool
+	}{
+		{"vendor/lib/file.go", true},
+		{"vendor", true},
+		{"vendor/keep.go", false},
+		{"internal/api/service.pb.go", true},
+		{"service.pb.go", true},
+		{"build/output.bin", true},
+		{"cmd/build/main.go", false},
+		{"docs/api/v1/generated/index.md", true},
+		{"docs/generated", true},
+		{"internal/app/app.go", false},
+		{"./vendor/lib/file.go", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			t.Parallel()
+			if got := m.Match(tt.path); got != tt.expected {
+				t.Errorf("Match(%q) = %v, want %v", tt.path, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMatchPath(t *testing.T) {
+	t.Parallel()
+
+	m, err := Parse([]byte("vendor/\n"))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	root := filepath.Join(string(filepath.Separator), "project")
+	if !m.MatchPath(root, filepath.Join(root, "vendor", "lib.go")) {
+		t.Error("Expected absolute path inside root to be ignored")
+	}
+	if m.MatchPath(root, filepath.Join(string(filepath.Separator), "other", "vendor", "lib.go")) {
+		t.Error("Expected path outside root not to be ignored")
+	}
+	if m.MatchPath(root, "") {
+		t.Error("Expected empty path not to be ignored")
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	t.Parallel()
+
+	m, err := Load(filepath.Join(t.TempDir(), ".bumpersignore"))
+	if err != nil {
+		t.Fatalf("Expected no error for missing file, got: %v", err)
+	}
+	if !m.Empty() {
+		t.Error("Expected empty matcher for missing file")
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), ".bumpersignore")
+	if err := os.WriteFile(path, []byte("generated/\n"), 0o600); err != nil {
+		t.Fatalf("Failed to write ignore file: %v", err)
+	}
+
+	m, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if !m.Match("generated/types.go") {
+		t.Error("Expected generated/types.go to be ignored")
+	}
+}
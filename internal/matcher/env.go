@@ -0,0 +1,70 @@
+package matcher
+
+import (
+	"regexp"
+	"strings"
+)
+
+// envNamePattern matches the NAME= start of a shell variable assignment
+var envNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*=`)
+
+// SplitEnvAssignments splits the leading VAR=value assignments off a shell command, as in
+// "FOO=bar make deploy". Assignments are returned as KEY=VALUE with shell quoting removed,
+// along with the rest of the command. Parsing stops at the first word that isn't an
+// assignment or at an unterminated quote.
+func SplitEnvAssignments(command string) (env []string, rest string) {
+	rest = strings.TrimLeft(command, " \t")
+	for {
+		loc := envNamePattern.FindStringIndex(rest)
+		if loc == nil {
+			return env, rest
+		}
+
+		value, consumed, ok := scanShellWord(rest[loc[1]:])
+		if !ok {
+			return env, rest
+		}
+
+		env = append(env, rest[:loc[1]]+value)
+		rest = strings.TrimLeft(rest[loc[1]+consumed:], " \t")
+	}
+}
+
+// scanShellWord reads one whitespace-delimited shell word, returning it unquoted along
+// with the number of bytes consumed. ok is false when a quote is left open.
+func scanShellWord(s string) (word string, consumed int, ok bool) {
+	var b strings.Builder
+	i := 0
+	for i < len(s) {
+		switch c := s[i]; {
+		case c == ' ' || c == '\t' || c == '\n':
+			return b.String(), i, true
+		case c == '\'':
+			end := strings.IndexByte(s[i+1:], '\'')
+			if end < 0 {
+				return "", 0, false
+			}
+			b.WriteString(s[i+1 : i+1+end])
+			i += end + 2
+		case c == '"':
+			i++
+			for ; i < len(s) && s[i] != '"'; i++ {
+				if s[i] == '\\' && i+1 < len(s) {
+					i++
+				}
+				b.WriteByte(s[i])
+			}
+			if i >= len(s) {
+				return "", 0, false
+			}
+			i++
+		case c == '\\' && i+1 < len(s):
+			b.WriteByte(s[i+1])
+			i += 2
+		default:
+			b.WriteByte(c)
+			i++
+		}
+	}
+	return b.String(), i, true
+}
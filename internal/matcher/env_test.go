@@ -0,0 +1,88 @@
+package matcher
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitEnvAssignments(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		command  string
+		wantRest string
+		wantEnv  []string
+	}{
+		{
+			name:     "no assignments",
+			command:  "make deploy",
+			wantRest: "make deploy",
+		},
+		{
+			name:     "single assignment",
+			command:  "FOO=bar make deploy",
+			wantEnv:  []string{"FOO=bar"},
+			wantRest: "make deploy",
+		},
+		{
+			name:     "multiple assignments",
+			command:  "AWS_PROFILE=prod  REGION=us-east-1 terraform apply",
+			wantEnv:  []string{"AWS_PROFILE=prod", "REGION=us-east-1"},
+			wantRest: "terraform apply",
+		},
+		{
+			name:     "double quoted value",
+			command:  `MSG="hello world" ./notify.sh`,
+			wantEnv:  []string{"MSG=hello world"},
+			wantRest: "./notify.sh",
+		},
+		{
+			name:     "single quoted value",
+			command:  `PS1='$ ' bash`,
+			wantEnv:  []string{"PS1=$ "},
+			wantRest: "bash",
+		},
+		{
+			name:     "escaped quote in double quotes",
+			command:  `Q="say \"hi\"" echo`,
+			wantEnv:  []string{`Q=say "hi"`},
+			wantRest: "echo",
+		},
+		{
+			name:     "empty value",
+			command:  "FOO= make",
+			wantEnv:  []string{"FOO="},
+			wantRest: "make",
+		},
+		{
+			name:    "only assignments",
+			command: "FOO=bar BAZ='qux quux'",
+			wantEnv: []string{"FOO=bar", "BAZ=qux quux"},
+		},
+		{
+			name:     "unterminated quote stops parsing",
+			command:  `FOO="bar make`,
+			wantRest: `FOO="bar make`,
+		},
+		{
+			name:     "assignment after command is not env",
+			command:  "make FOO=bar",
+			wantRest: "make FOO=bar",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			env, rest := SplitEnvAssignments(tt.command)
+			if !reflect.DeepEqual(env, tt.wantEnv) {
+				t.Errorf("env = %q, want %q", env, tt.wantEnv)
+			}
+			if rest != tt.wantRest {
+				t.Errorf("rest = %q, want %q", rest, tt.wantRest)
+			}
+		})
+	}
+}
@@ -2,21 +2,45 @@ package matcher
 
 import (
 	"errors"
+	"fmt"
 	"regexp"
+	"strings"
 
 	"github.com/wizzomafizzo/bumpers/internal/config"
 	"github.com/wizzomafizzo/bumpers/internal/template"
 )
 
 var (
-	ErrNoRuleMatch  = errors.New("no rule matched the command")
-	ErrInvalidRegex = errors.New("invalid regex pattern")
+	ErrNoRuleMatch = errors.New("no rule matched the command")
+	// ErrPatternCompile is matched by every *PatternError
+	ErrPatternCompile = errors.New("invalid regex pattern")
+	// ErrInvalidRegex is the former name of ErrPatternCompile
+	ErrInvalidRegex = ErrPatternCompile
 )
 
+// PatternError is a rule pattern that doesn't compile as a regex
+type PatternError struct {
+	Err     error
+	Pattern string
+}
+
+func (e *PatternError) Error() string {
+	return fmt.Sprintf("invalid regex pattern '%s': %v", e.Pattern, e.Err)
+}
+
+func (e *PatternError) Unwrap() error { return e.Err }
+
+func (*PatternError) Is(target error) bool { return target == ErrPatternCompile }
+
 func NewRuleMatcher(rules []config.Rule) (*RuleMatcher, error) {
-	// Validate all patterns can be compiled as regex
+	// Validate all patterns can be compiled as regex, skipping soft-disabled rules with an
+	// empty pattern, which never match
 	for i := range rules {
-		if err := validatePattern(rules[i].GetMatch().Pattern); err != nil {
+		match, ok := rules[i].EffectiveMatch()
+		if !ok {
+			continue
+		}
+		if err := validatePattern(match.AnchoredPattern()); err != nil {
 			return nil, err
 		}
 	}
@@ -28,13 +52,29 @@ func NewRuleMatcher(rules []config.Rule) (*RuleMatcher, error) {
 func validatePattern(pattern string) error {
 	// Try to compile as regex to validate syntax
 	if _, err := regexp.Compile(pattern); err != nil {
-		return ErrInvalidRegex
+		return &PatternError{Pattern: pattern, Err: err}
 	}
 	return nil
 }
 
+// ExceptionHook is called when a rule's pattern matched but one of its exceptions suppressed it
+type ExceptionHook func(rule *config.Rule, value, exception string)
+
 type RuleMatcher struct {
-	rules []config.Rule
+	onException ExceptionHook
+	selectMode  string
+	rules       []config.Rule
+}
+
+// SetExceptionHook registers a callback used to trace rules suppressed by an except entry
+func (m *RuleMatcher) SetExceptionHook(hook ExceptionHook) {
+	m.onException = hook
+}
+
+// SetSelectMode chooses which rule wins when several match: config.SelectFirst (the
+// default) returns the first in config order, config.SelectSpecific the most specific
+func (m *RuleMatcher) SetSelectMode(mode string) {
+	m.selectMode = mode
 }
 
 func (m *RuleMatcher) Match(command, toolName string) (*config.Rule, error) {
@@ -42,16 +82,48 @@ func (m *RuleMatcher) Match(command, toolName string) (*config.Rule, error) {
 }
 
 func (m *RuleMatcher) MatchWithContext(command, toolName string, context map[string]any) (*config.Rule, error) {
+	if m.selectMode == config.SelectSpecific {
+		matched, err := m.MatchAll(command, toolName, context)
+		if err != nil {
+			return nil, err
+		}
+		return mostSpecific(matched), nil
+	}
+
+	if matched := m.collectMatches(command, toolName, context, 1); len(matched) > 0 {
+		return matched[0], nil
+	}
+	return nil, ErrNoRuleMatch
+}
+
+// MatchAll returns every rule matching the command and tool, in config order
+func (m *RuleMatcher) MatchAll(command, toolName string, context map[string]any) ([]*config.Rule, error) {
+	matched := m.collectMatches(command, toolName, context, 0)
+	if len(matched) == 0 {
+		return nil, ErrNoRuleMatch
+	}
+	return matched, nil
+}
+
+// collectMatches returns the rules matching the command and tool in config order, stopping
+// once limit are found; a limit of 0 checks every rule
+func (m *RuleMatcher) collectMatches(
+	command, toolName string, context map[string]any, limit int,
+) []*config.Rule {
+	var matched []*config.Rule
 	for i := range m.rules {
+		if limit > 0 && len(matched) == limit {
+			break
+		}
 		if m.matchesRule(command, toolName, context, &m.rules[i]) {
-			return &m.rules[i], nil
+			matched = append(matched, &m.rules[i])
 		}
 	}
-	return nil, ErrNoRuleMatch
+	return matched
 }
 
 // matchesRule checks if a single rule matches the given command and tool
-func (*RuleMatcher) matchesRule(command, toolName string, context map[string]any, rule *config.Rule) bool {
+func (m *RuleMatcher) matchesRule(command, toolName string, context map[string]any, rule *config.Rule) bool {
 	// Filter rules by tool first
 	toolPattern := rule.Tool
 	if toolPattern == "" {
@@ -69,19 +141,87 @@ func (*RuleMatcher) matchesRule(command, toolName string, context map[string]any
 		return false
 	}
 
-	// Now check if command matches
-	pattern := rule.GetMatch().Pattern
+	return m.matchesValue(command, context, rule)
+}
 
-	// Process template if context provided
-	if context != nil {
-		if processedPattern, templateErr := template.Execute(pattern, context); templateErr == nil {
-			pattern = processedPattern
+// MatchValue returns the first rule whose match accepts value, without filtering by tool.
+// It's used for events such as PostToolUse, whose rules check their tool themselves.
+func (m *RuleMatcher) MatchValue(value string, context map[string]any) (*config.Rule, error) {
+	for i := range m.rules {
+		if m.matchesValue(value, context, &m.rules[i]) {
+			return &m.rules[i], nil
 		}
 	}
+	return nil, ErrNoRuleMatch
+}
+
+// matchesValue checks the rule's match against command: word counts, the anchored pattern,
+// arguments after the match and except entries
+func (m *RuleMatcher) matchesValue(command string, context map[string]any, rule *config.Rule) bool {
+	match, ok := rule.EffectiveMatch()
+	if !ok {
+		return false
+	}
+	// Word counts are a cheap pre-filter, checked before any regex is compiled
+	if match.HasWordCountLimits() && !match.WordCountWithinLimits(len(strings.Fields(command))) {
+		return false
+	}
+	pattern := match.AnchoredPattern()
+
+	// Process template if context provided
+	pattern = ExpandPattern(pattern, context)
 
 	cmdRe, err := regexp.Compile(pattern)
 	if err != nil {
 		return false
 	}
-	return cmdRe.MatchString(command)
+	loc := cmdRe.FindStringIndex(command)
+	if loc == nil {
+		return false
+	}
+
+	if match.HasArgLimits() && !match.ArgsWithinLimits(argsAfter(command, loc[1])) {
+		return false
+	}
+
+	if exception, excepted := MatchException(rule, command, context); excepted {
+		if m.onException != nil {
+			m.onException(rule, command, exception)
+		}
+		return false
+	}
+	return true
+}
+
+// argsAfter counts the whitespace-separated words in command after offset, e.g. the
+// arguments following a matched "git push"
+func argsAfter(command string, offset int) int {
+	return len(strings.Fields(command[offset:]))
+}
+
+// MatchException returns the first entry in rule.Except matching value. Entries are
+// template-expanded, then compared as an exact string or as a regex anchored to the whole value.
+func MatchException(rule *config.Rule, value string, context map[string]any) (string, bool) {
+	for _, exception := range rule.Except {
+		expanded := ExpandPattern(exception, context)
+		if value == expanded {
+			return exception, true
+		}
+		if re, err := regexp.Compile("^(?:" + expanded + ")$"); err == nil && re.MatchString(value) {
+			return exception, true
+		}
+	}
+	return "", false
+}
+
+// ExpandPattern processes template variables such as {{.ProjectRoot}} in a pattern,
+// returning it unchanged when there is no context or the template fails
+func ExpandPattern(pattern string, context map[string]any) string {
+	if context == nil {
+		return pattern
+	}
+	if processed, err := template.Execute(pattern, context); err == nil {
+		return processed
+	}
+	return pattern
 }
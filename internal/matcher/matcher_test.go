@@ -257,6 +257,15 @@ func TestNewRuleMatcherInvalidRegex(t *testing.T) {
 	if !errors.Is(err, ErrInvalidRegex) {
 		t.Errorf("Expected ErrInvalidRegex, got %v", err)
 	}
+
+	wrapped := fmt.Errorf("failed to create rule matcher: %w", err)
+	var patternErr *PatternError
+	if !errors.As(wrapped, &patternErr) || patternErr.Pattern != "[invalid-regex" {
+		t.Errorf("Expected a PatternError carrying the pattern, got %v", wrapped)
+	}
+	if !errors.Is(wrapped, ErrPatternCompile) {
+		t.Errorf("Expected ErrPatternCompile, got %v", wrapped)
+	}
 }
 
 func TestRuleMatcherWithToolFiltering(t *testing.T) {
@@ -582,3 +591,335 @@ func TestRuleMatcherWithTemplatePattern(t *testing.T) {
 		t.Errorf("Expected no match without context, got %v", err)
 	}
 }
+
+func TestRuleMatcherMatchAll_ReturnsAllOverlappingRulesInOrder(t *testing.T) {
+	t.Parallel()
+
+	rules := []config.Rule{
+		{Match: "^go", Send: "first"},
+		{Match: "^rm", Send: "unrelated"},
+		{Match: "test", Send: "second"},
+		{Match: "^go test", Send: "third"},
+		{Match: "go test", Tool: "^Write$", Send: "wrong tool"},
+	}
+
+	matcher, err := NewRuleMatcher(rules)
+	if err != nil {
+		t.Fatalf("Failed to create matcher: %v", err)
+	}
+
+	matches, err := matcher.MatchAll("go test ./...", "Bash", nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := []string{"first", "second", "third"}
+	if len(matches) != len(expected) {
+		t.Fatalf("Expected %d matches, got %d", len(expected), len(matches))
+	}
+	for i, match := range matches {
+		if match.Send != expected[i] {
+			t.Errorf("Match %d: expected %q, got %q", i, expected[i], match.Send)
+		}
+	}
+
+	// Returned pointers refer to the matcher's rules, matching MatchWithContext
+	first, err := matcher.MatchWithContext("go test ./...", "Bash", nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if first != matches[0] {
+		t.Error("Expected MatchAll first result to be the same rule as MatchWithContext")
+	}
+}
+
+func TestRuleMatcherMatchAll_NoMatch(t *testing.T) {
+	t.Parallel()
+
+	matcher, err := NewRuleMatcher([]config.Rule{{Match: "^go test", Send: "Use just test"}})
+	if err != nil {
+		t.Fatalf("Failed to create matcher: %v", err)
+	}
+
+	matches, err := matcher.MatchAll("npm test", "Bash", nil)
+	if !errors.Is(err, ErrNoRuleMatch) {
+		t.Errorf("Expected ErrNoRuleMatch, got %v", err)
+	}
+	if len(matches) != 0 {
+		t.Errorf("Expected no matches, got %d", len(matches))
+	}
+}
+
+func TestRuleMatcherExceptOverlappingRules(t *testing.T) {
+	t.Parallel()
+
+	rules := []config.Rule{
+		{Match: "^go test", Send: "Use just test", Except: []string{"go test ./..."}},
+		{Match: "^go ", Send: "Use just", Except: []string{"go test -run .*"}},
+	}
+	matcher, err := NewRuleMatcher(rules)
+	if err != nil {
+		t.Fatalf("Failed to create matcher: %v", err)
+	}
+
+	var traced []string
+	matcher.SetExceptionHook(func(rule *config.Rule, _, exception string) {
+		traced = append(traced, rule.Send+"|"+exception)
+	})
+
+	// Exact exception on the first rule falls through to the second rule
+	rule, err := matcher.Match("go test ./...", "Bash")
+	if err != nil {
+		t.Fatalf("Expected second rule to match, got %v", err)
+	}
+	if rule.Send != "Use just" {
+		t.Errorf("Expected second rule, got %q", rule.Send)
+	}
+
+	// Regex exception on the second rule still lets the first rule fire
+	rule, err = matcher.Match("go test -run TestFoo", "Bash")
+	if err != nil {
+		t.Fatalf("Expected first rule to match, got %v", err)
+	}
+	if rule.Send != "Use just test" {
+		t.Errorf("Expected first rule, got %q", rule.Send)
+	}
+
+	// MatchAll reports only the rules not suppressed by an exception
+	matches, err := matcher.MatchAll("go test ./...", "Bash", nil)
+	if err != nil || len(matches) != 1 || matches[0].Send != "Use just" {
+		t.Errorf("Expected only second rule from MatchAll, got %v (err %v)", matches, err)
+	}
+
+	if len(traced) == 0 || traced[0] != "Use just test|go test ./..." {
+		t.Errorf("Expected exception to be traced, got %v", traced)
+	}
+}
+
+func TestRuleMatcherExceptBothRulesAllowsCommand(t *testing.T) {
+	t.Parallel()
+
+	rules := []config.Rule{
+		{Match: "rm", Send: "No rm", Except: []string{"rm {{.ProjectRoot}}/tmp/cache"}},
+		{Match: "rm -", Send: "No rm flags", Except: []string{"rm .*/tmp/cache"}},
+	}
+	matcher, err := NewRuleMatcher(rules)
+	if err != nil {
+		t.Fatalf("Failed to create matcher: %v", err)
+	}
+
+	context := map[string]any{"ProjectRoot": "/work"}
+	if _, err := matcher.MatchWithContext("rm /work/tmp/cache", "Bash", context); !errors.Is(err, ErrNoRuleMatch) {
+		t.Errorf("Expected templated exception to suppress all rules, got %v", err)
+	}
+
+	// Exceptions are anchored to the whole value
+	if _, err := matcher.MatchWithContext("rm /work/tmp/cache/../../etc", "Bash", context); err != nil {
+		t.Errorf("Expected partial exception match not to suppress rule, got %v", err)
+	}
+}
+
+func TestRuleMatcherArgLimits(t *testing.T) {
+	t.Parallel()
+
+	rules := []config.Rule{
+		{Match: map[string]any{"pattern": "^git push", "max_args": 0}, Send: "Name the remote and branch"},
+		{Match: map[string]any{"pattern": "^rm\\b", "min_args": 2}, Send: "Remove files one at a time"},
+	}
+	matcher, err := NewRuleMatcher(rules)
+	if err != nil {
+		t.Fatalf("Failed to create matcher: %v", err)
+	}
+
+	tests := []struct {
+		command  string
+		expected string
+	}{
+		{"git push", "Name the remote and branch"},
+		{"git push  ", "Name the remote and branch"},
+		{"git push origin feature", ""},
+		{"rm a.txt", ""},
+		{"rm a.txt b.txt", "Remove files one at a time"},
+	}
+	for _, tt := range tests {
+		rule, err := matcher.Match(tt.command, "Bash")
+		if tt.expected == "" {
+			if !errors.Is(err, ErrNoRuleMatch) {
+				t.Errorf("%q: expected no match, got %v (err %v)", tt.command, rule, err)
+			}
+			continue
+		}
+		if err != nil || rule.Send != tt.expected {
+			t.Errorf("%q: expected %q, got %v (err %v)", tt.command, tt.expected, rule, err)
+		}
+	}
+}
+
+func TestRuleMatcherWordCountLimits(t *testing.T) {
+	t.Parallel()
+
+	rules := []config.Rule{
+		{Match: map[string]any{"pattern": "rm", "min_word_count": 2}, Send: "Check the paths"},
+		{Match: map[string]any{"pattern": "^ls", "max_word_count": 1}, Send: "Use the Glob tool"},
+	}
+	matcher, err := NewRuleMatcher(rules)
+	if err != nil {
+		t.Fatalf("Failed to create matcher: %v", err)
+	}
+
+	tests := []struct {
+		command  string
+		expected string
+	}{
+		{"rm", ""},
+		{"  rm  ", ""},
+		{"rm -rf", "Check the paths"},
+		{"ls", "Use the Glob tool"},
+		{"ls -la", ""},
+	}
+	for _, tt := range tests {
+		rule, err := matcher.Match(tt.command, "Bash")
+		if tt.expected == "" {
+			if !errors.Is(err, ErrNoRuleMatch) {
+				t.Errorf("%q: expected no match, got %v (err %v)", tt.command, rule, err)
+			}
+			continue
+		}
+		if err != nil || rule.Send != tt.expected {
+			t.Errorf("%q: expected %q, got %v (err %v)", tt.command, tt.expected, rule, err)
+		}
+	}
+}
+
+func TestRuleMatcherAnchor(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		anchor   string
+		command  string
+		expected bool
+	}{
+		{"start", "rm -rf build", true},
+		{"start", "npm run rm", false},
+		{"start", "del tmp", true},
+		{"full", "rm", true},
+		{"full", "rm -rf build", false},
+		{"full", "sudo rm", false},
+		{"full", "del", true},
+		{"", "npm run rm", true},
+	}
+	for _, tt := range tests {
+		rules := []config.Rule{{Match: map[string]any{"pattern": "rm|del", "anchor": tt.anchor}, Send: "Careful"}}
+		matcher, err := NewRuleMatcher(rules)
+		if err != nil {
+			t.Fatalf("Failed to create matcher: %v", err)
+		}
+
+		rule, err := matcher.Match(tt.command, "Bash")
+		if matched := err == nil && rule != nil; matched != tt.expected {
+			t.Errorf("anchor %q, %q: expected match %v, got %v (err %v)", tt.anchor, tt.command, tt.expected, matched, err)
+		}
+	}
+}
+
+func TestRuleMatcherSkipsEmptyPatterns(t *testing.T) {
+	_, _ = testutil.NewTestContext(t) // Context-aware logging available
+	t.Parallel()
+
+	rules := []config.Rule{
+		{Match: "", Send: "Soft-disabled"},
+		{Match: map[string]any{"pattern": "  "}, Send: "Whitespace only"},
+		{Match: "^go test", Send: "Use just test instead"},
+	}
+
+	matcher, err := NewRuleMatcher(rules)
+	if err != nil {
+		t.Fatalf("Failed to create matcher: %v", err)
+	}
+
+	if _, err := matcher.Match("make build", "Bash"); !errors.Is(err, ErrNoRuleMatch) {
+		t.Errorf("Expected ErrNoRuleMatch for soft-disabled rules, got %v", err)
+	}
+	match, err := matcher.Match("go test ./...", "Bash")
+	if err != nil {
+		t.Fatalf("Expected a match, got %v", err)
+	}
+	if match != &rules[2] {
+		t.Errorf("Expected the go test rule, got %v", match)
+	}
+}
+
+func TestRuleMatcherMatchWithContextIsFirstOfMatchAll(t *testing.T) {
+	t.Parallel()
+
+	rules := []config.Rule{
+		{Match: "^go test", Send: "Use just test", Except: []string{"go test ./..."}},
+		{Match: "^go ", Send: "Use just"},
+		{Match: "rm -rf", Send: "Careful"},
+		{Match: "{{.ProjectRoot}}/secret", Tool: "^Read$", Send: "Private"},
+	}
+	matcher, err := NewRuleMatcher(rules)
+	if err != nil {
+		t.Fatalf("Failed to create matcher: %v", err)
+	}
+
+	context := map[string]any{"ProjectRoot": "/project"}
+	inputs := []struct{ command, tool string }{
+		{"go test -v", "Bash"},
+		{"go test ./...", "Bash"},
+		{"go build && rm -rf bin", "Bash"},
+		{"/project/secret", "Read"},
+		{"ls", "Bash"},
+	}
+	for _, input := range inputs {
+		first, firstErr := matcher.MatchWithContext(input.command, input.tool, context)
+		all, allErr := matcher.MatchAll(input.command, input.tool, context)
+		if (firstErr == nil) != (allErr == nil) {
+			t.Errorf("%q: MatchWithContext error %v, MatchAll error %v", input.command, firstErr, allErr)
+			continue
+		}
+		if len(all) > 0 && first != all[0] {
+			t.Errorf("%q: expected MatchWithContext to return MatchAll's first rule %q, got %q",
+				input.command, all[0].Send, first.Send)
+		}
+	}
+}
+
+func TestRuleMatcherMatchValueIgnoresTool(t *testing.T) {
+	t.Parallel()
+
+	rules := []config.Rule{
+		{
+			Match:  map[string]any{"pattern": "^panic:", "max_args": 1},
+			Send:   "Short panic",
+			Except: []string{"panic: ignored"},
+		},
+		{Match: "FAIL", Send: "Tests failed", Tool: "^Edit$"},
+	}
+	matcher, err := NewRuleMatcher(rules)
+	if err != nil {
+		t.Fatalf("Failed to create matcher: %v", err)
+	}
+
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{value: "panic: boom", want: "Short panic"},
+		{value: "panic: index out of range", want: ""},
+		{value: "panic: ignored", want: ""},
+		{value: "FAIL pkg", want: "Tests failed"},
+	}
+	for _, tt := range tests {
+		rule, err := matcher.MatchValue(tt.value, nil)
+		got := ""
+		if err == nil {
+			got = rule.Send
+		} else if !errors.Is(err, ErrNoRuleMatch) {
+			t.Fatalf("MatchValue(%q) returned unexpected error: %v", tt.value, err)
+		}
+		if got != tt.want {
+			t.Errorf("MatchValue(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
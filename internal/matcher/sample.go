@@ -0,0 +1,31 @@
+package matcher
+
+import "unicode/utf8"
+
+// SampleSeparator joins the head and tail of a sampled value
+const SampleSeparator = "\n...[truncated]...\n"
+
+// SampleValue keeps the first and last halves of values longer than maxBytes, joined by
+// SampleSeparator, so patterns anchored at either end still match. It reports whether the
+// value was truncated. Cuts are moved to rune boundaries.
+func SampleValue(value string, maxBytes int) (string, bool) {
+	if maxBytes <= 0 || len(value) <= maxBytes {
+		return value, false
+	}
+
+	half := (maxBytes - len(SampleSeparator)) / 2
+	if half <= 0 {
+		half = maxBytes / 2
+	}
+
+	headEnd := half
+	for headEnd > 0 && !utf8.RuneStart(value[headEnd]) {
+		headEnd--
+	}
+	tailStart := len(value) - half
+	for tailStart < len(value) && !utf8.RuneStart(value[tailStart]) {
+		tailStart++
+	}
+
+	return value[:headEnd] + SampleSeparator + value[tailStart:], true
+}
@@ -0,0 +1,90 @@
+package matcher
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/wizzomafizzo/bumpers/internal/config"
+)
+
+func TestSampleValueShortValueUnchanged(t *testing.T) {
+	t.Parallel()
+
+	sampled, truncated := SampleValue("go test ./...", 100)
+	if truncated || sampled != "go test ./..." {
+		t.Errorf("Expected short value unchanged, got %q (truncated %v)", sampled, truncated)
+	}
+}
+
+func TestSampleValueKeepsAnchors(t *testing.T) {
+	t.Parallel()
+
+	value := "package main\n" + strings.Repeat("x", 10_000) + "\n// END"
+	sampled, truncated := SampleValue(value, 1024)
+	if !truncated {
+		t.Fatal("Expected value to be truncated")
+	}
+	if len(sampled) > 1024 {
+		t.Errorf("Expected sample within cap, got %d bytes", len(sampled))
+	}
+
+	if !regexp.MustCompile(`^package main`).MatchString(sampled) {
+		t.Error("Expected start anchor to still match")
+	}
+	if !regexp.MustCompile(`// END$`).MatchString(sampled) {
+		t.Error("Expected end anchor to still match")
+	}
+	if !strings.Contains(sampled, SampleSeparator) {
+		t.Error("Expected sample to contain separator")
+	}
+}
+
+func TestSampleValueRespectsRuneBoundaries(t *testing.T) {
+	t.Parallel()
+
+	value := strings.Repeat("é", 1000)
+	sampled, truncated := SampleValue(value, 101)
+	if !truncated {
+		t.Fatal("Expected value to be truncated")
+	}
+	if !utf8.ValidString(sampled) {
+		t.Error("Expected sample to be valid UTF-8")
+	}
+}
+
+func largeContent() string {
+	return "package main\n" + strings.Repeat("func f() { return }\n", 10<<20/20) + "// END"
+}
+
+// BenchmarkMatchLargeContentFull benchmarks matching rules against a 10MB value
+func BenchmarkMatchLargeContentFull(b *testing.B) {
+	benchmarkMatchLargeContent(b, 0)
+}
+
+// BenchmarkMatchLargeContentSampled benchmarks matching rules against a 10MB value capped to 1MB
+func BenchmarkMatchLargeContentSampled(b *testing.B) {
+	benchmarkMatchLargeContent(b, config.DefaultMaxMatchBytes)
+}
+
+func benchmarkMatchLargeContent(b *testing.B, maxBytes int) {
+	b.Helper()
+	content := largeContent()
+	ruleMatcher, err := NewRuleMatcher([]config.Rule{
+		{Match: "TODO", Tool: "^Write$", Send: "No TODOs"},
+		{Match: "password\\s*=", Tool: "^Write$", Send: "No secrets"},
+		{Match: "// END$", Tool: "^Write$", Send: "Found end"},
+	})
+	if err != nil {
+		b.Fatalf("Failed to create matcher: %v", err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		value, _ := SampleValue(content, maxBytes)
+		if _, err := ruleMatcher.Match(value, "Write"); err != nil {
+			b.Fatalf("Expected end anchored rule to match: %v", err)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package matcher
+
+import (
+	"regexp"
+	"regexp/syntax"
+
+	"github.com/wizzomafizzo/bumpers/internal/config"
+)
+
+// Tool pattern specificity levels, weighted above any literal prefix length
+const (
+	toolSpecificityWildcard = iota // matches open-ended tool names, e.g. ".*" or "Edit.*"
+	toolSpecificityList            // alternation of literal names, e.g. "^(Write|Edit)$"
+	toolSpecificityExact           // a single literal tool name, e.g. "^Bash$"
+
+	toolSpecificityWeight = 1 << 16
+)
+
+// Specificity scores how narrowly a rule targets a call. A tool pattern naming a single
+// tool beats a list of tools, which beats a wildcard; ties are broken by the length of
+// the match pattern's literal prefix.
+func Specificity(rule *config.Rule) int {
+	return toolSpecificity(rule.Tool)*toolSpecificityWeight + literalPrefixLen(rule.GetMatch().Pattern)
+}
+
+// toolSpecificity classifies a tool pattern, treating an empty pattern as the ^Bash$ default
+func toolSpecificity(pattern string) int {
+	if pattern == "" {
+		return toolSpecificityExact
+	}
+
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return toolSpecificityWildcard
+	}
+	re = re.Simplify()
+
+	switch {
+	case isLiteral(re):
+		return toolSpecificityExact
+	case !hasWildcard(re):
+		return toolSpecificityList
+	default:
+		return toolSpecificityWildcard
+	}
+}
+
+// isLiteral reports whether re matches exactly one string, ignoring anchors
+func isLiteral(re *syntax.Regexp) bool {
+	switch re.Op {
+	case syntax.OpLiteral, syntax.OpBeginLine, syntax.OpEndLine, syntax.OpBeginText, syntax.OpEndText,
+		syntax.OpEmptyMatch:
+		return true
+	case syntax.OpCapture:
+		return isLiteral(re.Sub[0])
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			if !isLiteral(sub) {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
+
+// hasWildcard reports whether re can match an open-ended set of strings
+func hasWildcard(re *syntax.Regexp) bool {
+	switch re.Op {
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL, syntax.OpCharClass,
+		syntax.OpStar, syntax.OpPlus, syntax.OpQuest, syntax.OpRepeat:
+		return true
+	default:
+		for _, sub := range re.Sub {
+			if hasWildcard(sub) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// literalPrefixLen returns the length of the literal text every match of pattern starts with
+func literalPrefixLen(pattern string) int {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return 0
+	}
+	prefix, _ := re.LiteralPrefix()
+	return len(prefix)
+}
+
+// mostSpecific returns the highest scoring rule, preferring the earliest on ties
+func mostSpecific(rules []*config.Rule) *config.Rule {
+	best := rules[0]
+	bestScore := Specificity(best)
+	for _, rule := range rules[1:] {
+		if score := Specificity(rule); score > bestScore {
+			best, bestScore = rule, score
+		}
+	}
+	return best
+}
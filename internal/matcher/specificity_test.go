@@ -0,0 +1,112 @@
+package matcher
+
+import (
+	"testing"
+
+	"github.com/wizzomafizzo/bumpers/internal/config"
+)
+
+func TestSpecificityOrdering(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		moreRule config.Rule
+		lessRule config.Rule
+	}{
+		{
+			name:     "exact tool beats wildcard tool",
+			moreRule: config.Rule{Match: "rm", Tool: "^Bash$"},
+			lessRule: config.Rule{Match: "rm", Tool: ".*"},
+		},
+		{
+			name:     "exact tool beats tool list",
+			moreRule: config.Rule{Match: "x", Tool: "^Write$"},
+			lessRule: config.Rule{Match: "x", Tool: "^(Write|Edit)$"},
+		},
+		{
+			name:     "tool list beats wildcard",
+			moreRule: config.Rule{Match: "x", Tool: "^(Write|Edit)$"},
+			lessRule: config.Rule{Match: "x", Tool: "Edit.*"},
+		},
+		{
+			name:     "longer literal prefix wins for same tool",
+			moreRule: config.Rule{Match: "^git push --force"},
+			lessRule: config.Rule{Match: "^git"},
+		},
+		{
+			name:     "empty tool counts as exact",
+			moreRule: config.Rule{Match: "rm"},
+			lessRule: config.Rule{Match: "rm", Tool: ".*"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			more, less := Specificity(&tt.moreRule), Specificity(&tt.lessRule)
+			if more <= less {
+				t.Errorf("expected %d > %d", more, less)
+			}
+		})
+	}
+}
+
+func TestSelectModeSpecificBeatsGeneral(t *testing.T) {
+	t.Parallel()
+
+	rules := []config.Rule{
+		{Match: "^git", Tool: ".*", Send: "general"},
+		{Match: "^git push --force", Tool: "^Bash$", Send: "specific"},
+	}
+
+	tests := []struct {
+		mode string
+		want string
+	}{
+		{mode: "", want: "general"},
+		{mode: config.SelectFirst, want: "general"},
+		{mode: config.SelectSpecific, want: "specific"},
+	}
+
+	for _, tt := range tests {
+		t.Run("mode="+tt.mode, func(t *testing.T) {
+			t.Parallel()
+
+			ruleMatcher, err := NewRuleMatcher(rules)
+			if err != nil {
+				t.Fatalf("Failed to create matcher: %v", err)
+			}
+			ruleMatcher.SetSelectMode(tt.mode)
+
+			match, err := ruleMatcher.Match("git push --force origin main", "Bash")
+			if err != nil {
+				t.Fatalf("Expected match, got error: %v", err)
+			}
+			if match.Send != tt.want {
+				t.Errorf("Expected %q rule, got %q", tt.want, match.Send)
+			}
+		})
+	}
+}
+
+func TestSelectModeSpecificTiesKeepConfigOrder(t *testing.T) {
+	t.Parallel()
+
+	ruleMatcher, err := NewRuleMatcher([]config.Rule{
+		{Match: "^rm", Send: "first"},
+		{Match: "^rm", Send: "second"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create matcher: %v", err)
+	}
+	ruleMatcher.SetSelectMode(config.SelectSpecific)
+
+	match, err := ruleMatcher.Match("rm -rf /tmp", "Bash")
+	if err != nil {
+		t.Fatalf("Expected match, got error: %v", err)
+	}
+	if match.Send != "first" {
+		t.Errorf("Expected earliest rule on tie, got %q", match.Send)
+	}
+}
@@ -0,0 +1,140 @@
+// Package metrics collects per-hook stage timings and counters and logs them as one
+// structured line. Collection only happens when debug logging is enabled; otherwise the
+// collector is nil and every method is a no-op.
+package metrics
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/wizzomafizzo/bumpers/internal/logging"
+)
+
+// Hook processing stages, in the order they usually run
+const (
+	StageDetect       = "detect"
+	StageState        = "state"
+	StageConfigLoad   = "config_load"
+	StageIntent       = "intent"
+	StageMatch        = "match"
+	StageTemplate     = "template"
+	StageAIGeneration = "ai_generation"
+)
+
+// Counters
+const (
+	RulesEvaluated      = "rules_evaluated"
+	TranscriptBytesRead = "transcript_bytes_read"
+)
+
+type contextKey struct{}
+
+// Collector accumulates stage durations and counters for one hook invocation
+type Collector struct {
+	start    time.Time
+	stages   map[string]time.Duration
+	counters map[string]int64
+	order    []string
+	mu       sync.Mutex
+}
+
+// Start attaches a new collector to ctx when debug logging is enabled. The returned
+// collector is nil, and all its methods no-ops, when it is not.
+func Start(ctx context.Context) (context.Context, *Collector) {
+	logger := logging.Get(ctx)
+	if logger.GetLevel() > zerolog.DebugLevel || zerolog.GlobalLevel() > zerolog.DebugLevel {
+		return ctx, nil
+	}
+
+	collector := &Collector{
+		start:    time.Now(),
+		stages:   make(map[string]time.Duration),
+		counters: make(map[string]int64),
+	}
+	return context.WithValue(ctx, contextKey{}, collector), collector
+}
+
+// FromContext returns the collector attached to ctx, or nil
+func FromContext(ctx context.Context) *Collector {
+	collector, _ := ctx.Value(contextKey{}).(*Collector)
+	return collector
+}
+
+// Track starts timing stage and returns a function that records the elapsed time.
+// Repeated stages accumulate.
+func (c *Collector) Track(stage string) func() {
+	if c == nil {
+		return func() {}
+	}
+	start := time.Now()
+	return func() {
+		elapsed := time.Since(start)
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if _, ok := c.stages[stage]; !ok {
+			c.order = append(c.order, stage)
+		}
+		c.stages[stage] += elapsed
+	}
+}
+
+// Add increases counter by n
+func (c *Collector) Add(counter string, n int64) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.counters[counter] += n
+}
+
+// Stages returns a copy of the recorded stage durations
+func (c *Collector) Stages() map[string]time.Duration {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	stages := make(map[string]time.Duration, len(c.stages))
+	for stage, duration := range c.stages {
+		stages[stage] = duration
+	}
+	return stages
+}
+
+// Counter returns the current value of counter
+func (c *Collector) Counter(counter string) int64 {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.counters[counter]
+}
+
+// Log writes one debug line with the total time, each stage's duration, the counters
+// and the final decision
+func (c *Collector) Log(ctx context.Context, decision string) {
+	if c == nil {
+		return
+	}
+	total := time.Since(c.start)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stages := zerolog.Dict()
+	for _, stage := range c.order {
+		stages = stages.Dur(stage, c.stages[stage])
+	}
+
+	logging.Get(ctx).Debug().
+		Str("decision", decision).
+		Dur("total", total).
+		Dict("stages", stages).
+		Int64(RulesEvaluated, c.counters[RulesEvaluated]).
+		Int64(TranscriptBytesRead, c.counters[TranscriptBytesRead]).
+		Msg("hook metrics")
+}
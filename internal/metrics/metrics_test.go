@@ -0,0 +1,84 @@
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wizzomafizzo/bumpers/internal/logging"
+)
+
+func newLoggerContext(t *testing.T, level zerolog.Level) (context.Context, *strings.Builder) {
+	t.Helper()
+	var output strings.Builder
+	ctx, err := logging.New(context.Background(), nil, logging.Config{
+		Writer:    zerolog.SyncWriter(&output),
+		ProjectID: "test-project",
+		Level:     level,
+	})
+	require.NoError(t, err)
+	return ctx, &output
+}
+
+func TestStartDisabledWithoutDebugLogging(t *testing.T) {
+	t.Parallel()
+
+	ctx, output := newLoggerContext(t, zerolog.InfoLevel)
+	ctx, collector := Start(ctx)
+	assert.Nil(t, collector)
+	assert.Nil(t, FromContext(ctx))
+
+	// A nil collector is safe to use
+	collector.Track(StageMatch)()
+	collector.Add(RulesEvaluated, 1)
+	collector.Log(ctx, "allow")
+	assert.Empty(t, collector.Stages())
+	assert.Zero(t, collector.Counter(RulesEvaluated))
+	assert.Empty(t, output.String())
+}
+
+func TestCollectorRecordsStagesAndCounters(t *testing.T) {
+	t.Parallel()
+
+	ctx, output := newLoggerContext(t, zerolog.DebugLevel)
+	ctx, collector := Start(ctx)
+	require.NotNil(t, collector)
+	assert.Same(t, collector, FromContext(ctx))
+
+	stop := FromContext(ctx).Track(StageMatch)
+	time.Sleep(time.Millisecond)
+	stop()
+	FromContext(ctx).Track(StageMatch)()
+	FromContext(ctx).Track(StageTemplate)()
+	FromContext(ctx).Add(RulesEvaluated, 2)
+	FromContext(ctx).Add(RulesEvaluated, 3)
+
+	stages := collector.Stages()
+	assert.GreaterOrEqual(t, stages[StageMatch], time.Millisecond)
+	assert.Contains(t, stages, StageTemplate)
+	assert.Equal(t, int64(5), collector.Counter(RulesEvaluated))
+
+	collector.Log(ctx, "block")
+
+	var line struct {
+		Stages              map[string]float64 `json:"stages"`
+		Decision            string             `json:"decision"`
+		Message             string             `json:"message"`
+		Total               float64            `json:"total"`
+		RulesEvaluated      int64              `json:"rules_evaluated"`
+		TranscriptBytesRead int64              `json:"transcript_bytes_read"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(output.String()), &line))
+	assert.Equal(t, "hook metrics", line.Message)
+	assert.Equal(t, "block", line.Decision)
+	assert.Equal(t, int64(5), line.RulesEvaluated)
+	assert.Zero(t, line.TranscriptBytesRead)
+	assert.Contains(t, line.Stages, StageMatch)
+	assert.Contains(t, line.Stages, StageTemplate)
+	assert.GreaterOrEqual(t, line.Total, line.Stages[StageMatch]+line.Stages[StageTemplate])
+}
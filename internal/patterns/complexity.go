@@ -0,0 +1,102 @@
+package patterns
+
+import (
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+	"time"
+)
+
+// HighComplexity is the score at which a pattern is flagged as potentially slow
+const HighComplexity = 10
+
+// benchmarkIterations is how many times each sample is matched when timing a pattern
+const benchmarkIterations = 200
+
+// benchmarkSamples are typical tool inputs used to estimate a pattern's match time
+var benchmarkSamples = []string{
+	"go test ./...",
+	"git commit -m \"Fix parser handling of nested blocks\"",
+	"find . -name '*.go' -type f -exec grep -n TODO {} +",
+	"/home/user/project/internal/config/config.go",
+	strings.Repeat("echo building step; ", 50),
+}
+
+// Stats describes how costly a pattern is to match
+type Stats struct {
+	Pattern    string
+	MatchTime  time.Duration // average time to match one sample input
+	Complexity int
+	Anchored   bool // match must start at the beginning of the input
+}
+
+// Flagged reports whether the pattern's score is at or above HighComplexity
+func (s *Stats) Flagged() bool {
+	return s.Complexity >= HighComplexity
+}
+
+// Analyze scores pattern's complexity and times it against typical inputs
+func Analyze(pattern string) (Stats, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return Stats{}, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+	}
+
+	ast, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return Stats{}, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+	}
+
+	return Stats{
+		Pattern:    pattern,
+		Complexity: complexity(ast, 0),
+		Anchored:   anchored(ast),
+		MatchTime:  MatchTime(re, benchmarkSamples, benchmarkIterations),
+	}, nil
+}
+
+// MatchTime returns the average time re takes to match one of samples
+func MatchTime(re *regexp.Regexp, samples []string, iterations int) time.Duration {
+	if len(samples) == 0 || iterations <= 0 {
+		return 0
+	}
+
+	start := time.Now()
+	for range iterations {
+		for _, sample := range samples {
+			re.MatchString(sample)
+		}
+	}
+	return time.Since(start) / time.Duration(iterations*len(samples))
+}
+
+// complexity scores an AST: each quantifier costs more the deeper it is nested inside
+// other quantifiers, and each extra alternation branch adds one
+func complexity(re *syntax.Regexp, depth int) int {
+	score := 0
+	switch re.Op {
+	case syntax.OpStar, syntax.OpPlus, syntax.OpQuest, syntax.OpRepeat:
+		score += 2 << (2 * depth) // 2, 8, 32, ...
+		depth++
+	case syntax.OpAlternate:
+		score += len(re.Sub) - 1
+	}
+
+	for _, sub := range re.Sub {
+		score += complexity(sub, depth)
+	}
+	return score
+}
+
+// anchored reports whether the first thing re matches is the start of the input
+func anchored(re *syntax.Regexp) bool {
+	switch re.Op {
+	case syntax.OpBeginText, syntax.OpBeginLine:
+		return true
+	case syntax.OpConcat, syntax.OpCapture:
+		return len(re.Sub) > 0 && anchored(re.Sub[0])
+	default:
+		return false
+	}
+}
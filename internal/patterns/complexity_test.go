@@ -0,0 +1,65 @@
+package patterns
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestAnalyze(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		pattern        string
+		wantComplexity int
+		wantAnchored   bool
+		wantFlagged    bool
+	}{
+		{name: "literal", pattern: "rm -rf", wantComplexity: 0},
+		{name: "anchored literal", pattern: "^go test", wantAnchored: true},
+		{name: "single quantifier", pattern: "^go test.*", wantComplexity: 2, wantAnchored: true},
+		{name: "alternation", pattern: "^(npm|yarn|pnpm) install", wantComplexity: 2, wantAnchored: true},
+		{name: "nested quantifier", pattern: "(a+)+b", wantComplexity: 10, wantFlagged: true},
+		{name: "doubly nested quantifier", pattern: "((a*)*)*", wantComplexity: 42, wantFlagged: true},
+		{name: "line anchor", pattern: "(?m)^rm", wantAnchored: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			stats, err := Analyze(tt.pattern)
+			if err != nil {
+				t.Fatalf("Analyze(%q) failed: %v", tt.pattern, err)
+			}
+			if stats.Complexity != tt.wantComplexity {
+				t.Errorf("Complexity = %d, want %d", stats.Complexity, tt.wantComplexity)
+			}
+			if stats.Anchored != tt.wantAnchored {
+				t.Errorf("Anchored = %v, want %v", stats.Anchored, tt.wantAnchored)
+			}
+			if stats.Flagged() != tt.wantFlagged {
+				t.Errorf("Flagged() = %v, want %v", stats.Flagged(), tt.wantFlagged)
+			}
+			if stats.MatchTime <= 0 {
+				t.Errorf("Expected a positive match time, got %v", stats.MatchTime)
+			}
+		})
+	}
+}
+
+func TestAnalyzeInvalidPattern(t *testing.T) {
+	t.Parallel()
+
+	if _, err := Analyze("[unclosed"); err == nil {
+		t.Error("Expected error for invalid pattern")
+	}
+}
+
+func TestMatchTimeWithoutSamples(t *testing.T) {
+	t.Parallel()
+
+	if got := MatchTime(regexp.MustCompile("x"), nil, 10); got != 0 {
+		t.Errorf("Expected zero match time without samples, got %v", got)
+	}
+}
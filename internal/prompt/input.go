@@ -6,8 +6,8 @@ import (
 	"io"
 	"strings"
 
-	"github.com/fatih/color"
 	"github.com/peterh/liner"
+	"github.com/wizzomafizzo/bumpers/internal/display"
 )
 
 // Prompter interface wraps basic prompting functionality for testability
@@ -36,7 +36,7 @@ func TextInput(prompt string) (string, error) {
 	line.SetCtrlCAborts(true) // Ctrl+C to cancel
 
 	// Colored prompt
-	coloredPrompt := color.CyanString(prompt + " ")
+	coloredPrompt := display.Colorize(prompt+" ", display.Cyan)
 	result, err := line.Prompt(coloredPrompt)
 	if err != nil {
 		if errors.Is(err, liner.ErrPromptAborted) || errors.Is(err, io.EOF) {
@@ -56,7 +56,7 @@ func AITextInput(prompt string, _ func(string) string) (string, error) {
 	line.SetCtrlCAborts(true) // Ctrl+C to cancel
 
 	// Colored prompt
-	coloredPrompt := color.CyanString(prompt + " (Tab for AI generation): ")
+	coloredPrompt := display.Colorize(prompt+" (Tab for AI generation): ", display.Cyan)
 	result, err := line.Prompt(coloredPrompt)
 	if err != nil {
 		if errors.Is(err, liner.ErrPromptAborted) || errors.Is(err, io.EOF) {
@@ -85,14 +85,14 @@ func AITextInputWithPrompter(prompter Prompter, prompt string, patternGenerator
 		linerPrompter.SetCompleter(func(line string) []string {
 			if line != "" {
 				generated := patternGenerator(line)
-				color.Yellow("\nGenerated pattern: %s", generated)
+				fmt.Println(display.Colorize("\nGenerated pattern: "+generated, display.Yellow))
 				return []string{generated}
 			}
 			return []string{}
 		})
 	}
 
-	coloredPrompt := color.CyanString(prompt + " (Tab for AI generation): ")
+	coloredPrompt := display.Colorize(prompt+" (Tab for AI generation): ", display.Cyan)
 	result, err := prompter.Prompt(coloredPrompt)
 	if err != nil {
 		return "", fmt.Errorf("AI text input with prompter failed: %w", err)
@@ -102,7 +102,7 @@ func AITextInputWithPrompter(prompter Prompter, prompt string, patternGenerator
 
 // TextInputWithPrompter provides simple text input using a custom prompter
 func TextInputWithPrompter(prompter Prompter, prompt string) (string, error) {
-	coloredPrompt := color.CyanString(prompt + " ")
+	coloredPrompt := display.Colorize(prompt+" ", display.Cyan)
 	result, err := prompter.Prompt(coloredPrompt)
 	if err != nil {
 		return "", fmt.Errorf("text input with prompter failed: %w", err)
@@ -131,13 +131,13 @@ func MultiLineInput(prompt string) (string, error) {
 
 	line.SetCtrlCAborts(true) // Ctrl+C to cancel
 
-	color.Cyan("%s (Press Enter twice when done)\n", prompt)
+	fmt.Println(display.Colorize(prompt+" (Press Enter twice when done)", display.Cyan))
 
 	lines := make([]string, 0, 10) // pre-allocate with initial capacity
 	emptyLineCount := 0
 
 	for {
-		input, err := line.Prompt(color.YellowString("  "))
+		input, err := line.Prompt(display.Colorize("  ", display.Yellow))
 		if err != nil {
 			if errors.Is(err, liner.ErrPromptAborted) || errors.Is(err, io.EOF) {
 				return "", errors.New("cancelled by user")
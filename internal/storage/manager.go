@@ -2,9 +2,13 @@ package storage
 
 import (
 	"context"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/wizzomafizzo/bumpers/internal/rules"
 	_ "modernc.org/sqlite"
@@ -13,7 +17,6 @@ import (
 // StateManager handles persistent state storage for Bumpers configuration using SQLite only
 type StateManager struct {
 	db        *sql.DB
-	operation *rules.OperationState
 	projectID string
 }
 
@@ -121,7 +124,7 @@ func (m *StateManager) SetRulesEnabled(ctx context.Context, enabled bool) error
 		return fmt.Errorf("failed to set rules enabled state: %w", err)
 	}
 
-	return nil
+	return m.bumpRevision(ctx)
 }
 
 // GetSkipNext returns whether the next rule-processing hook should be skipped
@@ -145,7 +148,7 @@ func (m *StateManager) SetSkipNext(ctx context.Context, skip bool) error {
 		return fmt.Errorf("failed to set skip next state: %w", err)
 	}
 
-	return nil
+	return m.bumpRevision(ctx)
 }
 
 // ConsumeSkipNext returns the current skip flag value and resets it to false
@@ -165,20 +168,526 @@ func (m *StateManager) ConsumeSkipNext(ctx context.Context) (bool, error) {
 	return value, nil
 }
 
-// GetOperationMode returns the current operation state
-func (m *StateManager) GetOperationMode(_ context.Context) (*rules.OperationState, error) {
-	if m.operation == nil {
+// operationModeKey stores the plan/execute operation state
+const operationModeKey = "state:operation_mode"
+
+// GetOperationMode returns the current operation state, or the default state if none is stored
+func (m *StateManager) GetOperationMode(ctx context.Context) (*rules.OperationState, error) {
+	var valueJSON []byte
+	err := m.db.QueryRowContext(ctx,
+		"SELECT value FROM state WHERE key = ? AND project_id = ?",
+		operationModeKey, m.projectID).Scan(&valueJSON)
+
+	if err == sql.ErrNoRows {
 		return rules.DefaultState(), nil
 	}
-	return m.operation, nil
+	if err != nil {
+		return nil, fmt.Errorf("failed to get operation mode: %w", err)
+	}
+
+	var state rules.OperationState
+	if err := json.Unmarshal(valueJSON, &state); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal operation mode: %w", err)
+	}
+
+	return &state, nil
 }
 
 // SetOperationMode sets the operation state
-func (m *StateManager) SetOperationMode(_ context.Context, state *rules.OperationState) error {
-	m.operation = state
+func (m *StateManager) SetOperationMode(ctx context.Context, state *rules.OperationState) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to marshal operation mode: %w", err)
+	}
+
+	_, err = m.db.ExecContext(ctx,
+		"INSERT OR REPLACE INTO state (key, project_id, value) VALUES (?, ?, ?)",
+		operationModeKey, m.projectID, data)
+	if err != nil {
+		return fmt.Errorf("failed to set operation mode: %w", err)
+	}
+
+	return m.bumpRevision(ctx)
+}
+
+// ClearOperationMode removes the stored operation state so the default applies again
+func (m *StateManager) ClearOperationMode(ctx context.Context) error {
+	_, err := m.db.ExecContext(ctx,
+		"DELETE FROM state WHERE key = ? AND project_id = ?",
+		operationModeKey, m.projectID)
+	if err != nil {
+		return fmt.Errorf("failed to clear operation mode: %w", err)
+	}
+
+	return m.bumpRevision(ctx)
+}
+
+// Clear removes all of the project's stored state, restoring every default
+func (m *StateManager) Clear(ctx context.Context) error {
+	_, err := m.db.ExecContext(ctx, "DELETE FROM state WHERE project_id = ?", m.projectID)
+	if err != nil {
+		return fmt.Errorf("failed to clear state: %w", err)
+	}
+
+	return nil
+}
+
+// Keys storing the session ID a once-per-session warning was last logged for
+const (
+	missingConfigWarnedKey = "state:missing_config_warned"
+	versionWarnedKey       = "state:version_warned"
+)
+
+// MarkMissingConfigWarned records that the missing config warning has been logged for
+// sessionID, reporting false if it already had been
+func (m *StateManager) MarkMissingConfigWarned(ctx context.Context, sessionID string) (bool, error) {
+	return m.markSessionWarned(ctx, missingConfigWarnedKey, sessionID, "missing config")
+}
+
+// MarkVersionWarned records that the required_version warning has been logged for
+// sessionID, reporting false if it already had been
+func (m *StateManager) MarkVersionWarned(ctx context.Context, sessionID string) (bool, error) {
+	return m.markSessionWarned(ctx, versionWarnedKey, sessionID, "version")
+}
+
+// markSessionWarned stores sessionID under key, reporting false if it was already stored
+func (m *StateManager) markSessionWarned(ctx context.Context, key, sessionID, name string) (bool, error) {
+	var valueJSON []byte
+	err := m.db.QueryRowContext(ctx,
+		"SELECT value FROM state WHERE key = ? AND project_id = ?",
+		key, m.projectID).Scan(&valueJSON)
+	if err != nil && err != sql.ErrNoRows {
+		return false, fmt.Errorf("failed to get %s warning state: %w", name, err)
+	}
+
+	var warned string
+	if err == nil {
+		if unmarshalErr := json.Unmarshal(valueJSON, &warned); unmarshalErr != nil {
+			return false, fmt.Errorf("failed to unmarshal %s warning state: %w", name, unmarshalErr)
+		}
+	}
+	if err == nil && warned == sessionID {
+		return false, nil
+	}
+
+	data, err := json.Marshal(sessionID)
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal %s warning state: %w", name, err)
+	}
+
+	_, err = m.db.ExecContext(ctx,
+		"INSERT OR REPLACE INTO state (key, project_id, value) VALUES (?, ?, ?)",
+		key, m.projectID, data)
+	if err != nil {
+		return false, fmt.Errorf("failed to set %s warning state: %w", name, err)
+	}
+
+	return true, nil
+}
+
+// approvalKeyPrefix starts the key of each approval, followed by the session ID and the hash
+// of the rule and value it approves
+const approvalKeyPrefix = "approval:"
+
+// Approval is a pending or granted approval for a value matched by an approval: session rule
+type Approval struct {
+	// Token is what the user passes to bumpers approve or $approve
+	Token string `json:"token"`
+	// Value is the matched value, shown when it's approved
+	Value    string `json:"value"`
+	Approved bool   `json:"approved"`
+}
+
+// GetApproval returns the approval stored for hash in sessionID, or nil if there is none
+func (m *StateManager) GetApproval(ctx context.Context, sessionID, hash string) (*Approval, error) {
+	var valueJSON []byte
+	err := m.db.QueryRowContext(ctx,
+		"SELECT value FROM state WHERE key = ? AND project_id = ?",
+		approvalKeyPrefix+sessionID+":"+hash, m.projectID).Scan(&valueJSON)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get approval: %w", err)
+	}
+
+	var approval Approval
+	if err := json.Unmarshal(valueJSON, &approval); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal approval: %w", err)
+	}
+
+	return &approval, nil
+}
+
+// SetApproval stores approval for hash in sessionID
+func (m *StateManager) SetApproval(ctx context.Context, sessionID, hash string, approval *Approval) error {
+	return m.setApproval(ctx, approvalKeyPrefix+sessionID+":"+hash, approval)
+}
+
+// setApproval stores approval under key
+func (m *StateManager) setApproval(ctx context.Context, key string, approval *Approval) error {
+	data, err := json.Marshal(approval)
+	if err != nil {
+		return fmt.Errorf("failed to marshal approval: %w", err)
+	}
+
+	_, err = m.db.ExecContext(ctx,
+		"INSERT OR REPLACE INTO state (key, project_id, value) VALUES (?, ?, ?)",
+		key, m.projectID, data)
+	if err != nil {
+		return fmt.Errorf("failed to set approval: %w", err)
+	}
+
+	return m.bumpRevision(ctx)
+}
+
+// Approve grants the pending approval with token, returning it, or nil if no pending
+// approval has that token. An empty sessionID looks through every session's approvals.
+func (m *StateManager) Approve(ctx context.Context, sessionID, token string) (*Approval, error) {
+	prefix := escapeLike(approvalKeyPrefix)
+	if sessionID != "" {
+		prefix = escapeLike(approvalKeyPrefix + sessionID + ":")
+	}
+	rows, err := m.db.QueryContext(ctx,
+		"SELECT key, value FROM state WHERE key LIKE ? ESCAPE '\\' AND project_id = ?",
+		prefix+"%", m.projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list approvals: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var key string
+	var approval Approval
+	found := false
+	for !found && rows.Next() {
+		var valueJSON []byte
+		if err := rows.Scan(&key, &valueJSON); err != nil {
+			return nil, fmt.Errorf("failed to scan approval: %w", err)
+		}
+		if err := json.Unmarshal(valueJSON, &approval); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal approval: %w", err)
+		}
+		found = approval.Token == token && !approval.Approved
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list approvals: %w", err)
+	}
+	_ = rows.Close()
+	if !found {
+		return nil, nil
+	}
+
+	approval.Approved = true
+	if err := m.setApproval(ctx, key, &approval); err != nil {
+		return nil, err
+	}
+	return &approval, nil
+}
+
+// ClearApprovals removes the pending and granted approvals of sessionID, so they last one
+// session without touching other sessions in the project
+func (m *StateManager) ClearApprovals(ctx context.Context, sessionID string) error {
+	_, err := m.db.ExecContext(ctx,
+		"DELETE FROM state WHERE key LIKE ? ESCAPE '\\' AND project_id = ?",
+		escapeLike(approvalKeyPrefix+sessionID+":")+"%", m.projectID)
+	if err != nil {
+		return fmt.Errorf("failed to clear approvals: %w", err)
+	}
+
+	return m.bumpRevision(ctx)
+}
+
+// revisionKey counts changes to the state that decides tool calls: the rules enabled and
+// skip flags, the operation mode and approvals
+const revisionKey = "state:revision"
+
+// Revision returns the state revision, which changes whenever state deciding tool calls
+// does, so a decision made at one revision is stale at any other
+func (m *StateManager) Revision(ctx context.Context) (int64, error) {
+	var valueJSON []byte
+	err := m.db.QueryRowContext(ctx,
+		"SELECT value FROM state WHERE key = ? AND project_id = ?",
+		revisionKey, m.projectID).Scan(&valueJSON)
+
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get state revision: %w", err)
+	}
+
+	var revision int64
+	if err := json.Unmarshal(valueJSON, &revision); err != nil {
+		return 0, fmt.Errorf("failed to unmarshal state revision: %w", err)
+	}
+
+	return revision, nil
+}
+
+// bumpRevision increments the state revision
+func (m *StateManager) bumpRevision(ctx context.Context) error {
+	revision, err := m.Revision(ctx)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(revision + 1)
+	if err != nil {
+		return fmt.Errorf("failed to marshal state revision: %w", err)
+	}
+
+	_, err = m.db.ExecContext(ctx,
+		"INSERT OR REPLACE INTO state (key, project_id, value) VALUES (?, ?, ?)",
+		revisionKey, m.projectID, data)
+	if err != nil {
+		return fmt.Errorf("failed to set state revision: %w", err)
+	}
+
+	return nil
+}
+
+// decisionKeyPrefix starts the key of each cached decision, followed by the session ID and
+// the hash of the tool call and config it was made for
+const decisionKeyPrefix = "decision:"
+
+// Decision is a cached PreToolUse response, reused for exact repeats of a tool call
+type Decision struct {
+	ExpiresAt time.Time `json:"expires_at"`
+	// Message is the hook response blocking the call
+	Message string `json:"message"`
+	// RuleKey is the tracking key of the rule that matched, empty when none did
+	RuleKey string `json:"rule_key,omitempty"`
+	// Revision is the state revision the decision was made at
+	Revision int64 `json:"revision"`
+}
+
+// GetDecision returns the decision cached for hash in sessionID, or nil if there is none
+func (m *StateManager) GetDecision(ctx context.Context, sessionID, hash string) (*Decision, error) {
+	var valueJSON []byte
+	err := m.db.QueryRowContext(ctx,
+		"SELECT value FROM state WHERE key = ? AND project_id = ?",
+		decisionKeyPrefix+sessionID+":"+hash, m.projectID).Scan(&valueJSON)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get decision: %w", err)
+	}
+
+	var decision Decision
+	if err := json.Unmarshal(valueJSON, &decision); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal decision: %w", err)
+	}
+
+	return &decision, nil
+}
+
+// SetDecision caches decision for hash in sessionID
+func (m *StateManager) SetDecision(ctx context.Context, sessionID, hash string, decision *Decision) error {
+	data, err := json.Marshal(decision)
+	if err != nil {
+		return fmt.Errorf("failed to marshal decision: %w", err)
+	}
+
+	_, err = m.db.ExecContext(ctx,
+		"INSERT OR REPLACE INTO state (key, project_id, value) VALUES (?, ?, ?)",
+		decisionKeyPrefix+sessionID+":"+hash, m.projectID, data)
+	if err != nil {
+		return fmt.Errorf("failed to set decision: %w", err)
+	}
+
+	return nil
+}
+
+// ClearDecisions removes the decisions cached for sessionID, leaving other sessions' cached
+// decisions in place
+func (m *StateManager) ClearDecisions(ctx context.Context, sessionID string) error {
+	_, err := m.db.ExecContext(ctx,
+		"DELETE FROM state WHERE key LIKE ? ESCAPE '\\' AND project_id = ?",
+		escapeLike(decisionKeyPrefix+sessionID+":")+"%", m.projectID)
+	if err != nil {
+		return fmt.Errorf("failed to clear decisions: %w", err)
+	}
+
 	return nil
 }
 
+// noteShownKeyPrefix starts the key of each once_per_session note shown, followed by the
+// session ID and the hash of the note
+const noteShownKeyPrefix = "note_shown:"
+
+// NoteShown reports whether the note with hash was marked shown in sessionID and the mark
+// hasn't expired
+func (m *StateManager) NoteShown(ctx context.Context, sessionID, hash string) (bool, error) {
+	var valueJSON []byte
+	err := m.db.QueryRowContext(ctx,
+		"SELECT value FROM state WHERE key = ? AND project_id = ?",
+		noteShownKeyPrefix+sessionID+":"+hash, m.projectID).Scan(&valueJSON)
+
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get shown note: %w", err)
+	}
+
+	var expiresAt int64
+	if err := json.Unmarshal(valueJSON, &expiresAt); err != nil {
+		return false, fmt.Errorf("failed to unmarshal shown note: %w", err)
+	}
+
+	return time.Now().Unix() < expiresAt, nil
+}
+
+// MarkNoteShown marks the note with hash shown in sessionID until expiresAt, removing the
+// marks of every session that have expired
+func (m *StateManager) MarkNoteShown(ctx context.Context, sessionID, hash string, expiresAt time.Time) error {
+	_, err := m.db.ExecContext(ctx,
+		"DELETE FROM state WHERE key LIKE ? ESCAPE '\\' AND project_id = ? AND CAST(value AS INTEGER) <= ?",
+		escapeLike(noteShownKeyPrefix)+"%", m.projectID, time.Now().Unix())
+	if err != nil {
+		return fmt.Errorf("failed to clear expired shown notes: %w", err)
+	}
+
+	data, err := json.Marshal(expiresAt.Unix())
+	if err != nil {
+		return fmt.Errorf("failed to marshal shown note: %w", err)
+	}
+
+	_, err = m.db.ExecContext(ctx,
+		"INSERT OR REPLACE INTO state (key, project_id, value) VALUES (?, ?, ?)",
+		noteShownKeyPrefix+sessionID+":"+hash, m.projectID, data)
+	if err != nil {
+		return fmt.Errorf("failed to mark note shown: %w", err)
+	}
+
+	return nil
+}
+
+// ownMessageKeyPrefix starts the key of each message bumpers sent, followed by the session
+// ID and the message's hash
+const ownMessageKeyPrefix = "own_message:"
+
+// RecordOwnMessage stores a message bumpers sent in sessionID, so later hooks in the session
+// can recognize it in tool output
+func (m *StateManager) RecordOwnMessage(ctx context.Context, sessionID, message string) error {
+	sum := sha256.Sum256([]byte(message))
+	data, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to marshal own message: %w", err)
+	}
+
+	_, err = m.db.ExecContext(ctx,
+		"INSERT OR REPLACE INTO state (key, project_id, value) VALUES (?, ?, ?)",
+		ownMessageKeyPrefix+sessionID+":"+hex.EncodeToString(sum[:]), m.projectID, data)
+	if err != nil {
+		return fmt.Errorf("failed to record own message: %w", err)
+	}
+
+	return nil
+}
+
+// OwnMessages returns the messages recorded with RecordOwnMessage for sessionID
+func (m *StateManager) OwnMessages(ctx context.Context, sessionID string) ([]string, error) {
+	rows, err := m.db.QueryContext(ctx,
+		"SELECT value FROM state WHERE key LIKE ? ESCAPE '\\' AND project_id = ?",
+		escapeLike(ownMessageKeyPrefix+sessionID+":")+"%", m.projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list own messages: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var messages []string
+	for rows.Next() {
+		var valueJSON []byte
+		if err := rows.Scan(&valueJSON); err != nil {
+			return nil, fmt.Errorf("failed to scan own message: %w", err)
+		}
+		var message string
+		if err := json.Unmarshal(valueJSON, &message); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal own message: %w", err)
+		}
+		messages = append(messages, message)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list own messages: %w", err)
+	}
+
+	return messages, nil
+}
+
+// ClearOwnMessages removes the messages recorded for every session
+func (m *StateManager) ClearOwnMessages(ctx context.Context) error {
+	_, err := m.db.ExecContext(ctx,
+		"DELETE FROM state WHERE key LIKE ? AND project_id = ?",
+		ownMessageKeyPrefix+"%", m.projectID)
+	if err != nil {
+		return fmt.Errorf("failed to clear own messages: %w", err)
+	}
+
+	return nil
+}
+
+// ruleFiredKeyPrefix starts the key of each rule's last-fired time, followed by the rule's
+// tracking key
+const ruleFiredKeyPrefix = "rule_fired:"
+
+// RecordRuleFired stores at as the last time the rule with ruleKey, its config.Rule.TrackingKey,
+// matched
+func (m *StateManager) RecordRuleFired(ctx context.Context, ruleKey string, at time.Time) error {
+	data, err := json.Marshal(at)
+	if err != nil {
+		return fmt.Errorf("failed to marshal rule fired time: %w", err)
+	}
+
+	_, err = m.db.ExecContext(ctx,
+		"INSERT OR REPLACE INTO state (key, project_id, value) VALUES (?, ?, ?)",
+		ruleFiredKeyPrefix+ruleKey, m.projectID, data)
+	if err != nil {
+		return fmt.Errorf("failed to record rule fired: %w", err)
+	}
+
+	return nil
+}
+
+// RuleFiredTimes returns the last time each rule matched, keyed by its tracking key. Rules
+// that never matched are left out.
+func (m *StateManager) RuleFiredTimes(ctx context.Context) (map[string]time.Time, error) {
+	rows, err := m.db.QueryContext(ctx,
+		"SELECT key, value FROM state WHERE key LIKE ? ESCAPE '\\' AND project_id = ?",
+		escapeLike(ruleFiredKeyPrefix)+"%", m.projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list rule fired times: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	fired := make(map[string]time.Time)
+	for rows.Next() {
+		var key string
+		var valueJSON []byte
+		if err := rows.Scan(&key, &valueJSON); err != nil {
+			return nil, fmt.Errorf("failed to scan rule fired time: %w", err)
+		}
+		var at time.Time
+		if err := json.Unmarshal(valueJSON, &at); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal rule fired time: %w", err)
+		}
+		fired[strings.TrimPrefix(key, ruleFiredKeyPrefix)] = at
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list rule fired times: %w", err)
+	}
+
+	return fired, nil
+}
+
+// escapeLike escapes the LIKE wildcards in value, for patterns using ESCAPE '\'
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
+
 // NewSQLManager creates a new SQL-based state manager instance
 func NewSQLManager(db *sql.DB, projectID string) (*StateManager, error) {
 	return &StateManager{
@@ -4,6 +4,7 @@ import (
 	"context"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"github.com/wizzomafizzo/bumpers/internal/rules"
@@ -192,3 +193,193 @@ func TestSetOperationMode(t *testing.T) {
 	require.Equal(t, 1, stored.TriggerCount)
 	require.Equal(t, int64(123456789), stored.UpdatedAt)
 }
+
+func TestOperationModePersistsAcrossManagers(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+
+	first, err := NewStateManager(dbPath, "test-project")
+	require.NoError(t, err)
+	require.NoError(t, first.SetOperationMode(ctx, &rules.OperationState{Mode: rules.PlanMode}))
+	require.NoError(t, first.Close())
+
+	second, err := NewStateManager(dbPath, "test-project")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = second.Close() })
+
+	stored, err := second.GetOperationMode(ctx)
+	require.NoError(t, err)
+	require.Equal(t, rules.PlanMode, stored.Mode)
+
+	// Other projects keep the default
+	other, err := NewStateManager(dbPath, "other-project")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = other.Close() })
+	otherState, err := other.GetOperationMode(ctx)
+	require.NoError(t, err)
+	require.Equal(t, rules.ExecuteMode, otherState.Mode)
+}
+
+func TestClearOperationMode(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	manager := createTestManager(t)
+
+	require.NoError(t, manager.SetOperationMode(ctx, &rules.OperationState{Mode: rules.PlanMode}))
+	require.NoError(t, manager.ClearOperationMode(ctx))
+
+	state, err := manager.GetOperationMode(ctx)
+	require.NoError(t, err)
+	require.Equal(t, rules.ExecuteMode, state.Mode)
+}
+
+func TestClear(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	manager := createTestManager(t)
+
+	require.NoError(t, manager.SetOperationMode(ctx, &rules.OperationState{Mode: rules.PlanMode}))
+	require.NoError(t, manager.SetRulesEnabled(ctx, false))
+	require.NoError(t, manager.SetSkipNext(ctx, true))
+	require.NoError(t, manager.Clear(ctx))
+
+	state, err := manager.GetOperationMode(ctx)
+	require.NoError(t, err)
+	require.Equal(t, rules.ExecuteMode, state.Mode)
+	enabled, err := manager.GetRulesEnabled(ctx)
+	require.NoError(t, err)
+	require.True(t, enabled)
+	skip, err := manager.GetSkipNext(ctx)
+	require.NoError(t, err)
+	require.False(t, skip)
+}
+
+func TestApprovals(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	manager := createTestManager(t)
+
+	approval, err := manager.GetApproval(ctx, "session_1", "hash1")
+	require.NoError(t, err)
+	require.Nil(t, approval)
+
+	require.NoError(t, manager.SetApproval(ctx, "session_1", "hash1",
+		&Approval{Token: "abc123", Value: "terraform apply"}))
+	require.NoError(t, manager.SetApproval(ctx, "session_1", "hash2",
+		&Approval{Token: "def456", Value: "terraform destroy"}))
+	require.NoError(t, manager.SetApproval(ctx, "session11", "hash1",
+		&Approval{Token: "ghi789", Value: "terraform apply"}))
+
+	approved, err := manager.Approve(ctx, "", "wrong")
+	require.NoError(t, err)
+	require.Nil(t, approved)
+
+	// A token is only found in its own session, or in any with an empty session ID
+	approved, err = manager.Approve(ctx, "session11", "abc123")
+	require.NoError(t, err)
+	require.Nil(t, approved)
+	approved, err = manager.Approve(ctx, "session_1", "abc123")
+	require.NoError(t, err)
+	require.Equal(t, &Approval{Token: "abc123", Value: "terraform apply", Approved: true}, approved)
+
+	approval, err = manager.GetApproval(ctx, "session_1", "hash1")
+	require.NoError(t, err)
+	require.True(t, approval.Approved)
+	approval, err = manager.GetApproval(ctx, "session_1", "hash2")
+	require.NoError(t, err)
+	require.False(t, approval.Approved)
+	approval, err = manager.GetApproval(ctx, "session11", "hash1")
+	require.NoError(t, err)
+	require.False(t, approval.Approved, "approving in one session doesn't approve in another")
+
+	approved, err = manager.Approve(ctx, "", "ghi789")
+	require.NoError(t, err)
+	require.NotNil(t, approved)
+
+	// The session ID's underscore is matched literally, so session11 keeps its approvals
+	require.NoError(t, manager.ClearApprovals(ctx, "session_1"))
+	approval, err = manager.GetApproval(ctx, "session_1", "hash1")
+	require.NoError(t, err)
+	require.Nil(t, approval)
+	approval, err = manager.GetApproval(ctx, "session11", "hash1")
+	require.NoError(t, err)
+	require.NotNil(t, approval)
+}
+
+func TestOwnMessages(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	manager := createTestManager(t)
+
+	messages, err := manager.OwnMessages(ctx, "session_1")
+	require.NoError(t, err)
+	require.Empty(t, messages)
+
+	require.NoError(t, manager.RecordOwnMessage(ctx, "session_1", "Use make test"))
+	require.NoError(t, manager.RecordOwnMessage(ctx, "session_1", "Use make test"))
+	require.NoError(t, manager.RecordOwnMessage(ctx, "session11", "Use just lint"))
+
+	// The session ID's underscore is matched literally, not as a LIKE wildcard
+	messages, err = manager.OwnMessages(ctx, "session_1")
+	require.NoError(t, err)
+	require.Equal(t, []string{"Use make test"}, messages)
+
+	require.NoError(t, manager.ClearOwnMessages(ctx))
+	messages, err = manager.OwnMessages(ctx, "session11")
+	require.NoError(t, err)
+	require.Empty(t, messages)
+}
+
+func TestNoteShown(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	manager := createTestManager(t)
+
+	shown, err := manager.NoteShown(ctx, "session1", "note1")
+	require.NoError(t, err)
+	require.False(t, shown)
+
+	require.NoError(t, manager.MarkNoteShown(ctx, "session1", "note1", time.Now().Add(time.Hour)))
+	require.NoError(t, manager.MarkNoteShown(ctx, "session2", "note1", time.Now().Add(-time.Second)))
+
+	shown, err = manager.NoteShown(ctx, "session1", "note1")
+	require.NoError(t, err)
+	require.True(t, shown)
+
+	shown, err = manager.NoteShown(ctx, "session2", "note1")
+	require.NoError(t, err)
+	require.False(t, shown, "expired marks count as not shown")
+
+	shown, err = manager.NoteShown(ctx, "session1", "note2")
+	require.NoError(t, err)
+	require.False(t, shown)
+
+	// Marking another note removes the expired mark, and keeps the others
+	require.NoError(t, manager.MarkNoteShown(ctx, "session1", "note2", time.Now().Add(time.Hour)))
+	var count int
+	require.NoError(t, manager.db.QueryRowContext(ctx,
+		"SELECT COUNT(*) FROM state WHERE key LIKE 'note_shown:%'").Scan(&count))
+	require.Equal(t, 2, count)
+}
+
+func TestRuleFiredTimes(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	manager := createTestManager(t)
+
+	fired, err := manager.RuleFiredTimes(ctx)
+	require.NoError(t, err)
+	require.Empty(t, fired)
+
+	first := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	require.NoError(t, manager.RecordRuleFired(ctx, "no-rm", first))
+	require.NoError(t, manager.RecordRuleFired(ctx, "no-rm", first.Add(time.Hour)))
+	require.NoError(t, manager.RecordRuleFired(ctx, "use-just", first))
+
+	fired, err = manager.RuleFiredTimes(ctx)
+	require.NoError(t, err)
+	require.Len(t, fired, 2)
+	require.True(t, fired["no-rm"].Equal(first.Add(time.Hour)), "the latest time replaces earlier ones")
+	require.True(t, fired["use-just"].Equal(first))
+}
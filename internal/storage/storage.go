@@ -52,3 +52,33 @@ func (m *Manager) GetDatabasePath() (string, error) {
 	}
 	return filepath.Join(dataDir, constants.DatabaseFilename), nil
 }
+
+// GetStatePath returns the XDG state directory for bumpers, creating it if necessary. State
+// such as the operation mode and rule toggles lives here rather than with the cache, which
+// can be deleted freely.
+func (m *Manager) GetStatePath() (string, error) {
+	stateDir := filepath.Join(xdg.StateHome, AppName)
+	err := m.fs.MkdirAll(stateDir, 0o750)
+	if err != nil {
+		return "", fmt.Errorf("failed to create state directory %s: %w", stateDir, err)
+	}
+	return stateDir, nil
+}
+
+// GetAuditLogPath returns the default path of the audit log written by the audit rule action
+func (m *Manager) GetAuditLogPath() (string, error) {
+	stateDir, err := m.GetStatePath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(stateDir, constants.AuditLogFilename), nil
+}
+
+// GetStateDatabasePath returns the full path to the bumpers state database
+func (m *Manager) GetStateDatabasePath() (string, error) {
+	stateDir, err := m.GetStatePath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(stateDir, constants.StateDatabaseFilename), nil
+}
@@ -44,6 +44,24 @@ func TestStorageManagerPaths(t *testing.T) {
 				return filepath.Join(xdg.DataHome, AppName, constants.DatabaseFilename)
 			},
 		},
+		{
+			name: "GetStatePath returns correct path",
+			methodCall: func(m *Manager) (string, error) {
+				return m.GetStatePath()
+			},
+			expectedPath: func() string {
+				return filepath.Join(xdg.StateHome, AppName)
+			},
+		},
+		{
+			name: "GetStateDatabasePath returns correct path",
+			methodCall: func(m *Manager) (string, error) {
+				return m.GetStateDatabasePath()
+			},
+			expectedPath: func() string {
+				return filepath.Join(xdg.StateHome, AppName, constants.StateDatabaseFilename)
+			},
+		},
 	}
 
 	for _, tt := range tests {
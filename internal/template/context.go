@@ -1,6 +1,11 @@
 package template
 
-import "time"
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
 
 // SharedContext contains variables available to all template types
 type SharedContext struct {
@@ -9,9 +14,15 @@ type SharedContext struct {
 
 // RuleContext contains variables specific to rule templates
 type RuleContext struct {
-	Command string
+	Command      string
+	ToolName     string // Tool that triggered the rule, e.g. "Write"
+	MatchedField string // Field whose value matched, e.g. "command" or "file_path"
+	SessionID    string // Claude session the hook event belongs to, empty when unknown
 }
 
+// sessionIDShortLength is how many characters of the session ID {{.SessionIDShort}} keeps
+const sessionIDShortLength = 8
+
 // CommandContext contains variables specific to command templates
 type CommandContext struct {
 	Name string
@@ -23,6 +34,17 @@ type CommandContext struct {
 // Currently empty but provided for consistency and future expansion
 type NoteContext struct{}
 
+// NotificationContext contains variables specific to notification templates
+type NotificationContext struct {
+	Message string
+}
+
+// CacheKeyContext contains variables specific to generate.cache_key_extra templates
+type CacheKeyContext struct {
+	ProjectRoot string
+	GitBranch   string
+}
+
 // NewSharedContext creates a new shared context with current date
 func NewSharedContext() SharedContext {
 	return SharedContext{
@@ -38,6 +60,10 @@ func MergeContexts(shared SharedContext, specific any) map[string]any {
 
 	if ruleCtx, ok := specific.(RuleContext); ok {
 		result["Command"] = ruleCtx.Command
+		result["ToolName"] = ruleCtx.ToolName
+		result["MatchedField"] = ruleCtx.MatchedField
+		result["SessionID"] = ruleCtx.SessionID
+		result["SessionIDShort"] = shortSessionID(ruleCtx.SessionID)
 	}
 
 	if cmdCtx, ok := specific.(CommandContext); ok {
@@ -46,14 +72,30 @@ func MergeContexts(shared SharedContext, specific any) map[string]any {
 		result["Argv"] = cmdCtx.Argv
 	}
 
+	if notificationCtx, ok := specific.(NotificationContext); ok {
+		result["Message"] = notificationCtx.Message
+	}
+
+	if cacheKeyCtx, ok := specific.(CacheKeyContext); ok {
+		result["ProjectRoot"] = cacheKeyCtx.ProjectRoot
+		result["GitBranch"] = cacheKeyCtx.GitBranch
+	}
+
 	return result
 }
 
+// shortSessionID returns the first characters of a session ID for concise display
+func shortSessionID(sessionID string) string {
+	if len(sessionID) <= sessionIDShortLength {
+		return sessionID
+	}
+	return sessionID[:sessionIDShortLength]
+}
+
 // BuildRuleContext creates a complete context for rule templates
-func BuildRuleContext(command string) map[string]any {
+func BuildRuleContext(ruleCtx RuleContext) map[string]any {
 	shared := NewSharedContext()
-	specific := RuleContext{Command: command}
-	return MergeContexts(shared, specific)
+	return MergeContexts(shared, ruleCtx)
 }
 
 // BuildCommandContext creates a complete context for command templates
@@ -79,3 +121,31 @@ func BuildNoteContext() map[string]any {
 	specific := NoteContext{}
 	return MergeContexts(shared, specific)
 }
+
+// BuildNotificationContext creates a complete context for notification templates
+func BuildNotificationContext(message string) map[string]any {
+	shared := NewSharedContext()
+	specific := NotificationContext{Message: message}
+	return MergeContexts(shared, specific)
+}
+
+// BuildCacheKeyContext creates a complete context for cache key templates
+func BuildCacheKeyContext(projectRoot string) map[string]any {
+	shared := NewSharedContext()
+	specific := CacheKeyContext{ProjectRoot: projectRoot, GitBranch: GitBranch(projectRoot)}
+	return MergeContexts(shared, specific)
+}
+
+// GitBranch returns the branch checked out in the git repository at dir, the commit hash
+// for a detached HEAD, or an empty string when dir is not a git repository
+func GitBranch(dir string) string {
+	if dir == "" {
+		return ""
+	}
+	data, err := os.ReadFile(filepath.Join(dir, ".git", "HEAD")) //nolint:gosec // reading git metadata
+	if err != nil {
+		return ""
+	}
+	head := strings.TrimSpace(string(data))
+	return strings.TrimPrefix(head, "ref: refs/heads/")
+}
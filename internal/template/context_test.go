@@ -1,6 +1,8 @@
 package template
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 )
@@ -48,7 +50,7 @@ func TestMergeContexts_WithRuleContext(t *testing.T) {
 func TestBuildRuleContext(t *testing.T) {
 	t.Parallel()
 
-	result := BuildRuleContext("go test")
+	result := BuildRuleContext(RuleContext{Command: "go test", ToolName: "Bash", MatchedField: "command"})
 
 	// Should have both shared and rule-specific context
 	expectedDate := time.Now().Format("2006-01-02")
@@ -58,6 +60,12 @@ func TestBuildRuleContext(t *testing.T) {
 	if result["Command"] != "go test" {
 		t.Errorf("Expected Command to be 'go test', got %v", result["Command"])
 	}
+	if result["ToolName"] != "Bash" {
+		t.Errorf("Expected ToolName to be 'Bash', got %v", result["ToolName"])
+	}
+	if result["MatchedField"] != "command" {
+		t.Errorf("Expected MatchedField to be 'command', got %v", result["MatchedField"])
+	}
 }
 
 func TestBuildCommandContext(t *testing.T) {
@@ -111,6 +119,29 @@ func TestBuildCommandContextWithArgs(t *testing.T) {
 	}
 }
 
+func TestExecuteRuleTemplateSessionID(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		sessionID string
+		want      string
+	}{
+		{sessionID: "4f6c2a1e-93b7-4d8e-a0f5-2c1b9e7d3a60", want: "4f6c2a1e: blocked in 4f6c2a1e-93b7-4d8e-a0f5-2c1b9e7d3a60"},
+		{sessionID: "abc123", want: "abc123: blocked in abc123"},
+		{sessionID: "", want: ": blocked in "},
+	}
+	for _, tt := range tests {
+		result, err := ExecuteRuleTemplate("{{.SessionIDShort}}: blocked in {{.SessionID}}",
+			RuleContext{SessionID: tt.sessionID})
+		if err != nil {
+			t.Fatalf("ExecuteRuleTemplate() error = %v", err)
+		}
+		if result != tt.want {
+			t.Errorf("ExecuteRuleTemplate() with session %q = %q, want %q", tt.sessionID, result, tt.want)
+		}
+	}
+}
+
 func TestMergeContexts_WithCommandContextArgs(t *testing.T) {
 	t.Parallel()
 
@@ -158,6 +189,63 @@ func TestBuildNoteContext(t *testing.T) {
 	}
 }
 
+func TestBuildNotificationContext(t *testing.T) {
+	t.Parallel()
+
+	result := BuildNotificationContext("Claude needs your permission to use Bash")
+
+	if result["Message"] != "Claude needs your permission to use Bash" {
+		t.Errorf("Expected Message to be set, got %v", result["Message"])
+	}
+
+	expectedDate := time.Now().Format("2006-01-02")
+	if result["Today"] != expectedDate {
+		t.Errorf("Expected Today to be %q, got %v", expectedDate, result["Today"])
+	}
+}
+
+func TestBuildCacheKeyContext(t *testing.T) {
+	t.Parallel()
+
+	projectRoot := t.TempDir()
+	gitDir := filepath.Join(projectRoot, ".git")
+	if err := os.MkdirAll(gitDir, 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(gitDir, "HEAD"), []byte("ref: refs/heads/feature/x\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	result := BuildCacheKeyContext(projectRoot)
+
+	if result["GitBranch"] != "feature/x" {
+		t.Errorf("Expected GitBranch to be 'feature/x', got %v", result["GitBranch"])
+	}
+	if result["ProjectRoot"] != projectRoot {
+		t.Errorf("Expected ProjectRoot to be %q, got %v", projectRoot, result["ProjectRoot"])
+	}
+}
+
+func TestGitBranch(t *testing.T) {
+	t.Parallel()
+
+	detached := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(detached, ".git"), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	hash := "0123456789abcdef0123456789abcdef01234567"
+	if err := os.WriteFile(filepath.Join(detached, ".git", "HEAD"), []byte(hash+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := GitBranch(detached); got != hash {
+		t.Errorf("Expected detached HEAD to return %q, got %q", hash, got)
+	}
+	if got := GitBranch(t.TempDir()); got != "" {
+		t.Errorf("Expected empty branch outside a repository, got %q", got)
+	}
+}
+
 // Table-driven test for context building functions
 func TestBuildContexts(t *testing.T) {
 	t.Parallel()
@@ -171,13 +259,21 @@ func TestBuildContexts(t *testing.T) {
 		expectedLen  int
 	}{
 		{
-			name:      "rule context",
-			buildFunc: func() map[string]any { return BuildRuleContext("go test") },
+			name: "rule context",
+			buildFunc: func() map[string]any {
+				return BuildRuleContext(RuleContext{
+					Command: "go test", ToolName: "Bash", MatchedField: "command", SessionID: "abc123",
+				})
+			},
 			expectedKeys: map[string]any{
-				"Today":   expectedDate,
-				"Command": "go test",
+				"Today":          expectedDate,
+				"Command":        "go test",
+				"ToolName":       "Bash",
+				"MatchedField":   "command",
+				"SessionID":      "abc123",
+				"SessionIDShort": "abc123",
 			},
-			expectedLen: 2,
+			expectedLen: 6,
 		},
 		{
 			name:      "command context",
@@ -2,7 +2,9 @@ package template
 
 import (
 	"encoding/base64"
+	"errors"
 	"fmt"
+	"io"
 	"path/filepath"
 	"strings"
 	"text/template"
@@ -21,6 +23,9 @@ func createFuncMap(fs afero.Fs, commandCtx *CommandContext) template.FuncMap {
 		"testPath": func(filename string) bool {
 			return testPath(fs, filename)
 		},
+		"file": func(filename string) string {
+			return file(fs, filename)
+		},
 		"argc": func() int {
 			return argc(commandCtx)
 		},
@@ -34,33 +39,8 @@ func createFuncMap(fs afero.Fs, commandCtx *CommandContext) template.FuncMap {
 // Returns empty string if file doesn't exist, is outside project, or on error
 // Text files returned as-is, binary files returned as base64 data URI
 func readFile(fs afero.Fs, filename string) string {
-	// Get project root
-	projectRoot, err := project.FindRoot()
-	if err != nil {
-		return ""
-	}
-
-	// Clean the filename to prevent directory traversal
-	cleanFilename := filepath.Clean(filename)
-
-	// Convert to absolute path within project root
-	fullPath := filepath.Join(projectRoot, cleanFilename)
-
-	// Resolve any symlinks and get absolute path
-	resolvedPath, err := filepath.Abs(fullPath)
-	if err != nil {
-		return ""
-	}
-
-	// Ensure the resolved path is still within the project root
-	resolvedProjectRoot, err := filepath.Abs(projectRoot)
-	if err != nil {
-		return ""
-	}
-
-	// Check if resolved path is within project root
-	if !strings.HasPrefix(resolvedPath, resolvedProjectRoot+string(filepath.Separator)) &&
-		resolvedPath != resolvedProjectRoot {
+	resolvedPath, ok := projectPath(filename)
+	if !ok {
 		return ""
 	}
 
@@ -80,41 +60,116 @@ func readFile(fs afero.Fs, filename string) string {
 	return fmt.Sprintf("data:application/octet-stream;base64,%s", encoded)
 }
 
-// testPath securely checks if a file or directory exists within the project root
-// Returns false if file doesn't exist, is outside project, or on error
-func testPath(fs afero.Fs, filename string) bool {
-	// Get project root
+// maxFileBytes caps how much of a file the file function includes in a message
+const maxFileBytes = 32 << 10
+
+// fileTruncatedMarker ends file contents cut off at maxFileBytes
+const fileTruncatedMarker = "\n[truncated]"
+
+// ErrOutsideProject is returned for a file path that resolves outside the project root
+var ErrOutsideProject = errors.New("path is outside the project root")
+
+// ErrNotText is returned for a file whose contents aren't valid UTF-8
+var ErrNotText = errors.New("file is not text")
+
+// file reads a text file from within the project root for inclusion in a message, cut off
+// at maxFileBytes. Returns empty string if the file doesn't exist, is outside the project,
+// isn't text, or on error.
+func file(fs afero.Fs, filename string) string {
 	projectRoot, err := project.FindRoot()
 	if err != nil {
-		return false
+		return ""
 	}
+	content, err := ProjectFile(fs, projectRoot, filename)
+	if err != nil {
+		return ""
+	}
+	return content
+}
 
-	// Clean the filename to prevent directory traversal
-	cleanFilename := filepath.Clean(filename)
+// ProjectFile reads a text file under projectRoot for inclusion in a message, the way the
+// file function does: relative paths are resolved against projectRoot, paths outside it are
+// refused with ErrOutsideProject, contents past 32KB are cut off and marked, and anything
+// that isn't UTF-8 is refused with ErrNotText.
+func ProjectFile(fs afero.Fs, projectRoot, filename string) (string, error) {
+	resolvedPath, ok := pathUnder(projectRoot, filename)
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrOutsideProject, filename)
+	}
 
-	// Convert to absolute path within project root
-	fullPath := filepath.Join(projectRoot, cleanFilename)
+	f, err := fs.Open(resolvedPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to open file: %w", err)
+	}
+	defer func() {
+		_ = f.Close()
+	}()
 
-	// Resolve any symlinks and get absolute path
-	resolvedPath, err := filepath.Abs(fullPath)
+	// Read one byte past the cap to tell whether the file was cut off
+	content, err := io.ReadAll(io.LimitReader(f, maxFileBytes+1))
 	if err != nil {
-		return false
+		return "", fmt.Errorf("failed to read file: %w", err)
+	}
+	truncated := len(content) > maxFileBytes
+	if truncated {
+		content = content[:maxFileBytes]
+		// Don't split a multi-byte character at the cap
+		for i := 1; i < utf8.UTFMax && !utf8.Valid(content); i++ {
+			content = content[:len(content)-1]
+		}
+	}
+	if !utf8.Valid(content) {
+		return "", fmt.Errorf("%w: %s", ErrNotText, filename)
+	}
+	if truncated {
+		return string(content) + fileTruncatedMarker, nil
 	}
+	return string(content), nil
+}
 
-	// Ensure the resolved path is still within the project root
+// projectPath resolves filename against the project root, reporting false when the project
+// root can't be found or the path falls outside it
+func projectPath(filename string) (string, bool) {
+	projectRoot, err := project.FindRoot()
+	if err != nil {
+		return "", false
+	}
+	return pathUnder(projectRoot, filepath.Join(projectRoot, filepath.Clean(filename)))
+}
+
+// pathUnder resolves filename against projectRoot, or takes it as is when it's absolute,
+// reporting false when the result falls outside projectRoot
+func pathUnder(projectRoot, filename string) (string, bool) {
+	resolvedPath := filename
+	if !filepath.IsAbs(resolvedPath) {
+		resolvedPath = filepath.Join(projectRoot, resolvedPath)
+	}
+	resolvedPath, err := filepath.Abs(resolvedPath)
+	if err != nil {
+		return "", false
+	}
 	resolvedProjectRoot, err := filepath.Abs(projectRoot)
 	if err != nil {
-		return false
+		return "", false
 	}
 
-	// Check if resolved path is within project root
 	if !strings.HasPrefix(resolvedPath, resolvedProjectRoot+string(filepath.Separator)) &&
 		resolvedPath != resolvedProjectRoot {
+		return "", false
+	}
+	return resolvedPath, true
+}
+
+// testPath securely checks if a file or directory exists within the project root
+// Returns false if file doesn't exist, is outside project, or on error
+func testPath(fs afero.Fs, filename string) bool {
+	resolvedPath, ok := projectPath(filename)
+	if !ok {
 		return false
 	}
 
 	// Check if the file or directory exists
-	_, err = fs.Stat(resolvedPath)
+	_, err := fs.Stat(resolvedPath)
 	return err == nil
 }
 
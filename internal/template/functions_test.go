@@ -1,9 +1,12 @@
 package template
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"unicode/utf8"
 
 	"github.com/spf13/afero"
 	"github.com/wizzomafizzo/bumpers/internal/project"
@@ -128,6 +131,86 @@ func TestReadFile_DirectoryTraversal_WithRealFS_ReturnsEmpty(t *testing.T) {
 	}
 }
 
+func TestFile(t *testing.T) {
+	t.Parallel()
+
+	projectRoot, err := project.FindRoot()
+	if err != nil {
+		t.Fatalf("Failed to find project root: %v", err)
+	}
+	fs := afero.NewMemMapFs()
+	writeFile := func(name string, content []byte) {
+		if err := afero.WriteFile(fs, filepath.Join(projectRoot, name), content, 0o600); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+	writeFile("TODO.md", []byte("- write tests\n"))
+	writeFile("big.md", []byte("a"+strings.Repeat("é", maxFileBytes)))
+	writeFile("binary.dat", []byte{0xFF, 0xFE, 0x00, 0x01})
+	if err := afero.WriteFile(fs, filepath.Join(filepath.Dir(projectRoot), "secret.txt"),
+		[]byte("secret"), 0o600); err != nil {
+		t.Fatalf("Failed to write outside file: %v", err)
+	}
+
+	if got := file(fs, "TODO.md"); got != "- write tests\n" {
+		t.Errorf("Expected file content, got %q", got)
+	}
+	if got := file(fs, "../secret.txt"); got != "" {
+		t.Errorf("Directory traversal should return empty string, got %q", got)
+	}
+	if got := file(fs, "docs/../../secret.txt"); got != "" {
+		t.Errorf("Directory traversal should return empty string, got %q", got)
+	}
+	if got := file(fs, "binary.dat"); got != "" {
+		t.Errorf("Binary file should return empty string, got %q", got)
+	}
+	if got := file(fs, "missing.md"); got != "" {
+		t.Errorf("Missing file should return empty string, got %q", got)
+	}
+
+	big := file(fs, "big.md")
+	if !strings.HasSuffix(big, fileTruncatedMarker) {
+		t.Fatalf("Expected large file to be truncated, got %d bytes", len(big))
+	}
+	content := strings.TrimSuffix(big, fileTruncatedMarker)
+	if len(content) > maxFileBytes || !utf8.ValidString(content) {
+		t.Errorf("Expected at most %d bytes of valid UTF-8, got %d bytes", maxFileBytes, len(content))
+	}
+}
+
+func TestProjectFile(t *testing.T) {
+	t.Parallel()
+
+	projectRoot := "/project"
+	fs := afero.NewMemMapFs()
+	for name, content := range map[string]string{
+		"/project/CLAUDE.md": "Use just\n",
+		"/secret.txt":        "secret",
+		"/project/bin.dat":   "\xff\xfe",
+	} {
+		if err := afero.WriteFile(fs, name, []byte(content), 0o600); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	for _, name := range []string{"CLAUDE.md", "/project/CLAUDE.md"} {
+		if got, err := ProjectFile(fs, projectRoot, name); err != nil || got != "Use just\n" {
+			t.Errorf("ProjectFile(%q) = %q, %v, want the file's content", name, got, err)
+		}
+	}
+	for _, name := range []string{"../secret.txt", "/secret.txt"} {
+		if _, err := ProjectFile(fs, projectRoot, name); !errors.Is(err, ErrOutsideProject) {
+			t.Errorf("ProjectFile(%q) error = %v, want ErrOutsideProject", name, err)
+		}
+	}
+	if _, err := ProjectFile(fs, projectRoot, "bin.dat"); !errors.Is(err, ErrNotText) {
+		t.Errorf("Expected ErrNotText for a binary file, got %v", err)
+	}
+	if _, err := ProjectFile(fs, projectRoot, "missing.md"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected os.ErrNotExist for a missing file, got %v", err)
+	}
+}
+
 func setupMemoryFS(_ *testing.T) (fs afero.Fs, cleanup func()) {
 	return afero.NewMemMapFs(), func() {}
 }
@@ -49,12 +49,21 @@ func ExecuteWithCommandContext(templateStr string, data any, commandCtx *Command
 	return result.String(), nil
 }
 
-// ExecuteRuleTemplate processes a rule message template with the given command
-func ExecuteRuleTemplate(message, command string) (string, error) {
-	context := BuildRuleContext(command)
+// ExecuteRuleTemplate processes a rule message template with the matched command, tool and field
+func ExecuteRuleTemplate(message string, ruleCtx RuleContext) (string, error) {
+	context := BuildRuleContext(ruleCtx)
 	return Execute(message, context)
 }
 
+// ExecuteCacheKeyTemplate processes a generate.cache_key_extra template for the given project
+func ExecuteCacheKeyTemplate(extra, projectRoot string) (string, error) {
+	if extra == "" {
+		return "", nil
+	}
+	context := BuildCacheKeyContext(projectRoot)
+	return Execute(extra, context)
+}
+
 // ExecuteCommandTemplate processes a command message template with the given command name
 func ExecuteCommandTemplate(message, commandName string) (string, error) {
 	context := BuildCommandContext(commandName)
@@ -77,3 +86,9 @@ func ExecuteNoteTemplate(message string) (string, error) {
 	context := BuildNoteContext()
 	return Execute(message, context)
 }
+
+// ExecuteNotificationTemplate processes a notification message template with the notification text
+func ExecuteNotificationTemplate(message, notificationMessage string) (string, error) {
+	context := BuildNotificationContext(notificationMessage)
+	return Execute(message, context)
+}
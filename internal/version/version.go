@@ -0,0 +1,213 @@
+// Package version holds the bumpers build version and evaluates the semver constraints
+// configs pin it to with settings.required_version.
+package version
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Dev is the version of builds made without a release version
+const Dev = "dev"
+
+// Version is the running binary's version, set at build time with
+// -ldflags "-X github.com/wizzomafizzo/bumpers/internal/version.Version=v1.2.3"
+var Version = Dev
+
+// ErrInvalidVersion is returned when a version or constraint can't be parsed
+var ErrInvalidVersion = errors.New("invalid version")
+
+// IsDev reports whether v is a development build, which can't be compared to a constraint
+func IsDev(v string) bool {
+	return v == "" || v == Dev
+}
+
+// semver is a parsed major.minor.patch version with an optional pre-release
+type semver struct {
+	pre   string
+	parts [3]int
+}
+
+// parse parses a version like "1.2.3", "v1.2" or "1.2.3-rc.1+build", returning how many
+// of major, minor and patch were given. Missing parts are zero.
+func parse(s string) (semver, int, error) {
+	var v semver
+	s = strings.TrimPrefix(strings.TrimSpace(s), "v")
+	if i := strings.IndexByte(s, '+'); i >= 0 {
+		s = s[:i]
+	}
+	if i := strings.IndexByte(s, '-'); i >= 0 {
+		s, v.pre = s[:i], s[i+1:]
+	}
+
+	fields := strings.Split(s, ".")
+	if s == "" || len(fields) > 3 {
+		return v, 0, fmt.Errorf("%w: %q", ErrInvalidVersion, s)
+	}
+	for i, field := range fields {
+		n, err := strconv.Atoi(field)
+		if err != nil || n < 0 {
+			return v, 0, fmt.Errorf("%w: %q", ErrInvalidVersion, s)
+		}
+		v.parts[i] = n
+	}
+	return v, len(fields), nil
+}
+
+// compare returns -1, 0 or 1 as a is less than, equal to or greater than b. A pre-release
+// sorts before its release.
+func compare(a, b semver) int {
+	for i := range a.parts {
+		if a.parts[i] != b.parts[i] {
+			if a.parts[i] < b.parts[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	switch {
+	case a.pre == b.pre:
+		return 0
+	case a.pre == "":
+		return 1
+	case b.pre == "":
+		return -1
+	case a.pre < b.pre:
+		return -1
+	default:
+		return 1
+	}
+}
+
+// bound is one comparison a version must pass, like ">= 1.2.0"
+type bound struct {
+	op string
+	v  semver
+}
+
+// test reports whether v passes the comparison
+func (b bound) test(v semver) bool {
+	c := compare(v, b.v)
+	switch b.op {
+	case ">":
+		return c > 0
+	case ">=":
+		return c >= 0
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case "!=":
+		return c != 0
+	default:
+		return c == 0
+	}
+}
+
+// parseTerm parses one term of a constraint into the bounds it stands for. "^1.2.3" allows
+// changes that keep the first non-zero part, and "~1.2.3" allows patch changes, or minor
+// changes when only the major version is given.
+func parseTerm(term string) ([]bound, error) {
+	op := ""
+	for _, candidate := range []string{">=", "<=", "!=", ">", "<", "=", "^", "~"} {
+		if strings.HasPrefix(term, candidate) {
+			op = candidate
+			break
+		}
+	}
+
+	v, given, err := parse(term[len(op):])
+	if err != nil {
+		return nil, err
+	}
+	if op != "^" && op != "~" {
+		if op == "" {
+			op = "="
+		}
+		return []bound{{op: op, v: v}}, nil
+	}
+
+	upper := semver{}
+	switch {
+	case op == "~" && given == 1:
+		upper.parts = [3]int{v.parts[0] + 1, 0, 0}
+	case op == "~":
+		upper.parts = [3]int{v.parts[0], v.parts[1] + 1, 0}
+	case v.parts[0] > 0 || given == 1:
+		upper.parts = [3]int{v.parts[0] + 1, 0, 0}
+	case v.parts[1] > 0 || given == 2:
+		upper.parts = [3]int{0, v.parts[1] + 1, 0}
+	default:
+		upper.parts = [3]int{0, 0, v.parts[2] + 1}
+	}
+	// A pre-release of the upper bound, like 2.0.0-rc.1, is still excluded
+	upper.pre = "0"
+	return []bound{{op: ">=", v: v}, {op: "<", v: upper}}, nil
+}
+
+// parseConstraint parses a constraint into alternatives joined by "||", each a list of
+// bounds separated by spaces or commas that must all pass
+func parseConstraint(constraint string) ([][]bound, error) {
+	var alternatives [][]bound
+	for _, alternative := range strings.Split(constraint, "||") {
+		// Allow a space between an operator and its version, like ">= 1.2"
+		alternative = strings.NewReplacer(", ", " ", ",", " ").Replace(alternative)
+		fields := strings.Fields(alternative)
+		var bounds []bound
+		for i := 0; i < len(fields); i++ {
+			term := fields[i]
+			if strings.Trim(term, "<>=!^~") == "" && i+1 < len(fields) {
+				i++
+				term += fields[i]
+			}
+			termBounds, err := parseTerm(term)
+			if err != nil {
+				return nil, fmt.Errorf("invalid constraint %q: %w", constraint, err)
+			}
+			bounds = append(bounds, termBounds...)
+		}
+		if len(bounds) == 0 {
+			return nil, fmt.Errorf("%w: empty constraint %q", ErrInvalidVersion, constraint)
+		}
+		alternatives = append(alternatives, bounds)
+	}
+	return alternatives, nil
+}
+
+// ValidateConstraint reports whether constraint can be parsed
+func ValidateConstraint(constraint string) error {
+	_, err := parseConstraint(constraint)
+	return err
+}
+
+// Satisfies reports whether version v meets constraint, such as ">=1.2.0 <2.0.0", "^1.4"
+// or "~1.4.2 || >=2.1". Development builds satisfy every constraint.
+func Satisfies(v, constraint string) (bool, error) {
+	alternatives, err := parseConstraint(constraint)
+	if err != nil {
+		return false, err
+	}
+	if IsDev(v) {
+		return true, nil
+	}
+
+	parsed, _, err := parse(v)
+	if err != nil {
+		return false, err
+	}
+	for _, bounds := range alternatives {
+		passed := true
+		for _, b := range bounds {
+			if !b.test(parsed) {
+				passed = false
+				break
+			}
+		}
+		if passed {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,64 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSatisfies(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		version    string
+		constraint string
+		want       bool
+	}{
+		{version: "1.4.2", constraint: ">=1.2.0", want: true},
+		{version: "v1.4.2", constraint: ">= 1.2", want: true},
+		{version: "1.1.9", constraint: ">=1.2.0", want: false},
+		{version: "1.4.2", constraint: ">=1.2.0 <2.0.0", want: true},
+		{version: "2.0.0", constraint: ">=1.2.0, <2.0.0", want: false},
+		{version: "1.4.2", constraint: "1.4.2", want: true},
+		{version: "1.4.3", constraint: "=1.4.2", want: false},
+		{version: "1.4.3", constraint: "!=1.4.2", want: true},
+		{version: "1.9.0", constraint: "^1.4", want: true},
+		{version: "2.0.0", constraint: "^1.4", want: false},
+		{version: "2.0.0-rc.1", constraint: "^1.4", want: false},
+		{version: "0.3.1", constraint: "^0.3.0", want: true},
+		{version: "0.4.0", constraint: "^0.3.0", want: false},
+		{version: "1.4.9", constraint: "~1.4.2", want: true},
+		{version: "1.5.0", constraint: "~1.4.2", want: false},
+		{version: "1.9.0", constraint: "~1", want: true},
+		{version: "1.2.0-beta", constraint: ">=1.2.0", want: false},
+		{version: "1.2.0+build.7", constraint: ">=1.2.0", want: true},
+		{version: "2.2.0", constraint: "~1.4 || >=2.1", want: true},
+		{version: "2.0.5", constraint: "~1.4 || >=2.1", want: false},
+		{version: "dev", constraint: ">=9.0.0", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version+" "+tt.constraint, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := Satisfies(tt.version, tt.constraint)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSatisfiesInvalid(t *testing.T) {
+	t.Parallel()
+
+	for _, constraint := range []string{"", ">=", "1.2.3.4", ">=one", "^1.x", "1.2 ||"} {
+		require.ErrorIs(t, ValidateConstraint(constraint), ErrInvalidVersion, constraint)
+	}
+
+	_, err := Satisfies("not-a-version", ">=1.0.0")
+	require.ErrorIs(t, err, ErrInvalidVersion)
+
+	_, err = Satisfies(Dev, "bogus")
+	require.ErrorIs(t, err, ErrInvalidVersion, "invalid constraints are reported even for dev builds")
+}